@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"sync"
 	"time"
 
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
@@ -35,6 +36,7 @@ const (
 	ActionDoing   Status = "doing"
 	ActionDone    Status = "done" // means success
 	ActionFailed  Status = "failed"
+	ActionAborted Status = "aborted" // means cancelled before or during execution
 )
 
 // ItemStatus represents the status of an action item
@@ -62,8 +64,19 @@ type Action interface {
 	GetNode() *pb.Node
 	GetExecuteLogBuffer() io.ReadWriter
 	SetExecuteLogBuffer(io.ReadWriter)
+	GetAttempts() int
+	SetAttempts(int)
+	SetStatusObserver(StatusObserver)
+	// Update changes the state of the action under its lock, View reads it under the lock,
+	// so that the action can be read by other goroutines while it's being executed.
+	Update(change func())
+	View(read func())
 }
 
+// StatusObserver is called after the status or the error of an action is set, from is the status
+// before it's set, which is the same as the current one if only the error is set.
+type StatusObserver func(from Status)
+
 // Base is the basic metadata of an action
 type Base struct {
 	Name              string
@@ -73,7 +86,12 @@ type Base struct {
 	LogFilePath       string
 	CreationTimestamp time.Time
 	Node              *pb.Node
-	ExecuteLogBuffer  io.ReadWriter
+	ExecuteLogBuffer  io.ReadWriter `json:"-"`
+	Attempts          int           // the number of attempts made by the last execution
+
+	statusObserver StatusObserver
+	// lock guards the state changed during the execution against the readers in other goroutines
+	lock sync.RWMutex
 }
 
 func (b *Base) GetName() string {
@@ -81,11 +99,19 @@ func (b *Base) GetName() string {
 }
 
 func (b *Base) GetStatus() Status {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
 	return b.Status
 }
 
 func (b *Base) SetStatus(status Status) {
+	b.lock.Lock()
+	from := b.Status
 	b.Status = status
+	b.lock.Unlock()
+	if b.statusObserver != nil && status != from {
+		b.statusObserver(from)
+	}
 }
 
 func (b *Base) GetType() Type {
@@ -93,11 +119,19 @@ func (b *Base) GetType() Type {
 }
 
 func (b *Base) GetErr() *pb.Error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
 	return b.Err
 }
 
 func (b *Base) SetErr(err *pb.Error) {
+	b.lock.Lock()
 	b.Err = err
+	status := b.Status
+	b.lock.Unlock()
+	if b.statusObserver != nil {
+		b.statusObserver(status)
+	}
 }
 
 func (b *Base) GetLogFilePath() string {
@@ -124,6 +158,38 @@ func (b *Base) SetExecuteLogBuffer(buf io.ReadWriter) {
 	b.ExecuteLogBuffer = buf
 }
 
+func (b *Base) GetAttempts() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.Attempts
+}
+
+func (b *Base) SetAttempts(attempts int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.Attempts = attempts
+}
+
+func (b *Base) SetStatusObserver(observer StatusObserver) {
+	b.statusObserver = observer
+}
+
+// Update calls change under the lock of the action, change must not call the methods of the action
+// which take the lock as well.
+func (b *Base) Update(change func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	change()
+}
+
+// View calls read under the read lock of the action, read must not call the methods of the action
+// which take the lock as well.
+func (b *Base) View(read func()) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	read()
+}
+
 // GenActionLogFilePath is a helper to return a file path based on the base path and aciton name
 func GenActionLogFilePath(basePath, actionName string, nodeName string) string {
 	if basePath == "" || actionName == "" || nodeName == "" {
@@ -16,6 +16,7 @@ package action
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"math/rand"
@@ -101,12 +102,12 @@ func NewConnectivityCheckAction(cfg *ConnectivityCheckActionConfig) (Action, err
 }
 
 func init() {
-	RegisterExecutor(ActionTypeConnectivityCheck, new(connectivityCheckExecutor))
+	RegisterExecutor(ActionTypeConnectivityCheck, func() Executor { return new(connectivityCheckExecutor) })
 }
 
 type connectivityCheckExecutor struct{}
 
-func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
+func (e *connectivityCheckExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	connectivityCheckAction, ok := act.(*ConnectivityCheckAction)
 	if !ok {
 		return errOfTypeMismatched(new(ConnectivityCheckAction), act)
@@ -122,6 +123,9 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 	// make a executor client for destination node to capture packets
 	dstMachine, err := machine.NewMachine(dstNode)
 	if err != nil {
+		if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+			return pbErr
+		}
 		return &pb.Error{
 			Reason: "failed to start SSH client",
 			Detail: fmt.Sprintf("Failed to create connetion to %s by connecting to %s, error %v",
@@ -130,10 +134,14 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 		}
 	}
 	defer dstMachine.Close()
+	defer closeOnCancel(ctx, dstMachine)()
 
 	// make a executor client for source node to send packets
 	srcMachine, err := machine.NewMachine(srcNode)
 	if err != nil {
+		if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+			return pbErr
+		}
 		return &pb.Error{
 			Reason: "failed to start SSH client",
 			Detail: fmt.Sprintf("Failed to create connetion to %s by connecting to %s, error %v",
@@ -142,8 +150,12 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 		}
 	}
 	defer srcMachine.Close()
+	defer closeOnCancel(ctx, srcMachine)()
 
 	for _, checkItem := range connectivityCheckAction.CheckItems {
+		if ctx.Err() != nil {
+			return errOfAborted(ctx)
+		}
 		randGen := rand.New(rand.NewSource(time.Now().UnixNano()))
 		srcPort := (randGen.Uint32() % 16384) + 45000
 		captureCommand := []string{"timeout", "5",
@@ -174,7 +186,9 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 			}
 		}
 		if checkItem.CheckResult != nil {
-			checkItem.CheckResult.Status = string(ItemDoing)
+			act.Update(func() {
+				checkItem.CheckResult.Status = string(ItemDoing)
+			})
 		}
 
 		executeLogBuf := act.GetExecuteLogBuffer()
@@ -185,6 +199,7 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 			var e error
 			dstCommand := command.NewShellCommand(dstMachine,
 				captureCommand[0], captureCommand[1:]...).
+				WithContext(ctx).
 				WithDescription("capture test packet on " + dstNode.Name).
 				WithExecuteLogWriter(dstExecuteLogBuf)
 
@@ -196,6 +211,7 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 		time.Sleep(time.Second)
 		srcExecuteLogBuf := &bytes.Buffer{}
 		srcCommand := command.NewShellCommand(srcMachine, sendCommand[0], sendCommand[1:]...).
+			WithContext(ctx).
 			WithDescription("send test packet").
 			WithExecuteLogWriter(srcExecuteLogBuf)
 		_, srcStderr, srcErr := srcCommand.Execute()
@@ -211,8 +227,10 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 					sendCommand[0], srcNode.Name),
 			}
 			if checkItem.CheckResult != nil {
-				checkItem.CheckResult.Status = string(ItemFailed)
-				checkItem.CheckResult.Err = checkErr
+				act.Update(func() {
+					checkItem.CheckResult.Status = string(ItemFailed)
+					checkItem.CheckResult.Err = checkErr
+				})
 				continue
 			}
 		}
@@ -230,13 +248,17 @@ func (e *connectivityCheckExecutor) Execute(act Action) *pb.Error {
 				FixMethods: "configure network or firewall to allow these packets",
 			}
 			if checkItem.CheckResult != nil {
-				checkItem.CheckResult.Status = string(ItemFailed)
-				checkItem.CheckResult.Err = checkErr
+				act.Update(func() {
+					checkItem.CheckResult.Status = string(ItemFailed)
+					checkItem.CheckResult.Err = checkErr
+				})
 			}
 			// does not return here to continue to check other items
 		} else {
 			if checkItem.CheckResult != nil {
-				checkItem.CheckResult.Status = string(ItemDone)
+				act.Update(func() {
+					checkItem.CheckResult.Status = string(ItemDone)
+				})
 			}
 		}
 	} // end of for in range chekItems
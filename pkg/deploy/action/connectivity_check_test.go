@@ -15,6 +15,7 @@
 package action
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,7 +44,7 @@ func TestConnectivityCheck(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, normalAction)
 
-	pbErr := executor.Execute(normalAction)
+	pbErr := executor.Execute(context.Background(), normalAction)
 	assert.Nil(t, pbErr)
 
 	errorAction, err := NewConnectivityCheckAction(&ConnectivityCheckActionConfig{
@@ -59,7 +60,7 @@ func TestConnectivityCheck(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, errorAction)
 
-	pbErr = executor.Execute(errorAction)
+	pbErr = executor.Execute(context.Background(), errorAction)
 	assert.NoError(t, err)
 	assert.NotNil(t, pbErr)
 }
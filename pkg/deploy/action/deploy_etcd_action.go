@@ -17,11 +17,15 @@ package action
 import (
 	"crypto"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
 
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation/etcd"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
@@ -39,8 +43,8 @@ type DeployEtcdActionConfig struct {
 type DeployEtcdAction struct {
 	Base
 
-	CACrt        *x509.Certificate
-	CAKey        crypto.Signer
+	CACrt        *x509.Certificate `json:"-"`
+	CAKey        crypto.Signer     `json:"-"`
 	ClusterNodes []*pb.Node
 }
 
@@ -74,3 +78,56 @@ func NewDeployEtcdAction(cfg *DeployEtcdActionConfig) (Action, error) {
 		ClusterNodes: cfg.ClusterNodes,
 	}, nil
 }
+
+// deployEtcdActionJSON is the serialized form of a DeployEtcdAction, the ca cert and key are
+// encoded in PEM, so that the actions reloaded by the task store can be resumed with the same ca.
+type deployEtcdActionJSON struct {
+	*deployEtcdActionFields
+	CACrt []byte `json:",omitempty"`
+	CAKey []byte `json:",omitempty"`
+}
+
+// deployEtcdActionFields has the same fields as DeployEtcdAction without its json methods.
+type deployEtcdActionFields DeployEtcdAction
+
+func (a *DeployEtcdAction) MarshalJSON() ([]byte, error) {
+	encodedKey, encodedCrt, err := etcd.ToByte(a.CACrt, a.CAKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&deployEtcdActionJSON{
+		deployEtcdActionFields: (*deployEtcdActionFields)(a),
+		CACrt:                  encodedCrt,
+		CAKey:                  encodedKey,
+	})
+}
+
+func (a *DeployEtcdAction) UnmarshalJSON(data []byte) error {
+	content := &deployEtcdActionJSON{deployEtcdActionFields: (*deployEtcdActionFields)(a)}
+	if err := json.Unmarshal(data, content); err != nil {
+		return err
+	}
+
+	if len(content.CACrt) > 0 {
+		certs, err := certutil.ParseCertsPEM(content.CACrt)
+		if err != nil {
+			return fmt.Errorf("failed to parse etcd ca cert: %v", err)
+		}
+		a.CACrt = certs[0]
+	}
+
+	if len(content.CAKey) > 0 {
+		key, err := keyutil.ParsePrivateKeyPEM(content.CAKey)
+		if err != nil {
+			return fmt.Errorf("failed to parse etcd ca key: %v", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("etcd ca key is not a crypto.Signer: %T", key)
+		}
+		a.CAKey = signer
+	}
+
+	return nil
+}
@@ -15,21 +15,24 @@
 package action
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation/etcd"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
 func init() {
-	RegisterExecutor(ActionTypeDeployEtcd, new(deployEtcdExecutor))
+	RegisterExecutor(ActionTypeDeployEtcd, func() Executor { return new(deployEtcdExecutor) })
 }
 
 type deployEtcdExecutor struct {
 }
 
-func (a *deployEtcdExecutor) Execute(act Action) *pb.Error {
+func (a *deployEtcdExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	etcdAction, ok := act.(*DeployEtcdAction)
 	if !ok {
 		return errOfTypeMismatched(new(DeployEtcdAction), act)
@@ -41,15 +44,27 @@ func (a *deployEtcdExecutor) Execute(act Action) *pb.Error {
 
 	logger.Debug("Start to execute deploy etcd action")
 
-	config := &etcd.DeployEtcdOperationConfig{
-		Logger:       logger,
-		Node:         etcdAction.Node,
-		CACrt:        etcdAction.CACrt,
-		CAKey:        etcdAction.CAKey,
-		ClusterNodes: etcdAction.ClusterNodes,
+	m, err := newMachineContext(ctx, etcdAction.Node)
+	if err != nil {
+		if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+			return pbErr
+		}
+		if pbErr := errOfTransient(err); pbErr != nil {
+			return pbErr
+		}
+		return &pb.Error{
+			Reason: "failed to connect to target node",
+			Detail: err.Error(),
+		}
 	}
+	defer closeOnCancel(ctx, m)()
+
+	config := newDeployEtcdOperationConfig(etcdAction, logger)
+	config.Context = ctx
+	config.Machine = m
 	op, err := etcd.NewDeployEtcdOperation(config)
 	if err != nil {
+		m.Close()
 		return &pb.Error{
 			Reason: "failed to get etcd operation",
 			Detail: err.Error(),
@@ -58,7 +73,6 @@ func (a *deployEtcdExecutor) Execute(act Action) *pb.Error {
 
 	logger.Debugf("Start to deploy etcd on node: %s", etcdAction.Node.Name)
 
-	etcdAction.Status = ActionDone
 	if err := op.Do(); err != nil {
 		return &pb.Error{
 			Reason:     "failed to do etcd operation",
@@ -70,3 +84,40 @@ func (a *deployEtcdExecutor) Execute(act Action) *pb.Error {
 	logger.Debug("Finish to execute deploy etcd action")
 	return nil
 }
+
+func (a *deployEtcdExecutor) Plan(act Action, newMachine machine.Factory) *pb.Error {
+	etcdAction, ok := act.(*DeployEtcdAction)
+	if !ok {
+		return errOfTypeMismatched(new(DeployEtcdAction), act)
+	}
+
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldAction: act.GetName(),
+	})
+
+	config := newDeployEtcdOperationConfig(etcdAction, logger)
+	m, err := newMachine(etcdAction.Node)
+	if err != nil {
+		return errOfPlanFailed(err)
+	}
+	config.Machine = m
+
+	op, err := etcd.NewDeployEtcdOperation(config)
+	if err != nil {
+		return errOfPlanFailed(err)
+	}
+	if err := op.Plan(); err != nil {
+		return errOfPlanFailed(err)
+	}
+	return nil
+}
+
+func newDeployEtcdOperationConfig(etcdAction *DeployEtcdAction, logger *logrus.Entry) *etcd.DeployEtcdOperationConfig {
+	return &etcd.DeployEtcdOperationConfig{
+		Logger:       logger,
+		Node:         etcdAction.Node,
+		CACrt:        etcdAction.CACrt,
+		CAKey:        etcdAction.CAKey,
+		ClusterNodes: etcdAction.ClusterNodes,
+	}
+}
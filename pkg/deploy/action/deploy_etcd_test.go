@@ -15,6 +15,7 @@
 package action
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -48,7 +49,7 @@ func TestDeployEtcd(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, normalAction)
 
-	pbErr := executor.Execute(normalAction)
+	pbErr := executor.Execute(context.Background(), normalAction)
 	assert.Nil(t, pbErr)
 
 	errorAction, err := NewDeployEtcdAction(&DeployEtcdActionConfig{
@@ -62,7 +63,7 @@ func TestDeployEtcd(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, errorAction)
 
-	pbErr = executor.Execute(errorAction)
+	pbErr = executor.Execute(context.Background(), errorAction)
 	assert.NoError(t, err)
 	assert.NotNil(t, pbErr)
 }
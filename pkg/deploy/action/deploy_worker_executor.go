@@ -15,11 +15,9 @@
 package action
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 
 	"github.com/sirupsen/logrus"
 
@@ -30,7 +28,7 @@ import (
 )
 
 func init() {
-	RegisterExecutor(ActionTypeDeployWorker, new(deployWorkerExecutor))
+	RegisterExecutor(ActionTypeDeployWorker, func() Executor { return new(deployWorkerExecutor) })
 }
 
 type deployWorkerExecutor struct {
@@ -41,7 +39,7 @@ type deployWorkerExecutor struct {
 	executeLogWriter io.Writer
 }
 
-func (executor *deployWorkerExecutor) Execute(act Action) *protos.Error {
+func (executor *deployWorkerExecutor) Execute(ctx context.Context, act Action) *protos.Error {
 
 	action, ok := act.(*DeployWorkerAction)
 	if !ok {
@@ -51,8 +49,8 @@ func (executor *deployWorkerExecutor) Execute(act Action) *protos.Error {
 	executor.action = action
 
 	executor.initLogger()
-	executor.initExecuteLogWriter()
-	defer executor.closeExecuteLogWriter()
+	// the execute logs are appended to the log file of the action which the execution setup opened
+	executor.executeLogWriter = action.GetExecuteLogBuffer()
 
 	executor.logger.Info("start to execute deploy worker executor")
 
@@ -61,21 +59,18 @@ func (executor *deployWorkerExecutor) Execute(act Action) *protos.Error {
 		return err
 	}
 	defer executor.disconnectSSH()
+	defer closeOnCancel(ctx, executor.machine)()
 
 	if err := executor.connectMasterNode(); err != nil {
 		return err
 	}
 	defer executor.disconnectMasterNode()
+	defer closeOnCancel(ctx, executor.masterMachine)()
 
-	operations := []func() *protos.Error{
-		executor.startKubelet,
-		executor.joinCluster,
-		executor.appendLabel,
-		executor.appendAnnotation,
-		executor.appendTaint,
-	}
-
-	for _, operation := range operations {
+	for _, operation := range executor.operations() {
+		if ctx.Err() != nil {
+			return errOfAborted(ctx)
+		}
 		err := operation()
 		if err != nil {
 			return err
@@ -87,6 +82,48 @@ func (executor *deployWorkerExecutor) Execute(act Action) *protos.Error {
 	return nil
 }
 
+// Plan plans the operations with the machines created by newMachine instead of connecting the nodes.
+func (executor *deployWorkerExecutor) Plan(act Action, newMachine deployMachine.Factory) *protos.Error {
+
+	action, ok := act.(*DeployWorkerAction)
+	if !ok {
+		return errOfTypeMismatched(new(DeployWorkerAction), act)
+	}
+	if len(action.config.MasterNodes) == 0 {
+		return errOfPlanFailed(fmt.Errorf("no master node to join"))
+	}
+
+	executor.action = action
+	executor.initLogger()
+
+	var err error
+	if executor.machine, err = newMachine(action.config.NodeCfg.GetNode()); err != nil {
+		return errOfPlanFailed(err)
+	}
+	if executor.masterMachine, err = newMachine(action.config.MasterNodes[0]); err != nil {
+		return errOfPlanFailed(err)
+	}
+
+	for _, operation := range executor.operations() {
+		if err := operation(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (executor *deployWorkerExecutor) operations() []func() *protos.Error {
+
+	// the kubelet is started by the prepare worker action before
+	return []func() *protos.Error{
+		executor.joinCluster,
+		executor.appendLabel,
+		executor.appendAnnotation,
+		executor.appendTaint,
+	}
+}
+
 func (executor *deployWorkerExecutor) connectSSH() *protos.Error {
 
 	executor.logger.Debug("Start to connect ssh")
@@ -94,6 +131,10 @@ func (executor *deployWorkerExecutor) connectSSH() *protos.Error {
 	var err error
 	executor.machine, err = deployMachine.NewMachine(executor.action.config.NodeCfg.GetNode())
 	if err != nil {
+		if pbError := errOfHostKeyMismatched(err); pbError != nil {
+			executor.logger.WithField("error", pbError).Error("connect ssh error")
+			return pbError
+		}
 		pbError := &protos.Error{
 			Reason:     "Connect ssh error",                                                                                                                                                 // 连接SSH失败。
 			Detail:     fmt.Sprintf("SSH connect to %s(%s) failed , error: %v.", executor.action.config.NodeCfg.GetNode().GetName(), executor.action.config.NodeCfg.GetNode().GetIp(), err), // 连接%s(%s)失败，失败原因：%v。
@@ -112,6 +153,9 @@ func (executor *deployWorkerExecutor) connectMasterNode() *protos.Error {
 	executor.masterMachine, err = deployMachine.NewMachine(executor.action.config.MasterNodes[0])
 	if err != nil {
 		logrus.WithFields(logrus.Fields{"error": err}).Error("failed to connect master node")
+		if pbError := errOfHostKeyMismatched(err); pbError != nil {
+			return pbError
+		}
 		return &protos.Error{
 			Reason:     "connecting failed",
 			Detail:     fmt.Sprintf("failed to connect master node, err: %s", err),
@@ -135,28 +179,6 @@ func (executor *deployWorkerExecutor) initLogger() {
 	})
 }
 
-func (executor *deployWorkerExecutor) startKubelet() *protos.Error {
-
-	executor.logger.Debug("Start to install kubelet")
-
-	operation := worker.NewStartKubelet(
-		&worker.StartKubeletConfig{
-			Machine:          executor.machine,
-			Node:             executor.action.config.NodeCfg,
-			Logger:           executor.logger,
-			ExecuteLogWriter: executor.executeLogWriter,
-		},
-	)
-
-	if err := operation.Execute(); err != nil {
-		executor.logger.WithField("error", err).Error("install kubelet error")
-		return err
-	}
-
-	executor.logger.Info("Finish to install kubelet action")
-	return nil
-}
-
 func (executor *deployWorkerExecutor) joinCluster() *protos.Error {
 
 	executor.logger.Debug("Start to join cluster")
@@ -258,37 +280,3 @@ func (executor *deployWorkerExecutor) disconnectSSH() {
 
 	executor.logger.Debug("ssh disconnected")
 }
-
-func (executor *deployWorkerExecutor) initExecuteLogWriter() {
-
-	if executor.action.LogFilePath == "" {
-		return
-	}
-
-	var err error
-	// LogFilePath /app/deploy/logs/unknown/deploy-worker/deploy-worker-{nodeName}.log
-	err = os.MkdirAll(filepath.Dir(executor.action.LogFilePath), os.FileMode(0755))
-	if err != nil {
-		return
-	}
-	executor.executeLogWriter, err = os.OpenFile(executor.action.LogFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(0644))
-	if err != nil {
-		executor.logger.Errorf("init deploy worker execute log writer error, error message: %v", err)
-		executor.executeLogWriter = bytes.NewBuffer([]byte{})
-		return
-	}
-}
-
-func (executor *deployWorkerExecutor) closeExecuteLogWriter() {
-
-	switch writer := executor.executeLogWriter.(type) {
-	case *os.File:
-		err := writer.Close()
-		if err != nil {
-			executor.logger.Errorf("close deploy worker execute log writer error, error message: %v", err)
-		}
-	case *bytes.Buffer:
-		// Open executed log file handle error, so write to logrus(at least we can find the log)
-		logrus.Infof("%s\n", writer.String())
-	}
-}
@@ -15,6 +15,10 @@
 package action
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,7 +56,7 @@ func TestDeployWorker(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, normalAction)
 
-	pbErr := executor.Execute(normalAction)
+	pbErr := executor.Execute(context.Background(), normalAction)
 	assert.Nil(t, pbErr)
 
 	errorAction, err := NewDeployWorkerAction(&DeployWorkerActionConfig{
@@ -66,7 +70,44 @@ func TestDeployWorker(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, errorAction)
 
-	pbErr = executor.Execute(errorAction)
+	pbErr = executor.Execute(context.Background(), errorAction)
 	assert.NoError(t, err)
 	assert.NotNil(t, pbErr)
 }
+
+func TestDeployWorkerExecuteLogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deploy-worker")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	act, err := NewDeployWorkerAction(&DeployWorkerActionConfig{
+		NodeCfg: &pb.NodeDeployConfig{
+			Node: &pb.Node{
+				Name: "normal",
+				Ip:   "10.10.10.10",
+			},
+		},
+		MasterNodes: []*pb.Node{
+			{
+				Name: "normal",
+				Ip:   "10.1.1.1",
+			},
+		},
+		ClusterConfig: &pb.ClusterConfig{
+			KubeAPIServerConnect: &pb.KubeAPIServerConnect{
+				Type: "test",
+			},
+		},
+		LogFileBasePath: dir,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, setup(act))
+
+	// the execute logs are appended after the logs written by the setup
+	assert.Nil(t, new(deployWorkerExecutor).Execute(context.Background(), act))
+	content, err := ioutil.ReadFile(act.GetLogFilePath())
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# action logs "), string(content))
+	assert.Contains(t, string(content), executeLogsHeader)
+	assert.Contains(t, string(content), "init_deploy_kubetool.sh join")
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+const executeLogsHeader = "# action execute logs: \n"
+
+// executeLogFile is the execute log buffer which appends the logs to the action log file as they are
+// written, so that the logs can be tailed while the action is running. Nothing can be read from it
+// since the logs have been written into the file.
+type executeLogFile struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func openExecuteLogFile(path string) (*executeLogFile, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, os.FileMode(0644))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = file.WriteString(executeLogsHeader); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &executeLogFile{file: file}, nil
+}
+
+func (f *executeLogFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.file.Write(p)
+}
+
+func (f *executeLogFile) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (f *executeLogFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.file.Close()
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, the execute logs of an action may be
+// written by the commands running concurrently.
+type syncBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) Read(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Read(p)
+}
@@ -15,7 +15,8 @@ package action
 // limitations under the License.
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -24,25 +25,33 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy"
+	"github.com/kpaas-io/kpaas/pkg/deploy/command"
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
 // Executor represents the interface of an action executor.
-// Concrete executors implements the logic of actions.
+// Concrete executors implements the logic of actions, and should stop as soon as
+// possible once the context is cancelled.
 type Executor interface {
-	Execute(act Action) *pb.Error
+	Execute(ctx context.Context, act Action) *pb.Error
 }
 
-var _executorRegistry map[Type]Executor
+// ExecutorFactory creates an executor. A new executor is created for each action, so that the
+// executor can keep the state of the execution while the actions are executed concurrently.
+type ExecutorFactory func() Executor
 
-// RegisterExecutor is to register an Executor for an action type
-func RegisterExecutor(actionType Type, exec Executor) error {
+var _executorRegistry map[Type]ExecutorFactory
+
+// RegisterExecutor is to register an ExecutorFactory for an action type
+func RegisterExecutor(actionType Type, factory ExecutorFactory) error {
 	if _executorRegistry == nil {
-		_executorRegistry = make(map[Type]Executor)
+		_executorRegistry = make(map[Type]ExecutorFactory)
 	}
-	if exec == nil {
-		err := fmt.Errorf("the Executor to be registered is nil")
+	if factory == nil {
+		err := fmt.Errorf("the ExecutorFactory to be registered is nil")
 		logrus.Error(err)
 		return err
 	}
@@ -51,23 +60,27 @@ func RegisterExecutor(actionType Type, exec Executor) error {
 		logrus.Error(err)
 		return err
 	}
-	_executorRegistry[actionType] = exec
+	_executorRegistry[actionType] = factory
 	return nil
 }
 
-// NewExecutor is a simple factory method to return an action executor based on action type.
+// NewExecutor is a simple factory method to return a new action executor based on action type.
 func NewExecutor(actionType Type) (Executor, error) {
-	exec, ok := _executorRegistry[actionType]
+	factory, ok := _executorRegistry[actionType]
 	if !ok {
 		return nil, fmt.Errorf("%s: %s", consts.MsgActionTypeUnsupported, actionType)
 	}
 
+	exec := factory()
+	if exec == nil {
+		return nil, fmt.Errorf("no Executor created for type %v", actionType)
+	}
 	return exec, nil
 }
 
 // ExecuteAction creates and run the executor for an action,
 // a *sync.WaitGroup should be passed in.
-func ExecuteAction(act Action, wg *sync.WaitGroup) {
+func ExecuteAction(ctx context.Context, act Action, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	if act == nil {
@@ -79,6 +92,12 @@ func ExecuteAction(act Action, wg *sync.WaitGroup) {
 		consts.LogFieldActionType: act.GetType(),
 	})
 
+	if ctx.Err() != nil {
+		AbortAction(act, ctx.Err())
+		logger.Debug("Action is aborted before execution")
+		return
+	}
+
 	logger.Debug("Start to execute action")
 
 	executor, err := NewExecutor(act.GetType())
@@ -104,9 +123,17 @@ func ExecuteAction(act Action, wg *sync.WaitGroup) {
 		return
 	}
 
-	defer writeExecuteLogs(act)
+	exeErr := executeWithRetry(ctx, executor, act, logger)
+	// Write the execute logs before the final status is set, so that the log is
+	// complete once the action is seen as finished.
+	writeExecuteLogs(act)
 
-	if exeErr := executor.Execute(act); exeErr != nil {
+	if exeErr != nil {
+		if ctx.Err() != nil {
+			AbortAction(act, ctx.Err())
+			deploy.PBErrLogger(act.GetErr(), logger).Error()
+			return
+		}
 		act.SetStatus(ActionFailed)
 		act.SetErr(exeErr)
 		deploy.PBErrLogger(act.GetErr(), logger).Error()
@@ -117,6 +144,116 @@ func ExecuteAction(act Action, wg *sync.WaitGroup) {
 	logger.Debug("Finish to execute action")
 }
 
+// executeWithRetry executes the action, and retries it by the retry policy of the action type
+// if it fails. Each attempt is recorded in the execute log.
+func executeWithRetry(ctx context.Context, executor Executor, act Action, logger *logrus.Entry) *pb.Error {
+	policy := GetRetryPolicy(act.GetType())
+	for attempt := 1; ; attempt++ {
+		act.SetAttempts(attempt)
+		writeAttemptLog(act.GetExecuteLogBuffer(), attempt, policy)
+
+		exeErr := executor.Execute(ctx, act)
+		if exeErr == nil || ctx.Err() != nil || !policy.ShouldRetry(attempt, &ExecuteError{Err: exeErr}) {
+			return exeErr
+		}
+
+		deploy.PBErrLogger(exeErr, logger).Warnf("Attempt %d failed, retry after %v", attempt, policy.Backoff(attempt))
+		writeRetryLog(act.GetExecuteLogBuffer(), attempt, policy, exeErr)
+		if err := policy.Wait(ctx, attempt); err != nil {
+			return exeErr
+		}
+	}
+}
+
+// AbortAction marks an action as aborted because of the cancellation.
+func AbortAction(act Action, cause error) {
+	act.SetStatus(ActionAborted)
+	act.SetErr(&pb.Error{
+		Reason:     consts.MsgActionAborted,
+		Detail:     cause.Error(),
+		FixMethods: "execute the action again if needed",
+	})
+}
+
+// closeOnCancel closes the machine once the context is cancelled, so that the in-flight
+// commands on the machine are aborted. The returned function stops watching the context.
+func closeOnCancel(ctx context.Context, m machine.IMachine) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.Close()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+// newMachineContext creates the machine of the node, it returns once the context is cancelled instead of
+// waiting for the connection, which is closed after it's established.
+func newMachineContext(ctx context.Context, node *pb.Node) (machine.IMachine, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		machine machine.IMachine
+		err     error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		m, err := machine.NewMachine(node)
+		resultCh <- result{machine: m, err: err}
+	}()
+
+	select {
+	case r := <-resultCh:
+		return r.machine, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-resultCh; r.err == nil {
+				r.machine.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// cancelableMachines creates the machines which are closed once the context is cancelled, it's used by
+// the executors running items in parallel, each of which creates its own machines.
+type cancelableMachines struct {
+	ctx   context.Context
+	lock  sync.Mutex
+	stops []func()
+}
+
+func newCancelableMachines(ctx context.Context) *cancelableMachines {
+	return &cancelableMachines{ctx: ctx}
+}
+
+// newMachine is a machine.Factory.
+func (c *cancelableMachines) newMachine(node *pb.Node) (machine.IMachine, error) {
+	m, err := newMachineContext(c.ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stops = append(c.stops, closeOnCancel(c.ctx, m))
+	return m, nil
+}
+
+// stop stops watching the context for all the created machines.
+func (c *cancelableMachines) stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, stop := range c.stops {
+		stop()
+	}
+	c.stops = nil
+}
+
 func errOfTypeMismatched(expected, actual interface{}) *pb.Error {
 	return &pb.Error{
 		Reason: consts.MsgActionTypeMismatched,
@@ -124,6 +261,42 @@ func errOfTypeMismatched(expected, actual interface{}) *pb.Error {
 	}
 }
 
+// errOfHostKeyMismatched returns a pb.Error if the err is caused by a mismatched ssh host key, otherwise returns nil.
+func errOfHostKeyMismatched(err error) *pb.Error {
+	var mismatchErr *mssh.HostKeyMismatchError
+	if !errors.As(err, &mismatchErr) {
+		return nil
+	}
+
+	return &pb.Error{
+		Reason:     consts.MsgHostKeyMismatched,
+		Detail:     mismatchErr.Error(),
+		FixMethods: consts.MsgHostKeyMismatchedFixMethods,
+	}
+}
+
+// errOfAborted returns the pb.Error of an action aborted because of the cancellation.
+func errOfAborted(ctx context.Context) *pb.Error {
+	return &pb.Error{
+		Reason: consts.MsgActionAborted,
+		Detail: ctx.Err().Error(),
+	}
+}
+
+// errOfCommandTimeout returns a pb.Error if the err is caused by a command timeout, otherwise returns nil.
+func errOfCommandTimeout(err error) *pb.Error {
+	var timeoutErr *command.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		return nil
+	}
+
+	return &pb.Error{
+		Reason:     consts.MsgCommandTimeout,
+		Detail:     timeoutErr.Error(),
+		FixMethods: consts.MsgCommandTimeoutFixMethods,
+	}
+}
+
 // Do some setup work before execut the action, like check and create log file...
 func setup(act Action) error {
 	if act == nil {
@@ -155,13 +328,29 @@ func setup(act Action) error {
 		}
 	}
 
-	// TODO: setup exeute log buffer with a thread safe ReadWriter.
-	if act.GetExecuteLogBuffer() == nil {
-		act.SetExecuteLogBuffer(&bytes.Buffer{})
+	// the log file of the last execution has been closed
+	if _, ok := act.GetExecuteLogBuffer().(*executeLogFile); ok || act.GetExecuteLogBuffer() == nil {
+		act.SetExecuteLogBuffer(newExecuteLogBuffer(act, logger))
 	}
 	return nil
 }
 
+// newExecuteLogBuffer returns the buffer appending to the log file of the action, so that the execute logs
+// can be tailed while the action is running. An in-memory buffer is returned if the log file can't be used.
+func newExecuteLogBuffer(act Action, logger *logrus.Entry) io.ReadWriter {
+	logFilePath := act.GetLogFilePath()
+	if logFilePath == "" {
+		return &syncBuffer{}
+	}
+
+	logFile, err := openExecuteLogFile(logFilePath)
+	if err != nil {
+		logger.Warnf("Failed to open the log file for execute logs: %s", err)
+		return &syncBuffer{}
+	}
+	return logFile
+}
+
 // Write action information into the log file
 func writeActionLogHeader(file *os.File, act Action) error {
 	if file == nil {
@@ -212,6 +401,15 @@ func writeExecuteLogs(act Action) {
 	logger := logrus.WithFields(logrus.Fields{
 		consts.LogFieldAction: act.GetName(),
 	})
+
+	// the logs have been written into the log file
+	if logFile, ok := act.GetExecuteLogBuffer().(*executeLogFile); ok {
+		if err := logFile.Close(); err != nil {
+			logger.WithField("error", err).Warning("failed to close the log file")
+		}
+		return
+	}
+
 	logFilePath := act.GetLogFilePath()
 	if logFilePath == "" {
 		logger.Warning("action log file not specified")
@@ -227,7 +425,7 @@ func writeExecuteLogs(act Action) {
 	defer file.Close()
 	buf := act.GetExecuteLogBuffer()
 
-	_, err = file.WriteString("# action execute logs: \n")
+	_, err = file.WriteString(executeLogsHeader)
 	if err != nil {
 		logger.WithField("error", err).WithField("file", logFilePath).
 			Warningf("failed to write to log files %s", logFilePath)
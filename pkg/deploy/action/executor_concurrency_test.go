@@ -0,0 +1,336 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation/etcd"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// the number of the actions of each type executed concurrently
+const concurrentActionCount = 8
+
+// slowMachine makes the commands take a while, so that the concurrent actions interleave.
+type slowMachine struct {
+	machine.IMachine
+}
+
+func newSlowPlanMachine(node *pb.Node) (machine.IMachine, error) {
+	m, err := machine.NewPlanMachine(node)
+	if err != nil {
+		return nil, err
+	}
+	return &slowMachine{IMachine: m}, nil
+}
+
+func (m *slowMachine) Run(cmd string) (stdout, stderr []byte, err error) {
+	time.Sleep(10 * time.Millisecond)
+	return m.IMachine.Run(cmd)
+}
+
+func (m *slowMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	time.Sleep(10 * time.Millisecond)
+	return m.IMachine.RunContext(ctx, cmd)
+}
+
+func (m *slowMachine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error) {
+	time.Sleep(10 * time.Millisecond)
+	return m.IMachine.RunStream(ctx, cmd, stdoutWriter, stderrWriter)
+}
+
+// registerExecutors registers the executors of all the action types again, since some tests
+// clean up all the executors registered.
+func registerExecutors() {
+	factories := map[Type]ExecutorFactory{
+		ActionTypeNodeCheck:         func() Executor { return new(nodeCheckExecutor) },
+		ActionTypeConnectivityCheck: func() Executor { return new(connectivityCheckExecutor) },
+		ActionTypeTestConnection:    func() Executor { return new(testConnectionExecutor) },
+		ActionTypeNodeInit:          func() Executor { return new(nodeInitExecutor) },
+		ActionTypeDeployEtcd:        func() Executor { return new(deployEtcdExecutor) },
+		ActionTypeInitMaster:        func() Executor { return new(initMasterExecutor) },
+		ActionTypeJoinMaster:        func() Executor { return new(joinMasterExecutor) },
+		ActionTypePrepareWorker:     func() Executor { return new(prepareWorkerExecutor) },
+		ActionTypeDeployWorker:      func() Executor { return new(deployWorkerExecutor) },
+		ActionTypeFetchKubeConfig:   func() Executor { return new(fetchKubeConfigExecutor) },
+	}
+	for actionType, factory := range factories {
+		if _, ok := _executorRegistry[actionType]; !ok {
+			RegisterExecutor(actionType, factory)
+		}
+	}
+}
+
+// executeConcurrently executes the actions created by newAction concurrently against the mock
+// machines, the action of the node named "error" is expected to fail, the others are expected to
+// be done. It's supposed to be run with the race detector.
+func executeConcurrently(t *testing.T, newAction func(node *pb.Node) (Action, error)) {
+	registerExecutors()
+	defer func(isTesting bool) { machine.IsTesting = isTesting }(machine.IsTesting)
+	machine.IsTesting = true
+
+	var actions []Action
+	for i := 0; i <= concurrentActionCount; i++ {
+		node := &pb.Node{
+			Name: fmt.Sprintf("normal-%d", i),
+			Ip:   fmt.Sprintf("10.10.10.%d", i+1),
+		}
+		if i == concurrentActionCount {
+			node.Name = "error"
+		}
+		act, err := newAction(node)
+		if !assert.NoError(t, err) {
+			return
+		}
+		actions = append(actions, act)
+	}
+
+	var wg sync.WaitGroup
+	for _, act := range actions {
+		wg.Add(1)
+		go ExecuteAction(context.Background(), act, &wg)
+	}
+	wg.Wait()
+
+	for _, act := range actions[:concurrentActionCount] {
+		assert.Equal(t, ActionDone, act.GetStatus(), "action %s: %v", act.GetName(), act.GetErr())
+	}
+	errorAction := actions[concurrentActionCount]
+	assert.Equal(t, ActionFailed, errorAction.GetStatus())
+	assert.NotNil(t, errorAction.GetErr())
+}
+
+func TestExecuteNodeCheckConcurrently(t *testing.T) {
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewNodeCheckAction(&NodeCheckActionConfig{
+			NodeCheckConfig: &pb.NodeCheckConfig{Node: node},
+		})
+	})
+}
+
+func TestExecuteConnectivityCheckConcurrently(t *testing.T) {
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewConnectivityCheckAction(&ConnectivityCheckActionConfig{
+			SourceNode:      node,
+			DestinationNode: &pb.Node{Name: "destination", Ip: "10.10.20.1"},
+		})
+	})
+}
+
+func TestExecuteTestConnectionConcurrently(t *testing.T) {
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewTestConnectionAction(&TestConnectionActionConfig{Node: node})
+	})
+}
+
+func TestExecuteNodeInitConcurrently(t *testing.T) {
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewNodeInitAction(&NodeInitActionConfig{
+			NodeInitConfig: &pb.NodeDeployConfig{Node: node, Roles: []string{"master"}},
+		})
+	})
+}
+
+func TestExecuteDeployEtcdConcurrently(t *testing.T) {
+	caCrt, caKey, err := etcd.CreateAsCA(&certutil.Config{CommonName: "test"})
+	assert.NoError(t, err)
+
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewDeployEtcdAction(&DeployEtcdActionConfig{
+			CaCrt: caCrt,
+			CaKey: caKey,
+			Node:  node,
+		})
+	})
+}
+
+func TestExecuteInitMasterConcurrently(t *testing.T) {
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewInitMasterAction(&InitMasterActionConfig{
+			Node:        node,
+			MasterNodes: []*pb.Node{node},
+			EtcdNodes:   []*pb.Node{node},
+			ClusterConfig: &pb.ClusterConfig{
+				KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "test"},
+			},
+		})
+	})
+}
+
+// kubeConfigMachine serves the kube config of the api server at the url, so that the operations
+// talking to the api server via the master, like checking whether a node has joined, can be tested.
+type kubeConfigMachine struct {
+	machine.IMachine
+	url string
+}
+
+func (m *kubeConfigMachine) FetchFile(dst io.Writer, remotePath string) error {
+	_, err := fmt.Fprintf(dst, `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`, m.url)
+	return err
+}
+
+func TestExecuteJoinMasterConcurrently(t *testing.T) {
+	// the nodes of the even numbers have joined already
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/api/v1/nodes/")
+		w.Header().Set("Content-Type", "application/json")
+		var i int
+		if _, err := fmt.Sscanf(name, "normal-%d", &i); err == nil && i%2 == 0 {
+			fmt.Fprintf(w, `{"kind":"Node","apiVersion":"v1","metadata":{"name":%q}}`, name)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+	}))
+	defer apiServer.Close()
+
+	recorder := machine.NewRecorder(func(node *pb.Node) (machine.IMachine, error) {
+		if node.Name == "error" {
+			return nil, fmt.Errorf("failed to connect to %s", node.Name)
+		}
+		m, err := newSlowPlanMachine(node)
+		if err != nil {
+			return nil, err
+		}
+		return &kubeConfigMachine{IMachine: m, url: apiServer.URL}, nil
+	})
+	machine.SetFactory(recorder.NewMachine)
+	defer machine.SetFactory(nil)
+
+	master := &pb.Node{Name: "master", Ip: "10.10.20.1"}
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewJoinMasterAction(&JoinMasterActionConfig{
+			CertKey:     "cert-key",
+			Node:        node,
+			Roles:       []string{"master"},
+			MasterNodes: []*pb.Node{master},
+			ClusterConfig: &pb.ClusterConfig{
+				KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "firstMasterIP"},
+			},
+		})
+	})
+
+	// only the nodes not joined yet run kubeadm join
+	transcripts := recorder.Transcripts()
+	for i := 0; i < concurrentActionCount; i++ {
+		name := fmt.Sprintf("normal-%d", i)
+		var joined bool
+		if transcript, ok := transcripts[name]; ok {
+			for _, step := range transcript.Steps {
+				joined = joined || strings.HasPrefix(step.Command, "kubeadm join")
+			}
+		}
+		assert.Equal(t, i%2 != 0, joined, name)
+	}
+}
+
+func TestExecutePrepareWorkerConcurrently(t *testing.T) {
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewPrepareWorkerAction(&PrepareWorkerActionConfig{NodeCfg: &pb.NodeDeployConfig{Node: node}})
+	})
+}
+
+func TestExecuteDeployWorkerConcurrently(t *testing.T) {
+	master := &pb.Node{Name: "master", Ip: "10.10.20.1"}
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewDeployWorkerAction(&DeployWorkerActionConfig{
+			NodeCfg:     &pb.NodeDeployConfig{Node: node, Labels: map[string]string{"node": node.Name}},
+			MasterNodes: []*pb.Node{master},
+			ClusterConfig: &pb.ClusterConfig{
+				KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "test"},
+			},
+		})
+	})
+}
+
+func TestExecuteFetchKubeConfigConcurrently(t *testing.T) {
+	executeConcurrently(t, func(node *pb.Node) (Action, error) {
+		return NewFetchKubeConfigAction(&FetchKubeConfigActionConfig{Node: node})
+	})
+}
+
+// The executor keeps the state of the execution, each worker should be labeled by its own action.
+func TestExecuteDeployWorkerConcurrentlyOnOwnNodes(t *testing.T) {
+	registerExecutors()
+	recorder := machine.NewRecorder(newSlowPlanMachine)
+	machine.SetFactory(recorder.NewMachine)
+	defer machine.SetFactory(nil)
+
+	master := &pb.Node{Name: "master", Ip: "10.10.20.1"}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentActionCount; i++ {
+		node := &pb.Node{
+			Name: fmt.Sprintf("worker-%d", i),
+			Ip:   fmt.Sprintf("10.10.10.%d", i+1),
+		}
+		act, err := NewDeployWorkerAction(&DeployWorkerActionConfig{
+			NodeCfg:     &pb.NodeDeployConfig{Node: node, Labels: map[string]string{"node": node.Name}},
+			MasterNodes: []*pb.Node{master},
+			ClusterConfig: &pb.ClusterConfig{
+				KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "test"},
+			},
+		})
+		assert.NoError(t, err)
+		wg.Add(1)
+		go ExecuteAction(context.Background(), act, &wg)
+	}
+	wg.Wait()
+
+	labeled := make(map[string]int)
+	for _, step := range recorder.Transcripts()[master.Name].Steps {
+		if !strings.Contains(step.Command, "label node") {
+			continue
+		}
+		for i := 0; i < concurrentActionCount; i++ {
+			worker := fmt.Sprintf("worker-%d", i)
+			if strings.Contains(step.Command, fmt.Sprintf("label node %s node=%s", worker, worker)) {
+				labeled[worker]++
+			}
+		}
+	}
+	assert.Len(t, labeled, concurrentActionCount)
+	for worker, count := range labeled {
+		assert.Equal(t, 1, count, worker)
+	}
+}
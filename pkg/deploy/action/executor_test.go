@@ -15,11 +15,21 @@
 package action
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kpaas-io/kpaas/pkg/deploy/command"
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
@@ -33,7 +43,7 @@ type actionMockupForExecutorTest struct {
 
 type executorMockupForExecutorTest struct{}
 
-func (e *executorMockupForExecutorTest) Execute(act Action) *pb.Error {
+func (e *executorMockupForExecutorTest) Execute(ctx context.Context, act Action) *pb.Error {
 	mockupAct, ok := act.(*actionMockupForExecutorTest)
 	if !ok {
 		return new(pb.Error)
@@ -45,7 +55,11 @@ func (e *executorMockupForExecutorTest) Execute(act Action) *pb.Error {
 }
 
 func TestRegisterExecutor(t *testing.T) {
-	err := RegisterExecutor(ActionTypeTestExecutorMockup, new(executorMockupForExecutorTest))
+	var created int
+	err := RegisterExecutor(ActionTypeTestExecutorMockup, func() Executor {
+		created++
+		return new(executorMockupForExecutorTest)
+	})
 	assert.NoError(t, err)
 
 	exec, err := NewExecutor(ActionTypeTestExecutorMockup)
@@ -53,7 +67,15 @@ func TestRegisterExecutor(t *testing.T) {
 	assert.NotNil(t, exec)
 	assert.IsType(t, new(executorMockupForExecutorTest), exec)
 
-	err = RegisterExecutor(ActionTypeTestExecutorMockup, new(executorMockupForExecutorTest))
+	// a new executor is created each time
+	_, err = NewExecutor(ActionTypeTestExecutorMockup)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+
+	err = RegisterExecutor(ActionTypeTestExecutorMockup, func() Executor { return new(executorMockupForExecutorTest) })
+	assert.Error(t, err)
+
+	err = RegisterExecutor(ActionTypeTestExecutorMockup+"-nil", nil)
 	assert.Error(t, err)
 
 	// cleanup
@@ -61,7 +83,7 @@ func TestRegisterExecutor(t *testing.T) {
 }
 
 func TestExecuteAction(t *testing.T) {
-	err := RegisterExecutor(ActionTypeTestExecutorMockup, new(executorMockupForExecutorTest))
+	err := RegisterExecutor(ActionTypeTestExecutorMockup, func() Executor { return new(executorMockupForExecutorTest) })
 	assert.NoError(t, err)
 
 	input := []struct {
@@ -96,7 +118,7 @@ func TestExecuteAction(t *testing.T) {
 	for _, tt := range input {
 		var wg sync.WaitGroup
 		wg.Add(1)
-		ExecuteAction(tt.action, &wg)
+		ExecuteAction(context.Background(), tt.action, &wg)
 		wg.Wait()
 
 		assert.Equal(t, tt.wantStatus, tt.action.GetStatus())
@@ -106,3 +128,104 @@ func TestExecuteAction(t *testing.T) {
 	// cleanup
 	_executorRegistry = nil
 }
+
+func TestExecuteActionCancelled(t *testing.T) {
+	err := RegisterExecutor(ActionTypeTestExecutorMockup, func() Executor { return new(executorMockupForExecutorTest) })
+	assert.NoError(t, err)
+
+	act := &actionMockupForExecutorTest{
+		Base: Base{
+			Name:       "action1",
+			ActionType: ActionTypeTestExecutorMockup,
+			Status:     ActionPending,
+		},
+		goodResult: true,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	ExecuteAction(ctx, act, &wg)
+	wg.Wait()
+
+	assert.Equal(t, ActionAborted, act.GetStatus())
+	assert.NotNil(t, act.GetErr())
+
+	// cleanup
+	_executorRegistry = nil
+}
+
+func TestExecuteLogsStreamedIntoLogFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "action-log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	act := &actionMockupForExecutorTest{
+		Base: Base{
+			Name:        "action1",
+			ActionType:  ActionTypeTestExecutorMockup,
+			LogFilePath: filepath.Join(dir, "action1.log"),
+		},
+	}
+	assert.NoError(t, setup(act))
+	assert.IsType(t, new(executeLogFile), act.GetExecuteLogBuffer())
+
+	// the logs can be read from the log file before the action finishes
+	fmt.Fprintf(act.GetExecuteLogBuffer(), "[stdout] pulling images\n")
+	content, err := ioutil.ReadFile(act.GetLogFilePath())
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "# action logs \n"))
+	assert.True(t, strings.HasSuffix(string(content), executeLogsHeader+"[stdout] pulling images\n"))
+
+	writeExecuteLogs(act)
+	after, err := ioutil.ReadFile(act.GetLogFilePath())
+	assert.NoError(t, err)
+	assert.Equal(t, content, after)
+}
+
+func TestExecuteLogsWithoutLogFile(t *testing.T) {
+	act := &actionMockupForExecutorTest{
+		Base: Base{
+			Name:       "action1",
+			ActionType: ActionTypeTestExecutorMockup,
+		},
+	}
+	assert.NoError(t, setup(act))
+	assert.IsType(t, new(syncBuffer), act.GetExecuteLogBuffer())
+
+	fmt.Fprintf(act.GetExecuteLogBuffer(), "[stdout] pulling images\n")
+	content, err := ioutil.ReadAll(act.GetExecuteLogBuffer())
+	assert.NoError(t, err)
+	assert.Equal(t, "[stdout] pulling images\n", string(content))
+}
+
+func TestErrOfHostKeyMismatched(t *testing.T) {
+	assert.Nil(t, errOfHostKeyMismatched(fmt.Errorf("connection refused")))
+
+	err := fmt.Errorf("failed to create execution client, error: %w", &mssh.HostKeyMismatchError{
+		Host:                "192.168.0.1",
+		ExpectedFingerprint: "SHA256:expected",
+		ActualFingerprint:   "SHA256:actual",
+	})
+	pbErr := errOfHostKeyMismatched(err)
+	assert.NotNil(t, pbErr)
+	assert.Equal(t, consts.MsgHostKeyMismatched, pbErr.Reason)
+	assert.Contains(t, pbErr.Detail, "SHA256:actual")
+	assert.Equal(t, consts.MsgHostKeyMismatchedFixMethods, pbErr.FixMethods)
+}
+
+func TestErrOfCommandTimeout(t *testing.T) {
+	assert.Nil(t, errOfCommandTimeout(fmt.Errorf("exit code 1")))
+
+	err := fmt.Errorf("run cmd bash error: %w", &command.TimeoutError{
+		Command: "bash /tmp/init_deploy_kubetool.sh setup kubelet",
+		Timeout: 30 * time.Minute,
+	})
+	pbErr := errOfCommandTimeout(err)
+	assert.NotNil(t, pbErr)
+	assert.Equal(t, consts.MsgCommandTimeout, pbErr.Reason)
+	assert.Contains(t, pbErr.Detail, "30m0s")
+	assert.Equal(t, consts.MsgCommandTimeoutFixMethods, pbErr.FixMethods)
+}
@@ -16,6 +16,7 @@ package action
 
 import (
 	"bytes"
+	"context"
 
 	"github.com/sirupsen/logrus"
 
@@ -26,13 +27,13 @@ import (
 )
 
 func init() {
-	RegisterExecutor(ActionTypeFetchKubeConfig, new(fetchKubeConfigExecutor))
+	RegisterExecutor(ActionTypeFetchKubeConfig, func() Executor { return new(fetchKubeConfigExecutor) })
 }
 
 type fetchKubeConfigExecutor struct {
 }
 
-func (a *fetchKubeConfigExecutor) Execute(act Action) *pb.Error {
+func (a *fetchKubeConfigExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	kubeCfgAction, ok := act.(*FetchKubeConfigAction)
 	if !ok {
 		return errOfTypeMismatched(new(FetchKubeConfigAction), act)
@@ -54,6 +55,12 @@ func (a *fetchKubeConfigExecutor) Execute(act Action) *pb.Error {
 
 	m, err := machine.NewMachine(kubeCfgAction.Node)
 	if err != nil {
+		if pbErr = errOfHostKeyMismatched(err); pbErr != nil {
+			return pbErr
+		}
+		if pbErr = errOfTransient(err); pbErr != nil {
+			return pbErr
+		}
 		pbErr = &pb.Error{
 			Reason: "failed to connect to target node",
 			Detail: err.Error(),
@@ -61,9 +68,13 @@ func (a *fetchKubeConfigExecutor) Execute(act Action) *pb.Error {
 		return pbErr
 	}
 	defer m.Close()
+	defer closeOnCancel(ctx, m)()
 
 	var buf bytes.Buffer
 	if err = m.FetchFile(&buf, consts.KubeConfigPath); err != nil {
+		if pbErr = errOfTransient(err); pbErr != nil {
+			return pbErr
+		}
 		pbErr = &pb.Error{
 			Reason: "failed to fetch kube config",
 			Detail: err.Error(),
@@ -72,7 +83,9 @@ func (a *fetchKubeConfigExecutor) Execute(act Action) *pb.Error {
 	}
 
 	// Update action
-	kubeCfgAction.KubeConfig = buf.Bytes()
+	kubeCfgAction.Update(func() {
+		kubeCfgAction.KubeConfig = buf.Bytes()
+	})
 
 	logger.Debug("Finsih to execute action")
 	return nil
@@ -15,23 +15,26 @@
 package action
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation/master"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
 func init() {
-	RegisterExecutor(ActionTypeInitMaster, new(initMasterExecutor))
+	RegisterExecutor(ActionTypeInitMaster, func() Executor { return new(initMasterExecutor) })
 }
 
 type initMasterExecutor struct {
 }
 
-func (a *initMasterExecutor) Execute(act Action) *pb.Error {
+func (a *initMasterExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	action, ok := act.(*InitMasterAction)
 	if !ok {
 		return errOfTypeMismatched(new(InitMasterAction), act)
@@ -43,23 +46,27 @@ func (a *initMasterExecutor) Execute(act Action) *pb.Error {
 
 	logger.Debug("Start to init first master action")
 
-	var needUntaint bool
-	rolesSet := sets.NewString(action.Roles...)
-	if rolesSet.Has(string(constant.MachineRoleIngress)) || rolesSet.Has(string(constant.MachineRoleWorker)) {
-		needUntaint = true
-	}
-	config := &master.InitMasterOperationConfig{
-		Logger:        logger,
-		CertKey:       action.CertKey,
-		Node:          action.Node,
-		NeedUntaint:   needUntaint,
-		MasterNodes:   action.MasterNodes,
-		EtcdNodes:     action.EtcdNodes,
-		ClusterConfig: action.ClusterConfig,
+	m, err := newMachineContext(ctx, action.Node)
+	if err != nil {
+		if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+			return pbErr
+		}
+		if pbErr := errOfTransient(err); pbErr != nil {
+			return pbErr
+		}
+		return &pb.Error{
+			Reason: "failed to connect to target node",
+			Detail: err.Error(),
+		}
 	}
+	defer closeOnCancel(ctx, m)()
 
+	config := newInitMasterOperationConfig(action, logger)
+	config.Context = ctx
+	config.Machine = m
 	op, err := master.NewInitMasterOperation(config)
 	if err != nil {
+		m.Close()
 		return &pb.Error{
 			Reason: "failed to get init master operation",
 			Detail: err.Error(),
@@ -69,6 +76,9 @@ func (a *initMasterExecutor) Execute(act Action) *pb.Error {
 	logger.Debugf("Start to init master on nodes: %s", action.Node.Name)
 
 	if err := op.Do(); err != nil {
+		if pbErr := errOfCommandTimeout(err); pbErr != nil {
+			return pbErr
+		}
 		return &pb.Error{
 			Reason:     "failed to do init master operation",
 			Detail:     err.Error(),
@@ -79,3 +89,50 @@ func (a *initMasterExecutor) Execute(act Action) *pb.Error {
 	logger.Debug("Finish to execute init master action")
 	return nil
 }
+
+func (a *initMasterExecutor) Plan(act Action, newMachine machine.Factory) *pb.Error {
+	action, ok := act.(*InitMasterAction)
+	if !ok {
+		return errOfTypeMismatched(new(InitMasterAction), act)
+	}
+
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldAction: act.GetName(),
+	})
+
+	config := newInitMasterOperationConfig(action, logger)
+	m, err := newMachine(action.Node)
+	if err != nil {
+		return errOfPlanFailed(err)
+	}
+	config.Machine = m
+
+	op, err := master.NewInitMasterOperation(config)
+	if err != nil {
+		return errOfPlanFailed(err)
+	}
+	if err := op.Plan(); err != nil {
+		return errOfPlanFailed(err)
+	}
+	return nil
+}
+
+func newInitMasterOperationConfig(action *InitMasterAction, logger *logrus.Entry) *master.InitMasterOperationConfig {
+	var needUntaint bool
+	rolesSet := sets.NewString(action.Roles...)
+	if rolesSet.Has(string(constant.MachineRoleIngress)) || rolesSet.Has(string(constant.MachineRoleWorker)) {
+		needUntaint = true
+	}
+
+	return &master.InitMasterOperationConfig{
+		Logger:        logger,
+		CertKey:       action.CertKey,
+		Node:          action.Node,
+		NeedUntaint:   needUntaint,
+		MasterNodes:   action.MasterNodes,
+		EtcdNodes:     action.EtcdNodes,
+		ClusterConfig: action.ClusterConfig,
+
+		ExecuteLogWriter: action.GetExecuteLogBuffer(),
+	}
+}
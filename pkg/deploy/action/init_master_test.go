@@ -15,6 +15,7 @@
 package action
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -56,7 +57,7 @@ func TestInitMaster(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, normalAction)
 
-	pbErr := executor.Execute(normalAction)
+	pbErr := executor.Execute(context.Background(), normalAction)
 	assert.Nil(t, pbErr)
 
 	errorAction, err := NewInitMasterAction(&InitMasterActionConfig{
@@ -69,7 +70,7 @@ func TestInitMaster(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, errorAction)
 
-	pbErr = executor.Execute(errorAction)
+	pbErr = executor.Execute(context.Background(), errorAction)
 	assert.NoError(t, err)
 	assert.NotNil(t, pbErr)
 }
@@ -15,23 +15,26 @@
 package action
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation/master"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
 func init() {
-	RegisterExecutor(ActionTypeJoinMaster, new(joinMasterExecutor))
+	RegisterExecutor(ActionTypeJoinMaster, func() Executor { return new(joinMasterExecutor) })
 }
 
 type joinMasterExecutor struct {
 }
 
-func (a *joinMasterExecutor) Execute(act Action) *pb.Error {
+func (a *joinMasterExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	action, ok := act.(*JoinMasterAction)
 	if !ok {
 		return errOfTypeMismatched(new(JoinMasterAction), act)
@@ -43,23 +46,27 @@ func (a *joinMasterExecutor) Execute(act Action) *pb.Error {
 
 	logger.Debugf("Start to join master:%v action", action.Node.Name)
 
-	var needUntaint bool
-	rolesSet := sets.NewString(action.Roles...)
-	if rolesSet.Has(string(constant.MachineRoleIngress)) || rolesSet.Has(string(constant.MachineRoleWorker)) {
-		needUntaint = true
-	}
-
-	config := &master.JoinMasterOperationConfig{
-		Logger:        logger,
-		CertKey:       action.CertKey,
-		Node:          action.Node,
-		NeedUntaint:   needUntaint,
-		MasterNodes:   action.MasterNodes,
-		ClusterConfig: action.ClusterConfig,
+	m, err := newMachineContext(ctx, action.Node)
+	if err != nil {
+		if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+			return pbErr
+		}
+		if pbErr := errOfTransient(err); pbErr != nil {
+			return pbErr
+		}
+		return &pb.Error{
+			Reason: "failed to connect to target node",
+			Detail: err.Error(),
+		}
 	}
+	defer closeOnCancel(ctx, m)()
 
+	config := newJoinMasterOperationConfig(action, logger)
+	config.Context = ctx
+	config.Machine = m
 	op, err := master.NewJoinMasterOperation(config)
 	if err != nil {
+		m.Close()
 		return &pb.Error{
 			Reason: "failed to get join master operation",
 			Detail: err.Error(),
@@ -67,6 +74,9 @@ func (a *joinMasterExecutor) Execute(act Action) *pb.Error {
 	}
 
 	if err := op.Do(); err != nil {
+		if pbErr := errOfCommandTimeout(err); pbErr != nil {
+			return pbErr
+		}
 		return &pb.Error{
 			Reason:     "failed to do join master operation",
 			Detail:     err.Error(),
@@ -77,3 +87,49 @@ func (a *joinMasterExecutor) Execute(act Action) *pb.Error {
 	logger.Debug("Finish to execute join master action")
 	return nil
 }
+
+func (a *joinMasterExecutor) Plan(act Action, newMachine machine.Factory) *pb.Error {
+	action, ok := act.(*JoinMasterAction)
+	if !ok {
+		return errOfTypeMismatched(new(JoinMasterAction), act)
+	}
+
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldAction: act.GetName(),
+	})
+
+	config := newJoinMasterOperationConfig(action, logger)
+	m, err := newMachine(action.Node)
+	if err != nil {
+		return errOfPlanFailed(err)
+	}
+	config.Machine = m
+
+	op, err := master.NewJoinMasterOperation(config)
+	if err != nil {
+		return errOfPlanFailed(err)
+	}
+	if err := op.Plan(); err != nil {
+		return errOfPlanFailed(err)
+	}
+	return nil
+}
+
+func newJoinMasterOperationConfig(action *JoinMasterAction, logger *logrus.Entry) *master.JoinMasterOperationConfig {
+	var needUntaint bool
+	rolesSet := sets.NewString(action.Roles...)
+	if rolesSet.Has(string(constant.MachineRoleIngress)) || rolesSet.Has(string(constant.MachineRoleWorker)) {
+		needUntaint = true
+	}
+
+	return &master.JoinMasterOperationConfig{
+		Logger:        logger,
+		CertKey:       action.CertKey,
+		Node:          action.Node,
+		NeedUntaint:   needUntaint,
+		MasterNodes:   action.MasterNodes,
+		ClusterConfig: action.ClusterConfig,
+
+		ExecuteLogWriter: action.GetExecuteLogBuffer(),
+	}
+}
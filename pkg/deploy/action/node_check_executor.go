@@ -15,6 +15,7 @@
 package action
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strings"
@@ -70,7 +71,7 @@ const (
 var systemDistributions = [3]string{check.DistributionCentos, check.DistributionUbuntu, check.DistributionRHEL}
 
 func init() {
-	RegisterExecutor(ActionTypeNodeCheck, new(nodeCheckExecutor))
+	RegisterExecutor(ActionTypeNodeCheck, func() Executor { return new(nodeCheckExecutor) })
 }
 
 type nodeCheckExecutor struct {
@@ -100,7 +101,10 @@ func ExecuteCheckScript(item check.ItemEnum, config *pb.NodeCheckConfig, checkIt
 		checkItemReport.Err.Reason = ItemErrOperation
 		checkItemReport.Err.Detail = fmt.Sprintf("stdErr: %v, err: %v", stdErr, err.Error())
 		checkItemReport.Err.FixMethods = ItemHelperOperation
-		return "", checkItemReport, fmt.Errorf("fail to run check %v scripts", item)
+		if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+			checkItemReport.Err = pbErr
+		}
+		return "", checkItemReport, fmt.Errorf("fail to run check %v scripts: %w", item, err)
 	}
 
 	checkItemStdOut := strings.Trim(string(stdOut), "\n")
@@ -132,6 +136,11 @@ func CheckDockerExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckItem) {
 	if err != nil {
 		logger.Errorf("check docker failed, err: %v", err)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	err = check.CheckDockerVersion(comparedDockerVersion, desiredDockerVersion, ">")
@@ -166,6 +175,11 @@ func CheckCPUExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckItem) {
 	if err != nil {
 		logger.Errorf("check cpu failed, err: %v", err)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	var desiredCPUCore float64
@@ -217,6 +231,11 @@ func CheckKernelExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckItem) {
 	if err != nil {
 		logger.Errorf("check kernel failed, err: %v", err)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	err = check.CheckKernelVersion(kernelVersion, desiredKernelVersion, ">")
@@ -251,6 +270,11 @@ func CheckMemoryExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckItem) {
 	if err != nil {
 		logger.Errorf("check memory failed, err: %v", err)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	var desiredMemory float64
@@ -304,6 +328,11 @@ func CheckRootDiskExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckItem)
 	if err != nil {
 		logger.Errorf("check root disk failed, err: %v", err)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	var desiredRootDiskVolume float64
@@ -356,6 +385,11 @@ func CheckDistributionExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckIt
 	if err != nil {
 		logger.Errorf("check distro failed, err: %v", err)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	disName = strings.Trim(disName, "\"")
@@ -388,7 +422,10 @@ func CheckSysPrefExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckItem) {
 	checkItemReport := newNodeCheckItem(check.SystemPreference)
 
 	_, checkItemReport, err := ExecuteCheckScript(check.SystemPreference, ncAction.NodeCheckConfig, checkItemReport)
-	if err != nil {
+	if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+		logger.Errorf("check system preference failed, err: %v", err)
+		checkItemReport.Status = ItemFailed
+	} else if err != nil {
 		logger.Debugf("%v: %v", CheckFailed, err)
 		checkItemReport.Status = ItemFailed
 		checkItemReport.Err = new(pb.Error)
@@ -419,6 +456,11 @@ func CheckSysManagerExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckItem
 	if err != nil {
 		logger.Errorf("check system manager failed, err: %v", err)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	err = check.CheckSystemManager(systemManager, desiredSystemManager)
@@ -456,6 +498,11 @@ func CheckPortOccupiedExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckIt
 	if err != nil {
 		logger.Errorf("check port occupied failed, err: %v, occupied port: %v", err, portOccupied)
 		checkItemReport.Status = ItemFailed
+		// the node isn't trusted if its host key mismatched, there is no result to check
+		if errOfHostKeyMismatched(err) != nil {
+			ch <- checkItemReport
+			return
+		}
 	}
 
 	portResult, err := check.CheckPortOccupied(portOccupied)
@@ -474,7 +521,7 @@ func CheckPortOccupiedExecutor(ncAction *NodeCheckAction, ch chan<- *NodeCheckIt
 	ch <- checkItemReport
 }
 
-func (a *nodeCheckExecutor) Execute(act Action) *pb.Error {
+func (a *nodeCheckExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	nodeCheckAction, ok := act.(*NodeCheckAction)
 	if !ok {
 		return errOfTypeMismatched(new(NodeCheckAction), act)
@@ -501,18 +548,24 @@ func (a *nodeCheckExecutor) Execute(act Action) *pb.Error {
 	go CheckSysManagerExecutor(nodeCheckAction, channel)
 	go CheckPortOccupiedExecutor(nodeCheckAction, channel)
 
-	// update check items
-	for report := range channel {
-		nodeCheckAction.CheckItems = append(nodeCheckAction.CheckItems, report)
-
-		if len(nodeCheckAction.CheckItems) == 9 {
-			break
-		}
+	// update check items, the check items are short and read only, so they are waited to finish even
+	// if the action is cancelled, and none of them is left running.
+	for len(nodeCheckAction.CheckItems) < 9 {
+		report := <-channel
+		nodeCheckAction.Update(func() {
+			nodeCheckAction.CheckItems = append(nodeCheckAction.CheckItems, report)
+		})
+	}
+	if ctx.Err() != nil {
+		return errOfAborted(ctx)
 	}
 
 	// If any of check item was failed, we should return an error
 	failedItems := getFailedCheckItems(nodeCheckAction)
 	if len(failedItems) > 0 {
+		if pbErr := getHostKeyMismatchedCheckItemErr(nodeCheckAction); pbErr != nil {
+			return pbErr
+		}
 		return &pb.Error{
 			Reason: fmt.Sprintf("%d check item(s) failed", len(failedItems)),
 			Detail: fmt.Sprintf("failed check item list: %v", failedItems),
@@ -523,6 +576,17 @@ func (a *nodeCheckExecutor) Execute(act Action) *pb.Error {
 	return nil
 }
 
+// getHostKeyMismatchedCheckItemErr returns the error of the check item failed because of the host key mismatch,
+// the error is reported as the action's error, so that it can be fixed by the fix methods.
+func getHostKeyMismatchedCheckItemErr(checkAction *NodeCheckAction) *pb.Error {
+	for _, item := range checkAction.CheckItems {
+		if item.Status != ItemDone && item.Err.GetReason() == consts.MsgHostKeyMismatched {
+			return item.Err
+		}
+	}
+	return nil
+}
+
 func getFailedCheckItems(checkAction *NodeCheckAction) []string {
 	var failedItemName []string
 	for _, item := range checkAction.CheckItems {
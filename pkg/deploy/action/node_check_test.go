@@ -15,11 +15,15 @@
 package action
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
 	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
@@ -41,7 +45,7 @@ func TestNodeCheck(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, normalAction)
 
-	pbErr := executor.Execute(normalAction)
+	pbErr := executor.Execute(context.Background(), normalAction)
 	assert.Nil(t, pbErr)
 
 	errorAction, err := NewNodeCheckAction(&NodeCheckActionConfig{
@@ -55,7 +59,40 @@ func TestNodeCheck(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, errorAction)
 
-	pbErr = executor.Execute(errorAction)
+	pbErr = executor.Execute(context.Background(), errorAction)
 	assert.NoError(t, err)
 	assert.NotNil(t, pbErr)
 }
+
+// newHostKeyMismatchedMachine fails to create the machine because the host key of the node was changed
+func newHostKeyMismatchedMachine(node *pb.Node) (machine.IMachine, error) {
+	return nil, fmt.Errorf("failed to create execution client, error: %w", &mssh.HostKeyMismatchError{
+		Host:                node.GetIp(),
+		ExpectedFingerprint: "SHA256:expected",
+		ActualFingerprint:   "SHA256:actual",
+	})
+}
+
+func TestNodeCheckHostKeyMismatched(t *testing.T) {
+	machine.SetFactory(newHostKeyMismatchedMachine)
+	defer machine.SetFactory(nil)
+
+	action, err := NewNodeCheckAction(&NodeCheckActionConfig{
+		NodeCheckConfig: &pb.NodeCheckConfig{
+			Node: &pb.Node{
+				Name: "node1",
+				Ip:   "10.10.10.10",
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	pbErr := new(nodeCheckExecutor).Execute(context.Background(), action)
+	assert.NotNil(t, pbErr)
+	assert.Equal(t, consts.MsgHostKeyMismatched, pbErr.Reason)
+	assert.Equal(t, consts.MsgHostKeyMismatchedFixMethods, pbErr.FixMethods)
+	for _, item := range action.(*NodeCheckAction).CheckItems {
+		assert.Equal(t, ItemFailed, item.Status)
+		assert.Equal(t, consts.MsgHostKeyMismatched, item.Err.Reason, item.Name)
+	}
+}
@@ -15,13 +15,16 @@
 package action
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
 	it "github.com/kpaas-io/kpaas/pkg/deploy/operation/init"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
@@ -33,13 +36,14 @@ const (
 )
 
 func init() {
-	RegisterExecutor(ActionTypeNodeInit, new(nodeInitExecutor))
+	RegisterExecutor(ActionTypeNodeInit, func() Executor { return new(nodeInitExecutor) })
 }
 
 type nodeInitExecutor struct{}
 
-// due to items, ItemInitScripts exec remote scripts and return std, report, error
-func ExecuteInitScript(item it.ItemEnum, action *NodeInitAction, initItemReport *NodeInitItem) (string, *NodeInitItem, error) {
+// due to items, ItemInitScripts exec remote scripts and return std, report, error.
+// The machines of the item are created by newMachine, machine.NewMachine is used if it's nil.
+func ExecuteInitScript(item it.ItemEnum, action *NodeInitAction, newMachine machine.Factory, initItemReport *NodeInitItem) (string, *NodeInitItem, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"node":      action.Node.GetName(),
 		"init_item": item,
@@ -47,11 +51,8 @@ func ExecuteInitScript(item it.ItemEnum, action *NodeInitAction, initItemReport
 
 	initItemReport = newNodeInitItem(item)
 
-	initAction := &operation.NodeInitAction{
-		NodeInitConfig: action.NodeInitConfig,
-		NodesConfig:    action.NodesConfig,
-		ClusterConfig:  action.ClusterConfig,
-	}
+	initAction := newNodeInitOperationAction(action)
+	initAction.MachineFactory = newMachine
 
 	initItem := it.NewInitOperations().CreateOperations(item, initAction)
 	if initItem == nil {
@@ -71,6 +72,13 @@ func ExecuteInitScript(item it.ItemEnum, action *NodeInitAction, initItemReport
 		initItemReport.Err.Reason = ItemErrScript
 		initItemReport.Err.Detail = fmt.Sprintf("stdErr: %v, err: %v", stdErr, err.Error())
 		initItemReport.Err.FixMethods = ItemHelperOperation
+		if pbErr := errOfHostKeyMismatched(err); pbErr != nil {
+			initItemReport.Err = pbErr
+		} else if pbErr := errOfCommandTimeout(err); pbErr != nil {
+			initItemReport.Err = pbErr
+		} else if pbErr := errOfTransient(err); pbErr != nil {
+			initItemReport.Err = pbErr
+		}
 		return "", initItemReport, fmt.Errorf("can not execute init %v operation command on node: %v", item, action.Node.Name)
 	}
 
@@ -79,6 +87,16 @@ func ExecuteInitScript(item it.ItemEnum, action *NodeInitAction, initItemReport
 	return initItemStdOut, initItemReport, nil
 }
 
+func newNodeInitOperationAction(action *NodeInitAction) *operation.NodeInitAction {
+	return &operation.NodeInitAction{
+		NodeInitConfig: action.NodeInitConfig,
+		NodesConfig:    action.NodesConfig,
+		ClusterConfig:  action.ClusterConfig,
+
+		ExecuteLogWriter: action.GetExecuteLogBuffer(),
+	}
+}
+
 func newNodeInitItem(item it.ItemEnum) *NodeInitItem {
 
 	return &NodeInitItem{
@@ -89,7 +107,7 @@ func newNodeInitItem(item it.ItemEnum) *NodeInitItem {
 }
 
 // goroutine exec item init event and write to channel
-func InitAsyncExecutor(item it.ItemEnum, ncAction *NodeInitAction, ch chan<- *NodeInitItem) {
+func InitAsyncExecutor(item it.ItemEnum, ncAction *NodeInitAction, newMachine machine.Factory, ch chan<- *NodeInitItem) {
 
 	logger := logrus.WithFields(logrus.Fields{
 		"node":      ncAction.Node.GetName(),
@@ -99,7 +117,7 @@ func InitAsyncExecutor(item it.ItemEnum, ncAction *NodeInitAction, ch chan<- *No
 	logger.Debugf("Start to execute init")
 
 	initItemReport := newNodeInitItem(item)
-	_, initItemReport, err := ExecuteInitScript(item, ncAction, initItemReport)
+	_, initItemReport, err := ExecuteInitScript(item, ncAction, newMachine, initItemReport)
 	if err != nil {
 		logger.Errorf("%v: %v", InitFailed, err)
 		initItemReport.Status = ItemFailed
@@ -112,7 +130,7 @@ func InitAsyncExecutor(item it.ItemEnum, ncAction *NodeInitAction, ch chan<- *No
 	ch <- initItemReport
 }
 
-func (a *nodeInitExecutor) Execute(act Action) *pb.Error {
+func (a *nodeInitExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	nodeInitAction, ok := act.(*NodeInitAction)
 	if !ok {
 		return errOfTypeMismatched(new(NodeInitAction), act)
@@ -123,6 +141,11 @@ func (a *nodeInitExecutor) Execute(act Action) *pb.Error {
 	})
 	logger.Debug("Start to execute node init action")
 
+	// the items of the last attempt are dropped if the action is retried
+	nodeInitAction.Update(func() {
+		nodeInitAction.InitItems = nil
+	})
+
 	initGroup := constructInitGroup(nodeInitAction)
 	if len(initGroup) == 0 {
 		logger.Error("initialization item group is empty")
@@ -131,22 +154,37 @@ func (a *nodeInitExecutor) Execute(act Action) *pb.Error {
 	// make enough length of init items
 	channel := make(chan *NodeInitItem, len(initGroup))
 
+	// the machines of the items are closed once the action is cancelled, so that the running items are aborted
+	machines := newCancelableMachines(ctx)
+	defer machines.stop()
 	for item := range initGroup {
-		go InitAsyncExecutor(item, nodeInitAction, channel)
+		go InitAsyncExecutor(item, nodeInitAction, machines.newMachine, channel)
 	}
 
-	// update init items
-	for report := range channel {
-		nodeInitAction.InitItems = append(nodeInitAction.InitItems, report)
-
-		if len(nodeInitAction.InitItems) == len(initGroup) {
-			break
-		}
+	// update init items, the aborted items are waited as well, so that none of them is left running
+	for len(nodeInitAction.InitItems) < len(initGroup) {
+		report := <-channel
+		nodeInitAction.Update(func() {
+			nodeInitAction.InitItems = append(nodeInitAction.InitItems, report)
+		})
+	}
+	if ctx.Err() != nil {
+		return errOfAborted(ctx)
 	}
 
 	// If any of init item was failed, we should return an error
 	failedItems := getFailedInitItems(nodeInitAction)
 	if len(failedItems) > 0 {
+		if pbErr := getHostKeyMismatchedInitItemErr(nodeInitAction); pbErr != nil {
+			return pbErr
+		}
+		if allInitItemsFailedTransiently(nodeInitAction) {
+			return &pb.Error{
+				Reason:     consts.MsgTransientFailure,
+				Detail:     fmt.Sprintf("%d init item(s) failed: %v", len(failedItems), failedItems),
+				FixMethods: consts.MsgTransientFailureFixMethods,
+			}
+		}
 		return &pb.Error{
 			Reason: fmt.Sprintf("%d init item(s) failed", len(failedItems)),
 			Detail: fmt.Sprintf("failed init item list: %v", failedItems),
@@ -157,6 +195,34 @@ func (a *nodeInitExecutor) Execute(act Action) *pb.Error {
 	return nil
 }
 
+// Plan plans the init items one by one in the order of their names, which are executed in parallel actually.
+func (a *nodeInitExecutor) Plan(act Action, newMachine machine.Factory) *pb.Error {
+	nodeInitAction, ok := act.(*NodeInitAction)
+	if !ok {
+		return errOfTypeMismatched(new(NodeInitAction), act)
+	}
+
+	var items []string
+	for item := range constructInitGroup(nodeInitAction) {
+		items = append(items, string(item))
+	}
+	sort.Strings(items)
+
+	for _, item := range items {
+		initAction := newNodeInitOperationAction(nodeInitAction)
+		initAction.MachineFactory = newMachine
+
+		initItem := it.NewInitOperations().CreateOperations(it.ItemEnum(item), initAction)
+		if initItem == nil {
+			return errOfPlanFailed(fmt.Errorf("fail to construct init %v operation for node %v", item, nodeInitAction.Node.Name))
+		}
+		if _, _, err := initItem.RunCommands(nodeInitAction.Node, initAction); err != nil {
+			return errOfPlanFailed(fmt.Errorf("init %v: %v", item, err))
+		}
+	}
+	return nil
+}
+
 func getFailedInitItems(initAction *NodeInitAction) []string {
 	var failedItemName []string
 	for _, item := range initAction.InitItems {
@@ -167,6 +233,28 @@ func getFailedInitItems(initAction *NodeInitAction) []string {
 	return failedItemName
 }
 
+// getHostKeyMismatchedInitItemErr returns the error of the init item failed because of the host key mismatch,
+// the error is reported as the action's error, so that it can be fixed by the fix methods.
+func getHostKeyMismatchedInitItemErr(initAction *NodeInitAction) *pb.Error {
+	for _, item := range initAction.InitItems {
+		if item.Status != nodeInitItemDone && item.Err.GetReason() == consts.MsgHostKeyMismatched {
+			return item.Err
+		}
+	}
+	return nil
+}
+
+// allInitItemsFailedTransiently returns true if all the failed init items failed because of the transient failures,
+// so that the action is worth retrying.
+func allInitItemsFailedTransiently(initAction *NodeInitAction) bool {
+	for _, item := range initAction.InitItems {
+		if item.Status != nodeInitItemDone && item.Err.GetReason() != consts.MsgTransientFailure {
+			return false
+		}
+	}
+	return true
+}
+
 // check if contains role
 func containsRole(initAction *NodeInitAction, wantRole constant.MachineRole) bool {
 	for _, role := range initAction.NodeInitConfig.Roles {
@@ -15,10 +15,12 @@
 package action
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
 	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
@@ -42,7 +44,7 @@ func TestNodeInitExecute(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, normalAction)
 
-	pbErr := executor.Execute(normalAction)
+	pbErr := executor.Execute(context.Background(), normalAction)
 	assert.Nil(t, pbErr)
 
 	errorAction, err := NewNodeInitAction(&NodeInitActionConfig{
@@ -57,7 +59,31 @@ func TestNodeInitExecute(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, errorAction)
 
-	pbErr = executor.Execute(errorAction)
+	pbErr = executor.Execute(context.Background(), errorAction)
 	assert.NoError(t, err)
 	assert.NotNil(t, pbErr)
 }
+
+func TestNodeInitHostKeyMismatched(t *testing.T) {
+	machine.SetFactory(newHostKeyMismatchedMachine)
+	defer machine.SetFactory(nil)
+
+	action, err := NewNodeInitAction(&NodeInitActionConfig{
+		NodeInitConfig: &pb.NodeDeployConfig{
+			Node: &pb.Node{
+				Name: "node1",
+				Ip:   "10.10.10.10",
+			},
+			Roles: []string{"master"},
+		},
+	})
+	assert.NoError(t, err)
+
+	pbErr := new(nodeInitExecutor).Execute(context.Background(), action)
+	assert.NotNil(t, pbErr)
+	assert.Equal(t, consts.MsgHostKeyMismatched, pbErr.Reason)
+	assert.Equal(t, consts.MsgHostKeyMismatchedFixMethods, pbErr.FixMethods)
+	for _, item := range action.(*NodeInitAction).InitItems {
+		assert.Equal(t, consts.MsgHostKeyMismatched, item.Err.Reason, item.Name)
+	}
+}
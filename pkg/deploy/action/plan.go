@@ -0,0 +1,112 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation/etcd"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// Planner is implemented by the executors which can plan the actions without executing them. The
+// machines must be created by newMachine, so that the commands run and the files put are recorded
+// instead of being done on the nodes.
+type Planner interface {
+	Plan(act Action, newMachine machine.Factory) *pb.Error
+}
+
+// the paths of the files whose content is not shown in the plans: the scripts shipped with the
+// deploy controller, and the certs and keys
+var planHiddenContent = []string{
+	"^" + regexp.QuoteMeta(operation.InitRemoteScriptPath+"/"),
+	"^" + regexp.QuoteMeta(etcd.DefaultPKIDir),
+}
+
+// PlanAction returns the commands and files the action would run and put on the nodes, nothing is
+// done on the nodes. The commands are planned as if all of them succeed with empty outputs.
+func PlanAction(act Action) *pb.ActionPlan {
+	actionPlan := &pb.ActionPlan{
+		Name:     act.GetName(),
+		Type:     string(act.GetType()),
+		NodeName: act.GetNode().GetName(),
+	}
+
+	executor, err := NewExecutor(act.GetType())
+	if err != nil {
+		actionPlan.Err = &pb.Error{
+			Reason: consts.MsgActionExecutorCreationFailed,
+			Detail: err.Error(),
+		}
+		return actionPlan
+	}
+	planner, ok := executor.(Planner)
+	if !ok {
+		actionPlan.Err = &pb.Error{
+			Reason: consts.MsgActionPlanUnsupported,
+			Detail: fmt.Sprintf("no planner for the action type: %s", act.GetType()),
+		}
+		return actionPlan
+	}
+
+	recorder := machine.NewPlanRecorder()
+	recorder.IgnoreContent(planHiddenContent...)
+	actionPlan.Err = planner.Plan(act, recorder.NewMachine)
+	actionPlan.Steps = planSteps(recorder.Transcripts(), actionPlan.NodeName)
+
+	return actionPlan
+}
+
+func errOfPlanFailed(err error) *pb.Error {
+	return &pb.Error{
+		Reason: consts.MsgActionPlanFailed,
+		Detail: err.Error(),
+	}
+}
+
+// planSteps returns the steps recorded, the ones on the node of the action come first.
+func planSteps(transcripts map[string]*machine.Transcript, nodeName string) []*pb.PlanStep {
+	nodes := make([]string, 0, len(transcripts))
+	for node := range transcripts {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if (nodes[i] == nodeName) != (nodes[j] == nodeName) {
+			return nodes[i] == nodeName
+		}
+		return nodes[i] < nodes[j]
+	})
+
+	var steps []*pb.PlanStep
+	for _, node := range nodes {
+		for _, step := range transcripts[node].Steps {
+			steps = append(steps, &pb.PlanStep{
+				NodeName:      node,
+				Action:        step.Action,
+				Command:       step.Command,
+				Path:          step.Path,
+				Mode:          step.Mode,
+				Content:       step.Content,
+				ContentHidden: step.AnyContent,
+			})
+		}
+	}
+	return steps
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation/etcd"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func TestPlanInitMasterAction(t *testing.T) {
+	registerExecutors()
+	act, err := NewInitMasterAction(&InitMasterActionConfig{
+		CertKey:     "certkey",
+		Node:        &pb.Node{Name: "master1", Ip: "10.1.1.1"},
+		MasterNodes: []*pb.Node{{Name: "master1", Ip: "10.1.1.1"}},
+		EtcdNodes:   []*pb.Node{{Name: "etcd1", Ip: "10.1.1.2"}},
+		ClusterConfig: &pb.ClusterConfig{
+			KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "firstMasterIP"},
+			ServiceSubnet:        "10.96.0.0/12",
+			PodSubnet:            "172.16.0.0/16",
+		},
+	})
+	assert.NoError(t, err)
+
+	plan := PlanAction(act)
+	assert.Nil(t, plan.Err)
+	assert.Equal(t, string(ActionTypeInitMaster), plan.Type)
+	assert.Equal(t, "master1", plan.NodeName)
+	if !assert.Len(t, plan.Steps, 6) {
+		return
+	}
+
+	// the certs are fetched and generated on execution
+	for _, step := range plan.Steps[:3] {
+		assert.Equal(t, machine.StepPutFile, step.Action)
+		assert.True(t, step.ContentHidden)
+		assert.Empty(t, step.Content)
+	}
+	assert.Equal(t, "/etc/kubernetes/pki/apiserver-etcd-client.key", plan.Steps[2].Path)
+	assert.Equal(t, "0600", plan.Steps[2].Mode)
+
+	// the kubeadm config is generated from the cluster config
+	kubeadmConfig := plan.Steps[3]
+	assert.Equal(t, "/etc/kubernetes/kubeadm_config.yaml", kubeadmConfig.Path)
+	assert.False(t, kubeadmConfig.ContentHidden)
+	assert.Contains(t, kubeadmConfig.Content, "certificateKey: certkey")
+	assert.Contains(t, kubeadmConfig.Content, "controlPlaneEndpoint: 10.1.1.1:6443")
+	assert.Contains(t, kubeadmConfig.Content, "https://10.1.1.2:2379")
+	assert.Contains(t, kubeadmConfig.Content, "podSubnet: 172.16.0.0/16")
+
+	assert.Equal(t, &pb.PlanStep{NodeName: "master1", Action: machine.StepRun, Command: "systemctl start kubelet"}, plan.Steps[4])
+	assert.Equal(t, "kubeadm init --config /etc/kubernetes/kubeadm_config.yaml --upload-certs", plan.Steps[5].Command)
+}
+
+func TestPlanDeployEtcdAction(t *testing.T) {
+	registerExecutors()
+	nodes := []*pb.Node{{Name: "etcd1", Ip: "10.1.1.1"}, {Name: "etcd2", Ip: "10.1.1.2"}}
+	caCrt, caKey, err := etcd.CreateAsCA(&certutil.Config{CommonName: "etcd-ca"})
+	assert.NoError(t, err)
+	act, err := NewDeployEtcdAction(&DeployEtcdActionConfig{
+		CaCrt:        caCrt,
+		CaKey:        caKey,
+		Node:         nodes[0],
+		ClusterNodes: nodes,
+	})
+	assert.NoError(t, err)
+
+	plan := PlanAction(act)
+	assert.Nil(t, plan.Err)
+
+	var commands []string
+	for _, step := range plan.Steps {
+		if step.Action == machine.StepRun {
+			commands = append(commands, step.Command)
+		} else {
+			// the certs and keys are not shown
+			assert.True(t, step.ContentHidden)
+		}
+	}
+	if assert.Len(t, commands, 2) {
+		assert.Equal(t, "docker ps -q --filter name=etcd-kpaas-etcd1", commands[0])
+		assert.Contains(t, commands[1], "docker run -d --restart=always --net=host")
+		assert.Contains(t, commands[1], "--initial-cluster=etcd2=https://10.1.1.2:2380,etcd1=https://10.1.1.1:2380")
+	}
+}
+
+func TestPlanUnsupportedAction(t *testing.T) {
+	registerExecutors()
+	act, err := NewTestConnectionAction(&TestConnectionActionConfig{Node: &pb.Node{Name: "node1"}})
+	assert.NoError(t, err)
+
+	plan := PlanAction(act)
+	assert.Equal(t, consts.MsgActionPlanUnsupported, plan.Err.Reason)
+	assert.Empty(t, plan.Steps)
+}
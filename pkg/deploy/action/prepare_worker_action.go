@@ -0,0 +1,62 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const ActionTypePrepareWorker Type = "PrepareWorker"
+
+// PrepareWorkerActionConfig represents the config for a prepare worker action, which prepares the
+// worker node to join the cluster before the masters are deployed.
+type PrepareWorkerActionConfig struct {
+	NodeCfg         *pb.NodeDeployConfig
+	LogFileBasePath string
+}
+
+type PrepareWorkerAction struct {
+	Base
+	config *PrepareWorkerActionConfig
+}
+
+func NewPrepareWorkerAction(config *PrepareWorkerActionConfig) (Action, error) {
+
+	if config == nil {
+		return nil, fmt.Errorf("action config is nil")
+	}
+	if config.NodeCfg == nil {
+		return nil, fmt.Errorf("invalid action config: NodeCfg is nil")
+	}
+	if config.NodeCfg.Node == nil {
+		return nil, fmt.Errorf("invalid action config: NodeCfg.Node is nil")
+	}
+
+	actionName := GenActionName(ActionTypePrepareWorker)
+	return &PrepareWorkerAction{
+		Base: Base{
+			Name:              actionName,
+			ActionType:        ActionTypePrepareWorker,
+			Status:            ActionPending,
+			LogFilePath:       GenActionLogFilePath(config.LogFileBasePath, actionName, config.NodeCfg.Node.Name),
+			CreationTimestamp: time.Now(),
+			Node:              config.NodeCfg.Node,
+		},
+		config: config,
+	}, nil
+}
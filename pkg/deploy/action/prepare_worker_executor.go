@@ -0,0 +1,156 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	deployMachine "github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation/worker"
+	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func init() {
+	RegisterExecutor(ActionTypePrepareWorker, func() Executor { return new(prepareWorkerExecutor) })
+}
+
+// prepareWorkerExecutor prepares the worker node, it doesn't need the masters,
+// so that it can run while the etcd and the masters are being deployed.
+type prepareWorkerExecutor struct {
+	logger           *logrus.Entry
+	machine          deployMachine.IMachine
+	action           *PrepareWorkerAction
+	executeLogWriter io.Writer
+}
+
+func (executor *prepareWorkerExecutor) Execute(ctx context.Context, act Action) *protos.Error {
+
+	action, ok := act.(*PrepareWorkerAction)
+	if !ok {
+		return errOfTypeMismatched(new(PrepareWorkerAction), act)
+	}
+
+	executor.action = action
+
+	executor.initLogger()
+	// the execute logs are appended to the log file of the action which the execution setup opened
+	executor.executeLogWriter = action.GetExecuteLogBuffer()
+
+	executor.logger.Info("start to execute prepare worker executor")
+
+	if err := executor.connectSSH(); err != nil {
+		return err
+	}
+	defer executor.disconnectSSH()
+	defer closeOnCancel(ctx, executor.machine)()
+
+	if err := executor.startKubelet(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return errOfAborted(ctx)
+	}
+
+	executor.logger.Info("prepare worker finished")
+
+	return nil
+}
+
+// Plan plans the operations with the machine created by newMachine instead of connecting the node.
+func (executor *prepareWorkerExecutor) Plan(act Action, newMachine deployMachine.Factory) *protos.Error {
+
+	action, ok := act.(*PrepareWorkerAction)
+	if !ok {
+		return errOfTypeMismatched(new(PrepareWorkerAction), act)
+	}
+
+	executor.action = action
+	executor.initLogger()
+
+	var err error
+	if executor.machine, err = newMachine(action.config.NodeCfg.GetNode()); err != nil {
+		return errOfPlanFailed(err)
+	}
+
+	return executor.startKubelet()
+}
+
+func (executor *prepareWorkerExecutor) connectSSH() *protos.Error {
+
+	executor.logger.Debug("Start to connect ssh")
+
+	var err error
+	executor.machine, err = deployMachine.NewMachine(executor.action.config.NodeCfg.GetNode())
+	if err != nil {
+		if pbError := errOfHostKeyMismatched(err); pbError != nil {
+			executor.logger.WithField("error", pbError).Error("connect ssh error")
+			return pbError
+		}
+		pbError := &protos.Error{
+			Reason:     "Connect ssh error",                                                                                                                                                 // 连接SSH失败。
+			Detail:     fmt.Sprintf("SSH connect to %s(%s) failed , error: %v.", executor.action.config.NodeCfg.GetNode().GetName(), executor.action.config.NodeCfg.GetNode().GetIp(), err), // 连接%s(%s)失败，失败原因：%v。
+			FixMethods: "Please check node reliability, make SSH service is available.",                                                                                                     // 请检查节点的可用性，确保SSH服务可用。
+		}
+		executor.logger.WithField("error", pbError).Error("connect ssh error")
+		return pbError
+	}
+
+	executor.logger.Debug("ssh connected")
+	return nil
+}
+
+func (executor *prepareWorkerExecutor) initLogger() {
+	executor.logger = logrus.WithFields(logrus.Fields{
+		consts.LogFieldAction: executor.action.GetName(),
+		"nodeName":            executor.action.config.NodeCfg.GetNode().GetName(),
+		"nodeIP":              executor.action.config.NodeCfg.GetNode().GetIp(),
+	})
+}
+
+func (executor *prepareWorkerExecutor) startKubelet() *protos.Error {
+
+	executor.logger.Debug("Start to install kubelet")
+
+	operation := worker.NewStartKubelet(
+		&worker.StartKubeletConfig{
+			Machine:          executor.machine,
+			Node:             executor.action.config.NodeCfg,
+			Logger:           executor.logger,
+			ExecuteLogWriter: executor.executeLogWriter,
+		},
+	)
+
+	if err := operation.Execute(); err != nil {
+		executor.logger.WithField("error", err).Error("install kubelet error")
+		return err
+	}
+
+	executor.logger.Info("Finish to install kubelet action")
+	return nil
+}
+
+func (executor *prepareWorkerExecutor) disconnectSSH() {
+
+	executor.logger.Debug("Start to disconnect ssh")
+
+	executor.machine.Close()
+
+	executor.logger.Debug("ssh disconnected")
+}
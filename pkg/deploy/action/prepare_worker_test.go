@@ -0,0 +1,62 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func TestPrepareWorker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prepare-worker")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	normalAction, err := NewPrepareWorkerAction(&PrepareWorkerActionConfig{
+		NodeCfg: &pb.NodeDeployConfig{
+			Node: &pb.Node{
+				Name: "normal",
+				Ip:   "10.10.10.10",
+			},
+		},
+		LogFileBasePath: dir,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, setup(normalAction))
+
+	// the kubelet is started without any master
+	assert.Nil(t, new(prepareWorkerExecutor).Execute(context.Background(), normalAction))
+	content, err := ioutil.ReadFile(normalAction.GetLogFilePath())
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(content), "systemctl restart kubelet"), string(content))
+
+	errorAction, err := NewPrepareWorkerAction(&PrepareWorkerActionConfig{
+		NodeCfg: &pb.NodeDeployConfig{
+			Node: &pb.Node{
+				Name: "error",
+				Ip:   "10.10.10.10",
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, new(prepareWorkerExecutor).Execute(context.Background(), errorAction))
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/retry"
+)
+
+// connectionRetryPolicy retries the actions which are safe to be executed again, if they failed
+// because of the transient failures of the connections to the nodes.
+var connectionRetryPolicy = &retry.Policy{
+	MaxAttempts:    3,
+	InitialBackoff: 5 * time.Second,
+	MaxBackoff:     30 * time.Second,
+	Retryable:      isTransientExecuteError,
+}
+
+var (
+	retryPoliciesLock sync.RWMutex
+	// the actions are executed only once if there is no retry policy for the type
+	retryPolicies = map[Type]*retry.Policy{
+		ActionTypeTestConnection:  connectionRetryPolicy,
+		ActionTypeFetchKubeConfig: connectionRetryPolicy,
+		ActionTypeNodeInit:        connectionRetryPolicy,
+	}
+)
+
+// SetRetryPolicy sets the retry policy of the action type, the actions of the type are not retried
+// if the policy is nil. The errors passed to the Retryable of the policy are *ExecuteError.
+func SetRetryPolicy(actionType Type, policy *retry.Policy) {
+	retryPoliciesLock.Lock()
+	defer retryPoliciesLock.Unlock()
+
+	if policy == nil {
+		delete(retryPolicies, actionType)
+		return
+	}
+	retryPolicies[actionType] = policy
+}
+
+// GetRetryPolicy returns the retry policy of the action type, nil if the actions are not retried.
+func GetRetryPolicy(actionType Type) *retry.Policy {
+	retryPoliciesLock.RLock()
+	defer retryPoliciesLock.RUnlock()
+
+	return retryPolicies[actionType]
+}
+
+// ExecuteError is the error returned by an executor, which is classified by the retry policy.
+type ExecuteError struct {
+	Err *pb.Error
+}
+
+func (e *ExecuteError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Err.GetReason(), e.Err.GetDetail())
+}
+
+// isTransientExecuteError returns true if the executor failed because of a transient failure.
+func isTransientExecuteError(err error) bool {
+	var executeErr *ExecuteError
+	return errors.As(err, &executeErr) && executeErr.Err.GetReason() == consts.MsgTransientFailure
+}
+
+// errOfTransient returns a pb.Error if the err is a transient failure, otherwise returns nil.
+func errOfTransient(err error) *pb.Error {
+	if !retry.IsTransient(err) {
+		return nil
+	}
+
+	return &pb.Error{
+		Reason:     consts.MsgTransientFailure,
+		Detail:     err.Error(),
+		FixMethods: consts.MsgTransientFailureFixMethods,
+	}
+}
+
+// writeAttemptLog writes the beginning of an attempt into the execute log, if the action can be retried.
+func writeAttemptLog(w io.Writer, attempt int, policy *retry.Policy) {
+	if w == nil || policy.Attempts() <= 1 {
+		return
+	}
+	fmt.Fprintf(w, "# action attempt: %d/%d\n", attempt, policy.Attempts())
+}
+
+// writeRetryLog writes the failure of an attempt into the execute log before it's retried.
+func writeRetryLog(w io.Writer, attempt int, policy *retry.Policy, pbErr *pb.Error) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "# action attempt %d failed: %v: %v, retry after %v\n",
+		attempt, pbErr.GetReason(), pbErr.GetDetail(), policy.Backoff(attempt))
+}
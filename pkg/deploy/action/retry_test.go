@@ -0,0 +1,150 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/retry"
+)
+
+const ActionTypeTestRetryMockup Type = "ActionTypeMockupForRetryTest"
+
+// flakyExecutor fails the executions with the errors in turn, and succeeds after the errors run out.
+type flakyExecutor struct {
+	errs       []*pb.Error
+	executions int
+}
+
+func (e *flakyExecutor) Execute(ctx context.Context, act Action) *pb.Error {
+	defer func() { e.executions++ }()
+	if e.executions < len(e.errs) {
+		return e.errs[e.executions]
+	}
+	return nil
+}
+
+func executeFlakyAction(t *testing.T, ctx context.Context, executor *flakyExecutor, logFilePath string) Action {
+	_executorRegistry = nil
+	assert.NoError(t, RegisterExecutor(ActionTypeTestRetryMockup, func() Executor { return executor }))
+
+	act := &actionMockupForExecutorTest{
+		Base: Base{
+			Name:        "action1",
+			ActionType:  ActionTypeTestRetryMockup,
+			LogFilePath: logFilePath,
+		},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	ExecuteAction(ctx, act, &wg)
+	wg.Wait()
+
+	// cleanup
+	_executorRegistry = nil
+	return act
+}
+
+func TestExecuteActionWithRetry(t *testing.T) {
+	SetRetryPolicy(ActionTypeTestRetryMockup, &retry.Policy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Retryable:      isTransientExecuteError,
+	})
+	defer SetRetryPolicy(ActionTypeTestRetryMockup, nil)
+
+	dir, err := ioutil.TempDir("", "action-retry")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	transientErr := errOfTransient(syscall.ECONNRESET)
+	assert.NotNil(t, transientErr)
+	logFilePath := filepath.Join(dir, "action1.log")
+
+	executor := &flakyExecutor{errs: []*pb.Error{transientErr, transientErr}}
+	act := executeFlakyAction(t, context.Background(), executor, logFilePath)
+	assert.Equal(t, ActionDone, act.GetStatus())
+	assert.Nil(t, act.GetErr())
+	assert.Equal(t, 3, act.GetAttempts())
+
+	content, err := ioutil.ReadFile(logFilePath)
+	assert.NoError(t, err)
+	log := string(content)
+	assert.Contains(t, log, "# action attempt: 1/3\n")
+	assert.Contains(t, log, "# action attempt 1 failed: "+consts.MsgTransientFailure+": connection reset by peer, retry after 1ms\n")
+	assert.Contains(t, log, "# action attempt 2 failed: ")
+	assert.Contains(t, log, "# action attempt: 3/3\n")
+
+	// the attempts run out
+	executor = &flakyExecutor{errs: []*pb.Error{transientErr, transientErr, transientErr, transientErr}}
+	act = executeFlakyAction(t, context.Background(), executor, "")
+	assert.Equal(t, ActionFailed, act.GetStatus())
+	assert.Equal(t, transientErr, act.GetErr())
+	assert.Equal(t, 3, executor.executions)
+	assert.Equal(t, 3, act.GetAttempts())
+
+	// the error is not transient
+	scriptErr := &pb.Error{Reason: "1 init item(s) failed"}
+	executor = &flakyExecutor{errs: []*pb.Error{scriptErr}}
+	act = executeFlakyAction(t, context.Background(), executor, "")
+	assert.Equal(t, ActionFailed, act.GetStatus())
+	assert.Equal(t, scriptErr, act.GetErr())
+	assert.Equal(t, 1, act.GetAttempts())
+}
+
+func TestExecuteActionCancelledDuringBackoff(t *testing.T) {
+	SetRetryPolicy(ActionTypeTestRetryMockup, &retry.Policy{MaxAttempts: 3, InitialBackoff: time.Hour})
+	defer SetRetryPolicy(ActionTypeTestRetryMockup, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	executor := &flakyExecutor{errs: []*pb.Error{{Reason: "failed"}}}
+	act := executeFlakyAction(t, ctx, executor, "")
+	assert.Equal(t, ActionAborted, act.GetStatus())
+	assert.Equal(t, 1, executor.executions)
+}
+
+func TestActionWithoutRetryPolicy(t *testing.T) {
+	assert.Nil(t, GetRetryPolicy(ActionTypeTestRetryMockup))
+	assert.NotNil(t, GetRetryPolicy(ActionTypeNodeInit))
+	assert.Nil(t, GetRetryPolicy(ActionTypeInitMaster))
+
+	executor := &flakyExecutor{errs: []*pb.Error{errOfTransient(syscall.ECONNRESET)}}
+	act := executeFlakyAction(t, context.Background(), executor, "")
+	assert.Equal(t, ActionFailed, act.GetStatus())
+	assert.Equal(t, 1, act.GetAttempts())
+	assert.False(t, strings.Contains(act.GetErr().GetDetail(), "attempt"))
+}
+
+func TestIsTransientExecuteError(t *testing.T) {
+	assert.True(t, isTransientExecuteError(&ExecuteError{Err: errOfTransient(syscall.ECONNRESET)}))
+	assert.False(t, isTransientExecuteError(&ExecuteError{Err: &pb.Error{Reason: consts.MsgCommandTimeout}}))
+	assert.False(t, isTransientExecuteError(syscall.ECONNRESET))
+	assert.Nil(t, errOfTransient(os.ErrPermission))
+}
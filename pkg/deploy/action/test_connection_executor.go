@@ -15,22 +15,23 @@
 package action
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy"
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
-	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
 func init() {
-	RegisterExecutor(ActionTypeTestConnection, new(testConnectionExecutor))
+	RegisterExecutor(ActionTypeTestConnection, func() Executor { return new(testConnectionExecutor) })
 }
 
 type testConnectionExecutor struct {
 }
 
-func (a *testConnectionExecutor) Execute(act Action) *pb.Error {
+func (a *testConnectionExecutor) Execute(ctx context.Context, act Action) *pb.Error {
 	testConnTask, ok := act.(*TestConnectionAction)
 	if !ok {
 		return errOfTypeMismatched(new(TestConnectionAction), act)
@@ -46,12 +47,21 @@ func (a *testConnectionExecutor) Execute(act Action) *pb.Error {
 
 	logger.Debug("Start to execute action")
 
-	// machine.NewMachine() will test if the machine can be connected via ssh.
-	m, err := machine.NewMachine(testConnTask.Node)
+	// creating the machine will test if the machine can be connected via ssh.
+	m, err := newMachineContext(ctx, testConnTask.Node)
 	if err != nil {
-		pbErr := &pb.Error{
-			Reason: "failed to test connection",
-			Detail: err.Error(),
+		if ctx.Err() != nil {
+			return errOfAborted(ctx)
+		}
+		pbErr := errOfHostKeyMismatched(err)
+		if pbErr == nil {
+			pbErr = errOfTransient(err)
+		}
+		if pbErr == nil {
+			pbErr = &pb.Error{
+				Reason: "failed to test connection",
+				Detail: err.Error(),
+			}
 		}
 		deploy.PBErrLogger(pbErr, logger).Debug()
 		return pbErr
@@ -15,21 +15,40 @@
 package command
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"time"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	"github.com/kpaas-io/kpaas/pkg/deploy/retry"
 	"github.com/kpaas-io/kpaas/pkg/deploy/utils"
 )
 
 // ShellCommand is a command execute by shell
 type ShellCommand struct {
+	ctx              context.Context
 	machine          machine.IMachine
 	cmd              string
 	args             []string
 	executeLogWriter io.Writer
 	description      string
+	timeout          time.Duration
+	streamingLog     bool
+	retryPolicy      *retry.Policy
+	attempts         int
+}
+
+// TimeoutError is returned if the command doesn't finish in time, the command has been killed on the machine.
+type TimeoutError struct {
+	Command string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("command(%v) timed out after %v and is killed", e.Command, e.Timeout)
 }
 
 func NewShellCommand(machine machine.IMachine, cmd string, args ...string) *ShellCommand {
@@ -50,25 +69,115 @@ func (c *ShellCommand) WithExecuteLogWriter(w io.Writer) *ShellCommand {
 	return c
 }
 
+// WithContext sets the context of the command, the command is killed once the context is cancelled.
+func (c *ShellCommand) WithContext(ctx context.Context) *ShellCommand {
+	c.ctx = ctx
+	return c
+}
+
+// WithTimeout sets the timeout of the command, the command is killed if it doesn't finish in time.
+// There is no timeout if it's not set.
+func (c *ShellCommand) WithTimeout(timeout time.Duration) *ShellCommand {
+	c.timeout = timeout
+	return c
+}
+
+// WithStreamingLog makes the output written into the execute log line by line as it arrives,
+// instead of after the command finishes, so that the progress of a long running command can be seen.
+func (c *ShellCommand) WithStreamingLog() *ShellCommand {
+	c.streamingLog = true
+	return c
+}
+
+// WithRetry makes the command retried by the policy if it fails, each attempt is written into the execute log.
+// The command is retried only if it's safe to be run again.
+func (c *ShellCommand) WithRetry(policy *retry.Policy) *ShellCommand {
+	c.retryPolicy = policy
+	return c
+}
+
+// GetAttempts returns the number of attempts made by the last execution.
+func (c *ShellCommand) GetAttempts() int {
+	return c.attempts
+}
+
+// Execute runs the command, and retries it by the retry policy if it fails. The output of a failed
+// attempt is considered as well, but the command which exited successfully is never run again.
+// The retries stop once the context of the command is done.
 func (c *ShellCommand) Execute() (stdout, stderr []byte, err error) {
-	startTime := time.Now()
-	stdout, stderr, err = c.machine.Run(c.GetCommand())
-	endTime := time.Now()
+	for c.attempts = 1; ; c.attempts++ {
+		stdout, stderr, err = c.execute(c.attempts)
+		if err == nil {
+			return
+		}
+		if !c.retryPolicy.ShouldRetry(c.attempts, err) && !c.retryPolicy.ShouldRetryOutput(c.attempts, stderr) {
+			return
+		}
+		if waitErr := c.retryPolicy.Wait(c.context(), c.attempts); waitErr != nil {
+			err = waitErr
+			return
+		}
+	}
+}
+
+func (c *ShellCommand) execute(attempt int) (stdout, stderr []byte, err error) {
+	ctx := c.context()
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	executeLogItem := &utils.ExecuteLogItem{
+		StartTime:   time.Now(),
+		Command:     c.cmd + " " + strings.Join(c.args, " "),
+		Description: c.description,
+		Timeout:     c.timeout,
+		Attempt:     attempt,
+		MaxAttempts: c.retryPolicy.Attempts(),
+	}
+
+	streaming := c.streamingLog && c.executeLogWriter != nil
+	if streaming {
+		utils.WriteExecuteLogHeader(c.executeLogWriter, executeLogItem)
+		stdoutWriter, stderrWriter := utils.NewStreamLogWriters(c.executeLogWriter)
+		stdout, stderr, err = c.machine.RunStream(ctx, c.GetCommand(), stdoutWriter, stderrWriter)
+		stdoutWriter.Flush()
+		stderrWriter.Flush()
+	} else {
+		stdout, stderr, err = c.machine.RunContext(ctx, c.GetCommand())
+	}
+
+	timedOut := errors.Is(err, context.DeadlineExceeded) && c.context().Err() == nil
+	if timedOut {
+		err = &TimeoutError{Command: c.GetCommand(), Timeout: c.timeout}
+	}
+
 	if c.executeLogWriter != nil {
-		executeLogItem := &utils.ExecuteLogItem{
-			StartTime:   startTime,
-			EndTime:     endTime,
-			Command:     c.cmd + " " + strings.Join(c.args, " "),
-			Stdout:      stdout,
-			Stderr:      stderr,
-			Err:         err,
-			Description: c.description,
+		executeLogItem.EndTime = time.Now()
+		executeLogItem.Stdout = stdout
+		executeLogItem.Stderr = stderr
+		executeLogItem.Err = err
+		executeLogItem.TimedOut = timedOut
+		if streaming {
+			utils.WriteExecuteLogFooter(c.executeLogWriter, executeLogItem)
+		} else {
+			utils.WriteExecuteLog(c.executeLogWriter, executeLogItem)
 		}
-		utils.WriteExecuteLog(c.executeLogWriter, executeLogItem)
 	}
 	return
 }
 
+func (c *ShellCommand) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 func (c *ShellCommand) GetCommand() string {
 
 	cmds := make([]string, 0, len(c.args)+1)
@@ -0,0 +1,197 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	"github.com/kpaas-io/kpaas/pkg/deploy/retry"
+)
+
+// hangingMachine runs commands which never finish until the context is done.
+type hangingMachine struct {
+	machine.IMachine
+}
+
+func (m *hangingMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	<-ctx.Done()
+	return []byte("downloading"), nil, fmt.Errorf("cmd(%v) is killed, error: %w", cmd, ctx.Err())
+}
+
+func TestShellCommandWithTimeout(t *testing.T) {
+	logBuffer := &bytes.Buffer{}
+	cmd := NewShellCommand(&hangingMachine{}, "yum", "install", "-y", "kubelet").
+		WithTimeout(10 * time.Millisecond).
+		WithExecuteLogWriter(logBuffer)
+
+	stdout, _, err := cmd.Execute()
+	assert.Equal(t, "downloading", string(stdout))
+
+	var timeoutErr *TimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, "yum install -y kubelet", timeoutErr.Command)
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	assert.Contains(t, logBuffer.String(), "[timeout] killed after 10ms")
+}
+
+// streamingMachine writes the output into the stream writers before the command finishes.
+type streamingMachine struct {
+	machine.IMachine
+}
+
+func (m *streamingMachine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (
+	stdout, stderr []byte, err error) {
+
+	io.WriteString(stdoutWriter, "[init] Using Kubernetes version\n[preflight] Running")
+	io.WriteString(stderrWriter, "W1219 warning\n")
+	io.WriteString(stdoutWriter, " pre-flight checks")
+	return []byte("[init] Using Kubernetes version\n[preflight] Running pre-flight checks"), []byte("W1219 warning\n"), nil
+}
+
+func TestShellCommandWithStreamingLog(t *testing.T) {
+	logBuffer := &bytes.Buffer{}
+	cmd := NewShellCommand(&streamingMachine{}, "kubeadm", "init").
+		WithDescription("init master").
+		WithExecuteLogWriter(logBuffer).
+		WithStreamingLog()
+
+	stdout, stderr, err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "[init] Using Kubernetes version\n[preflight] Running pre-flight checks", string(stdout))
+	assert.Equal(t, "W1219 warning\n", string(stderr))
+
+	log := logBuffer.String()
+	assert.Contains(t, log, "[description] init master")
+	assert.Contains(t, log, "[command] kubeadm init")
+	assert.Regexp(t, `\[output\]\n.* \[stdout\] \[init\] Using Kubernetes version\n`+
+		`.* \[stderr\] W1219 warning\n`+
+		`.* \[stdout\] \[preflight\] Running pre-flight checks\n`, log)
+	assert.Contains(t, log, "[end time]")
+}
+
+// flakyMachine fails the commands with the results in turn, and succeeds after the results run out.
+type flakyMachine struct {
+	machine.IMachine
+	stderrs []string
+	errs    []error
+	runs    int
+}
+
+func (m *flakyMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	defer func() { m.runs++ }()
+	if m.runs < len(m.errs) {
+		return nil, []byte(m.stderrs[m.runs]), m.errs[m.runs]
+	}
+	return []byte("installed"), nil, nil
+}
+
+func TestShellCommandWithRetry(t *testing.T) {
+	policy := &retry.Policy{
+		MaxAttempts:      3,
+		InitialBackoff:   time.Millisecond,
+		Retryable:        retry.IsTransient,
+		RetryableOutputs: []string{`Failed to fetch`},
+	}
+
+	logBuffer := &bytes.Buffer{}
+	m := &flakyMachine{
+		stderrs: []string{"", "E: Failed to fetch http://mirror/kubelet.deb"},
+		errs:    []error{fmt.Errorf("read: %w", syscall.ECONNRESET), errors.New("exit status 100")},
+	}
+	cmd := NewShellCommand(m, "apt-get", "install", "-y", "kubelet").
+		WithExecuteLogWriter(logBuffer).
+		WithRetry(policy)
+
+	stdout, _, err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "installed", string(stdout))
+	assert.Equal(t, 3, m.runs)
+	assert.Equal(t, 3, cmd.GetAttempts())
+
+	// each attempt is written into the execute log
+	log := logBuffer.String()
+	assert.Equal(t, 3, strings.Count(log, "[command] apt-get install -y kubelet"))
+	assert.Contains(t, log, "[attempt] 1/3\n")
+	assert.Contains(t, log, "[attempt] 2/3\n")
+	assert.Contains(t, log, "[attempt] 3/3\n")
+	assert.Contains(t, log, "connection reset by peer")
+
+	// the error is not retryable
+	m = &flakyMachine{stderrs: []string{""}, errs: []error{errors.New("permission denied")}}
+	cmd = NewShellCommand(m, "apt-get", "install", "-y", "kubelet").WithRetry(policy)
+	_, _, err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Equal(t, 1, cmd.GetAttempts())
+
+	// the attempts run out
+	m = &flakyMachine{stderrs: []string{"", "", "", ""}, errs: []error{io.EOF, io.EOF, io.EOF, io.EOF}}
+	cmd = NewShellCommand(m, "apt-get", "install", "-y", "kubelet").WithRetry(policy)
+	_, _, err = cmd.Execute()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 3, m.runs)
+
+	// the command exited successfully is not run again even if the output matches
+	m = &flakyMachine{stderrs: []string{"W: Failed to fetch http://mirror/kubelet.deb"}, errs: []error{nil}}
+	cmd = NewShellCommand(m, "apt-get", "install", "-y", "kubelet").WithRetry(policy)
+	_, _, err = cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, m.runs)
+	assert.Equal(t, 1, cmd.GetAttempts())
+}
+
+func TestShellCommandWithRetryCancelled(t *testing.T) {
+	policy := &retry.Policy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+		Retryable:      retry.IsTransient,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	m := &flakyMachine{stderrs: []string{"", ""}, errs: []error{io.EOF, io.EOF}}
+	cmd := NewShellCommand(m, "apt-get", "install", "-y", "kubelet").
+		WithContext(ctx).
+		WithRetry(policy)
+
+	// the backoff is interrupted once the context is cancelled
+	_, _, err := cmd.Execute()
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 1, m.runs)
+	assert.Equal(t, 1, cmd.GetAttempts())
+}
+
+func TestShellCommandWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	cmd := NewShellCommand(&hangingMachine{}, "yum", "install", "-y", "kubelet").
+		WithContext(ctx)
+
+	_, _, err := cmd.Execute()
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	var timeoutErr *TimeoutError
+	assert.False(t, errors.As(err, &timeoutErr))
+}
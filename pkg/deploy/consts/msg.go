@@ -26,6 +26,10 @@ const (
 	MsgEmptyTask                   string = "empty task"
 	MsgTaskProcessorCreationFailed string = "failed to create task processor"
 	MsgTaskGenSummaryFailed        string = "failed to generate task summary"
+	MsgTaskCancelled               string = "the task was cancelled"
+	MsgTaskNotRunning              string = "the task is not running"
+	MsgTaskAlreadyRunning          string = "the task is already running"
+	MsgTaskPlanFailed              string = "failed to plan the task"
 
 	// Action related messages
 	MsgActionTypeUnsupported         string = "unsupported action type"
@@ -36,4 +40,23 @@ const (
 	MsgActionInvalidConfig           string = "the action config is invalid"
 	MsgActionInvalidConfigNodeNotSet string = "the action's target node is not set"
 	MsgEmptyAction                   string = "empty action"
+	MsgActionAborted                 string = "the action was aborted"
+	MsgActionPlanUnsupported         string = "the action can't be planned"
+	MsgActionPlanFailed              string = "failed to plan the action"
+
+	// SSH related messages
+	MsgHostKeyMismatched           string = "the ssh host key of the node mismatched"
+	MsgHostKeyMismatchedFixMethods string = "the node may be reinstalled or the connection may be intercepted, " +
+		"verify the host key of the node, then forget the pinned host key with the API " +
+		"DELETE /api/v1/ssh/knownhosts/{ip}?port={port} and test the connection again"
+
+	// Command related messages
+	MsgCommandTimeout           string = "the command timed out and was killed"
+	MsgCommandTimeoutFixMethods string = "the command may hang on downloading packages or images, " +
+		"check the network and the mirrors of the node, read the execute log for the output before it was killed, then try again"
+
+	// Retry related messages
+	MsgTransientFailure           string = "the action failed because of a transient failure"
+	MsgTransientFailureFixMethods string = "the connection to the node or the mirrors may be unstable, " +
+		"the action has been retried, check the network of the node, read the execute log for the attempts, then try again"
 )
@@ -37,7 +37,7 @@ func NewExecClient(node *pb.Node) (*ExecClient, error) {
 	// use IP as host to create ssh client
 	sshClient, err := mssh.NewClient(node.Ssh.Auth.Username, node.Ip, node.Ssh)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new ssh client to machine: %v(%v), error: %v", node.Name, node.Ip, err)
+		return nil, fmt.Errorf("failed to create new ssh client to machine: %v(%v), error: %w", node.Name, node.Ip, err)
 	}
 
 	sftpClient, err := sftp.NewClient(sshClient)
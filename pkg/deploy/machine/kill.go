@@ -0,0 +1,92 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+// the directory on the remote machine to keep the pid files of the running commands
+const pidFileDir = "/tmp"
+
+// ErrMachineClosed is returned when the command is run on or killed by a closed machine.
+var ErrMachineClosed = errors.New("machine is closed")
+
+// beginRun returns false if the machine is closed, otherwise the command is counted as
+// in-flight until inflight.Done is called.
+func (m *Machine) beginRun() bool {
+	m.closeLock.RLock()
+	defer m.closeLock.RUnlock()
+
+	select {
+	case <-m.closeCh:
+		return false
+	default:
+	}
+
+	m.inflight.Add(1)
+	return true
+}
+
+func newPidFile() string {
+	return fmt.Sprintf("%v/kpaas-cmd-%d-%d.pid", pidFileDir, time.Now().UnixNano(), rand.Int63())
+}
+
+// trackPid wraps the command to write the pid of the shell into the pid file, which is
+// removed after the command finishes. The shell started by sshd is a process group leader,
+// so that the command and all its children can be killed by the process group.
+func trackPid(cmd, pidFile string) string {
+	// the command is run in a subshell so that the pid file is removed even if it exits,
+	// and is put in separated lines so that a trailing comment in it takes no effect
+	return fmt.Sprintf("echo $$ > %v\n(\n%v\n)\nrc=$?\nrm -f %v\nexit $rc", pidFile, cmd, pidFile)
+}
+
+// killCommand returns the command to kill the process group recorded in the pid file.
+func killCommand(pidFile string) string {
+	// "kill -9 -pgid" is understood by the kill of all the shells, while "--" and "-s" are not
+	return fmt.Sprintf("[ -f %[1]v ] && kill -9 -$(cat %[1]v); rm -f %[1]v", pidFile)
+}
+
+// kill kills the command running in the session on the remote machine. Closing the session
+// doesn't stop the remote process since no tty is allocated, so the process group is killed
+// by another session, via sudo if it's enabled as the process may be owned by root.
+func (m *Machine) kill(session *ssh.Session, pidFile string) {
+	defer session.Close()
+
+	// the session limit is bypassed, otherwise the sessions waiting to be killed may
+	// use up the limit
+	client, err := m.conn.getClient()
+	if err == nil {
+		var killSession *ssh.Session
+		if killSession, err = client.SSHClient.NewSession(); err == nil {
+			escalated, stdin := m.escalate(killCommand(pidFile))
+			killSession.Stdin = stdin
+			err = killSession.Run(escalated)
+			killSession.Close()
+		}
+	}
+
+	if err != nil {
+		logrus.Warnf("failed to kill the process group in %v on machine(%v), error: %v", pidFile, m.Name, err)
+		// the signal is only supported by the recent versions of sshd
+		session.Signal(ssh.SIGKILL)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackPid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kill-test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	pidFile := filepath.Join(dir, "cmd.pid")
+
+	// the exit code of the command is kept, and the pid file is removed
+	cmd := exec.Command("sh", "-c", trackPid("echo hello # comment\nexit 3", pidFile))
+	output, err := cmd.Output()
+	assert.Equal(t, "hello\n", string(output))
+	exitErr, ok := err.(*exec.ExitError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 3, exitErr.ExitCode())
+	}
+	_, err = os.Stat(pidFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestKillCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kill-test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	pidFile := filepath.Join(dir, "cmd.pid")
+
+	// sshd starts the command in a new session, the same is done here by Setsid
+	cmd := exec.Command("sh", "-c", trackPid("sleep 30 | cat", pidFile))
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	assert.Nil(t, cmd.Start())
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	assert.Eventually(t, func() bool {
+		pid, err := ioutil.ReadFile(pidFile)
+		return err == nil && strings.TrimSpace(string(pid)) != ""
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Nil(t, exec.Command("sh", "-c", killCommand(pidFile)).Run())
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("the command is not killed")
+	}
+	_, err = os.Stat(pidFile)
+	assert.True(t, os.IsNotExist(err))
+
+	// it's fine to kill the finished command
+	assert.Nil(t, exec.Command("sh", "-c", killCommand(pidFile)).Run())
+}
@@ -15,69 +15,128 @@
 package machine
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy"
-	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 )
 
 // Run will run command on remote machine
 func (m *Machine) Run(cmd string) (stdout, stderr []byte, err error) {
-	session, err := mssh.NewSession(m.SSHClient)
-	if err != nil {
-		return nil, nil, fmt.Errorf("unable to get session of machine(%v), error: %v", m.Name, err)
-	}
+	return m.RunContext(context.Background(), cmd)
+}
 
-	defer session.Close()
+// RunContext runs the command on remote machine, the command is killed once the context is done
+// or the machine is closed. The exit code of the command is not regarded as an error.
+func (m *Machine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(ctx, cmd, nil, nil)
+}
 
-	errReader, err := session.StderrPipe()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to pipe stderr for cmd(%v) on machine(%v), error: %v", cmd, m.Name, err)
+// RunStream runs the command like RunContext, and the output is also written into the writers as it arrives.
+func (m *Machine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error) {
+	if !m.beginRun() {
+		return nil, nil, fmt.Errorf("unable to run cmd(%v) on machine(%v), error: %w", cmd, m.Name, ErrMachineClosed)
 	}
+	defer m.inflight.Done()
 
-	outReader, err := session.StdoutPipe()
+	session, closeSession, err := m.conn.newSession(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to pipe stdout for cmd(%v) on machine(%v), error: %v", cmd, m.Name, err)
+		return nil, nil, fmt.Errorf("unable to get session of machine(%v), error: %w", m.Name, err)
 	}
 
-	if err = session.Start(cmd); err != nil {
+	defer closeSession()
+
+	var outBuffer, errBuffer bytes.Buffer
+	session.Stdout = teeWriter(&outBuffer, stdoutWriter)
+	session.Stderr = teeWriter(&errBuffer, stderrWriter)
+
+	escalated, stdin := m.escalate(cmd)
+	session.Stdin = stdin
+
+	pidFile := newPidFile()
+	if err = session.Start(trackPid(escalated, pidFile)); err != nil {
 		return nil, nil, fmt.Errorf("unable to  run cmd(%v) on machine(%v), error: %v", cmd, m.Name, err)
 	}
 
-	stderr, err = ioutil.ReadAll(errReader)
-	if err != nil {
-		return nil, nil, fmt.Errorf("unable to  read stderr message for cmd(%v) returned from machine(%v), error: %v", cmd, m.Name, err)
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	var cause error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		cause = ctx.Err()
+	case <-m.closeCh:
+		cause = ErrMachineClosed
 	}
 
-	stdout, err = ioutil.ReadAll(outReader)
-	if err != nil {
-		return nil, nil, fmt.Errorf("unable to  read stderr message for cmd(%v) returned from machine(%v), error: %v", cmd, m.Name, err)
+	if cause != nil {
+		logrus.Warnf("kill cmd(%v) on machine(%v), cause: %v", cmd, m.Name, cause)
+		m.kill(session, pidFile)
+		<-done
+		return outBuffer.Bytes(), errBuffer.Bytes(), fmt.Errorf("cmd(%v) on machine(%v) is killed, error: %w", cmd, m.Name, cause)
+	}
+
+	switch err.(type) {
+	case nil, *ssh.ExitError, *ssh.ExitMissingError:
+	default:
+		return nil, nil, fmt.Errorf("unable to  read output for cmd(%v) returned from machine(%v), error: %v", cmd, m.Name, err)
 	}
 
-	return
+	return outBuffer.Bytes(), errBuffer.Bytes(), nil
+}
+
+func teeWriter(buffer *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buffer
+	}
+	return io.MultiWriter(buffer, w)
 }
 
 func (m *Machine) PutFile(content io.Reader, remotePath string) error {
+	return m.putFile(content, remotePath, defaultFileMode)
+}
+
+// putFile puts the content to the remote path, the mode is set before the content is written,
+// so that the content of a private file is never readable by the others.
+func (m *Machine) putFile(content io.Reader, remotePath string, mode os.FileMode) error {
+	// the user may not have the permission to write the remote path
+	if m.sudoEnabled() {
+		return m.putFileWithSudo(content, remotePath, mode)
+	}
+
+	sftpClient, err := m.conn.sftpClient()
+	if err != nil {
+		return fmt.Errorf("unable to get sftp client of machine(%v), error: %v", m.Name, err)
+	}
+
 	// create parent dir if not exists
 	remoteDir := path.Dir(remotePath)
-	if err := m.SFTPClient.MkdirAll(remoteDir); err != nil {
+	if err := sftpClient.MkdirAll(remoteDir); err != nil {
 		return fmt.Errorf("mkdirall %v failed, error: %v", remoteDir, err)
 	}
 
-	remoteFile, err := m.SFTPClient.Create(remotePath)
+	remoteFile, err := sftpClient.Create(remotePath)
 	if err != nil {
 		return fmt.Errorf("create file %v failed: %v", remotePath, err)
 	}
 	defer remoteFile.Close()
 
+	if err = remoteFile.Chmod(mode); err != nil {
+		return fmt.Errorf("chmod file %v failed: %v", remotePath, err)
+	}
+
 	if _, err = io.Copy(remoteFile, content); err != nil {
 		return fmt.Errorf("copy content to remote file %v failed: %v", remotePath, err)
 	}
@@ -108,7 +167,16 @@ func (m *Machine) FetchFile(dst io.Writer, remotePath string) error {
 	if dst == nil {
 		return fmt.Errorf("the destination is nil")
 	}
-	remoteFile, err := m.SFTPClient.Open(remotePath)
+	if m.sudoEnabled() {
+		return m.fetchFileWithSudo(dst, remotePath)
+	}
+
+	sftpClient, err := m.conn.sftpClient()
+	if err != nil {
+		return fmt.Errorf("unable to get sftp client of machine(%v), error: %v", m.Name, err)
+	}
+
+	remoteFile, err := sftpClient.Open(remotePath)
 	if err != nil {
 		return fmt.Errorf("open remote file %v failed, error: %v", remotePath, err)
 	}
@@ -129,26 +197,19 @@ func (m *Machine) FetchDir(localDir, remoteDir string, fileNeeded func(path stri
 	remoteDir = strings.TrimSuffix(remoteDir, "/")
 	localDir = strings.TrimSuffix(localDir, "/") + "/" + filepath.Base(remoteDir)
 
-	if _, err := m.SFTPClient.Stat(remoteDir); os.IsNotExist(err) {
+	entries, err := m.listDir(remoteDir)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
 		return fmt.Errorf("%v:%v does not exist", m.Name, remoteDir)
 	}
 
-	walker := m.SFTPClient.Walk(remoteDir)
-
-	for walker.Step() {
-		if err := walker.Err(); err != nil {
-			return err
-		}
-
-		remotePath := walker.Path()
-		info, err := m.SFTPClient.Stat(remotePath)
-		if err != nil {
-			return fmt.Errorf("stat %v:%v failed, error: %v", m.Name, remotePath, err)
-		}
-
+	for _, entry := range entries {
+		remotePath := entry.path
 		localPath := localDir + strings.TrimPrefix(remotePath, remoteDir)
 
-		if info.IsDir() {
+		if entry.isDir {
 			if !deploy.FileExist(localPath) {
 				logrus.Debugf("make dir: %v", localPath)
 				if err := os.MkdirAll(localPath, 0755); err != nil {
@@ -173,6 +234,46 @@ func (m *Machine) FetchDir(localDir, remoteDir string, fileNeeded func(path stri
 	return nil
 }
 
+// remoteEntry is a file or directory in a remote directory.
+type remoteEntry struct {
+	path  string
+	isDir bool
+}
+
+// listDir lists the remote directory recursively, the directory itself is listed first. Nothing is listed
+// if the directory doesn't exist.
+func (m *Machine) listDir(remoteDir string) ([]remoteEntry, error) {
+	// the user may not have the permission to read the remote directory
+	if m.sudoEnabled() {
+		return m.listDirWithSudo(remoteDir)
+	}
+
+	sftpClient, err := m.conn.sftpClient()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get sftp client of machine(%v), error: %v", m.Name, err)
+	}
+
+	if _, err := sftpClient.Stat(remoteDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var entries []remoteEntry
+	walker := sftpClient.Walk(remoteDir)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return nil, err
+		}
+
+		remotePath := walker.Path()
+		info, err := sftpClient.Stat(remotePath)
+		if err != nil {
+			return nil, fmt.Errorf("stat %v:%v failed, error: %v", m.Name, remotePath, err)
+		}
+		entries = append(entries, remoteEntry{path: remotePath, isDir: info.IsDir()})
+	}
+	return entries, nil
+}
+
 func (m *Machine) PutDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
 	logrus.Debugf("copy %v to %v:%v", localDir, m.Name, remoteDir)
 
@@ -191,12 +292,10 @@ func (m *Machine) PutDir(localDir, remoteDir string, fileNeeded func(path string
 		remotePath := remoteDir + strings.TrimPrefix(localPath, localDir)
 		logrus.Debugf("copy %v to %v:%v", localPath, m.Name, remotePath)
 
-		// create directory
+		// create directory, it's done via sudo if it's enabled and nothing is done if it exists
 		if info.IsDir() {
-			if _, err := m.SFTPClient.Stat(remotePath); os.IsNotExist(err) {
-				if err := m.SFTPClient.MkdirAll(remotePath); err != nil {
-					return fmt.Errorf("creating %v:%v failed. error: %v", m.Name, remotePath, err)
-				}
+			if err := m.mkdirAll(remotePath); err != nil {
+				return fmt.Errorf("creating %v:%v failed. error: %v", m.Name, remotePath, err)
 			}
 		} else {
 			if fileNeeded(localPath) {
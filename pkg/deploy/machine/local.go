@@ -0,0 +1,379 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// LocalMachine is the machine of the node marked as localhost, which is the host running the deploy
+// controller. The commands are run via os/exec and the files are copied on the local filesystem,
+// so that a single-node cluster can be deployed without ssh to the host itself.
+type LocalMachine struct {
+	*pb.Node
+
+	closeOnce sync.Once
+	// closed on Close to abort the running commands
+	closeCh   chan struct{}
+	closeLock sync.RWMutex
+	inflight  sync.WaitGroup
+}
+
+func newLocalMachine(node *pb.Node) (IMachine, error) {
+	return &LocalMachine{
+		Node:    node,
+		closeCh: make(chan struct{}),
+	}, nil
+}
+
+// Close kills the running commands.
+func (m *LocalMachine) Close() {
+
+	m.closeOnce.Do(func() {
+		m.closeLock.Lock()
+		close(m.closeCh)
+		m.closeLock.Unlock()
+
+		m.inflight.Wait()
+	})
+
+}
+
+func (m *LocalMachine) GetName() string {
+	return m.Name
+}
+
+func (m *LocalMachine) GetIp() string {
+	return m.Ip
+}
+
+func (m *LocalMachine) GetNode() *pb.Node {
+	return m.Node
+}
+
+func (m *LocalMachine) beginRun() bool {
+	m.closeLock.RLock()
+	defer m.closeLock.RUnlock()
+
+	select {
+	case <-m.closeCh:
+		return false
+	default:
+	}
+
+	m.inflight.Add(1)
+	return true
+}
+
+func (m *LocalMachine) sudoEnabled() bool {
+	return m.Node.GetSsh().GetSudo().GetEnabled()
+}
+
+func (m *LocalMachine) Run(cmd string) (stdout, stderr []byte, err error) {
+	return m.RunContext(context.Background(), cmd)
+}
+
+// RunContext runs the command by sh, the command is killed once the context is done
+// or the machine is closed. The exit code of the command is not regarded as an error.
+func (m *LocalMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(ctx, cmd, nil, nil)
+}
+
+// RunStream runs the command like RunContext, and the output is also written into the writers as it arrives.
+func (m *LocalMachine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error) {
+	if !m.beginRun() {
+		return nil, nil, fmt.Errorf("unable to run cmd(%v) on machine(%v), error: %w", cmd, m.Name, ErrMachineClosed)
+	}
+	defer m.inflight.Done()
+
+	var outBuffer, errBuffer bytes.Buffer
+	escalated, stdin := m.escalate(cmd)
+	process := exec.Command("sh", "-c", escalated)
+	process.Stdin = stdin
+	process.Stdout = teeWriter(&outBuffer, stdoutWriter)
+	process.Stderr = teeWriter(&errBuffer, stderrWriter)
+	// the command and all its children are killed by the process group
+	process.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err = process.Start(); err != nil {
+		return nil, nil, fmt.Errorf("unable to  run cmd(%v) on machine(%v), error: %v", cmd, m.Name, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- process.Wait()
+	}()
+
+	var cause error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		cause = ctx.Err()
+	case <-m.closeCh:
+		cause = ErrMachineClosed
+	}
+
+	if cause != nil {
+		logrus.Warnf("kill cmd(%v) on machine(%v), cause: %v", cmd, m.Name, cause)
+		m.kill(process.Process.Pid)
+		<-done
+		return outBuffer.Bytes(), errBuffer.Bytes(), fmt.Errorf("cmd(%v) on machine(%v) is killed, error: %w", cmd, m.Name, cause)
+	}
+
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		return nil, nil, fmt.Errorf("unable to  read output for cmd(%v) on machine(%v), error: %v", cmd, m.Name, err)
+	}
+
+	return outBuffer.Bytes(), errBuffer.Bytes(), nil
+}
+
+func (m *LocalMachine) escalate(cmd string) (string, io.Reader) {
+	return escalateCommand(m.Node, cmd)
+}
+
+// kill kills the process group of the command, via sudo if it's enabled as the process may be owned by root.
+func (m *LocalMachine) kill(pgid int) {
+	var err error
+	if m.sudoEnabled() {
+		_, err = m.sudoRun(fmt.Sprintf("kill -9 -%d", pgid))
+	} else {
+		err = syscall.Kill(-pgid, syscall.SIGKILL)
+	}
+
+	if err != nil {
+		logrus.Warnf("failed to kill the process group %v on machine(%v), error: %v", pgid, m.Name, err)
+	}
+}
+
+// sudoRun runs the command via sudo if it's enabled, and returns an error if the command exits with non-zero code.
+func (m *LocalMachine) sudoRun(cmd string) ([]byte, error) {
+	escalated, stdin := m.escalate(cmd)
+
+	var stdout, stderr bytes.Buffer
+	process := exec.Command("sh", "-c", escalated)
+	process.Stdin = stdin
+	process.Stdout = &stdout
+	process.Stderr = &stderr
+	if err := process.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run cmd(%v) on machine(%v), error: %v, stderr: %s", cmd, m.Name, err, stderr.Bytes())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func (m *LocalMachine) PutFile(content io.Reader, remotePath string) error {
+	return m.putFile(content, remotePath, defaultFileMode)
+}
+
+func (m *LocalMachine) putFile(content io.Reader, remotePath string, mode os.FileMode) error {
+	// the controller may not have the permission to write the path
+	if m.sudoEnabled() {
+		return m.putFileWithSudo(content, remotePath, mode)
+	}
+
+	// create parent dir if not exists
+	remoteDir := filepath.Dir(remotePath)
+	if err := os.MkdirAll(remoteDir, 0755); err != nil {
+		return fmt.Errorf("mkdirall %v failed, error: %v", remoteDir, err)
+	}
+
+	remoteFile, err := os.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("create file %v failed: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	// the mode of an existing file is not changed by OpenFile
+	if err = remoteFile.Chmod(mode); err != nil {
+		return fmt.Errorf("chmod file %v failed: %v", remotePath, err)
+	}
+
+	if _, err = io.Copy(remoteFile, content); err != nil {
+		return fmt.Errorf("copy content to file %v failed: %v", remotePath, err)
+	}
+
+	logrus.Debugf("put file to: %v", remotePath)
+
+	return nil
+}
+
+// putFileWithSudo writes the content into a temporary file, then installs it to the path via sudo.
+func (m *LocalMachine) putFileWithSudo(content io.Reader, remotePath string, mode os.FileMode) error {
+	stagingFile, err := ioutil.TempFile("", "kpaas-staging-")
+	if err != nil {
+		return fmt.Errorf("create staging file failed: %v", err)
+	}
+	defer os.Remove(stagingFile.Name())
+
+	_, err = io.Copy(stagingFile, content)
+	stagingFile.Close()
+	if err != nil {
+		return fmt.Errorf("copy content to staging file %v failed: %v", stagingFile.Name(), err)
+	}
+
+	// -D creates the parent directories of the path
+	if _, err = m.sudoRun(fmt.Sprintf("install -D -m %04o %v %v", mode.Perm(), shellQuote(stagingFile.Name()), shellQuote(remotePath))); err != nil {
+		return fmt.Errorf("install file %v failed: %v", remotePath, err)
+	}
+
+	logrus.Debugf("put file to: %v via sudo", remotePath)
+
+	return nil
+}
+
+func (m *LocalMachine) FetchFileToLocalPath(localPath, remotePath string) error {
+	logrus.Debugf("Begin to fetch file from %s on %s to %s", remotePath, m.Name, localPath)
+
+	// create parent dir if not exists
+	localDir := filepath.Dir(localPath)
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return fmt.Errorf("mkdirall %v failed, error: %v", localDir, err)
+	}
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("create local file %v failed, error: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	return m.FetchFile(localFile, remotePath)
+}
+
+func (m *LocalMachine) FetchFile(dst io.Writer, remotePath string) error {
+	if dst == nil {
+		return fmt.Errorf("the destination is nil")
+	}
+
+	// the file may be readable by root only
+	if m.sudoEnabled() {
+		content, err := m.sudoRun("cat " + shellQuote(remotePath))
+		if err != nil {
+			return fmt.Errorf("read file %v failed, error: %v", remotePath, err)
+		}
+		if _, err = dst.Write(content); err != nil {
+			return fmt.Errorf("copy from file %v failed, error: %v", remotePath, err)
+		}
+		return nil
+	}
+
+	remoteFile, err := os.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("open file %v failed, error: %v", remotePath, err)
+	}
+	defer remoteFile.Close()
+
+	if _, err = io.Copy(dst, remoteFile); err != nil {
+		return fmt.Errorf("copy from file %v failed, error: %v", remotePath, err)
+	}
+
+	logrus.Debugf("fetch file from %s on %s", remotePath, m.Name)
+
+	return nil
+}
+
+func (m *LocalMachine) FetchDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	logrus.Debugf("fetch %v:%v to %v", m.Name, remoteDir, localDir)
+
+	remoteDir = strings.TrimSuffix(remoteDir, "/")
+	localDir = strings.TrimSuffix(localDir, "/") + "/" + filepath.Base(remoteDir)
+
+	if !deploy.FileExist(remoteDir) {
+		return fmt.Errorf("%v:%v does not exist", m.Name, remoteDir)
+	}
+
+	return m.copyDir(localDir, remoteDir, fileNeeded, func(dir string) error {
+		return os.MkdirAll(dir, 0755)
+	}, m.FetchFileToLocalPath)
+}
+
+func (m *LocalMachine) PutDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	logrus.Debugf("copy %v to %v:%v", localDir, m.Name, remoteDir)
+
+	localDir = strings.TrimPrefix(strings.TrimSuffix(localDir, "/"), "./")
+	remoteDir = strings.TrimSuffix(remoteDir, "/") + "/" + filepath.Base(localDir)
+
+	if !deploy.FileExist(localDir) {
+		return fmt.Errorf("local directory:%v doesn't exist", localDir)
+	}
+
+	return m.copyDir(remoteDir, localDir, fileNeeded, m.mkdirAll, func(dstPath, srcPath string) error {
+		srcFile, err := os.Open(srcPath)
+		if err != nil {
+			return fmt.Errorf("open %v failed, error: %v", srcPath, err)
+		}
+		defer srcFile.Close()
+
+		return m.PutFile(srcFile, dstPath)
+	})
+}
+
+// mkdirAll creates the directory, via sudo if it's enabled.
+func (m *LocalMachine) mkdirAll(dir string) error {
+	if m.sudoEnabled() {
+		_, err := m.sudoRun("mkdir -p " + shellQuote(dir))
+		return err
+	}
+
+	return os.MkdirAll(dir, 0755)
+}
+
+// copyDir walks the source directory, creates the directories and copies the needed files into the destination directory.
+func (m *LocalMachine) copyDir(dstDir, srcDir string, fileNeeded func(path string) bool,
+	mkdirAll func(dir string) error, copyFile func(dstPath, srcPath string) error) error {
+
+	if err := filepath.Walk(srcDir, func(srcPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("walk %v:%v failed, error: %v", m.Name, srcPath, err)
+		}
+
+		dstPath := dstDir + strings.TrimPrefix(srcPath, srcDir)
+		logrus.Debugf("copy %v to %v on %v", srcPath, dstPath, m.Name)
+
+		if info.IsDir() {
+			if err := mkdirAll(dstPath); err != nil {
+				return fmt.Errorf("creating %v:%v failed. error: %v", m.Name, dstPath, err)
+			}
+			return nil
+		}
+
+		if !fileNeeded(srcPath) {
+			return nil
+		}
+
+		if err := copyFile(dstPath, srcPath); err != nil {
+			return fmt.Errorf("failed to copy file:%v to %v:%v, error: %v", srcPath, m.Name, dstPath, err)
+		}
+		return nil
+
+	}); err != nil {
+		return fmt.Errorf("walk %v failed. error: %v", srcDir, err)
+	}
+
+	return nil
+}
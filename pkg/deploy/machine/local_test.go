@@ -0,0 +1,140 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func newTestLocalMachine(t *testing.T) *LocalMachine {
+	m, err := newLocalMachine(&pb.Node{Name: "local", Ip: "127.0.0.1", Localhost: true})
+	assert.NoError(t, err)
+	return m.(*LocalMachine)
+}
+
+func TestLocalMachineRun(t *testing.T) {
+	m := newTestLocalMachine(t)
+	defer m.Close()
+
+	stdout, stderr, err := m.Run("echo out; echo err >&2; exit 3")
+	assert.NoError(t, err)
+	assert.Equal(t, "out\n", string(stdout))
+	assert.Equal(t, "err\n", string(stderr))
+
+	streamed := &bytes.Buffer{}
+	stdout, _, err = m.RunStream(context.Background(), "echo streamed", streamed, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "streamed\n", string(stdout))
+	assert.Equal(t, "streamed\n", streamed.String())
+}
+
+func TestLocalMachineRunContext(t *testing.T) {
+	m := newTestLocalMachine(t)
+	defer m.Close()
+
+	dir, err := ioutil.TempDir("", "local-machine")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	marker := filepath.Join(dir, "marker")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// the child process is killed together with the shell
+	_, _, err = m.RunContext(ctx, "(sleep 1; touch "+marker+") & wait")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	time.Sleep(1500 * time.Millisecond)
+	_, err = os.Stat(marker)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLocalMachineClose(t *testing.T) {
+	m := newTestLocalMachine(t)
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := m.Run("sleep 10")
+		done <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	m.Close()
+	assert.True(t, errors.Is(<-done, ErrMachineClosed))
+
+	_, _, err := m.Run("true")
+	assert.True(t, errors.Is(err, ErrMachineClosed))
+}
+
+func TestLocalMachineFiles(t *testing.T) {
+	m := newTestLocalMachine(t)
+	defer m.Close()
+
+	dir, err := ioutil.TempDir("", "local-machine")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "etc", "kubernetes", "admin.conf")
+	assert.NoError(t, m.PutFile(strings.NewReader("kubeconfig"), filePath))
+
+	content := &bytes.Buffer{}
+	assert.NoError(t, m.FetchFile(content, filePath))
+	assert.Equal(t, "kubeconfig", content.String())
+
+	localPath := filepath.Join(dir, "fetched", "admin.conf")
+	assert.NoError(t, m.FetchFileToLocalPath(localPath, filePath))
+	fetched, err := ioutil.ReadFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(fetched))
+
+	// the directory is copied into the destination directory, without the files not needed
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "etc", "kubernetes", "skipped"), nil, 0644))
+	notSkipped := func(path string) bool {
+		return filepath.Base(path) != "skipped"
+	}
+
+	assert.NoError(t, m.PutDir(filepath.Join(dir, "etc", "kubernetes"), filepath.Join(dir, "put"), notSkipped))
+	put, err := ioutil.ReadFile(filepath.Join(dir, "put", "kubernetes", "admin.conf"))
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(put))
+	_, err = os.Stat(filepath.Join(dir, "put", "kubernetes", "skipped"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, m.FetchDir(filepath.Join(dir, "fetched"), filepath.Join(dir, "etc"), notSkipped))
+	fetched, err = ioutil.ReadFile(filepath.Join(dir, "fetched", "etc", "kubernetes", "admin.conf"))
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(fetched))
+
+	assert.Error(t, m.FetchDir(dir, filepath.Join(dir, "not-exist"), notSkipped))
+}
+
+func TestNewMachineOfLocalhost(t *testing.T) {
+	m, err := NewMachine(&pb.Node{Name: "local", Ip: "127.0.0.1", Localhost: true})
+	assert.NoError(t, err)
+	defer m.Close()
+	assert.IsType(t, new(LocalMachine), m)
+}
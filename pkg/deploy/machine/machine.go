@@ -15,8 +15,10 @@
 package machine
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
@@ -28,43 +30,118 @@ type IMachine interface {
 	Close()
 
 	Run(cmd string) (stdout, stderr []byte, err error)
+	// RunContext runs the command, the command is killed on the remote machine once the context is done.
+	RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error)
+	// RunStream runs the command like RunContext, and the output is written into stdoutWriter
+	// and stderrWriter as it arrives. The writers may be nil.
+	RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error)
 	FetchDir(localDir, remoteDir string, fileNeeded func(path string) bool) error
 	FetchFile(dst io.Writer, remotePath string) error
 	FetchFileToLocalPath(localPath, remotePath string) error
 	PutDir(localDir, remoteDir string, fileNeeded func(path string) bool) error
 	PutFile(content io.Reader, remotePath string) error
+	// PutFiles puts the files with their modes in parallel, the files which are up to date on the
+	// machine are skipped, and the uploaded files are verified by the SHA-256 checksums.
+	PutFiles(files ...*File) error
+}
+
+// Simulator is implemented by the machines which don't run anything on the node actually, like the
+// mock, planning and replaying ones, so that the operations can skip the checks out of the machine,
+// like connecting to the services deployed on the node.
+type Simulator interface {
+	IsSimulated() bool
+}
+
+// IsSimulated reports whether the machine doesn't run anything on the node actually.
+func IsSimulated(m IMachine) bool {
+	s, ok := m.(Simulator)
+	return ok && s.IsSimulated()
 }
 
 type Machine struct {
-	*ExecClient
 	*pb.Node
+
+	// the connection shared with the other machines of the same node
+	conn      *pooledConn
+	closeOnce sync.Once
+	// closed on Close to abort the running commands
+	closeCh   chan struct{}
+	closeLock sync.RWMutex
+	inflight  sync.WaitGroup
+
+	// the staging directory of the files put via sudo
+	stagingLock  sync.Mutex
+	stagingDir   string
+	stagingCount int
+}
+
+// Factory creates the machine of the node.
+type Factory func(node *pb.Node) (IMachine, error)
+
+var (
+	factoryLock sync.RWMutex
+	factory     Factory
+)
+
+// SetFactory replaces the way NewMachine creates the machines, like to record or replay the
+// interactions of the operations in the tests. The default way is restored if f is nil.
+func SetFactory(f Factory) {
+	factoryLock.Lock()
+	defer factoryLock.Unlock()
+
+	factory = f
 }
 
 func NewMachine(node *pb.Node) (IMachine, error) {
+	factoryLock.RLock()
+	f := factory
+	factoryLock.RUnlock()
+
+	if f != nil {
+		return f(node)
+	}
+
+	return newDefaultMachine(node)
+}
+
+func newDefaultMachine(node *pb.Node) (IMachine, error) {
 	if IsTesting {
 		return newMockMachine(node)
 	}
 
+	if node.GetLocalhost() {
+		return newLocalMachine(node)
+	}
+
 	return newMachine(node)
 }
 
 func newMachine(node *pb.Node) (IMachine, error) {
-	client, err := NewExecClient(node)
+	conn, err := globalPool.acquire(node)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create execution client for machine: %v(%v), error: %v", node.Name, node.Ip, err)
+		return nil, fmt.Errorf("failed to create execution client for machine: %v(%v), error: %w", node.Name, node.Ip, err)
 	}
 
 	return &Machine{
-		ExecClient: client,
-		Node:       node,
+		Node:    node,
+		conn:    conn,
+		closeCh: make(chan struct{}),
 	}, nil
 }
 
+// Close kills the running commands and releases the connection to the pool,
+// the connection is closed after it's idle for a while.
 func (m *Machine) Close() {
 
-	if m.ExecClient != nil {
-		m.ExecClient.Close()
-	}
+	m.closeOnce.Do(func() {
+		m.closeLock.Lock()
+		close(m.closeCh)
+		m.closeLock.Unlock()
+
+		m.inflight.Wait()
+		m.removeStagingDir()
+		m.conn.release()
+	})
 
 }
 
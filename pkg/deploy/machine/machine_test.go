@@ -0,0 +1,209 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine/sshtest"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func newTestServer(t *testing.T) *sshtest.Server {
+	server, err := sshtest.NewServer()
+	assert.NoError(t, err)
+	return server
+}
+
+func TestMachineRun(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	server.Handle(`^uname -r$`, sshtest.Reply("5.4.0\n", "", 0))
+	server.Handle(`^systemctl status docker$`, sshtest.Reply("", "Unit docker.service could not be found.\n", 4))
+	server.Handle(`^kubeadm init$`, func(exec *sshtest.Exec) int {
+		io.WriteString(exec.Stdout, "[init] Using Kubernetes version\n")
+		io.WriteString(exec.Stderr, "W1219 warning\n")
+		return 0
+	})
+
+	m, err := newMachine(server.Node("node1"))
+	assert.NoError(t, err)
+	defer m.Close()
+
+	stdout, stderr, err := m.Run("uname -r")
+	assert.NoError(t, err)
+	assert.Equal(t, "5.4.0\n", string(stdout))
+	assert.Empty(t, stderr)
+
+	// the exit code is not regarded as an error
+	_, stderr, err = m.Run("systemctl status docker")
+	assert.NoError(t, err)
+	assert.Equal(t, "Unit docker.service could not be found.\n", string(stderr))
+
+	streamed := &bytes.Buffer{}
+	stdout, _, err = m.RunStream(context.Background(), "kubeadm init", streamed, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "[init] Using Kubernetes version\n", string(stdout))
+	assert.Equal(t, "[init] Using Kubernetes version\n", streamed.String())
+
+	_, stderr, err = m.Run("unknown")
+	assert.NoError(t, err)
+	assert.Contains(t, string(stderr), "no responder")
+
+	assert.Equal(t, []string{"uname -r", "systemctl status docker", "kubeadm init", "unknown"}, server.Commands())
+}
+
+func TestMachineRunContextKilled(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	server.Handle(`^yum install -y kubelet$`, sshtest.Hang())
+
+	m, err := newMachine(server.Node("node1"))
+	assert.NoError(t, err)
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, _, err = m.RunContext(ctx, "yum install -y kubelet")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// the remote process is killed by the pid file
+	transcript := server.Transcript()
+	last := transcript[len(transcript)-1]
+	assert.True(t, last.Builtin)
+	assert.True(t, strings.Contains(last.Command, "kill -9"))
+}
+
+func TestMachineFiles(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	m, err := newMachine(server.Node("node1"))
+	assert.NoError(t, err)
+	defer m.Close()
+
+	assert.NoError(t, m.PutFiles(
+		&File{Path: "/etc/kubernetes/pki/etcd/ca.crt", Content: []byte("cert")},
+		&File{Path: "/etc/kubernetes/pki/etcd/ca.key", Content: []byte("key"), Mode: 0600},
+	))
+	info, err := os.Stat(server.Path("/etc/kubernetes/pki/etcd/ca.key"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	content := &bytes.Buffer{}
+	assert.NoError(t, m.FetchFile(content, "/etc/kubernetes/pki/etcd/ca.crt"))
+	assert.Equal(t, "cert", content.String())
+
+	localDir, err := ioutil.TempDir("", "machine-files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(localDir)
+
+	assert.NoError(t, m.FetchDir(localDir, "/etc/kubernetes", func(string) bool { return true }))
+	fetched, err := ioutil.ReadFile(filepath.Join(localDir, "kubernetes", "pki", "etcd", "ca.key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "key", string(fetched))
+
+	assert.NoError(t, m.PutDir(filepath.Join(localDir, "kubernetes"), "/tmp", func(string) bool { return true }))
+	put, err := ioutil.ReadFile(server.Path("/tmp/kubernetes/pki/etcd/ca.crt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "cert", string(put))
+}
+
+func TestMachineWithSudo(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	server.SetSudoPassword("sudo-password")
+	server.Handle(`^systemctl start kubelet$`, sshtest.Reply("", "", 0))
+
+	node := server.Node("node1")
+	node.Ssh.Auth.Username = "deployer"
+	node.Ssh.Sudo = &pb.Sudo{Enabled: true, Password: "sudo-password"}
+	server.SetPassword("deployer", node.Ssh.Auth.Credential)
+
+	m, err := newMachine(node)
+	assert.NoError(t, err)
+
+	_, _, err = m.Run("systemctl start kubelet")
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.PutFiles(&File{Path: "/etc/kubernetes/admin.conf", Content: []byte("kubeconfig"), Mode: 0600}))
+	info, err := os.Stat(server.Path("/etc/kubernetes/admin.conf"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	content := &bytes.Buffer{}
+	assert.NoError(t, m.FetchFile(content, "/etc/kubernetes/admin.conf"))
+	assert.Equal(t, "kubeconfig", content.String())
+
+	// the directories are listed and created via sudo as well
+	localDir, err := ioutil.TempDir("", "machine-files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(localDir)
+	assert.NoError(t, m.FetchDir(localDir, "/etc/kubernetes", func(string) bool { return true }))
+	fetched, err := ioutil.ReadFile(filepath.Join(localDir, "kubernetes", "admin.conf"))
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(fetched))
+	assert.Error(t, m.FetchDir(localDir, "/etc/not-exist", func(string) bool { return true }))
+
+	assert.NoError(t, m.PutDir(filepath.Join(localDir, "kubernetes"), "/tmp", func(string) bool { return true }))
+	put, err := ioutil.ReadFile(server.Path("/tmp/kubernetes/admin.conf"))
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(put))
+
+	// the staging directory is removed on close
+	m.Close()
+	staging, err := filepath.Glob(server.Path("/tmp/kpaas-staging.*"))
+	assert.NoError(t, err)
+	assert.Empty(t, staging)
+
+	for _, record := range server.Transcript() {
+		assert.True(t, record.Sudo || strings.HasPrefix(record.Command, "mktemp") || strings.HasPrefix(record.Command, "rm -rf"),
+			"command not run via sudo: %v", record.Command)
+	}
+}
+
+func TestMachineWithJumpHost(t *testing.T) {
+	jumpHost := newTestServer(t)
+	defer jumpHost.Close()
+	server := newTestServer(t)
+	defer server.Close()
+	server.Handle(`^hostname$`, sshtest.Reply("node1\n", "", 0))
+
+	node := server.Node("node1")
+	node.Ssh.JumpHosts = []*pb.JumpHost{jumpHost.JumpHost()}
+	node.Ssh.Auth.Type = mssh.AuthTypeKeyboardInteractive
+
+	m, err := newMachine(node)
+	assert.NoError(t, err)
+	defer m.Close()
+
+	stdout, _, err := m.Run("hostname")
+	assert.NoError(t, err)
+	assert.Equal(t, "node1\n", string(stdout))
+	assert.Empty(t, jumpHost.Transcript())
+}
@@ -15,6 +15,7 @@
 package machine
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -56,10 +57,33 @@ func (m *MockMachine) GetIp() string {
 
 func (m *MockMachine) Close() {}
 
+func (m *MockMachine) IsSimulated() bool {
+	return true
+}
+
 func (m *MockMachine) StartDockerTunnel() error {
 	return nil
 }
 
+func (m *MockMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return m.Run(cmd)
+}
+
+func (m *MockMachine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error) {
+	stdout, stderr, err = m.RunContext(ctx, cmd)
+	if stdoutWriter != nil {
+		stdoutWriter.Write(stdout)
+	}
+	if stderrWriter != nil {
+		stderrWriter.Write(stderr)
+	}
+	return
+}
+
 // Run return different response by node name
 func (m *MockMachine) Run(cmd string) (stdout, stderr []byte, err error) {
 	if m.Name == "error" {
@@ -125,6 +149,13 @@ func (m *MockMachine) PutFile(content io.Reader, remotePath string) error {
 	return nil
 }
 
+func (m *MockMachine) PutFiles(files ...*File) error {
+	if m.Name == "error" {
+		return errMachineErr
+	}
+	return nil
+}
+
 func (m *MockMachine) GetNode() *pb.Node {
 	return m.Node
 }
@@ -0,0 +1,96 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// NewPlanRecorder returns a recorder of the machines which never connect to the nodes, so that
+// the commands and files recorded are the plan of what would be done on the nodes.
+func NewPlanRecorder() *Recorder {
+	return NewRecorder(NewPlanMachine)
+}
+
+// NewPlanMachine creates the machine of the node for planning. It can be used as the Factory.
+func NewPlanMachine(node *pb.Node) (IMachine, error) {
+	return &PlanMachine{Node: node}, nil
+}
+
+// PlanMachine never connects to the node: the commands succeed with empty outputs, the files put
+// are dropped, and nothing can be fetched from it.
+type PlanMachine struct {
+	*pb.Node
+}
+
+func (m *PlanMachine) GetName() string {
+	return m.Name
+}
+
+func (m *PlanMachine) GetIp() string {
+	return m.Ip
+}
+
+func (m *PlanMachine) GetNode() *pb.Node {
+	return m.Node
+}
+
+func (m *PlanMachine) Close() {}
+
+func (m *PlanMachine) IsSimulated() bool {
+	return true
+}
+
+func (m *PlanMachine) Run(cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(context.Background(), cmd, nil, nil)
+}
+
+func (m *PlanMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(ctx, cmd, nil, nil)
+}
+
+func (m *PlanMachine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error) {
+	return nil, nil, ctx.Err()
+}
+
+func (m *PlanMachine) PutFile(content io.Reader, remotePath string) error {
+	_, err := io.Copy(ioutil.Discard, content)
+	return err
+}
+
+func (m *PlanMachine) PutFiles(files ...*File) error {
+	return nil
+}
+
+func (m *PlanMachine) PutDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	return nil
+}
+
+func (m *PlanMachine) FetchFile(dst io.Writer, remotePath string) error {
+	return fmt.Errorf("can't fetch %v from %v in a plan", remotePath, m.Name)
+}
+
+func (m *PlanMachine) FetchFileToLocalPath(localPath, remotePath string) error {
+	return m.FetchFile(nil, remotePath)
+}
+
+func (m *PlanMachine) FetchDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	return fmt.Errorf("can't fetch %v from %v in a plan", remoteDir, m.Name)
+}
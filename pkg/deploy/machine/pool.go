@@ -0,0 +1,342 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/sftp"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const (
+	DefaultMaxSessionsPerNode = 8
+	DefaultKeepaliveInterval  = 30 * time.Second
+	DefaultIdleTimeout        = 5 * time.Minute
+)
+
+// PoolOptions is the options of the ssh connection pool, the machines of the same node
+// and login share one connection.
+type PoolOptions struct {
+	// MaxSessionsPerNode is the max number of the concurrent sessions on the connection to a node,
+	// the sftp session included. It should not exceed the MaxSessions of sshd, which is 10 by default.
+	MaxSessionsPerNode int
+	// KeepaliveInterval is the interval to check the connection by keepalive requests,
+	// the broken connection is reconnected on the next use.
+	KeepaliveInterval time.Duration
+	// IdleTimeout is how long the connection is kept after it's not used by any machine.
+	IdleTimeout time.Duration
+}
+
+type connPool struct {
+	lock    sync.Mutex
+	options PoolOptions
+	conns   map[string]*pooledConn
+
+	// dial and ping are replaced in tests
+	dial func(node *pb.Node) (*ExecClient, error)
+	ping func(client *ExecClient) error
+}
+
+// pooledConn is the connection to a node shared by the machines, it's closed after idle
+// for IdleTimeout with no machine referring to it.
+type pooledConn struct {
+	pool *connPool
+	key  string
+	node *pb.Node
+
+	lock     sync.Mutex
+	client   *ExecClient
+	refCount int
+	lastUsed time.Time
+	closed   bool
+
+	sessions chan struct{}
+	stopCh   chan struct{}
+}
+
+var globalPool = newConnPool(PoolOptions{})
+
+// SetPoolOptions sets the options of the ssh connection pool, the zero fields are set to the default values.
+// The options apply to the connections created later.
+func SetPoolOptions(options PoolOptions) {
+	globalPool.setOptions(options)
+}
+
+// ClosePool closes all the pooled connections.
+func ClosePool() {
+	globalPool.closeAll()
+}
+
+func newConnPool(options PoolOptions) *connPool {
+	pool := &connPool{
+		conns: make(map[string]*pooledConn),
+		dial:  NewExecClient,
+		ping:  pingExecClient,
+	}
+	pool.setOptions(options)
+	return pool
+}
+
+func (p *connPool) setOptions(options PoolOptions) {
+	if options.MaxSessionsPerNode <= 0 {
+		options.MaxSessionsPerNode = DefaultMaxSessionsPerNode
+	}
+	if options.KeepaliveInterval <= 0 {
+		options.KeepaliveInterval = DefaultKeepaliveInterval
+	}
+	if options.IdleTimeout <= 0 {
+		options.IdleTimeout = DefaultIdleTimeout
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.options = options
+}
+
+// acquire returns the connection to the node, which is connected if it's not yet.
+// The connection must be released after use.
+func (p *connPool) acquire(node *pb.Node) (*pooledConn, error) {
+	key := connKey(node)
+
+	p.lock.Lock()
+	conn, ok := p.conns[key]
+	if !ok {
+		// one session is reserved for sftp
+		maxSessions := p.options.MaxSessionsPerNode - 1
+		if maxSessions < 1 {
+			maxSessions = 1
+		}
+		conn = &pooledConn{
+			pool:     p,
+			key:      key,
+			node:     node,
+			sessions: make(chan struct{}, maxSessions),
+			stopCh:   make(chan struct{}),
+		}
+		p.conns[key] = conn
+		go conn.keepalive(p.options.KeepaliveInterval, p.options.IdleTimeout)
+	}
+	conn.lock.Lock()
+	conn.refCount++
+	conn.lock.Unlock()
+	p.lock.Unlock()
+
+	if _, err := conn.getClient(); err != nil {
+		conn.release()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// evictIfIdle closes and removes the connection if it's idle for idleTimeout.
+func (p *connPool) evictIfIdle(conn *pooledConn, idleTimeout time.Duration) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+
+	if conn.refCount > 0 || time.Since(conn.lastUsed) < idleTimeout {
+		return false
+	}
+
+	logrus.Debugf("close idle ssh connection: %v", conn.key)
+	delete(p.conns, conn.key)
+	conn.closeLocked()
+	return true
+}
+
+func (p *connPool) closeAll() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key, conn := range p.conns {
+		conn.lock.Lock()
+		conn.closeLocked()
+		conn.lock.Unlock()
+		delete(p.conns, key)
+	}
+}
+
+// getClient returns the client of the connection, it reconnects if the connection is broken.
+func (c *pooledConn) getClient() (*ExecClient, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("the ssh connection to %v is closed", c.node.Ip)
+	}
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	client, err := c.pool.dial(c.node)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+
+	return client, nil
+}
+
+// reconnectIfBroken checks the client by a keepalive request, and discards it if it's broken,
+// so that the next getClient reconnects. It returns true if the client is discarded.
+func (c *pooledConn) reconnectIfBroken(client *ExecClient) bool {
+	err := c.pool.ping(client)
+	if err == nil {
+		return false
+	}
+	logrus.Warnf("ssh connection %v is broken, reconnect it, error: %v", c.key, err)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.client == client {
+		c.client = nil
+		client.Close()
+	}
+
+	return true
+}
+
+// acquireSession blocks until the number of sessions is under the limit or the context is done,
+// the returned function must be called after the session is closed.
+func (c *pooledConn) acquireSession(ctx context.Context) (func(), error) {
+	select {
+	case c.sessions <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-c.sessions
+	}, nil
+}
+
+func (c *pooledConn) release() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.refCount--
+	c.lastUsed = time.Now()
+}
+
+func (c *pooledConn) closeLocked() {
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	close(c.stopCh)
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+func (c *pooledConn) keepalive(interval, idleTimeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		if c.pool.evictIfIdle(c, idleTimeout) {
+			return
+		}
+
+		c.lock.Lock()
+		client := c.client
+		c.lock.Unlock()
+
+		if client != nil {
+			c.reconnectIfBroken(client)
+		}
+	}
+}
+
+// newSession opens a session on the connection, the connection is reconnected once if it's broken.
+// It gives up waiting for the session limit once the context is done. The returned function closes the session.
+func (c *pooledConn) newSession(ctx context.Context) (*ssh.Session, func(), error) {
+	releaseSession, err := c.acquireSession(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := c.getClient()
+	if err != nil {
+		releaseSession()
+		return nil, nil, err
+	}
+
+	session, err := mssh.NewSession(client.SSHClient)
+	if err != nil && c.reconnectIfBroken(client) {
+		if client, err = c.getClient(); err == nil {
+			session, err = mssh.NewSession(client.SSHClient)
+		}
+	}
+	if err != nil {
+		releaseSession()
+		return nil, nil, err
+	}
+
+	return session, func() {
+		session.Close()
+		releaseSession()
+	}, nil
+}
+
+func (c *pooledConn) sftpClient() (*sftp.Client, error) {
+	client, err := c.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SFTPClient, nil
+}
+
+// connKey returns the key of the connection to the node, the connections with different
+// logins or jump hosts are not shared. The credential is hashed to be kept out of the key.
+func connKey(node *pb.Node) string {
+	sshConfig := &pb.SSH{}
+	if node.GetSsh() != nil {
+		sshConfig = proto.Clone(node.Ssh).(*pb.SSH)
+	}
+	// sudo doesn't affect the connection
+	sshConfig.Sudo = nil
+
+	sum := sha256.Sum256([]byte(proto.CompactTextString(sshConfig)))
+	return fmt.Sprintf("%v@%v:%v/%x", sshConfig.GetAuth().GetUsername(), node.GetIp(), sshConfig.Port, sum[:8])
+}
+
+func pingExecClient(client *ExecClient) error {
+	_, _, err := client.SSHClient.SendRequest("keepalive@openssh.com", true, nil)
+	return err
+}
@@ -0,0 +1,230 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+type fakeDialer struct {
+	lock    sync.Mutex
+	dialed  int
+	dialErr error
+	pingErr error
+}
+
+func (d *fakeDialer) dial(node *pb.Node) (*ExecClient, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.dialErr != nil {
+		return nil, d.dialErr
+	}
+	d.dialed++
+	return &ExecClient{}, nil
+}
+
+func (d *fakeDialer) ping(client *ExecClient) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.pingErr
+}
+
+func (d *fakeDialer) getDialed() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.dialed
+}
+
+func newTestConnPool(options PoolOptions) (*connPool, *fakeDialer) {
+	dialer := &fakeDialer{}
+	pool := newConnPool(options)
+	pool.dial = dialer.dial
+	pool.ping = dialer.ping
+	return pool, dialer
+}
+
+func newTestNode(password string) *pb.Node {
+	return &pb.Node{
+		Name: "node1",
+		Ip:   "192.168.31.101",
+		Ssh: &pb.SSH{
+			Port: 22,
+			Auth: &pb.Auth{
+				Type:       "password",
+				Username:   "root",
+				Credential: password,
+			},
+		},
+	}
+}
+
+func TestConnKey(t *testing.T) {
+	node := newTestNode("123456")
+	key := connKey(node)
+	assert.Contains(t, key, "root@192.168.31.101:22/")
+	assert.NotContains(t, key, "123456")
+
+	withSudo := newTestNode("123456")
+	withSudo.Ssh.Sudo = &pb.Sudo{Enabled: true}
+	assert.Equal(t, key, connKey(withSudo))
+	assert.Nil(t, node.Ssh.Sudo)
+
+	assert.NotEqual(t, key, connKey(newTestNode("654321")))
+
+	withJumpHost := newTestNode("123456")
+	withJumpHost.Ssh.JumpHosts = []*pb.JumpHost{{Ip: "192.168.31.1", Port: 22}}
+	assert.NotEqual(t, key, connKey(withJumpHost))
+}
+
+func TestConnPoolShare(t *testing.T) {
+	pool, dialer := newTestConnPool(PoolOptions{})
+	defer pool.closeAll()
+
+	conn1, err := pool.acquire(newTestNode("123456"))
+	assert.Nil(t, err)
+	conn2, err := pool.acquire(newTestNode("123456"))
+	assert.Nil(t, err)
+	assert.True(t, conn1 == conn2)
+	assert.Equal(t, 2, conn1.refCount)
+	assert.Equal(t, 1, dialer.getDialed())
+
+	conn3, err := pool.acquire(newTestNode("654321"))
+	assert.Nil(t, err)
+	assert.False(t, conn1 == conn3)
+	assert.Equal(t, 2, dialer.getDialed())
+
+	conn1.release()
+	conn2.release()
+	conn3.release()
+	assert.Equal(t, 0, conn1.refCount)
+}
+
+func TestConnPoolDialError(t *testing.T) {
+	pool, dialer := newTestConnPool(PoolOptions{})
+	defer pool.closeAll()
+
+	dialer.dialErr = fmt.Errorf("connection refused")
+	_, err := pool.acquire(newTestNode("123456"))
+	assert.Equal(t, dialer.dialErr, err)
+
+	dialer.dialErr = nil
+	conn, err := pool.acquire(newTestNode("123456"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, conn.refCount)
+	assert.Equal(t, 1, dialer.getDialed())
+	conn.release()
+}
+
+func TestConnPoolEvictIdle(t *testing.T) {
+	pool, dialer := newTestConnPool(PoolOptions{
+		KeepaliveInterval: 10 * time.Millisecond,
+		IdleTimeout:       30 * time.Millisecond,
+	})
+	defer pool.closeAll()
+
+	conn, err := pool.acquire(newTestNode("123456"))
+	assert.Nil(t, err)
+
+	// the connection in use is never evicted
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, pool.evictIfIdle(conn, 0))
+	conn.release()
+
+	assert.Eventually(t, func() bool {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+		return len(pool.conns) == 0
+	}, time.Second, 10*time.Millisecond)
+	_, err = conn.getClient()
+	assert.NotNil(t, err)
+
+	conn, err = pool.acquire(newTestNode("123456"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, dialer.getDialed())
+	conn.release()
+}
+
+func TestConnPoolReconnect(t *testing.T) {
+	pool, dialer := newTestConnPool(PoolOptions{})
+	defer pool.closeAll()
+
+	conn, err := pool.acquire(newTestNode("123456"))
+	assert.Nil(t, err)
+	defer conn.release()
+
+	client, err := conn.getClient()
+	assert.Nil(t, err)
+	assert.False(t, conn.reconnectIfBroken(client))
+
+	dialer.pingErr = fmt.Errorf("connection reset by peer")
+	assert.True(t, conn.reconnectIfBroken(client))
+
+	reconnected, err := conn.getClient()
+	assert.Nil(t, err)
+	assert.False(t, client == reconnected)
+	assert.Equal(t, 2, dialer.getDialed())
+}
+
+func TestConnPoolSessionLimit(t *testing.T) {
+	pool, _ := newTestConnPool(PoolOptions{MaxSessionsPerNode: 3})
+	defer pool.closeAll()
+
+	conn, err := pool.acquire(newTestNode("123456"))
+	assert.Nil(t, err)
+	defer conn.release()
+
+	// one of the sessions is reserved for sftp
+	release1, err := conn.acquireSession(context.Background())
+	assert.Nil(t, err)
+	release2, err := conn.acquireSession(context.Background())
+	assert.Nil(t, err)
+
+	// the waiting is given up once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = conn.acquireSession(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		release3, err := conn.acquireSession(context.Background())
+		assert.Nil(t, err)
+		close(acquired)
+		release3()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("the session limit is exceeded")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release1()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("the session is not acquired after released")
+	}
+	release2()
+}
@@ -0,0 +1,514 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// the actions of the steps in a transcript
+const (
+	StepRun       = "run"
+	StepPutFile   = "putFile"
+	StepFetchFile = "fetchFile"
+	StepPutDir    = "putDir"
+	StepFetchDir  = "fetchDir"
+)
+
+// the extension of the transcript files, which are named by the nodes
+const transcriptFileExt = ".json"
+
+// Transcript is the interactions with the machine of a node in order, which can be saved as
+// a golden file and replayed to catch the changes of the commands and the files put.
+type Transcript struct {
+	Node  string  `json:"node"`
+	Steps []*Step `json:"steps"`
+}
+
+// Step is an interaction with the machine, the fields not used by the action are empty.
+type Step struct {
+	Action  string `json:"action"`
+	Command string `json:"command,omitempty"`
+	// Path is the path on the machine, and LocalPath is the one on the local filesystem.
+	Path      string `json:"path,omitempty"`
+	LocalPath string `json:"localPath,omitempty"`
+	Mode      string `json:"mode,omitempty"`
+	// Content is the content put or fetched, the content put is not compared on replay if
+	// AnyContent is set, like the certs and keys generated.
+	Content    string `json:"content,omitempty"`
+	AnyContent bool   `json:"anyContent,omitempty"`
+	Stdout     string `json:"stdout,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (s *Step) String() string {
+	switch s.Action {
+	case StepRun:
+		return fmt.Sprintf("%v %q", s.Action, s.Command)
+	case StepPutFile:
+		return fmt.Sprintf("%v %v(mode: %v, %d bytes)", s.Action, s.Path, s.Mode, len(s.Content))
+	default:
+		return fmt.Sprintf("%v %v", s.Action, s.Path)
+	}
+}
+
+func (s *Step) err() error {
+	if s.Error == "" {
+		return nil
+	}
+	return errors.New(s.Error)
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// matches returns true if the actual step issued is the same as the recorded one.
+func (s *Step) matches(actual *Step) bool {
+	if s.Action != actual.Action || s.Command != actual.Command || s.Path != actual.Path || s.Mode != actual.Mode {
+		return false
+	}
+	if s.Action == StepPutFile && !s.AnyContent && s.Content != actual.Content {
+		return false
+	}
+	return true
+}
+
+// LoadTranscripts loads the transcripts saved in the directory by node name.
+func LoadTranscripts(dir string) (map[string]*Transcript, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+transcriptFileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	transcripts := make(map[string]*Transcript)
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		transcript := new(Transcript)
+		if err = json.Unmarshal(content, transcript); err != nil {
+			return nil, fmt.Errorf("failed to parse transcript %v, error: %v", path, err)
+		}
+		transcripts[transcript.Node] = transcript
+	}
+
+	return transcripts, nil
+}
+
+// SaveTranscripts saves the transcripts into the directory, one file for each node.
+func SaveTranscripts(dir string, transcripts map[string]*Transcript) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for node, transcript := range transcripts {
+		content, err := json.MarshalIndent(transcript, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, node+transcriptFileExt), append(content, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Recorder records the interactions with the machines created by it.
+type Recorder struct {
+	newMachine Factory
+
+	lock        sync.Mutex
+	transcripts map[string]*Transcript
+	anyContent  []*regexp.Regexp
+}
+
+// NewRecorder returns a recorder of the machines created by newMachine, the real machines are
+// created if newMachine is nil.
+func NewRecorder(newMachine Factory) *Recorder {
+	if newMachine == nil {
+		newMachine = newDefaultMachine
+	}
+
+	return &Recorder{
+		newMachine:  newMachine,
+		transcripts: make(map[string]*Transcript),
+	}
+}
+
+// IgnoreContent marks the content put to the paths matching the patterns not to be compared on
+// replay, which is generated on each run.
+func (r *Recorder) IgnoreContent(patterns ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, pattern := range patterns {
+		r.anyContent = append(r.anyContent, regexp.MustCompile(pattern))
+	}
+}
+
+// NewMachine creates the machine of the node, whose interactions are recorded. It can be used as the Factory.
+func (r *Recorder) NewMachine(node *pb.Node) (IMachine, error) {
+	m, err := r.newMachine(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingMachine{IMachine: m, recorder: r}, nil
+}
+
+// Transcripts returns the transcripts recorded by node name.
+func (r *Recorder) Transcripts() map[string]*Transcript {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	transcripts := make(map[string]*Transcript, len(r.transcripts))
+	for node, transcript := range r.transcripts {
+		transcripts[node] = &Transcript{Node: node, Steps: append([]*Step(nil), transcript.Steps...)}
+	}
+	return transcripts
+}
+
+func (r *Recorder) record(node string, step *Step) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if step.Action == StepPutFile {
+		for _, pattern := range r.anyContent {
+			if pattern.MatchString(step.Path) {
+				step.AnyContent = true
+				step.Content = ""
+			}
+		}
+	}
+
+	transcript, ok := r.transcripts[node]
+	if !ok {
+		transcript = &Transcript{Node: node}
+		r.transcripts[node] = transcript
+	}
+	transcript.Steps = append(transcript.Steps, step)
+}
+
+// recordingMachine records the interactions with the machine into the recorder.
+type recordingMachine struct {
+	IMachine
+	recorder *Recorder
+}
+
+// IsSimulated reports whether the machine recorded is a simulated one.
+func (m *recordingMachine) IsSimulated() bool {
+	return IsSimulated(m.IMachine)
+}
+
+func (m *recordingMachine) Run(cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(context.Background(), cmd, nil, nil)
+}
+
+func (m *recordingMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(ctx, cmd, nil, nil)
+}
+
+func (m *recordingMachine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error) {
+	stdout, stderr, err = m.IMachine.RunStream(ctx, cmd, stdoutWriter, stderrWriter)
+	m.recorder.record(m.GetName(), &Step{
+		Action:  StepRun,
+		Command: cmd,
+		Stdout:  string(stdout),
+		Stderr:  string(stderr),
+		Error:   errString(err),
+	})
+	return
+}
+
+func (m *recordingMachine) PutFile(content io.Reader, remotePath string) error {
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	err = m.IMachine.PutFile(bytes.NewReader(data), remotePath)
+	m.recorder.record(m.GetName(), &Step{Action: StepPutFile, Path: remotePath, Content: string(data), Error: errString(err)})
+	return err
+}
+
+func (m *recordingMachine) PutFiles(files ...*File) error {
+	err := m.IMachine.PutFiles(files...)
+	for _, file := range files {
+		m.recorder.record(m.GetName(), &Step{
+			Action:  StepPutFile,
+			Path:    file.Path,
+			Mode:    fmt.Sprintf("%04o", file.mode()),
+			Content: string(file.Content),
+			Error:   errString(err),
+		})
+	}
+	return err
+}
+
+func (m *recordingMachine) FetchFile(dst io.Writer, remotePath string) error {
+	var content bytes.Buffer
+	err := m.IMachine.FetchFile(&content, remotePath)
+	m.recorder.record(m.GetName(), &Step{Action: StepFetchFile, Path: remotePath, Content: content.String(), Error: errString(err)})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, &content)
+	return err
+}
+
+func (m *recordingMachine) FetchFileToLocalPath(localPath, remotePath string) error {
+	return fetchFileToLocalPath(m, localPath, remotePath)
+}
+
+func (m *recordingMachine) PutDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	err := m.IMachine.PutDir(localDir, remoteDir, fileNeeded)
+	m.recorder.record(m.GetName(), &Step{Action: StepPutDir, Path: remoteDir, LocalPath: localDir, Error: errString(err)})
+	return err
+}
+
+func (m *recordingMachine) FetchDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	err := m.IMachine.FetchDir(localDir, remoteDir, fileNeeded)
+	m.recorder.record(m.GetName(), &Step{Action: StepFetchDir, Path: remoteDir, LocalPath: localDir, Error: errString(err)})
+	return err
+}
+
+// ReplayMismatchError is returned if the step issued doesn't match the transcript.
+type ReplayMismatchError struct {
+	Node  string
+	Index int
+	// Expected is nil if all the steps in the transcript have been replayed.
+	Expected *Step
+	Actual   *Step
+}
+
+func (e *ReplayMismatchError) Error() string {
+	if e.Expected == nil {
+		return fmt.Sprintf("unexpected step %d of node %v: %v", e.Index, e.Node, e.Actual)
+	}
+	return fmt.Sprintf("step %d of node %v mismatched, expected: %v, actual: %v", e.Index, e.Node, e.Expected, e.Actual)
+}
+
+// Replayer replays the transcripts to the machines created by it. The machines of the same node
+// replay the transcript of the node in turn, like they're connected to the same node.
+type Replayer struct {
+	lock        sync.Mutex
+	transcripts map[string]*Transcript
+	replayed    map[string]int
+	errs        []error
+}
+
+// NewReplayer returns a replayer of the transcripts by node name.
+func NewReplayer(transcripts map[string]*Transcript) *Replayer {
+	return &Replayer{
+		transcripts: transcripts,
+		replayed:    make(map[string]int),
+	}
+}
+
+// NewMachine creates the machine replaying the transcript of the node. It can be used as the Factory.
+func (r *Replayer) NewMachine(node *pb.Node) (IMachine, error) {
+	return &ReplayMachine{Node: node, replayer: r}, nil
+}
+
+// Verify returns an error if any step mismatched, or any transcript is not replayed completely.
+func (r *Replayer) Verify() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var messages []string
+	for _, err := range r.errs {
+		messages = append(messages, err.Error())
+	}
+	for node, transcript := range r.transcripts {
+		if replayed := r.replayed[node]; replayed < len(transcript.Steps) {
+			messages = append(messages, fmt.Sprintf("%d steps of node %v not replayed, the next one: %v",
+				len(transcript.Steps)-replayed, node, transcript.Steps[replayed]))
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(messages, "\n"))
+}
+
+// replay returns the recorded step matching the actual one.
+func (r *Replayer) replay(node string, actual *Step) (*Step, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	index := r.replayed[node]
+	var expected *Step
+	if transcript, ok := r.transcripts[node]; ok && index < len(transcript.Steps) {
+		expected = transcript.Steps[index]
+	}
+
+	if expected == nil || !expected.matches(actual) {
+		err := &ReplayMismatchError{Node: node, Index: index, Expected: expected, Actual: actual}
+		r.errs = append(r.errs, err)
+		return nil, err
+	}
+
+	r.replayed[node] = index + 1
+	return expected, nil
+}
+
+// ReplayMachine answers the interactions with the recorded results in the transcript of the node,
+// nothing is run or put actually.
+type ReplayMachine struct {
+	*pb.Node
+	replayer *Replayer
+}
+
+func (m *ReplayMachine) GetName() string {
+	return m.Name
+}
+
+func (m *ReplayMachine) GetIp() string {
+	return m.Ip
+}
+
+func (m *ReplayMachine) GetNode() *pb.Node {
+	return m.Node
+}
+
+func (m *ReplayMachine) Close() {}
+
+func (m *ReplayMachine) IsSimulated() bool {
+	return true
+}
+
+func (m *ReplayMachine) Run(cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(context.Background(), cmd, nil, nil)
+}
+
+func (m *ReplayMachine) RunContext(ctx context.Context, cmd string) (stdout, stderr []byte, err error) {
+	return m.RunStream(ctx, cmd, nil, nil)
+}
+
+func (m *ReplayMachine) RunStream(ctx context.Context, cmd string, stdoutWriter, stderrWriter io.Writer) (stdout, stderr []byte, err error) {
+	step, err := m.replayer.replay(m.Name, &Step{Action: StepRun, Command: cmd})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if stdoutWriter != nil {
+		io.WriteString(stdoutWriter, step.Stdout)
+	}
+	if stderrWriter != nil {
+		io.WriteString(stderrWriter, step.Stderr)
+	}
+	return []byte(step.Stdout), []byte(step.Stderr), step.err()
+}
+
+func (m *ReplayMachine) PutFile(content io.Reader, remotePath string) error {
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	step, err := m.replayer.replay(m.Name, &Step{Action: StepPutFile, Path: remotePath, Content: string(data)})
+	if err != nil {
+		return err
+	}
+	return step.err()
+}
+
+func (m *ReplayMachine) PutFiles(files ...*File) error {
+	var stepErr error
+	for _, file := range files {
+		step, err := m.replayer.replay(m.Name, &Step{
+			Action:  StepPutFile,
+			Path:    file.Path,
+			Mode:    fmt.Sprintf("%04o", file.mode()),
+			Content: string(file.Content),
+		})
+		if err != nil {
+			return err
+		}
+		stepErr = step.err()
+	}
+	return stepErr
+}
+
+func (m *ReplayMachine) FetchFile(dst io.Writer, remotePath string) error {
+	step, err := m.replayer.replay(m.Name, &Step{Action: StepFetchFile, Path: remotePath})
+	if err != nil {
+		return err
+	}
+	if err = step.err(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(dst, step.Content)
+	return err
+}
+
+func (m *ReplayMachine) FetchFileToLocalPath(localPath, remotePath string) error {
+	return fetchFileToLocalPath(m, localPath, remotePath)
+}
+
+func (m *ReplayMachine) PutDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	step, err := m.replayer.replay(m.Name, &Step{Action: StepPutDir, Path: remoteDir})
+	if err != nil {
+		return err
+	}
+	return step.err()
+}
+
+// FetchDir replays the step only, the files fetched are not recorded.
+func (m *ReplayMachine) FetchDir(localDir, remoteDir string, fileNeeded func(path string) bool) error {
+	step, err := m.replayer.replay(m.Name, &Step{Action: StepFetchDir, Path: remoteDir})
+	if err != nil {
+		return err
+	}
+	return step.err()
+}
+
+// fetchFileToLocalPath fetches the file to the local path via FetchFile of the machine.
+func fetchFileToLocalPath(m IMachine, localPath, remotePath string) error {
+	localDir := filepath.Dir(localPath)
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return fmt.Errorf("mkdirall %v failed, error: %v", localDir, err)
+	}
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("create local file %v failed, error: %v", localPath, err)
+	}
+	defer localFile.Close()
+
+	return m.FetchFile(localFile, remotePath)
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine/sshtest"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// runSteps issues the same interactions to the machine of the node, the config content can be changed.
+func runSteps(t *testing.T, newMachine Factory, node *pb.Node, config string) {
+	m, err := newMachine(node)
+	assert.NoError(t, err)
+	defer m.Close()
+
+	stdout, _, err := m.Run("uname -r")
+	assert.NoError(t, err)
+	assert.Equal(t, "5.4.0\n", string(stdout))
+
+	// the exit code is not regarded as an error
+	_, stderr, err := m.Run("systemctl status docker")
+	assert.NoError(t, err)
+	assert.Equal(t, "Unit docker.service could not be found.\n", string(stderr))
+
+	assert.NoError(t, m.PutFiles(
+		&File{Path: "/etc/kubernetes/kubeadm_config.yaml", Content: []byte(config)},
+		&File{Path: "/etc/kubernetes/pki/apiserver.key", Content: []byte(node.Name), Mode: 0600},
+	))
+
+	var content bytes.Buffer
+	assert.NoError(t, m.FetchFile(&content, "/etc/kubernetes/admin.conf"))
+	assert.Equal(t, "kind: Config\n", content.String())
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	server.Handle(`^uname -r$`, sshtest.Reply("5.4.0\n", "", 0))
+	server.Handle(`^systemctl status docker$`, sshtest.Reply("", "Unit docker.service could not be found.\n", 4))
+	assert.NoError(t, os.MkdirAll(server.Path("/etc/kubernetes"), 0755))
+	assert.NoError(t, ioutil.WriteFile(server.Path("/etc/kubernetes/admin.conf"), []byte("kind: Config\n"), 0600))
+
+	recorder := NewRecorder(newMachine)
+	recorder.IgnoreContent(`\.key$`)
+	runSteps(t, recorder.NewMachine, server.Node("node1"), "kind: InitConfiguration\n")
+
+	dir, err := ioutil.TempDir("", "replay")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, SaveTranscripts(dir, recorder.Transcripts()))
+	_, err = os.Stat(filepath.Join(dir, "node1.json"))
+	assert.NoError(t, err)
+
+	transcripts, err := LoadTranscripts(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, recorder.Transcripts(), transcripts)
+
+	steps := transcripts["node1"].Steps
+	assert.Len(t, steps, 5)
+	assert.Equal(t, "0644", steps[2].Mode)
+	assert.Equal(t, "kind: InitConfiguration\n", steps[2].Content)
+	assert.True(t, steps[3].AnyContent)
+	assert.Empty(t, steps[3].Content)
+
+	// the content of the key is ignored, so it's replayed to another node as well
+	replayer := NewReplayer(transcripts)
+	runSteps(t, replayer.NewMachine, &pb.Node{Name: "node1", Ip: "192.168.0.1"}, "kind: InitConfiguration\n")
+	assert.NoError(t, replayer.Verify())
+}
+
+func TestReplayMismatched(t *testing.T) {
+	transcripts := map[string]*Transcript{
+		"node1": {Node: "node1", Steps: []*Step{
+			{Action: StepRun, Command: "uname -r", Stdout: "5.4.0\n"},
+			{Action: StepPutFile, Path: "/etc/kubernetes/kubeadm_config.yaml", Mode: "0644", Content: "kind: InitConfiguration\n"},
+		}},
+	}
+
+	replayer := NewReplayer(transcripts)
+	m, err := replayer.NewMachine(&pb.Node{Name: "node1"})
+	assert.NoError(t, err)
+
+	stdout, _, err := m.Run("uname -r")
+	assert.NoError(t, err)
+	assert.Equal(t, "5.4.0\n", string(stdout))
+
+	err = m.PutFiles(&File{Path: "/etc/kubernetes/kubeadm_config.yaml", Content: []byte("kind: JoinConfiguration\n")})
+	mismatch, ok := err.(*ReplayMismatchError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, mismatch.Index)
+	assert.Equal(t, transcripts["node1"].Steps[1], mismatch.Expected)
+
+	err = replayer.Verify()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "step 1 of node node1 mismatched")
+	assert.Contains(t, err.Error(), "1 steps of node node1 not replayed")
+}
+
+func TestReplayUnexpectedStep(t *testing.T) {
+	replayer := NewReplayer(map[string]*Transcript{
+		"node1": {Node: "node1", Steps: []*Step{{Action: StepRun, Command: "uname -r", Stdout: "5.4.0\n"}}},
+	})
+
+	m, err := replayer.NewMachine(&pb.Node{Name: "node1"})
+	assert.NoError(t, err)
+	_, _, err = m.Run("uname -r")
+	assert.NoError(t, err)
+	_, _, err = m.Run("reboot")
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "unexpected step 1 of node node1"))
+
+	// no transcript is recorded for the node
+	m, err = replayer.NewMachine(&pb.Node{Name: "node2"})
+	assert.NoError(t, err)
+	_, _, err = m.Run("uname -r")
+	assert.Error(t, err)
+	assert.Error(t, replayer.Verify())
+}
+
+func TestIsSimulated(t *testing.T) {
+	node := &pb.Node{Name: "node1", Ip: "192.168.0.1"}
+
+	planMachine, err := NewPlanRecorder().NewMachine(node)
+	assert.NoError(t, err)
+	assert.True(t, IsSimulated(planMachine))
+
+	replayMachine, err := NewReplayer(nil).NewMachine(node)
+	assert.NoError(t, err)
+	assert.True(t, IsSimulated(replayMachine))
+
+	mockMachine, err := newMockMachine(node)
+	assert.NoError(t, err)
+	assert.True(t, IsSimulated(mockMachine))
+
+	// the real machines are recorded
+	assert.False(t, IsSimulated(&recordingMachine{IMachine: &Machine{Node: node}}))
+	assert.False(t, IsSimulated(&Machine{Node: node}))
+}
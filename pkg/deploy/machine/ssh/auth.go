@@ -0,0 +1,88 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const (
+	AuthTypePassword            = "password"
+	AuthTypePrivateKey          = "privatekey"
+	AuthTypeKeyboardInteractive = "keyboardinteractive"
+)
+
+// NewSigner parses the private key, which is decrypted by the passphrase if it's set, the returned
+// signer presents the OpenSSH user certificate instead of the public key if the certificate is set.
+func NewSigner(privateKey, passphrase, certificate string) (ssh.Signer, error) {
+	var signer ssh.Signer
+	var err error
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey([]byte(privateKey))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key, error: %w", err)
+	}
+
+	if certificate == "" {
+		return signer, nil
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(certificate))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate, error: %w", err)
+	}
+	cert, ok := publicKey.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("the certificate is a %v public key, not a certificate", publicKey.Type())
+	}
+	if cert.CertType != ssh.UserCert {
+		return nil, fmt.Errorf("the certificate is not a user certificate")
+	}
+	if !bytes.Equal(cert.Key.Marshal(), signer.PublicKey().Marshal()) {
+		return nil, fmt.Errorf("the certificate doesn't match the private key")
+	}
+
+	return ssh.NewCertSigner(cert, signer)
+}
+
+// newChallengeResponder answers the keyboard interactive questions with the challenge responses in order,
+// or with the credential if there are no challenge responses.
+func newChallengeResponder(auth *pb.Auth) ssh.KeyboardInteractiveChallenge {
+	next := 0
+	return func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+		answers := make([]string, len(questions))
+		for i, question := range questions {
+			if len(auth.ChallengeResponses) == 0 {
+				answers[i] = auth.Credential
+				continue
+			}
+
+			if next >= len(auth.ChallengeResponses) {
+				return nil, fmt.Errorf("no challenge response for the question: %q", question)
+			}
+			answers[i] = auth.ChallengeResponses[next]
+			next++
+		}
+		return answers, nil
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func newTestPrivateKey(t *testing.T, passphrase string) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	block := &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}
+	if passphrase != "" {
+		block, err = x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(passphrase), x509.PEMCipherAES256)
+		assert.Nil(t, err)
+	}
+	return key, string(pem.EncodeToMemory(block))
+}
+
+func newTestCertificate(t *testing.T, key *rsa.PrivateKey, certType uint32) string {
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+	_, caKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	caSigner, err := ssh.NewSignerFromKey(caKey)
+	assert.Nil(t, err)
+
+	cert := &ssh.Certificate{
+		Key:             publicKey,
+		CertType:        certType,
+		ValidPrincipals: []string{"kpaas"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	assert.Nil(t, cert.SignCert(rand.Reader, caSigner))
+	return string(ssh.MarshalAuthorizedKey(cert))
+}
+
+func TestNewSigner(t *testing.T) {
+	key, privateKey := newTestPrivateKey(t, "")
+	signer, err := NewSigner(privateKey, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, ssh.KeyAlgoRSA, signer.PublicKey().Type())
+
+	encryptedKey, encryptedPrivateKey := newTestPrivateKey(t, "secret")
+	_, err = NewSigner(encryptedPrivateKey, "", "")
+	assert.NotNil(t, err)
+	_, err = NewSigner(encryptedPrivateKey, "wrong", "")
+	assert.NotNil(t, err)
+	signer, err = NewSigner(encryptedPrivateKey, "secret", "")
+	assert.Nil(t, err)
+	expectedPublicKey, err := ssh.NewPublicKey(&encryptedKey.PublicKey)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedPublicKey.Marshal(), signer.PublicKey().Marshal())
+
+	signer, err = NewSigner(privateKey, "", newTestCertificate(t, key, ssh.UserCert))
+	assert.Nil(t, err)
+	assert.Equal(t, ssh.CertAlgoRSAv01, signer.PublicKey().Type())
+
+	_, err = NewSigner(privateKey, "", newTestCertificate(t, key, ssh.HostCert))
+	assert.NotNil(t, err)
+	_, err = NewSigner(privateKey, "", newTestCertificate(t, encryptedKey, ssh.UserCert))
+	assert.NotNil(t, err)
+	_, err = NewSigner(privateKey, "", "not a certificate")
+	assert.NotNil(t, err)
+}
+
+func TestChallengeResponder(t *testing.T) {
+	responder := newChallengeResponder(&pb.Auth{Credential: "123456"})
+	answers, err := responder("kpaas", "", []string{"Password:", "Password again:"}, []bool{false, false})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"123456", "123456"}, answers)
+
+	responder = newChallengeResponder(&pb.Auth{Credential: "123456", ChallengeResponses: []string{"password", "otp"}})
+	answers, err = responder("kpaas", "", []string{"Password:"}, []bool{false})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"password"}, answers)
+	answers, err = responder("kpaas", "", []string{"Verification code:"}, []bool{false})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"otp"}, answers)
+	_, err = responder("kpaas", "", []string{"One more:"}, []bool{false})
+	assert.NotNil(t, err)
+}
+
+func TestNewConfig(t *testing.T) {
+	_, err := newConfig("kpaas", &pb.Auth{Type: AuthTypeKeyboardInteractive, Credential: "123456"})
+	assert.Nil(t, err)
+	_, err = newConfig("kpaas", &pb.Auth{Type: "unknown"})
+	assert.NotNil(t, err)
+}
@@ -25,47 +25,171 @@ import (
 
 const (
 	defaultTimeout = 60 * time.Second
+	defaultPort    = 22
 )
 
+// the timeout of establishing a connection, including the tcp dial and the ssh handshake
+var dialTimeout = defaultTimeout
+
 func newConfig(user string, auth *pb.Auth) (*ssh.ClientConfig, error) {
 	var authMethod ssh.AuthMethod
 
-	switch {
-	case auth.Type == "password":
+	switch auth.Type {
+	case AuthTypePassword:
 		authMethod = ssh.Password(auth.Credential)
-	case auth.Type == "privatekey":
-		privateKey, err := ssh.ParsePrivateKey([]byte(auth.Credential))
+	case AuthTypePrivateKey:
+		signer, err := NewSigner(auth.Credential, auth.Passphrase, auth.Certificate)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse private key: %v, error: %v", auth.Credential, err)
+			return nil, err
 		}
 
-		authMethod = ssh.PublicKeys(privateKey)
+		authMethod = ssh.PublicKeys(signer)
+	case AuthTypeKeyboardInteractive:
+		authMethod = ssh.KeyboardInteractive(newChallengeResponder(auth))
 	default:
 		return nil, fmt.Errorf("unrecognized auth type: %v", auth.Type)
 	}
 
 	return &ssh.ClientConfig{
-		User:            user,
-		Auth:            []ssh.AuthMethod{authMethod},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         defaultTimeout,
+		User:    user,
+		Auth:    []ssh.AuthMethod{authMethod},
+		Timeout: dialTimeout,
 	}, nil
 }
 
+// NewClient creates a ssh client connected to the host, the connection is tunneled through
+// the jump hosts in sshConfig if there are any.
 func NewClient(user string, host string, sshConfig *pb.SSH) (*ssh.Client, error) {
-	config, err := newConfig(user, sshConfig.Auth)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get ssh client config: %v, error: %v", sshConfig, err)
+	var jumpClients []*ssh.Client
+	closeJumpClients := func() {
+		for i := len(jumpClients) - 1; i >= 0; i-- {
+			jumpClients[i].Close()
+		}
+	}
+
+	var via *ssh.Client
+	for _, jumpHost := range sshConfig.JumpHosts {
+		client, err := dial(via, jumpHost.GetAuth().GetUsername(), jumpHost.Ip, jumpHost.Port, jumpHost.Auth)
+		if err != nil {
+			closeJumpClients()
+			return nil, fmt.Errorf("failed to connect jump host: %v, error: %w", jumpHost.Ip, err)
+		}
+		jumpClients = append(jumpClients, client)
+		via = client
 	}
 
-	client, err := ssh.Dial("tcp", fmt.Sprintf("%v:%v", host, sshConfig.Port), config)
+	client, err := dial(via, user, host, sshConfig.Port, sshConfig.Auth)
 	if err != nil {
-		return nil, fmt.Errorf("failed to dial: %v, error: %v", host, err)
+		closeJumpClients()
+		return nil, err
+	}
+
+	if len(jumpClients) > 0 {
+		// the connections to the jump hosts are closed along with the connection to the host
+		go func() {
+			client.Wait()
+			closeJumpClients()
+		}()
 	}
 
 	return client, nil
 }
 
+// dialResult is the result of a dial, with the host key mismatch found in the handshake if any.
+type dialResult struct {
+	client   *ssh.Client
+	mismatch *HostKeyMismatchError
+	err      error
+}
+
+// dial connects to the host directly if via is nil, otherwise through the client via.
+// The default ssh port is used if port is not set.
+func dial(via *ssh.Client, user string, host string, port uint32, auth *pb.Auth) (*ssh.Client, error) {
+	config, err := newConfig(user, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ssh client config of: %v, error: %v", host, err)
+	}
+
+	if port == 0 {
+		port = defaultPort
+	}
+	address := fmt.Sprintf("%v:%v", host, port)
+	var r dialResult
+	if via == nil {
+		r.client, r.err = ssh.Dial("tcp", address, withHostKeyCallback(config, &r))
+	} else {
+		r = dialVia(via, address, config)
+	}
+	if r.mismatch != nil {
+		return nil, fmt.Errorf("failed to dial: %v, error: %w", host, r.mismatch)
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to dial: %v, error: %v", host, r.err)
+	}
+
+	return r.client, nil
+}
+
+// withHostKeyCallback returns a copy of config whose host key callback records the mismatch into r.
+// The host key is pinned on the first connection, and verified on the later ones.
+func withHostKeyCallback(config *ssh.ClientConfig, r *dialResult) *ssh.ClientConfig {
+	copied := *config
+	copied.HostKeyCallback = GetKnownHostsStore().HostKeyCallback(func(err *HostKeyMismatchError) {
+		r.mismatch = err
+	})
+	return &copied
+}
+
+// dialVia connects to the address through the client via. The tunneled connection doesn't support deadlines,
+// so it's given up if it's not established in the timeout of config like ssh.Dial, the jump hosts are closed
+// by the caller then, which aborts the pending dial and handshake.
+// The result of the handshake is owned by the goroutine until it's sent, so a late handshake never races with the caller.
+func dialVia(via *ssh.Client, address string, config *ssh.ClientConfig) dialResult {
+	if config.Timeout <= 0 {
+		var r dialResult
+		r.client, r.err = handshakeVia(via, address, withHostKeyCallback(config, &r))
+		return r
+	}
+
+	result := make(chan dialResult, 1)
+	go func() {
+		var r dialResult
+		r.client, r.err = handshakeVia(via, address, withHostKeyCallback(config, &r))
+		result <- r
+	}()
+
+	timer := time.NewTimer(config.Timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-result:
+		return r
+	case <-timer.C:
+		// close the connection if it's established too late
+		go func() {
+			if r := <-result; r.client != nil {
+				r.client.Close()
+			}
+		}()
+		return dialResult{err: fmt.Errorf("dial %v via %v: i/o timeout after %v", address, via.RemoteAddr(), config.Timeout)}
+	}
+}
+
+func handshakeVia(via *ssh.Client, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	conn, err := via.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return ssh.NewClient(clientConn, channels, requests), nil
+}
+
 func NewSession(client *ssh.Client) (*ssh.Session, error) {
 	session, err := client.NewSession()
 	if err != nil {
@@ -0,0 +1,101 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine/sshtest"
+)
+
+func TestNewClientViaJumpHostTimeout(t *testing.T) {
+	defer mssh.SetDialTimeout(100 * time.Millisecond)()
+
+	jumpHost, err := sshtest.NewServer()
+	assert.NoError(t, err)
+	defer jumpHost.Close()
+
+	// the host accepts the connections but never says hello
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	node := jumpHost.Node("node1")
+	node.Ssh.JumpHosts = append(node.Ssh.JumpHosts, jumpHost.JumpHost())
+	node.Ssh.Port = uint32(listener.Addr().(*net.TCPAddr).Port)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := mssh.NewClient(node.Ssh.Auth.Username, node.Ip, node.Ssh)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "i/o timeout"), err.Error())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection via the jump host is not timed out")
+	}
+}
+
+func TestNewClientViaJumpHostKeyMismatched(t *testing.T) {
+	jumpHost, err := sshtest.NewServer()
+	assert.NoError(t, err)
+	defer jumpHost.Close()
+	server, err := sshtest.NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+
+	node := server.Node("node1")
+	node.Ssh.JumpHosts = append(node.Ssh.JumpHosts, jumpHost.JumpHost())
+
+	// another key of the host was pinned
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	pinnedKey, err := ssh.NewPublicKey(publicKey)
+	assert.NoError(t, err)
+	store := mssh.NewMemoryKnownHostsStore()
+	assert.NoError(t, store.Pin(fmt.Sprintf("%s:%d", node.Ip, node.Ssh.Port), pinnedKey))
+	oldStore := mssh.GetKnownHostsStore()
+	mssh.SetKnownHostsStore(store)
+	defer mssh.SetKnownHostsStore(oldStore)
+
+	_, err = mssh.NewClient(node.Ssh.Auth.Username, node.Ip, node.Ssh)
+	var mismatchErr *mssh.HostKeyMismatchError
+	if assert.True(t, errors.As(err, &mismatchErr), "%v", err) {
+		assert.Equal(t, mssh.Fingerprint(pinnedKey), mismatchErr.ExpectedFingerprint)
+	}
+}
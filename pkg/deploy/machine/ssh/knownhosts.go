@@ -0,0 +1,267 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// KnownHostsFileName is the file name of the known hosts trust store
+const KnownHostsFileName = "known_hosts"
+
+// the comment of a known hosts line records when the host key was pinned
+const knownHostPinnedAtPrefix = "pinned-at="
+
+var (
+	knownHostsLock  sync.RWMutex
+	knownHostsStore = NewMemoryKnownHostsStore()
+)
+
+// KnownHost is a host key pinned on the first connection to the host
+type KnownHost struct {
+	// Host is the normalized address of the host, the port is omitted if it's 22,
+	// like "192.168.0.1" or "[192.168.0.1]:2222"
+	Host        string
+	Key         ssh.PublicKey
+	Fingerprint string
+	PinnedAt    time.Time
+}
+
+// HostKeyMismatchError is returned if the host key presented by the host is not the pinned one
+type HostKeyMismatchError struct {
+	Host                string
+	ExpectedFingerprint string
+	ActualFingerprint   string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("host key of %v mismatched, pinned fingerprint: %v, presented fingerprint: %v",
+		e.Host, e.ExpectedFingerprint, e.ActualFingerprint)
+}
+
+// KnownHostsStore keeps the pinned host keys, the keys are saved into a file
+// in known_hosts format if the file path is set.
+type KnownHostsStore struct {
+	lock  sync.RWMutex
+	path  string
+	hosts map[string]*KnownHost
+}
+
+// NewMemoryKnownHostsStore creates a known hosts store which is not persisted
+func NewMemoryKnownHostsStore() *KnownHostsStore {
+	return &KnownHostsStore{
+		hosts: make(map[string]*KnownHost),
+	}
+}
+
+// NewKnownHostsStore creates a known hosts store persisted in the file of path,
+// the pinned keys are loaded from the file if it exists.
+func NewKnownHostsStore(path string) (*KnownHostsStore, error) {
+	store := NewMemoryKnownHostsStore()
+	store.path = path
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts file: %v, error: %w", path, err)
+	}
+
+	for len(content) > 0 {
+		var hosts []string
+		var key ssh.PublicKey
+		var comment string
+		_, hosts, key, comment, content, err = ssh.ParseKnownHosts(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse known hosts file: %v, error: %w", path, err)
+		}
+		pinnedAt, _ := time.Parse(time.RFC3339, strings.TrimPrefix(comment, knownHostPinnedAtPrefix))
+		for _, host := range hosts {
+			store.hosts[host] = newKnownHost(host, key, pinnedAt)
+		}
+	}
+
+	return store, nil
+}
+
+// GetKnownHostsStore returns the known hosts store used to verify host keys
+func GetKnownHostsStore() *KnownHostsStore {
+	knownHostsLock.RLock()
+	defer knownHostsLock.RUnlock()
+
+	return knownHostsStore
+}
+
+// SetKnownHostsStore sets the known hosts store used to verify host keys
+func SetKnownHostsStore(store *KnownHostsStore) {
+	knownHostsLock.Lock()
+	defer knownHostsLock.Unlock()
+
+	knownHostsStore = store
+}
+
+// NormalizeHost normalizes the address of a host to the known hosts format,
+// the default port 22 is used if port is not set.
+func NormalizeHost(address string) string {
+	return knownhosts.Normalize(address)
+}
+
+// Fingerprint returns the SHA256 fingerprint of the key
+func Fingerprint(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
+
+func newKnownHost(host string, key ssh.PublicKey, pinnedAt time.Time) *KnownHost {
+	return &KnownHost{
+		Host:        host,
+		Key:         key,
+		Fingerprint: Fingerprint(key),
+		PinnedAt:    pinnedAt,
+	}
+}
+
+// Get returns the pinned key of the host address
+func (s *KnownHostsStore) Get(address string) (*KnownHost, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	knownHost, ok := s.hosts[NormalizeHost(address)]
+	return knownHost, ok
+}
+
+// List returns all the pinned keys sorted by host
+func (s *KnownHostsStore) List() []*KnownHost {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.listLocked()
+}
+
+func (s *KnownHostsStore) listLocked() []*KnownHost {
+	knownHosts := make([]*KnownHost, 0, len(s.hosts))
+	for _, knownHost := range s.hosts {
+		knownHosts = append(knownHosts, knownHost)
+	}
+	sort.Slice(knownHosts, func(i, j int) bool {
+		return knownHosts[i].Host < knownHosts[j].Host
+	})
+
+	return knownHosts
+}
+
+// Pin pins the key of the host address, the pinned key is replaced if it exists.
+func (s *KnownHostsStore) Pin(address string, key ssh.PublicKey) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	host := NormalizeHost(address)
+	s.hosts[host] = newKnownHost(host, key, time.Now())
+
+	return s.saveLocked()
+}
+
+// Forget removes the pinned key of the host address, so that the key will be pinned
+// again on the next connection. It returns false if the host was not pinned.
+func (s *KnownHostsStore) Forget(address string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	host := NormalizeHost(address)
+	if _, ok := s.hosts[host]; !ok {
+		return false, nil
+	}
+	delete(s.hosts, host)
+
+	return true, s.saveLocked()
+}
+
+// Verify checks the key presented by the host address against the pinned one, the key is
+// pinned if the host is unknown (trust on first use).
+// The check and the pin are done with the lock held, so only the first key presented
+// by the concurrent connections to an unknown host is pinned.
+func (s *KnownHostsStore) Verify(address string, key ssh.PublicKey) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	host := NormalizeHost(address)
+	knownHost, ok := s.hosts[host]
+	if !ok {
+		logrus.Infof("pin host key of %v, fingerprint: %v", host, Fingerprint(key))
+		s.hosts[host] = newKnownHost(host, key, time.Now())
+		return s.saveLocked()
+	}
+
+	if !bytes.Equal(knownHost.Key.Marshal(), key.Marshal()) {
+		return &HostKeyMismatchError{
+			Host:                knownHost.Host,
+			ExpectedFingerprint: knownHost.Fingerprint,
+			ActualFingerprint:   Fingerprint(key),
+		}
+	}
+
+	return nil
+}
+
+// HostKeyCallback returns a ssh.HostKeyCallback verifying the host keys with the store.
+// The ssh handshake doesn't keep the error type, so the mismatch error is also reported
+// via onMismatch.
+func (s *KnownHostsStore) HostKeyCallback(onMismatch func(err *HostKeyMismatchError)) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := s.Verify(hostname, key)
+		if mismatchErr, ok := err.(*HostKeyMismatchError); ok && onMismatch != nil {
+			onMismatch(mismatchErr)
+		}
+		return err
+	}
+}
+
+// saveLocked writes all the pinned keys into the file, it must be called with the lock held.
+func (s *KnownHostsStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	for _, knownHost := range s.listLocked() {
+		fmt.Fprintf(&buffer, "%v %v%v\n", knownhosts.Line([]string{knownHost.Host}, knownHost.Key),
+			knownHostPinnedAtPrefix, knownHost.PinnedAt.UTC().Format(time.RFC3339))
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory of known hosts file: %v, error: %w", s.path, err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, buffer.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write known hosts file: %v, error: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to rename known hosts file: %v, error: %w", s.path, err)
+	}
+
+	return nil
+}
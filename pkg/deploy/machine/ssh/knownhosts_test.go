@@ -0,0 +1,152 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestHostKey(t *testing.T) ssh.PublicKey {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	key, err := ssh.NewPublicKey(publicKey)
+	assert.Nil(t, err)
+	return key
+}
+
+func TestNormalizeHost(t *testing.T) {
+	assert.Equal(t, "192.168.0.1", NormalizeHost("192.168.0.1"))
+	assert.Equal(t, "192.168.0.1", NormalizeHost("192.168.0.1:22"))
+	assert.Equal(t, "[192.168.0.1]:2222", NormalizeHost("192.168.0.1:2222"))
+}
+
+func TestKnownHostsStoreVerify(t *testing.T) {
+	store := NewMemoryKnownHostsStore()
+	key := newTestHostKey(t)
+	otherKey := newTestHostKey(t)
+
+	// pinned on first use
+	assert.Nil(t, store.Verify("192.168.0.1:22", key))
+	knownHost, ok := store.Get("192.168.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, "192.168.0.1", knownHost.Host)
+	assert.Equal(t, Fingerprint(key), knownHost.Fingerprint)
+
+	assert.Nil(t, store.Verify("192.168.0.1:22", key))
+	// the same ip with another port is another host
+	assert.Nil(t, store.Verify("192.168.0.1:2222", otherKey))
+
+	err := store.Verify("192.168.0.1:22", otherKey)
+	var mismatchErr *HostKeyMismatchError
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &mismatchErr))
+	assert.Equal(t, "192.168.0.1", mismatchErr.Host)
+	assert.Equal(t, Fingerprint(key), mismatchErr.ExpectedFingerprint)
+	assert.Equal(t, Fingerprint(otherKey), mismatchErr.ActualFingerprint)
+
+	var reported *HostKeyMismatchError
+	callback := store.HostKeyCallback(func(err *HostKeyMismatchError) {
+		reported = err
+	})
+	assert.NotNil(t, callback("192.168.0.1:22", nil, otherKey))
+	assert.NotNil(t, reported)
+
+	forgotten, err := store.Forget("192.168.0.1")
+	assert.Nil(t, err)
+	assert.True(t, forgotten)
+	forgotten, err = store.Forget("192.168.0.1")
+	assert.Nil(t, err)
+	assert.False(t, forgotten)
+
+	// pinned again after forgotten
+	assert.Nil(t, store.Verify("192.168.0.1:22", otherKey))
+	assert.Len(t, store.List(), 2)
+}
+
+func TestKnownHostsStoreVerifyConcurrently(t *testing.T) {
+	store := NewMemoryKnownHostsStore()
+
+	const connections = 10
+	keys := make([]ssh.PublicKey, connections)
+	for i := range keys {
+		keys[i] = newTestHostKey(t)
+	}
+
+	// only one of the keys presented to the concurrent first connections is pinned
+	errs := make([]error, connections)
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = store.Verify("192.168.0.1:22", keys[i])
+		}(i)
+	}
+	wg.Wait()
+
+	knownHost, ok := store.Get("192.168.0.1")
+	assert.True(t, ok)
+	pinned := 0
+	for i, err := range errs {
+		if err == nil {
+			pinned++
+			assert.Equal(t, Fingerprint(keys[i]), knownHost.Fingerprint)
+		} else {
+			assert.IsType(t, &HostKeyMismatchError{}, err)
+		}
+	}
+	assert.Equal(t, 1, pinned)
+}
+
+func TestKnownHostsStorePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "knownhosts")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, KnownHostsFileName)
+
+	store, err := NewKnownHostsStore(path)
+	assert.Nil(t, err)
+	assert.Empty(t, store.List())
+
+	key := newTestHostKey(t)
+	assert.Nil(t, store.Pin("192.168.0.1:22", key))
+	assert.Nil(t, store.Pin("192.168.0.2:2222", newTestHostKey(t)))
+	_, err = store.Forget("192.168.0.2:2222")
+	assert.Nil(t, err)
+
+	reloaded, err := NewKnownHostsStore(path)
+	assert.Nil(t, err)
+	knownHosts := reloaded.List()
+	assert.Len(t, knownHosts, 1)
+	assert.Equal(t, "192.168.0.1", knownHosts[0].Host)
+	assert.Equal(t, Fingerprint(key), knownHosts[0].Fingerprint)
+	assert.Equal(t, store.List()[0].PinnedAt.Unix(), knownHosts[0].PinnedAt.Unix())
+	assert.Nil(t, reloaded.Verify("192.168.0.1:22", key))
+
+	// a broken file is not ignored silently
+	assert.Nil(t, ioutil.WriteFile(path, []byte("broken line\n"), 0600))
+	_, err = NewKnownHostsStore(path)
+	assert.NotNil(t, err)
+}
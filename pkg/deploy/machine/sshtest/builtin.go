@@ -0,0 +1,240 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// newBuiltins returns the responders of the commands used by the machine to manage the files and
+// the processes, they're run on the root directory of the server.
+func (s *Server) newBuiltins() []*responder {
+	builtins := []struct {
+		pattern string
+		respond func(exec *Exec, args []string) int
+	}{
+		{`^\[ -f (\S+) \] && kill -9 -\$\(cat \S+\); rm -f \S+$`, s.kill},
+		{`^for f in (.*); do if \[ -f "\$f" \]; then `, s.fileStates},
+		{`^mktemp -d (\S+)$`, s.mktemp},
+		{`^rm -r?f (.+)$`, s.remove},
+		{`^install -D -m ([0-7]+) (.+)$`, s.install},
+		{`^cat (.+)$`, s.cat},
+		{`^mkdir -p (.+)$`, s.mkdir},
+		{`^\[ ! -e (\S+) \] \|\| \{ find `, s.listDir},
+	}
+
+	var responders []*responder
+	for _, builtin := range builtins {
+		pattern := regexp.MustCompile(builtin.pattern)
+		respond := builtin.respond
+		responders = append(responders, &responder{
+			pattern: pattern,
+			respond: func(exec *Exec) int {
+				return respond(exec, pattern.FindStringSubmatch(exec.Command)[1:])
+			},
+			builtin: true,
+		})
+	}
+	return responders
+}
+
+func (s *Server) builtins() []*responder {
+	return s.builtinResponders
+}
+
+// Path returns the path on the local filesystem of the path on the server.
+func (s *Server) Path(remotePath string) string {
+	return filepath.Join(s.Root, filepath.Clean("/"+remotePath))
+}
+
+// kill kills the command tracked by the pid file.
+func (s *Server) kill(exec *Exec, args []string) int {
+	s.lock.Lock()
+	cancel, ok := s.running[args[0]]
+	s.lock.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return 0
+}
+
+// fileStates prints "<sha256> <octal mode> <path>" of each existing file.
+func (s *Server) fileStates(exec *Exec, args []string) int {
+	paths, err := splitWords(args[0])
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(s.Path(path))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		content, err := ioutil.ReadFile(s.Path(path))
+		if err != nil {
+			fmt.Fprintln(exec.Stderr, err)
+			return 1
+		}
+
+		sum := sha256.Sum256(content)
+		fmt.Fprintf(exec.Stdout, "%v %o %v\n", hex.EncodeToString(sum[:]), info.Mode().Perm(), path)
+	}
+	return 0
+}
+
+func (s *Server) mktemp(exec *Exec, args []string) int {
+	template := strings.TrimRight(args[0], "X")
+	dir := template + strconv.FormatInt(rand.Int63(), 36)
+
+	if err := os.MkdirAll(s.Path(dir), 0700); err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+	fmt.Fprintln(exec.Stdout, dir)
+	return 0
+}
+
+func (s *Server) remove(exec *Exec, args []string) int {
+	paths, err := splitWords(args[0])
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+
+	for _, path := range paths {
+		if err := os.RemoveAll(s.Path(path)); err != nil {
+			fmt.Fprintln(exec.Stderr, err)
+			return 1
+		}
+	}
+	return 0
+}
+
+func (s *Server) install(exec *Exec, args []string) int {
+	mode, err := strconv.ParseUint(args[0], 8, 32)
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+	paths, err := splitWords(args[1])
+	if err != nil || len(paths) != 2 {
+		fmt.Fprintf(exec.Stderr, "install: invalid arguments: %v\n", args[1])
+		return 1
+	}
+
+	content, err := ioutil.ReadFile(s.Path(paths[0]))
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+	dst := s.Path(paths[1])
+	if err = os.MkdirAll(filepath.Dir(dst), 0755); err == nil {
+		if err = ioutil.WriteFile(dst, content, os.FileMode(mode)); err == nil {
+			err = os.Chmod(dst, os.FileMode(mode))
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func (s *Server) cat(exec *Exec, args []string) int {
+	paths, err := splitWords(args[0])
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+
+	for _, path := range paths {
+		file, err := os.Open(s.Path(path))
+		if err != nil {
+			fmt.Fprintf(exec.Stderr, "cat: %v: No such file or directory\n", path)
+			return 1
+		}
+		io.Copy(exec.Stdout, file)
+		file.Close()
+	}
+	return 0
+}
+
+func (s *Server) mkdir(exec *Exec, args []string) int {
+	paths, err := splitWords(args[0])
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+
+	for _, path := range paths {
+		if err := os.MkdirAll(s.Path(path), 0755); err != nil {
+			fmt.Fprintln(exec.Stderr, err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// listDir prints "d <path>" of each directory and then "f <path>" of each file in the directory recursively.
+func (s *Server) listDir(exec *Exec, args []string) int {
+	paths, err := splitWords(args[0])
+	if err != nil || len(paths) != 1 {
+		fmt.Fprintf(exec.Stderr, "find: invalid arguments: %v\n", args[0])
+		return 1
+	}
+
+	root := s.Path(paths[0])
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return 0
+	}
+
+	var dirs, files []string
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		remotePath := paths[0] + strings.TrimPrefix(path, root)
+		if info.IsDir() {
+			dirs = append(dirs, remotePath)
+		} else {
+			files = append(files, remotePath)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintln(exec.Stderr, err)
+		return 1
+	}
+
+	for _, dir := range dirs {
+		fmt.Fprintf(exec.Stdout, "d %v\n", dir)
+	}
+	for _, file := range files {
+		fmt.Fprintf(exec.Stdout, "f %v\n", file)
+	}
+	return 0
+}
@@ -0,0 +1,276 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshtest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// the wrapper of the machine to track the pid of a command, see machine.trackPid
+	trackPidPattern = regexp.MustCompile(`(?s)^echo \$\$ > (\S+)\n\(\n(.*)\n\)\nrc=\$\?\nrm -f \S+\nexit \$rc$`)
+	// the wrapper of the machine to run a command via sudo, see machine.sudoCommand
+	sudoPattern = regexp.MustCompile(`(?s)^sudo (-k -S -p '' |-n )-- sh -c ('.*')$`)
+)
+
+// Exec is a command run on the server.
+type Exec struct {
+	// Command is the command with the wrappers added by the machine removed, like the pid tracking and sudo.
+	Command string
+	// Sudo is true if the command is run via sudo.
+	Sudo bool
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	ctx context.Context
+}
+
+// Context returns the context of the command, which is done when the command is killed.
+func (e *Exec) Context() context.Context {
+	return e.ctx
+}
+
+// Responder answers the command by writing the output, and returns the exit status.
+type Responder func(exec *Exec) int
+
+// Reply returns the responder answering the command with the output and the exit status.
+func Reply(stdout, stderr string, exitStatus int) Responder {
+	return func(exec *Exec) int {
+		io.WriteString(exec.Stdout, stdout)
+		io.WriteString(exec.Stderr, stderr)
+		return exitStatus
+	}
+}
+
+// Hang returns the responder which never finishes until the command is killed.
+func Hang() Responder {
+	return func(exec *Exec) int {
+		<-exec.Context().Done()
+		return killedExitStatus
+	}
+}
+
+// Record is a command recorded in the transcript.
+type Record struct {
+	Command string
+	Sudo    bool
+	// Builtin is true if the command is answered by the builtin responders of the server,
+	// which are the commands used by the machine to manage the files and the processes.
+	Builtin bool
+}
+
+type responder struct {
+	pattern *regexp.Regexp
+	respond Responder
+	builtin bool
+}
+
+// Handle registers the responder for the commands matching the regular expression. The responders
+// are matched in the registration order, and take precedence over the builtin ones.
+func (s *Server) Handle(pattern string, respond Responder) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.responders = append(s.responders, &responder{pattern: regexp.MustCompile(pattern), respond: respond})
+}
+
+// Transcript returns all the commands run on the server in order.
+func (s *Server) Transcript() []*Record {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]*Record(nil), s.transcript...)
+}
+
+// Commands returns the commands run on the server in order, without the builtin ones.
+func (s *Server) Commands() []string {
+	var commands []string
+	for _, record := range s.Transcript() {
+		if !record.Builtin {
+			commands = append(commands, record.Command)
+		}
+	}
+	return commands
+}
+
+// exec runs the command received and returns the exit status.
+func (s *Server) exec(ctx context.Context, command string, channel ssh.Channel) int {
+	exec := &Exec{
+		Command: command,
+		Stdin:   channel,
+		Stdout:  channel,
+		Stderr:  channel.Stderr(),
+	}
+
+	var pidFile string
+	if match := trackPidPattern.FindStringSubmatch(exec.Command); match != nil {
+		pidFile, exec.Command = match[1], match[2]
+	}
+
+	if match := sudoPattern.FindStringSubmatch(exec.Command); match != nil {
+		words, err := splitWords(match[2])
+		if err != nil || len(words) != 1 {
+			fmt.Fprintf(exec.Stderr, "sshtest: invalid sudo command: %v\n", exec.Command)
+			return 1
+		}
+		exec.Command, exec.Sudo = words[0], true
+
+		if status := s.authorizeSudo(exec, match[1] == "-n "); status != 0 {
+			return status
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exec.ctx = ctx
+
+	s.lock.Lock()
+	responder := s.matchLocked(exec.Command)
+	s.transcript = append(s.transcript, &Record{Command: exec.Command, Sudo: exec.Sudo, Builtin: responder != nil && responder.builtin})
+	if pidFile != "" {
+		s.running[pidFile] = cancel
+	}
+	s.lock.Unlock()
+
+	if pidFile != "" {
+		defer func() {
+			s.lock.Lock()
+			delete(s.running, pidFile)
+			s.lock.Unlock()
+		}()
+	}
+
+	if responder == nil {
+		fmt.Fprintf(exec.Stderr, "sshtest: no responder for command: %v\n", exec.Command)
+		return 127
+	}
+
+	status := responder.respond(exec)
+	if ctx.Err() != nil {
+		return killedExitStatus
+	}
+	return status
+}
+
+// authorizeSudo checks the sudo password fed from stdin, the password line is consumed.
+func (s *Server) authorizeSudo(exec *Exec, nonInteractive bool) int {
+	s.lock.Lock()
+	expected := s.sudoPassword
+	s.lock.Unlock()
+
+	if nonInteractive {
+		if expected != "" {
+			io.WriteString(exec.Stderr, "sudo: a password is required\n")
+			return 1
+		}
+		return 0
+	}
+
+	password, err := readLine(exec.Stdin)
+	if err != nil || (expected != "" && password != expected) {
+		io.WriteString(exec.Stderr, "Sorry, try again.\nsudo: 1 incorrect password attempt\n")
+		return 1
+	}
+	return 0
+}
+
+// readLine reads a line byte by byte, so that the rest of stdin is left to the command.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		if buf[0] == '\n' {
+			return string(line), nil
+		}
+		line = append(line, buf[0])
+	}
+}
+
+func (s *Server) matchLocked(command string) *responder {
+	for _, responder := range s.responders {
+		if responder.pattern.MatchString(command) {
+			return responder
+		}
+	}
+	for _, responder := range s.builtins() {
+		if responder.pattern.MatchString(command) {
+			return responder
+		}
+	}
+	return nil
+}
+
+// splitWords splits the arguments of a command like sh, only the single quotes and the backslashes
+// are supported, which are enough for the commands composed by the machine.
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord, inQuote := false, false
+
+	reader := bufio.NewReader(strings.NewReader(s))
+	for {
+		r, _, err := reader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+
+		switch {
+		case inQuote:
+			if r == '\'' {
+				inQuote = false
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'':
+			inQuote, inWord = true, true
+		case r == '\\':
+			next, _, err := reader.ReadRune()
+			if err != nil {
+				return nil, fmt.Errorf("trailing backslash in %q", s)
+			}
+			word.WriteRune(next)
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
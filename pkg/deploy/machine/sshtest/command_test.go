@@ -0,0 +1,83 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshtest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "'/tmp/a' '/tmp/b c'", want: []string{"/tmp/a", "/tmp/b c"}},
+		{input: `'it'\''s'  plain\ word`, want: []string{"it's", "plain word"}},
+		{input: "''", want: []string{""}},
+		{input: "", want: nil},
+	}
+	for _, test := range tests {
+		words, err := splitWords(test.input)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, words)
+	}
+
+	_, err := splitWords("'unterminated")
+	assert.Error(t, err)
+}
+
+func TestSudoPassword(t *testing.T) {
+	server, err := NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+	server.SetSudoPassword("sudo-password")
+	server.Handle(`^whoami$`, Reply("root\n", "", 0))
+
+	client, err := ssh.Dial("tcp", server.Address(), &ssh.ClientConfig{
+		User:            DefaultUser,
+		Auth:            []ssh.AuthMethod{ssh.Password(DefaultPassword)},
+		HostKeyCallback: ssh.FixedHostKey(server.HostKey),
+	})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	run := func(cmd, stdin string) (string, error) {
+		session, err := client.NewSession()
+		assert.NoError(t, err)
+		defer session.Close()
+
+		var stdout bytes.Buffer
+		session.Stdin = bytes.NewBufferString(stdin)
+		session.Stdout = &stdout
+		err = session.Run(cmd)
+		return stdout.String(), err
+	}
+
+	stdout, err := run(`sudo -k -S -p '' -- sh -c 'whoami'`, "sudo-password\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "root\n", stdout)
+
+	_, err = run(`sudo -k -S -p '' -- sh -c 'whoami'`, "wrong\n")
+	assert.Error(t, err)
+
+	_, err = run(`sudo -n -- sh -c 'whoami'`, "")
+	assert.Error(t, err)
+
+	assert.Equal(t, []*Record{{Command: "whoami", Sudo: true}}, server.Transcript())
+}
@@ -0,0 +1,408 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sshtest provides an in-process ssh server with the sftp subsystem, so that the machines,
+// operations and actions can be tested end to end on a single box without the real nodes.
+package sshtest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const (
+	// DefaultUser and DefaultPassword can login the server by password or keyboard interactive.
+	DefaultUser     = "root"
+	DefaultPassword = "sshtest"
+
+	// the exit status of the commands killed, like a process killed by SIGKILL
+	killedExitStatus = 128 + 9
+)
+
+// Server is an in-process ssh server listening on the loopback interface. The commands run on the
+// server are answered by the responders registered and recorded in the transcript, and the files
+// put via sftp are kept in the directory Root, which is the root of the filesystem seen via ssh.
+type Server struct {
+	// Root is the directory on the local filesystem as "/" of the server.
+	Root string
+	Host string
+	Port uint32
+	// HostKey is the public key presented to the clients.
+	HostKey ssh.PublicKey
+
+	listener net.Listener
+	config   *ssh.ServerConfig
+
+	lock         sync.Mutex
+	passwords    map[string]string
+	keys         map[string][]ssh.PublicKey
+	sudoPassword string
+	responders   []*responder
+	transcript   []*Record
+	// the responders of the commands used by the machine, see newBuiltins
+	builtinResponders []*responder
+	// the cancel functions of the running commands by their pid files
+	running map[string]context.CancelFunc
+	conns   map[*ssh.ServerConn]struct{}
+	closed  bool
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a server with a temporary root directory, which is removed when the server is closed.
+// The host key of the server is pinned in the known hosts store.
+func NewServer() (*Server, error) {
+	root, err := ioutil.TempDir("", "sshtest")
+	if err != nil {
+		return nil, err
+	}
+
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		os.RemoveAll(root)
+		return nil, err
+	}
+	hostKey, err := ssh.NewSignerFromKey(hostPrivateKey)
+	if err != nil {
+		os.RemoveAll(root)
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.RemoveAll(root)
+		return nil, err
+	}
+
+	s := &Server{
+		Root:      root,
+		Host:      "127.0.0.1",
+		Port:      uint32(listener.Addr().(*net.TCPAddr).Port),
+		HostKey:   hostKey.PublicKey(),
+		listener:  listener,
+		passwords: map[string]string{DefaultUser: DefaultPassword},
+		keys:      make(map[string][]ssh.PublicKey),
+		running:   make(map[string]context.CancelFunc),
+		conns:     make(map[*ssh.ServerConn]struct{}),
+	}
+	s.config = &ssh.ServerConfig{
+		PasswordCallback:            s.checkPassword,
+		PublicKeyCallback:           s.checkPublicKey,
+		KeyboardInteractiveCallback: s.checkKeyboardInteractive,
+	}
+	s.config.AddHostKey(hostKey)
+	s.builtinResponders = s.newBuiltins()
+
+	// the port may be used by another server before, whose host key has been pinned
+	if err = mssh.GetKnownHostsStore().Pin(s.Address(), s.HostKey); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Address returns the "host:port" of the server.
+func (s *Server) Address() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(int(s.Port)))
+}
+
+// SetPassword sets the login password of the user, the user can't login by password if it's empty.
+func (s *Server) SetPassword(user, password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if password == "" {
+		delete(s.passwords, user)
+		return
+	}
+	s.passwords[user] = password
+}
+
+// AuthorizeKey allows the user to login with the key.
+func (s *Server) AuthorizeKey(user string, key ssh.PublicKey) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.keys[user] = append(s.keys[user], key)
+}
+
+// SetSudoPassword sets the password required by sudo, sudo requires no password (NOPASSWD) if it's empty.
+func (s *Server) SetSudoPassword(password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sudoPassword = password
+}
+
+// Node returns the node connecting the server as DefaultUser by password.
+func (s *Server) Node(name string) *pb.Node {
+	return &pb.Node{
+		Name: name,
+		Ip:   s.Host,
+		Ssh: &pb.SSH{
+			Port: s.Port,
+			Auth: &pb.Auth{
+				Type:       mssh.AuthTypePassword,
+				Username:   DefaultUser,
+				Credential: DefaultPassword,
+			},
+		},
+	}
+}
+
+// JumpHost returns the jump host connecting the server as DefaultUser by password.
+func (s *Server) JumpHost() *pb.JumpHost {
+	return &pb.JumpHost{
+		Ip:   s.Host,
+		Port: s.Port,
+		Auth: &pb.Auth{
+			Type:       mssh.AuthTypePassword,
+			Username:   DefaultUser,
+			Credential: DefaultPassword,
+		},
+	}
+}
+
+// Close stops the server, closes all the connections and removes the root directory.
+func (s *Server) Close() {
+	s.lock.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	for _, cancel := range s.running {
+		cancel()
+	}
+	s.lock.Unlock()
+
+	s.listener.Close()
+	s.wg.Wait()
+	os.RemoveAll(s.Root)
+}
+
+func (s *Server) checkPassword(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if expected, ok := s.passwords[conn.User()]; ok && expected == string(password) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("password rejected for %v", conn.User())
+}
+
+func (s *Server) checkPublicKey(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, authorized := range s.keys[conn.User()] {
+		if string(authorized.Marshal()) == string(key.Marshal()) {
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown public key for %v", conn.User())
+}
+
+func (s *Server) checkKeyboardInteractive(conn ssh.ConnMetadata, client ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+	answers, err := client(conn.User(), "", []string{"Password: "}, []bool{false})
+	if err != nil {
+		return nil, err
+	}
+	if len(answers) != 1 {
+		return nil, fmt.Errorf("unexpected answers for %v", conn.User())
+	}
+	return s.checkPassword(conn, []byte(answers[0]))
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(conn)
+		}()
+	}
+}
+
+func (s *Server) handleConn(netConn net.Conn) {
+	conn, channels, requests, err := ssh.NewServerConn(netConn, s.config)
+	if err != nil {
+		netConn.Close()
+		return
+	}
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		conn.Close()
+		return
+	}
+	s.conns[conn] = struct{}{}
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		conn.Close()
+	}()
+
+	// the keepalive requests are replied with false like sshd
+	go ssh.DiscardRequests(requests)
+
+	var wg sync.WaitGroup
+	for newChannel := range channels {
+		switch newChannel.ChannelType() {
+		case "session":
+			wg.Add(1)
+			go func(newChannel ssh.NewChannel) {
+				defer wg.Done()
+				s.handleSession(newChannel)
+			}(newChannel)
+		case "direct-tcpip":
+			wg.Add(1)
+			go func(newChannel ssh.NewChannel) {
+				defer wg.Done()
+				handleDirectTCPIP(newChannel)
+			}(newChannel)
+		default:
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+		}
+	}
+	wg.Wait()
+}
+
+func (s *Server) handleSession(newChannel ssh.NewChannel) {
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+
+	done := make(chan struct{})
+	var cancelExec context.CancelFunc
+	for request := range requests {
+		switch request.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(request.Payload, &payload); err != nil || cancelExec != nil {
+				request.Reply(false, nil)
+				continue
+			}
+			request.Reply(true, nil)
+
+			var ctx context.Context
+			ctx, cancelExec = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				status := s.exec(ctx, payload.Command, channel)
+				channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+				channel.Close()
+			}()
+		case "subsystem":
+			var payload struct{ Name string }
+			if err := ssh.Unmarshal(request.Payload, &payload); err != nil || payload.Name != "sftp" || cancelExec != nil {
+				request.Reply(false, nil)
+				continue
+			}
+			request.Reply(true, nil)
+
+			server := sftp.NewRequestServer(channel, s.sftpHandlers())
+			go func() {
+				server.Serve()
+				server.Close()
+			}()
+		case "signal":
+			if cancelExec != nil {
+				cancelExec()
+			}
+			if request.WantReply {
+				request.Reply(true, nil)
+			}
+		default:
+			if request.WantReply {
+				request.Reply(false, nil)
+			}
+		}
+	}
+
+	if cancelExec != nil {
+		// the session is closed by the client
+		cancelExec()
+		<-done
+	}
+}
+
+// handleDirectTCPIP forwards the connection to the target address, like sshd does for the jump hosts.
+func handleDirectTCPIP(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, "invalid payload")
+		return
+	}
+
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	defer target.Close()
+
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+	go ssh.DiscardRequests(requests)
+
+	copied := make(chan struct{}, 2)
+	go func() {
+		io.Copy(target, channel)
+		copied <- struct{}{}
+	}()
+	go func() {
+		io.Copy(channel, target)
+		copied <- struct{}{}
+	}()
+	<-copied
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshtest
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/sftp"
+)
+
+// rootedFS serves the sftp requests on the root directory of the server.
+type rootedFS struct {
+	server *Server
+}
+
+func (s *Server) sftpHandlers() sftp.Handlers {
+	fs := &rootedFS{server: s}
+	return sftp.Handlers{FileGet: fs, FilePut: fs, FileCmd: fs, FileList: fs}
+}
+
+func (fs *rootedFS) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	return os.Open(fs.server.Path(r.Filepath))
+}
+
+func (fs *rootedFS) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	flags := os.O_RDWR
+	pflags := r.Pflags()
+	if pflags.Creat {
+		flags |= os.O_CREATE
+	}
+	if pflags.Trunc {
+		flags |= os.O_TRUNC
+	}
+	if pflags.Excl {
+		flags |= os.O_EXCL
+	}
+
+	return os.OpenFile(fs.server.Path(r.Filepath), flags, 0644)
+}
+
+func (fs *rootedFS) Filecmd(r *sftp.Request) error {
+	path := fs.server.Path(r.Filepath)
+
+	switch r.Method {
+	case "Setstat":
+		attrs, flags := r.Attributes(), r.AttrFlags()
+		if flags.Permissions {
+			if err := os.Chmod(path, os.FileMode(attrs.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+		if flags.Size {
+			return os.Truncate(path, int64(attrs.Size))
+		}
+		return nil
+	case "Rename":
+		return os.Rename(path, fs.server.Path(r.Target))
+	case "Rmdir", "Remove":
+		return os.Remove(path)
+	case "Mkdir":
+		return os.Mkdir(path, 0755)
+	case "Symlink":
+		return os.Symlink(r.Filepath, fs.server.Path(r.Target))
+	}
+
+	return sftp.ErrSshFxOpUnsupported
+}
+
+func (fs *rootedFS) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	path := fs.server.Path(r.Filepath)
+
+	switch r.Method {
+	case "List":
+		infos, err := ioutil.ReadDir(path)
+		return listerAt(infos), err
+	case "Stat":
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return listerAt{info}, nil
+	}
+
+	return nil, sftp.ErrSshFxOpUnsupported
+}
+
+type listerAt []os.FileInfo
+
+func (l listerAt) ListAt(infos []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+
+	n := copy(infos, l[offset:])
+	if n < len(infos) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// the pattern of the directory on the remote machine to stage the files, which are then
+// installed into the privileged paths via sudo.
+const stagingDirPattern = "/tmp/kpaas-staging.XXXXXX"
+
+func (m *Machine) sudoEnabled() bool {
+	return m.Node.GetSsh().GetSudo().GetEnabled()
+}
+
+// escalate wraps the command with sudo if it's enabled, the returned reader is used as the stdin
+// of the command to feed the sudo password.
+func (m *Machine) escalate(cmd string) (string, io.Reader) {
+	return escalateCommand(m.Node, cmd)
+}
+
+func escalateCommand(node *pb.Node, cmd string) (string, io.Reader) {
+	if !node.GetSsh().GetSudo().GetEnabled() {
+		return cmd, nil
+	}
+
+	password := node.GetSsh().GetSudo().GetPassword()
+	if password == "" {
+		return sudoCommand(cmd, false), nil
+	}
+	return sudoCommand(cmd, true), strings.NewReader(password + "\n")
+}
+
+// sudoCommand returns the command run by sh via sudo.
+func sudoCommand(cmd string, withPassword bool) string {
+	if withPassword {
+		// -k makes sudo always read the password from stdin, so that the password is never left to the command
+		return "sudo -k -S -p '' -- sh -c " + shellQuote(cmd)
+	}
+
+	// -n makes sudo fail instead of waiting for a password
+	return "sudo -n -- sh -c " + shellQuote(cmd)
+}
+
+// shellQuote quotes s as a single argument of sh
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// runChecked runs the command and returns an error if the command exits with non-zero code.
+func (m *Machine) runChecked(cmd string, stdin io.Reader) ([]byte, error) {
+	session, closeSession, err := m.conn.newSession(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("unable to get session of machine(%v), error: %v", m.Name, err)
+	}
+	defer closeSession()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err = session.Run(cmd); err != nil {
+		return nil, fmt.Errorf("failed to run cmd(%v) on machine(%v), error: %v, stderr: %s", cmd, m.Name, err, stderr.Bytes())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// sudoRun runs the command via sudo if it's enabled.
+func (m *Machine) sudoRun(cmd string) ([]byte, error) {
+	escalated, stdin := m.escalate(cmd)
+	return m.runChecked(escalated, stdin)
+}
+
+// getStagingDir returns the staging directory, it's created on the first call and removed when the machine is closed.
+func (m *Machine) getStagingDir() (string, error) {
+	m.stagingLock.Lock()
+	defer m.stagingLock.Unlock()
+
+	if m.stagingDir != "" {
+		return m.stagingDir, nil
+	}
+
+	stdout, err := m.runChecked("mktemp -d "+stagingDirPattern, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory, error: %v", err)
+	}
+	m.stagingDir = strings.TrimSpace(string(stdout))
+
+	return m.stagingDir, nil
+}
+
+func (m *Machine) removeStagingDir() {
+	m.stagingLock.Lock()
+	defer m.stagingLock.Unlock()
+
+	if m.stagingDir == "" {
+		return
+	}
+
+	if _, err := m.runChecked("rm -rf "+shellQuote(m.stagingDir), nil); err != nil {
+		logrus.Warnf("failed to remove staging directory %v on %v, error: %v", m.stagingDir, m.Name, err)
+	}
+	m.stagingDir = ""
+}
+
+// putFileWithSudo uploads the content into the staging directory, then installs it to the remote path via sudo.
+func (m *Machine) putFileWithSudo(content io.Reader, remotePath string, mode os.FileMode) error {
+	stagingDir, err := m.getStagingDir()
+	if err != nil {
+		return err
+	}
+
+	m.stagingLock.Lock()
+	m.stagingCount++
+	stagingPath := path.Join(stagingDir, fmt.Sprintf("%d-%v", m.stagingCount, path.Base(remotePath)))
+	m.stagingLock.Unlock()
+
+	sftpClient, err := m.conn.sftpClient()
+	if err != nil {
+		return fmt.Errorf("unable to get sftp client of machine(%v), error: %v", m.Name, err)
+	}
+
+	stagingFile, err := sftpClient.Create(stagingPath)
+	if err != nil {
+		return fmt.Errorf("create staging file %v failed: %v", stagingPath, err)
+	}
+	defer sftpClient.Remove(stagingPath)
+
+	// the staging file is readable by the user only
+	if err = stagingFile.Chmod(0600); err != nil {
+		stagingFile.Close()
+		return fmt.Errorf("chmod staging file %v failed: %v", stagingPath, err)
+	}
+
+	_, err = io.Copy(stagingFile, content)
+	stagingFile.Close()
+	if err != nil {
+		return fmt.Errorf("copy content to staging file %v failed: %v", stagingPath, err)
+	}
+
+	// -D creates the parent directories of the remote path
+	if _, err = m.sudoRun(fmt.Sprintf("install -D -m %04o %v %v", mode.Perm(), shellQuote(stagingPath), shellQuote(remotePath))); err != nil {
+		return fmt.Errorf("install file %v failed: %v", remotePath, err)
+	}
+
+	logrus.Debugf("put file to: %v via sudo", remotePath)
+
+	return nil
+}
+
+// fetchFileWithSudo reads the remote file via sudo, which may be readable by root only.
+func (m *Machine) fetchFileWithSudo(dst io.Writer, remotePath string) error {
+	content, err := m.sudoRun("cat " + shellQuote(remotePath))
+	if err != nil {
+		return fmt.Errorf("read remote file %v failed, error: %v", remotePath, err)
+	}
+
+	if _, err = dst.Write(content); err != nil {
+		return fmt.Errorf("copy from remote file %v failed, error: %v", remotePath, err)
+	}
+
+	logrus.Debugf("fetch file from %s on %s via sudo", remotePath, m.Name)
+
+	return nil
+}
+
+// listDirWithSudo lists the remote directory recursively via sudo, which may be readable by root only.
+// The directories are listed before the files, and nothing is listed if the directory doesn't exist.
+func (m *Machine) listDirWithSudo(remoteDir string) ([]remoteEntry, error) {
+	dir := shellQuote(remoteDir)
+	output, err := m.sudoRun(fmt.Sprintf("[ ! -e %[1]v ] || { find %[1]v -type d | sed 's/^/d /'; find %[1]v ! -type d | sed 's/^/f /'; }", dir))
+	if err != nil {
+		return nil, fmt.Errorf("list remote directory %v failed, error: %v", remoteDir, err)
+	}
+
+	var entries []remoteEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		entries = append(entries, remoteEntry{path: line[2:], isDir: line[0] == 'd'})
+	}
+	return entries, nil
+}
+
+// mkdirAll creates the remote directory, via sudo if it's enabled.
+func (m *Machine) mkdirAll(remoteDir string) error {
+	if m.sudoEnabled() {
+		_, err := m.sudoRun("mkdir -p " + shellQuote(remoteDir))
+		return err
+	}
+
+	sftpClient, err := m.conn.sftpClient()
+	if err != nil {
+		return err
+	}
+
+	return sftpClient.MkdirAll(remoteDir)
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, `'ls -l /root'`, shellQuote("ls -l /root"))
+	assert.Equal(t, `'echo '\''a b'\'''`, shellQuote("echo 'a b'"))
+}
+
+func TestEscalate(t *testing.T) {
+	m := &Machine{Node: &pb.Node{Ssh: &pb.SSH{}}}
+	cmd, stdin := m.escalate("systemctl restart docker")
+	assert.Equal(t, "systemctl restart docker", cmd)
+	assert.Nil(t, stdin)
+
+	m.Node.Ssh.Sudo = &pb.Sudo{Enabled: true}
+	cmd, stdin = m.escalate("systemctl restart docker")
+	assert.Equal(t, "sudo -n -- sh -c 'systemctl restart docker'", cmd)
+	assert.Nil(t, stdin)
+
+	m.Node.Ssh.Sudo.Password = "123456"
+	cmd, stdin = m.escalate("cd /tmp && echo 'done'")
+	assert.Equal(t, `sudo -k -S -p '' -- sh -c 'cd /tmp && echo '\''done'\'''`, cmd)
+	password, err := ioutil.ReadAll(stdin)
+	assert.Nil(t, err)
+	assert.Equal(t, "123456\n", string(password))
+}
@@ -0,0 +1,277 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// the mode of the files put without an explicit mode
+	defaultFileMode os.FileMode = 0644
+
+	// the max number of the files uploaded to a machine at the same time
+	maxParallelUploads = 4
+)
+
+// File is the content to be put to the path on the machine.
+type File struct {
+	Path    string
+	Content []byte
+	// Mode is the permission of the file, defaultFileMode is used if it's zero.
+	Mode os.FileMode
+}
+
+func (f *File) mode() os.FileMode {
+	if f.Mode == 0 {
+		return defaultFileMode
+	}
+	return f.Mode.Perm()
+}
+
+func (f *File) checksum() string {
+	sum := sha256.Sum256(f.Content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ChecksumMismatchError is returned if the checksum of the uploaded file doesn't match the content.
+type ChecksumMismatchError struct {
+	Machine  string
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum of %v:%v mismatched, expected: %v, actual: %v", e.Machine, e.Path, e.Expected, e.Actual)
+}
+
+// ModeMismatchError is returned if the mode of the uploaded file doesn't match the expected one.
+type ModeMismatchError struct {
+	Machine  string
+	Path     string
+	Expected os.FileMode
+	Actual   os.FileMode
+}
+
+func (e *ModeMismatchError) Error() string {
+	return fmt.Sprintf("mode of %v:%v mismatched, expected: %04o, actual: %04o", e.Machine, e.Path, e.Expected, e.Actual)
+}
+
+// pathLock is the lock of a path on a machine, it's released from the pathLocks once no one holds it.
+type pathLock struct {
+	sync.Mutex
+	refs int
+}
+
+var (
+	pathLocksLock sync.Mutex
+	// the locks of the paths being put by machine name and path
+	pathLocks = make(map[string]*pathLock)
+)
+
+// lockPaths locks the paths of the files on the machine, so that the operations putting the same
+// files, like the shared scripts, don't overwrite the files while the others are verifying them.
+// The paths are locked in order to avoid the deadlock, and the returned function unlocks them.
+func lockPaths(m IMachine, files []*File) (unlock func()) {
+	keySet := make(map[string]bool)
+	for _, file := range files {
+		keySet[m.GetName()+":"+file.Path] = true
+	}
+	var keys []string
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var locks []*pathLock
+	for _, key := range keys {
+		pathLocksLock.Lock()
+		lock, ok := pathLocks[key]
+		if !ok {
+			lock = new(pathLock)
+			pathLocks[key] = lock
+		}
+		lock.refs++
+		pathLocksLock.Unlock()
+
+		lock.Lock()
+		locks = append(locks, lock)
+	}
+
+	return func() {
+		for i, lock := range locks {
+			lock.Unlock()
+
+			pathLocksLock.Lock()
+			lock.refs--
+			if lock.refs == 0 {
+				delete(pathLocks, keys[i])
+			}
+			pathLocksLock.Unlock()
+		}
+	}
+}
+
+// remoteFileState is the checksum and the mode of a file on the machine.
+type remoteFileState struct {
+	checksum string
+	mode     os.FileMode
+}
+
+// putFiles puts the files to the machine in parallel. A file is skipped if the remote copy has the
+// same checksum and mode already, otherwise it's put by the put function and verified after transfer.
+// The paths are locked until the files are verified, since the same files may be put concurrently.
+func putFiles(m IMachine, files []*File, put func(content io.Reader, remotePath string, mode os.FileMode) error) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	unlock := lockPaths(m, files)
+	defer unlock()
+
+	states, err := statRemoteFiles(m, files)
+	if err != nil {
+		return err
+	}
+
+	var changed []*File
+	for _, file := range files {
+		if state, ok := states[file.Path]; ok && state.checksum == file.checksum() && state.mode == file.mode() {
+			logrus.Debugf("skip putting %v to %v, the remote copy is up to date", file.Path, m.GetName())
+			continue
+		}
+		changed = append(changed, file)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(changed))
+	sem := make(chan struct{}, maxParallelUploads)
+	wg := sync.WaitGroup{}
+	for i, file := range changed {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, file *File) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = put(bytes.NewReader(file.Content), file.Path, file.mode())
+		}(i, file)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return verifyRemoteFiles(m, changed)
+}
+
+// verifyRemoteFiles checks the checksums and the modes of the files uploaded to the machine.
+func verifyRemoteFiles(m IMachine, files []*File) error {
+	states, err := statRemoteFiles(m, files)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		state, ok := states[file.Path]
+		if !ok {
+			state = new(remoteFileState)
+		}
+		expected := file.checksum()
+		if state.checksum != expected {
+			return &ChecksumMismatchError{Machine: m.GetName(), Path: file.Path, Expected: expected, Actual: state.checksum}
+		}
+		if state.mode != file.mode() {
+			return &ModeMismatchError{Machine: m.GetName(), Path: file.Path, Expected: file.mode(), Actual: state.mode}
+		}
+	}
+
+	logrus.Debugf("%v files put to %v and verified", len(files), m.GetName())
+	return nil
+}
+
+// statRemoteFiles returns the states of the files on the machine by path, the files not exist are absent.
+func statRemoteFiles(m IMachine, files []*File) (map[string]*remoteFileState, error) {
+	stdout, stderr, err := m.Run(statFilesCommand(files))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checksums of files on machine(%v), error: %v", m.GetName(), err)
+	}
+	if len(stderr) > 0 {
+		return nil, fmt.Errorf("failed to get checksums of files on machine(%v), stderr: %s", m.GetName(), stderr)
+	}
+
+	return parseFileStates(stdout)
+}
+
+// statFilesCommand returns the command to print "<sha256> <octal mode> <path>" of each existing file.
+func statFilesCommand(files []*File) string {
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, shellQuote(file.Path))
+	}
+
+	return fmt.Sprintf(`for f in %v; do if [ -f "$f" ]; then `+
+		`echo "$(sha256sum "$f" | cut -d ' ' -f 1) $(stat -c %%a "$f") $f"; fi; done`, strings.Join(paths, " "))
+}
+
+func parseFileStates(output []byte) (map[string]*remoteFileState, error) {
+	states := make(map[string]*remoteFileState)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected file state: %q", line)
+		}
+		mode, err := strconv.ParseUint(fields[1], 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected file mode: %q, error: %v", line, err)
+		}
+
+		states[fields[2]] = &remoteFileState{checksum: fields[0], mode: os.FileMode(mode)}
+	}
+
+	return states, nil
+}
+
+// PutFiles puts the files to the machine, see putFiles for details.
+func (m *Machine) PutFiles(files ...*File) error {
+	return putFiles(m, files, m.putFile)
+}
+
+// PutFiles puts the files on the local filesystem, see putFiles for details.
+func (m *LocalMachine) PutFiles(files ...*File) error {
+	return putFiles(m, files, m.putFile)
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPutFiles(t *testing.T) {
+	m := newTestLocalMachine(t)
+	defer m.Close()
+
+	dir, err := ioutil.TempDir("", "put-files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := []*File{
+		{Path: filepath.Join(dir, "pki", "ca.crt"), Content: []byte("cert")},
+		{Path: filepath.Join(dir, "pki", "ca.key"), Content: []byte("key"), Mode: 0600},
+		{Path: filepath.Join(dir, "scripts", "lib.sh"), Content: []byte("#!/bin/bash"), Mode: 0755},
+	}
+
+	var puts int32
+	countingPut := func(content io.Reader, remotePath string, mode os.FileMode) error {
+		atomic.AddInt32(&puts, 1)
+		return m.putFile(content, remotePath, mode)
+	}
+
+	assert.NoError(t, putFiles(m, files, countingPut))
+	assert.Equal(t, int32(3), puts)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, file.Content, content)
+
+		info, err := os.Stat(file.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, file.mode(), info.Mode().Perm())
+	}
+
+	// the files up to date are skipped
+	assert.NoError(t, putFiles(m, files, countingPut))
+	assert.Equal(t, int32(3), puts)
+
+	// the file is put again if the content or the mode changed
+	assert.NoError(t, os.Chmod(files[1].Path, 0644))
+	assert.NoError(t, ioutil.WriteFile(files[2].Path, []byte("changed"), 0755))
+	assert.NoError(t, putFiles(m, files, countingPut))
+	assert.Equal(t, int32(5), puts)
+	info, err := os.Stat(files[1].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	content, err := ioutil.ReadFile(files[2].Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/bash", string(content))
+}
+
+func TestPutFilesChecksumMismatched(t *testing.T) {
+	m := newTestLocalMachine(t)
+	defer m.Close()
+
+	dir, err := ioutil.TempDir("", "put-files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := &File{Path: filepath.Join(dir, "ca.crt"), Content: []byte("cert")}
+	corruptedPut := func(content io.Reader, remotePath string, mode os.FileMode) error {
+		return m.putFile(strings.NewReader("corrupted"), remotePath, mode)
+	}
+
+	err = putFiles(m, []*File{file}, corruptedPut)
+	var mismatchErr *ChecksumMismatchError
+	assert.True(t, errors.As(err, &mismatchErr))
+	assert.Equal(t, file.Path, mismatchErr.Path)
+	assert.Equal(t, file.checksum(), mismatchErr.Expected)
+	assert.NotEqual(t, mismatchErr.Expected, mismatchErr.Actual)
+}
+
+func TestPutFilesModeMismatched(t *testing.T) {
+	m := newTestLocalMachine(t)
+	defer m.Close()
+
+	dir, err := ioutil.TempDir("", "put-files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := &File{Path: filepath.Join(dir, "lib.sh"), Content: []byte("#!/bin/bash"), Mode: 0755}
+	modeIgnoredPut := func(content io.Reader, remotePath string, mode os.FileMode) error {
+		return m.putFile(content, remotePath, 0644)
+	}
+
+	err = putFiles(m, []*File{file}, modeIgnoredPut)
+	var mismatchErr *ModeMismatchError
+	assert.True(t, errors.As(err, &mismatchErr))
+	assert.Equal(t, file.Path, mismatchErr.Path)
+	assert.Equal(t, os.FileMode(0755), mismatchErr.Expected)
+	assert.Equal(t, os.FileMode(0644), mismatchErr.Actual)
+}
+
+func TestPutFilesConcurrently(t *testing.T) {
+	m := newTestLocalMachine(t)
+	defer m.Close()
+
+	dir, err := ioutil.TempDir("", "put-files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the shared files are put by several operations at the same time
+	files := []*File{
+		{Path: filepath.Join(dir, "scripts", "lib.sh"), Content: []byte("#!/bin/bash"), Mode: 0755},
+		{Path: filepath.Join(dir, "scripts", "haproxy.sh"), Content: []byte("#!/bin/bash\nhaproxy"), Mode: 0755},
+	}
+	// the put truncates the file first and writes the content a while later
+	slowPut := func(content io.Reader, remotePath string, mode os.FileMode) error {
+		if err := m.putFile(strings.NewReader(""), remotePath, mode); err != nil {
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+		return m.putFile(content, remotePath, mode)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// each operation puts the files in its own order
+			ordered := []*File{files[i%2], files[(i+1)%2]}
+			errs[i] = putFiles(m, ordered, slowPut)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Empty(t, pathLocks)
+}
+
+func TestParseFileStates(t *testing.T) {
+	states, err := parseFileStates([]byte("abc 644 /tmp/a b.sh\ndef 600 /tmp/c.key\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*remoteFileState{
+		"/tmp/a b.sh": {checksum: "abc", mode: 0644},
+		"/tmp/c.key":  {checksum: "def", mode: 0600},
+	}, states)
+
+	states, err = parseFileStates(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, states)
+
+	_, err = parseFileStates([]byte("abc rw /tmp/a"))
+	assert.Error(t, err)
+}
@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kpaas-io/kpaas/pkg/deploy/assets"
 	"github.com/kpaas-io/kpaas/pkg/deploy/command"
 	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
@@ -45,13 +44,8 @@ func (ckops *CheckPortOccupiedOperation) RunCommands(config *pb.NodeCheckConfig)
 		defer m.Close()
 	}
 
-	scriptFile, err := assets.Assets.Open(portOccupiedScript)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, checkRemoteScriptPath+portOccupiedScript); err != nil {
+	// put the script to machine
+	if err := operation.PutAssets(m, checkRemoteScriptPath, portOccupiedScript); err != nil {
 		return nil, nil, err
 	}
 
@@ -15,7 +15,6 @@
 package check
 
 import (
-	"github.com/kpaas-io/kpaas/pkg/deploy/assets"
 	"github.com/kpaas-io/kpaas/pkg/deploy/command"
 	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
@@ -42,13 +41,8 @@ func (ckops *CheckSysPrefOperation) RunCommands(config *pb.NodeCheckConfig) (std
 		defer m.Close()
 	}
 
-	scriptFile, err := assets.Assets.Open(sysPrefScript)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, checkRemoteScriptPath+sysPrefScript); err != nil {
+	// put the script to machine
+	if err := operation.PutAssets(m, checkRemoteScriptPath, sysPrefScript); err != nil {
 		return nil, nil, err
 	}
 
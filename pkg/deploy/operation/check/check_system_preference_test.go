@@ -0,0 +1,66 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/assets"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine/sshtest"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func TestCheckSysPrefOperation(t *testing.T) {
+	server, err := sshtest.NewServer()
+	assert.NoError(t, err)
+	defer server.Close()
+	server.Handle(`^bash /tmp/scripts/check_system_preference.sh$`, sshtest.Reply("", "", 0))
+
+	ckops := &CheckSysPrefOperation{}
+	_, _, err = ckops.RunCommands(&pb.NodeCheckConfig{Node: server.Node("node1")})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bash /tmp/scripts/check_system_preference.sh"}, server.Commands())
+
+	// the script is put to the node
+	scriptFile, err := assets.Assets.Open(sysPrefScript)
+	assert.NoError(t, err)
+	defer scriptFile.Close()
+	script, err := ioutil.ReadAll(scriptFile)
+	assert.NoError(t, err)
+
+	put, err := ioutil.ReadFile(server.Path(checkRemoteScriptPath + sysPrefScript))
+	assert.NoError(t, err)
+	assert.Equal(t, script, put)
+	info, err := os.Stat(server.Path(checkRemoteScriptPath + sysPrefScript))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	// the script up to date is not put again
+	ckops = &CheckSysPrefOperation{}
+	_, _, err = ckops.RunCommands(&pb.NodeCheckConfig{Node: server.Node("node1")})
+	assert.NoError(t, err)
+	var checksums int
+	for _, record := range server.Transcript() {
+		if record.Builtin {
+			checksums++
+		}
+	}
+	// twice for the first put with the verification, once for the second put
+	assert.Equal(t, 3, checksums)
+}
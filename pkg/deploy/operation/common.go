@@ -16,10 +16,13 @@ package operation
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/sirupsen/logrus"
@@ -29,9 +32,11 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/kpaas-io/kpaas/pkg/deploy/assets"
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
 	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/retry"
 	"github.com/kpaas-io/kpaas/pkg/utils/idcreator"
 )
 
@@ -55,12 +60,44 @@ const (
 
 const (
 	InitRemoteScriptPath = "/tmp"
+
+	// the mode of the scripts put to the machine
+	scriptFileMode os.FileMode = 0755
 )
 
+// PackageRetryPolicy retries the commands installing packages from the mirrors, which may fail
+// because of the mirror hiccups or the lock held by the other package manager.
+var PackageRetryPolicy = &retry.Policy{
+	MaxAttempts:    3,
+	InitialBackoff: 10 * time.Second,
+	MaxBackoff:     time.Minute,
+	Retryable:      retry.IsTransient,
+	RetryableOutputs: []string{
+		`Failed to fetch`,
+		`Temporary failure resolving`,
+		`Could not resolve host`,
+		`Could not get lock`,
+		`Cannot find a valid baseurl`,
+		`Connection timed out`,
+	},
+}
+
 type NodeInitAction struct {
 	NodeInitConfig *pb.NodeDeployConfig
 	NodesConfig    []*pb.NodeDeployConfig
 	ClusterConfig  *pb.ClusterConfig
+	// the output of the long running init scripts is streamed into the writer
+	ExecuteLogWriter io.Writer
+	// MachineFactory creates the machines of the nodes, machine.NewMachine is used if it's nil.
+	MachineFactory machine.Factory
+}
+
+// NewMachine creates the machine of the node by the machine factory of the action.
+func (a *NodeInitAction) NewMachine(node *pb.Node) (machine.IMachine, error) {
+	if a.MachineFactory != nil {
+		return a.MachineFactory(node)
+	}
+	return machine.NewMachine(node)
 }
 
 // check if version is satisfied with standard version
@@ -240,6 +277,35 @@ func CheckIPValid(rawIP string) bool {
 	return false
 }
 
+// PutAssets puts the assets, like the scripts, under the remote directory with their asset paths.
+// The assets which are up to date on the machine are skipped, so that the scripts shared by the
+// init items are uploaded once.
+func PutAssets(m machine.IMachine, remoteDir string, assetPaths ...string) error {
+	files := make([]*machine.File, 0, len(assetPaths))
+	for _, assetPath := range assetPaths {
+		content, err := readAsset(assetPath)
+		if err != nil {
+			return err
+		}
+		files = append(files, &machine.File{Path: remoteDir + assetPath, Content: content, Mode: scriptFileMode})
+	}
+
+	if err := m.PutFiles(files...); err != nil {
+		return fmt.Errorf("failed to put assets to machine %v, error: %w", m.GetName(), err)
+	}
+	return nil
+}
+
+func readAsset(assetPath string) ([]byte, error) {
+	file, err := assets.Assets.Open(assetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ioutil.ReadAll(file)
+}
+
 func AlreadyJoined(hostname string, masterNode *pb.Node) (bool, error) {
 	clientset, err := GetKubeClient(masterNode)
 	if err != nil {
@@ -15,11 +15,11 @@
 package etcd
 
 import (
-	"bytes"
 	"context"
 	"crypto"
 	"crypto/x509"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -63,18 +63,27 @@ const (
 	defaultEtcdServerKeyPath  = defautEtcdPKIDir + "/" + defaultEtcdServerKeyName
 	defaultEtcdPeerCertPath   = defautEtcdPKIDir + "/" + defaultEtcdPeerCertName
 	defaultEtcdPeerKeyPath    = defautEtcdPKIDir + "/" + defaultEtcdPeerKeyName
+
+	// the modes of the certs and keys put to the machine, the keys are readable by root only
+	CertFileMode os.FileMode = 0644
+	KeyFileMode  os.FileMode = 0600
 )
 
 type DeployEtcdOperationConfig struct {
+	// the commands are killed once the context is cancelled, context.Background() is used if nil
+	Context      context.Context
 	Logger       *logrus.Entry
 	CACrt        *x509.Certificate
 	CAKey        crypto.Signer
 	Node         *pb.Node
 	ClusterNodes []*pb.Node
+	// the machine of the node, it's created if nil
+	Machine machine.IMachine
 }
 
 type deployEtcdOperation struct {
 	operation.BaseOperation
+	ctx                             context.Context
 	logger                          *logrus.Entry
 	caCrt                           *x509.Certificate
 	caKey                           crypto.Signer
@@ -86,17 +95,23 @@ type deployEtcdOperation struct {
 
 func NewDeployEtcdOperation(config *DeployEtcdOperationConfig) (*deployEtcdOperation, error) {
 	ops := &deployEtcdOperation{
+		ctx:          config.Context,
 		logger:       config.Logger,
 		caCrt:        config.CACrt,
 		caKey:        config.CAKey,
 		clusterNodes: config.ClusterNodes,
 	}
-	m, err := machine.NewMachine(config.Node)
-	if err != nil {
-		return nil, err
+	if ops.ctx == nil {
+		ops.ctx = context.Background()
+	}
+	ops.machine = config.Machine
+	if ops.machine == nil {
+		m, err := machine.NewMachine(config.Node)
+		if err != nil {
+			return nil, err
+		}
+		ops.machine = m
 	}
-
-	ops.machine = m
 	//ops.AddCommands(command.NewShellCommand(m, "bash", "/tmp/scripts/checkdocker.sh", nil))
 	return ops, nil
 }
@@ -119,7 +134,7 @@ func (d *deployEtcdOperation) removeExistEtcdContainer() error {
 			"-q",
 			"--filter",
 			filterArg,
-		),
+		).WithContext(d.ctx),
 	)
 
 	stdOut, stdErr, err := d.BaseOperation.Do()
@@ -145,7 +160,7 @@ func (d *deployEtcdOperation) removeExistEtcdContainer() error {
 			"rm",
 			"-f",
 			containerID,
-		),
+		).WithContext(d.ctx),
 	)
 
 	stdOut, stdErr, err = d.BaseOperation.Do()
@@ -173,13 +188,6 @@ func (d *deployEtcdOperation) PreDo() (err error) {
 		return fmt.Errorf("failed to convert key and cert to byte, error: %v", err)
 	}
 
-	if err := d.machine.PutFile(bytes.NewReader(encodedCACert), DefaultEtcdCACertPath); err != nil {
-		return fmt.Errorf("failed to put ca cert to:%v, error: %v", d.machine.GetName(), err)
-	}
-	if err := d.machine.PutFile(bytes.NewReader(encodedCAKey), defaultEtcdCAKeyPath); err != nil {
-		return fmt.Errorf("failed to put ca key to:%v, error: %v", d.machine.GetName(), err)
-	}
-
 	// put server cert and key to the etcd node
 	config, err := GetServerCrtConfig(d.machine.GetName(), d.machine.GetIp())
 	if err != nil {
@@ -190,13 +198,6 @@ func (d *deployEtcdOperation) PreDo() (err error) {
 		return fmt.Errorf("failed to generation etcd server key and cert for etcd node:%v, error: %v", d.machine.GetName(), err)
 	}
 
-	if err := d.machine.PutFile(bytes.NewReader(encodedServerCert), defaultEtcdServerCertPath); err != nil {
-		return fmt.Errorf("failed to put etcd server cert to:%v, error: %v", d.machine.GetName(), err)
-	}
-	if err := d.machine.PutFile(bytes.NewReader(encodedServerKey), defaultEtcdServerKeyPath); err != nil {
-		return fmt.Errorf("failed to put etcd server key to:%v, error: %v", d.machine.GetName(), err)
-	}
-
 	// put peer cert and key to the etcd node
 	config, err = GetPeerCrtConfig(d.machine.GetName(), d.machine.GetIp())
 	if err != nil {
@@ -211,11 +212,15 @@ func (d *deployEtcdOperation) PreDo() (err error) {
 	d.encodedPeerCert = encodedPeerCert
 	d.encodedPeerKey = encodedPeerKey
 
-	if err := d.machine.PutFile(bytes.NewReader(encodedPeerCert), defaultEtcdPeerCertPath); err != nil {
-		return fmt.Errorf("failed to put etcd peer cert to:%v, error: %v", d.machine.GetName(), err)
-	}
-	if err := d.machine.PutFile(bytes.NewReader(encodedPeerKey), defaultEtcdPeerKeyPath); err != nil {
-		return fmt.Errorf("failed to put etcd peer key to:%v, error: %v", d.machine.GetName(), err)
+	if err := d.machine.PutFiles(
+		&machine.File{Path: DefaultEtcdCACertPath, Content: encodedCACert, Mode: CertFileMode},
+		&machine.File{Path: defaultEtcdCAKeyPath, Content: encodedCAKey, Mode: KeyFileMode},
+		&machine.File{Path: defaultEtcdServerCertPath, Content: encodedServerCert, Mode: CertFileMode},
+		&machine.File{Path: defaultEtcdServerKeyPath, Content: encodedServerKey, Mode: KeyFileMode},
+		&machine.File{Path: defaultEtcdPeerCertPath, Content: encodedPeerCert, Mode: CertFileMode},
+		&machine.File{Path: defaultEtcdPeerKeyPath, Content: encodedPeerKey, Mode: KeyFileMode},
+	); err != nil {
+		return fmt.Errorf("failed to put etcd certs and keys to:%v, error: %v", d.machine.GetName(), err)
 	}
 
 	return nil
@@ -275,7 +280,7 @@ func (d *deployEtcdOperation) composeEtcdDockerCmd() {
 			nameArg,
 			defaultEtcdImageUrl,
 			strings.Join(cmd, " "),
-		),
+		).WithContext(d.ctx),
 	)
 }
 
@@ -288,7 +293,10 @@ func (d *deployEtcdOperation) Do() error {
 	peerCert, peerKey, peerErr := FetchEtcdCertAndKey(d.machine.GetNode(), "peer")
 	encodedPeerKey, encodedPeerCert, toByteErr := ToByte(peerCert, peerKey)
 
-	if caErr == nil && peerErr == nil && toByteErr == nil {
+	// the etcd cluster can't be reached if nothing is run on the machine actually
+	simulated := machine.IsSimulated(d.machine)
+
+	if caErr == nil && peerErr == nil && toByteErr == nil && !simulated {
 		d.caCrt, d.caKey, d.encodedPeerCert, d.encodedPeerKey = etcdCACrt, etcdCAKey, encodedPeerCert, encodedPeerKey
 
 		if err := etcdUpAndRunning(d); err == nil {
@@ -319,8 +327,7 @@ func (d *deployEtcdOperation) Do() error {
 
 	d.logger.Debugf("exec command: %#v done, %s, %s, %v", d.Commands, stdOut, stdErr, err)
 
-	_, needPostDo := d.machine.(*machine.Machine)
-	if needPostDo {
+	if !simulated {
 		// post do
 		if err := d.PostDo(); err != nil {
 			d.logger.Errorf("post do error:%v", err)
@@ -333,6 +340,21 @@ func (d *deployEtcdOperation) Do() error {
 	return nil
 }
 
+// Plan puts the certs and runs the commands of deploying etcd on the machine, which is supposed to be
+// a planning one. The existing etcd cluster is not checked, and the cluster is not waited to be ready.
+func (d *deployEtcdOperation) Plan() error {
+	defer d.machine.Close()
+
+	if err := d.PreDo(); err != nil {
+		return err
+	}
+
+	d.composeEtcdDockerCmd()
+
+	_, _, err := d.BaseOperation.Do()
+	return err
+}
+
 func (d *deployEtcdOperation) PostDo() error {
 	deadline := time.Now().Add(defaultEtcdClusterReadyTimeout)
 	for retries := 0; time.Now().Before(deadline); retries++ {
@@ -342,7 +364,11 @@ func (d *deployEtcdOperation) PostDo() error {
 		}
 
 		d.logger.Warnf("etd cluster not ready, error: %v, will retry", err)
-		time.Sleep(time.Second << uint(retries))
+		select {
+		case <-time.After(time.Second << uint(retries)):
+		case <-d.ctx.Done():
+			return d.ctx.Err()
+		}
 	}
 
 	return fmt.Errorf("wait for etcd cluster ready timeout after:%v", defaultEtcdClusterReadyTimeout)
@@ -356,7 +382,7 @@ func etcdUpAndRunning(d *deployEtcdOperation) error {
 	}
 	defer cli.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	ctx, cancel := context.WithTimeout(d.ctx, time.Second*10)
 	defer cancel()
 
 	resp, err := cli.MemberList(ctx)
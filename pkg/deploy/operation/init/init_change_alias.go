@@ -15,9 +15,7 @@
 package init
 
 import (
-	"github.com/kpaas-io/kpaas/pkg/deploy/assets"
 	"github.com/kpaas-io/kpaas/pkg/deploy/command"
-	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
@@ -33,7 +31,7 @@ type InitHostaliasOperation struct {
 
 func (itOps *InitHostaliasOperation) RunCommands(node *pb.Node, initAction *operation.NodeInitAction) (stdOut, stdErr []byte, err error) {
 
-	m, err := machine.NewMachine(node)
+	m, err := initAction.NewMachine(node)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -45,13 +43,8 @@ func (itOps *InitHostaliasOperation) RunCommands(node *pb.Node, initAction *oper
 		defer m.Close()
 	}
 
-	scriptFile, err := assets.Assets.Open(hostAliasScript)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, operation.InitRemoteScriptPath+hostAliasScript); err != nil {
+	// put the script to machine
+	if err := operation.PutAssets(m, operation.InitRemoteScriptPath, hostAliasScript); err != nil {
 		return nil, nil, err
 	}
 
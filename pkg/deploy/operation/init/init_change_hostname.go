@@ -18,7 +18,6 @@ import (
 	"fmt"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy/command"
-	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
@@ -30,7 +29,7 @@ type InitHostNameOperation struct {
 
 func (itOps *InitHostNameOperation) RunCommands(node *pb.Node, initAction *operation.NodeInitAction) (stdOut, stdErr []byte, err error) {
 
-	m, err := machine.NewMachine(node)
+	m, err := initAction.NewMachine(node)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -20,9 +20,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
-	"github.com/kpaas-io/kpaas/pkg/deploy/assets"
 	"github.com/kpaas-io/kpaas/pkg/deploy/command"
-	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
@@ -63,7 +61,7 @@ type InitHaproxyOperation struct {
 
 func (itOps *InitHaproxyOperation) RunCommands(node *pb.Node, initAction *operation.NodeInitAction) (stdOut, stdErr []byte, err error) {
 
-	m, err := machine.NewMachine(node)
+	m, err := initAction.NewMachine(node)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -86,47 +84,9 @@ func (itOps *InitHaproxyOperation) RunCommands(node *pb.Node, initAction *operat
 		return nil, nil, err
 	}
 
-	// put setup.sh to machine
-	scriptFile, err := assets.Assets.Open(haproxyScript)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, operation.InitRemoteScriptPath+haproxyScript); err != nil {
-		return nil, nil, err
-	}
-
-	// put docker.sh to machine
-	scriptFile, err = assets.Assets.Open(HaDockerFilePath)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, operation.InitRemoteScriptPath+HaDockerFilePath); err != nil {
-		return nil, nil, err
-	}
-
-	// put lib.sh to machine
-	scriptFile, err = assets.Assets.Open(HaLibFilePath)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, operation.InitRemoteScriptPath+HaLibFilePath); err != nil {
-		return nil, nil, err
-	}
-
-	// put systemd.sh to machine
-	scriptFile, err = assets.Assets.Open(HaSystemdFilePath)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, operation.InitRemoteScriptPath+HaSystemdFilePath); err != nil {
+	// put the scripts to machine
+	if err := operation.PutAssets(m, operation.InitRemoteScriptPath,
+		haproxyScript, HaDockerFilePath, HaLibFilePath, HaSystemdFilePath); err != nil {
 		return nil, nil, err
 	}
 
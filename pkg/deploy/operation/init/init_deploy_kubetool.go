@@ -17,18 +17,20 @@ package init
 import (
 	"fmt"
 	"net"
+	"time"
 
 	"k8s.io/kubernetes/pkg/registry/core/service/ipallocator"
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
-	"github.com/kpaas-io/kpaas/pkg/deploy/assets"
 	"github.com/kpaas-io/kpaas/pkg/deploy/command"
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
-	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/operation"
 	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
 )
 
+// the timeout of installing the kube tools, the script may hang on downloading the packages
+const kubeToolTimeout = 30 * time.Minute
+
 type InitKubeToolOperation struct {
 	operation.BaseOperation
 	NodeInitAction *operation.NodeInitAction
@@ -49,7 +51,7 @@ func (itOps *InitKubeToolOperation) RunCommands(node *pb.Node, initAction *opera
 	// we would use initAction's image repository in the future
 	imageRepository = fmt.Sprintf("--image-repository %v", constant.DefaultImageRepository)
 
-	m, err := machine.NewMachine(node)
+	m, err := initAction.NewMachine(node)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -61,35 +63,29 @@ func (itOps *InitKubeToolOperation) RunCommands(node *pb.Node, initAction *opera
 		defer m.Close()
 	}
 
-	// copy init_deploy_kubetool.sh to target machine
-	scriptFile, err := assets.Assets.Open(consts.DefaultKubeToolScript)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, operation.InitRemoteScriptPath+consts.DefaultKubeToolScript); err != nil {
-		return nil, nil, err
-	}
-
-	// copy commmon lib.sh to target machine
-	scriptFile, err = assets.Assets.Open(DefaultCommonLibPath)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer scriptFile.Close()
-
-	if err := m.PutFile(scriptFile, operation.InitRemoteScriptPath+DefaultCommonLibPath); err != nil {
+	// put the scripts to machine
+	if err := operation.PutAssets(m, operation.InitRemoteScriptPath,
+		consts.DefaultKubeToolScript, DefaultCommonLibPath); err != nil {
 		return nil, nil, err
 	}
 
 	// setup repos
 	itOps.AddCommands(command.NewShellCommand(m, "bash", fmt.Sprintf("%v setup repos %v", operation.InitRemoteScriptPath+consts.DefaultKubeToolScript,
-		pkgMirrorUrl)))
+		pkgMirrorUrl)).
+		WithDescription("setup repos").
+		WithTimeout(kubeToolTimeout).
+		WithRetry(operation.PackageRetryPolicy).
+		WithExecuteLogWriter(initAction.ExecuteLogWriter).
+		WithStreamingLog())
 
 	// install kubelet, kubeadm, kubectl
 	itOps.AddCommands(command.NewShellCommand(m, "bash", fmt.Sprintf("%v setup kubelet %v %v %v %v", operation.InitRemoteScriptPath+consts.DefaultKubeToolScript,
-		kubernetesVersion, imageRepository, clusterDNSIP, nodeIp)))
+		kubernetesVersion, imageRepository, clusterDNSIP, nodeIp)).
+		WithDescription("install kubelet, kubeadm and kubectl").
+		WithTimeout(kubeToolTimeout).
+		WithRetry(operation.PackageRetryPolicy).
+		WithExecuteLogWriter(initAction.ExecuteLogWriter).
+		WithStreamingLog())
 
 	// run commands
 	stdOut, stdErr, err = itOps.Do()
@@ -15,9 +15,10 @@
 package master
 
 import (
-	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -37,6 +38,7 @@ import (
 
 const (
 	defaultControlPlaneReadyTimeout    = 5 * time.Minute
+	defaultKubeadmTimeout              = 15 * time.Minute
 	kubeadmConfigFileName              = "kubeadm_config.yaml"
 	kubeadmConfigPath                  = consts.DefaultK8sConfigDir + "/" + kubeadmConfigFileName
 	defaultApiServerEtcdClientCertName = "apiserver-etcd-client.crt"
@@ -46,6 +48,8 @@ const (
 )
 
 type InitMasterOperationConfig struct {
+	// the commands are killed once the context is cancelled, context.Background() is used if nil
+	Context       context.Context
 	Logger        *logrus.Entry
 	CertKey       string
 	Node          *pb.Node
@@ -53,10 +57,15 @@ type InitMasterOperationConfig struct {
 	MasterNodes   []*pb.Node
 	EtcdNodes     []*pb.Node
 	ClusterConfig *pb.ClusterConfig
+	// the output of kubeadm init is streamed into the writer
+	ExecuteLogWriter io.Writer
+	// the machine of the node, it's created if nil
+	Machine machine.IMachine
 }
 
 type initMasterOperation struct {
 	operation.BaseOperation
+	ctx           context.Context
 	CertKey       string
 	Logger        *logrus.Entry
 	EtcdNodes     []*pb.Node
@@ -64,24 +73,35 @@ type initMasterOperation struct {
 	NeedUntaint   bool
 	machine       machine.IMachine
 	ClusterConfig *pb.ClusterConfig
+
+	executeLogWriter io.Writer
 }
 
 func NewInitMasterOperation(config *InitMasterOperationConfig) (*initMasterOperation, error) {
 	ops := &initMasterOperation{
+		ctx:           config.Context,
 		Logger:        config.Logger,
 		CertKey:       config.CertKey,
 		NeedUntaint:   config.NeedUntaint,
 		EtcdNodes:     config.EtcdNodes,
 		MasterNodes:   config.MasterNodes,
 		ClusterConfig: config.ClusterConfig,
+
+		executeLogWriter: config.ExecuteLogWriter,
 	}
 
-	m, err := machine.NewMachine(config.Node)
-	if err != nil {
-		return nil, err
+	if ops.ctx == nil {
+		ops.ctx = context.Background()
 	}
 
-	ops.machine = m
+	ops.machine = config.Machine
+	if ops.machine == nil {
+		m, err := machine.NewMachine(config.Node)
+		if err != nil {
+			return nil, err
+		}
+		ops.machine = m
+	}
 
 	return ops, nil
 }
@@ -101,16 +121,26 @@ func (op *initMasterOperation) PreDo() error {
 
 	_, encodedEtcdCACrt, err := etcd.ToByte(etcdCACrt, nil)
 
-	if err := op.machine.PutFile(bytes.NewReader(encodedEtcdCACrt), etcd.DefaultEtcdCACertPath); err != nil {
-		return fmt.Errorf("failed to put etcd ca cert to %v:%v, error: %v", op.machine.GetName(), etcd.DefaultEtcdCACertPath, err)
-	}
-	if err := op.machine.PutFile(bytes.NewReader(encodedAPIServerCert), defaultApiServerEtcdClientCertPath); err != nil {
-		return fmt.Errorf("failed to put apiserver etcd client cert to %v:%v, error: %v", op.machine.GetName(), defaultApiServerEtcdClientCertPath, err)
+	if err := op.putEtcdCerts(encodedEtcdCACrt, encodedAPIServerCert, encodedAPIServerKey); err != nil {
+		return err
 	}
-	if err := op.machine.PutFile(bytes.NewReader(encodedAPIServerKey), defaultApiServerEtcdClientKeyPath); err != nil {
-		return fmt.Errorf("failed to put apiserver etcd client key to %v:%v, error: %v", op.machine.GetName(), defaultApiServerEtcdClientKeyPath, err)
+
+	return op.prepareKubeadmInit()
+}
+
+func (op *initMasterOperation) putEtcdCerts(etcdCACrt, apiServerCert, apiServerKey []byte) error {
+	if err := op.machine.PutFiles(
+		&machine.File{Path: etcd.DefaultEtcdCACertPath, Content: etcdCACrt, Mode: etcd.CertFileMode},
+		&machine.File{Path: defaultApiServerEtcdClientCertPath, Content: apiServerCert, Mode: etcd.CertFileMode},
+		&machine.File{Path: defaultApiServerEtcdClientKeyPath, Content: apiServerKey, Mode: etcd.KeyFileMode},
+	); err != nil {
+		return fmt.Errorf("failed to put etcd ca cert and apiserver etcd client cert and key to %v, error: %v", op.machine.GetName(), err)
 	}
+	return nil
+}
 
+// prepareKubeadmInit puts the kubeadm config and adds the commands to init the master.
+func (op *initMasterOperation) prepareKubeadmInit() error {
 	kubeadmConfig, err := newInitConfig(op, op.CertKey)
 	if err != nil {
 		return fmt.Errorf("failed to generate %v, error: %v", kubeadmConfigPath, err)
@@ -121,10 +151,15 @@ func (op *initMasterOperation) PreDo() error {
 	}
 
 	op.AddCommands(
-		command.NewShellCommand(op.machine, "systemctl", "start", "kubelet"),
+		command.NewShellCommand(op.machine, "systemctl", "start", "kubelet").
+			WithContext(op.ctx),
 		command.NewShellCommand(op.machine, "kubeadm", "init",
 			"--config", kubeadmConfigPath,
-			"--upload-certs"),
+			"--upload-certs").
+			WithContext(op.ctx).
+			WithTimeout(defaultKubeadmTimeout).
+			WithExecuteLogWriter(op.executeLogWriter).
+			WithStreamingLog(),
 	)
 	return nil
 }
@@ -150,7 +185,7 @@ func (op *initMasterOperation) Do() error {
 	op.Logger.Debugf("init master result:\nstdout:\n%s\nstderr:\n%s\nerror:%v", stdOut, stdErr, err)
 
 	if err != nil {
-		return fmt.Errorf("failed to initilize first master, error:%s", stdErr)
+		return fmt.Errorf("failed to initilize first master, error: %w, stderr:%s", err, stdErr)
 	}
 
 	op.Logger.Debug("init master done, start post do")
@@ -164,6 +199,23 @@ func (op *initMasterOperation) Do() error {
 	return nil
 }
 
+// Plan puts the files and runs the commands of initializing the master on the machine, which is
+// supposed to be a planning one. The etcd certs are fetched and generated on execution, only their
+// paths are planned, and the steps after kubeadm init are not planned.
+func (op *initMasterOperation) Plan() error {
+	defer op.machine.Close()
+
+	if err := op.putEtcdCerts(nil, nil, nil); err != nil {
+		return err
+	}
+	if err := op.prepareKubeadmInit(); err != nil {
+		return err
+	}
+
+	_, _, err := op.BaseOperation.Do()
+	return err
+}
+
 func (op *initMasterOperation) PostDo() error {
 	// wait until master cluster ready
 
@@ -176,7 +228,11 @@ func (op *initMasterOperation) PostDo() error {
 			break
 		}
 		op.Logger.Warnf("controlplane not ready, error: %v, will retry", err)
-		time.Sleep(time.Second << uint(retries))
+		select {
+		case <-time.After(time.Second << uint(retries)):
+		case <-op.ctx.Done():
+			return op.ctx.Err()
+		}
 	}
 
 	if !up {
@@ -0,0 +1,112 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine/sshtest"
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation/etcd"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const initMasterTranscriptsDir = "testdata/init_master"
+
+var update = flag.Bool("update", false, "record the transcripts in testdata against the in-process ssh servers")
+
+// recordInitMaster starts the servers of the nodes, and returns the factory recording the interactions.
+func recordInitMaster(t *testing.T) (masterNode, etcdNode *pb.Node, newMachine machine.Factory, save func(), cleanup func()) {
+	masterServer, err := sshtest.NewServer()
+	assert.NoError(t, err)
+	masterServer.Handle(`^systemctl start kubelet$`, sshtest.Reply("", "", 0))
+	masterServer.Handle(`^kubeadm init --config /etc/kubernetes/kubeadm_config.yaml --upload-certs$`,
+		sshtest.Reply("Your Kubernetes control-plane has initialized successfully!\n", "", 0))
+
+	etcdServer, err := sshtest.NewServer()
+	assert.NoError(t, err)
+	caCrt, caKey, err := etcd.CreateAsCA(&certutil.Config{CommonName: "etcd-ca"})
+	assert.NoError(t, err)
+	encodedKey, encodedCrt, err := etcd.ToByte(caCrt, caKey)
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(etcdServer.Path(etcd.DefaultEtcdCACertPath)), 0755))
+	assert.NoError(t, ioutil.WriteFile(etcdServer.Path(etcd.DefaultEtcdCACertPath), encodedCrt, 0644))
+	assert.NoError(t, ioutil.WriteFile(etcdServer.Path(etcd.DefaultPKIDir+"etcd/ca.key"), encodedKey, 0600))
+
+	recorder := machine.NewRecorder(nil)
+	// the apiserver etcd client cert and key are signed on each run
+	recorder.IgnoreContent(`apiserver-etcd-client\.(crt|key)$`)
+
+	save = func() {
+		assert.NoError(t, machine.SaveTranscripts(initMasterTranscriptsDir, recorder.Transcripts()))
+	}
+	cleanup = func() {
+		masterServer.Close()
+		etcdServer.Close()
+	}
+	return masterServer.Node("master1"), etcdServer.Node("etcd1"), recorder.NewMachine, save, cleanup
+}
+
+func TestInitMasterOperationReplay(t *testing.T) {
+	// the nodes are the same as the ones recorded, so are the kubeadm configs generated
+	masterNode := &pb.Node{Name: "master1", Ip: "127.0.0.1"}
+	etcdNode := &pb.Node{Name: "etcd1", Ip: "127.0.0.1"}
+
+	var newMachine machine.Factory
+	var replayer *machine.Replayer
+	if *update {
+		var save, cleanup func()
+		masterNode, etcdNode, newMachine, save, cleanup = recordInitMaster(t)
+		defer cleanup()
+		defer save()
+	} else {
+		transcripts, err := machine.LoadTranscripts(initMasterTranscriptsDir)
+		assert.NoError(t, err)
+		replayer = machine.NewReplayer(transcripts)
+		newMachine = replayer.NewMachine
+	}
+
+	machine.SetFactory(newMachine)
+	defer machine.SetFactory(nil)
+
+	op, err := NewInitMasterOperation(&InitMasterOperationConfig{
+		Logger:      logrus.NewEntry(logrus.StandardLogger()),
+		CertKey:     "e6a2eb8581237ab72a4f494f30285ec12a9694d750b9785706a83bfcbbbd2204",
+		Node:        masterNode,
+		MasterNodes: []*pb.Node{masterNode},
+		EtcdNodes:   []*pb.Node{etcdNode},
+		ClusterConfig: &pb.ClusterConfig{
+			KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "firstMasterIP"},
+		},
+	})
+	assert.NoError(t, err)
+	defer op.machine.Close()
+
+	assert.NoError(t, op.PreDo())
+	_, _, err = op.BaseOperation.Do()
+	assert.NoError(t, err)
+
+	if replayer != nil {
+		assert.NoError(t, replayer.Verify())
+	}
+}
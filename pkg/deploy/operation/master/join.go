@@ -15,7 +15,9 @@
 package master
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -29,39 +31,56 @@ import (
 )
 
 type JoinMasterOperationConfig struct {
+	// the commands are killed once the context is cancelled, context.Background() is used if nil
+	Context       context.Context
 	Logger        *logrus.Entry
 	CertKey       string
 	Node          *pb.Node
 	NeedUntaint   bool
 	MasterNodes   []*pb.Node
 	ClusterConfig *pb.ClusterConfig
+	// the output of kubeadm join is streamed into the writer
+	ExecuteLogWriter io.Writer
+	// the machine of the node, it's created if nil
+	Machine machine.IMachine
 }
 
 type joinMasterOperation struct {
 	operation.BaseOperation
+	ctx           context.Context
 	Logger        *logrus.Entry
 	CertKey       string
 	NeedUntaint   bool
 	MasterNodes   []*pb.Node
 	machine       machine.IMachine
 	ClusterConfig *pb.ClusterConfig
+
+	executeLogWriter io.Writer
 }
 
 func NewJoinMasterOperation(config *JoinMasterOperationConfig) (*joinMasterOperation, error) {
 	ops := &joinMasterOperation{
+		ctx:           config.Context,
 		Logger:        config.Logger,
 		CertKey:       config.CertKey,
 		NeedUntaint:   config.NeedUntaint,
 		MasterNodes:   config.MasterNodes,
 		ClusterConfig: config.ClusterConfig,
-	}
 
-	m, err := machine.NewMachine(config.Node)
-	if err != nil {
-		return nil, err
+		executeLogWriter: config.ExecuteLogWriter,
+	}
+	if ops.ctx == nil {
+		ops.ctx = context.Background()
 	}
 
-	ops.machine = m
+	ops.machine = config.Machine
+	if ops.machine == nil {
+		m, err := machine.NewMachine(config.Node)
+		if err != nil {
+			return nil, err
+		}
+		ops.machine = m
+	}
 
 	return ops, nil
 }
@@ -77,12 +96,17 @@ func (op *joinMasterOperation) PreDo() error {
 	}
 
 	op.AddCommands(
-		command.NewShellCommand(op.machine, "systemctl", "start", "kubelet"),
+		command.NewShellCommand(op.machine, "systemctl", "start", "kubelet").
+			WithContext(op.ctx),
 		command.NewShellCommand(op.machine, "kubeadm", "join", endpoint,
 			"--token", Token,
 			"--control-plane",
 			"--certificate-key", op.CertKey,
-			"--discovery-token-unsafe-skip-ca-verification"),
+			"--discovery-token-unsafe-skip-ca-verification").
+			WithContext(op.ctx).
+			WithTimeout(defaultKubeadmTimeout).
+			WithExecuteLogWriter(op.executeLogWriter).
+			WithStreamingLog(),
 	)
 
 	return nil
@@ -114,7 +138,7 @@ func (op *joinMasterOperation) Do() error {
 	// join master
 	stdOut, stdErr, err := op.BaseOperation.Do()
 	if err != nil {
-		return fmt.Errorf("failed to join master:%v to cluster, error:%s", op.machine.GetName(), stdErr)
+		return fmt.Errorf("failed to join master:%v to cluster, error: %w, stderr:%s", op.machine.GetName(), err, stdErr)
 	}
 
 	op.Logger.Debugf("join %v done, stdout:%s\nstderr:%s\nerr:%v", op.machine.GetName(), stdOut, stdErr, err)
@@ -126,6 +150,19 @@ func (op *joinMasterOperation) Do() error {
 	return nil
 }
 
+// Plan runs the commands of joining the master on the machine, which is supposed to be a planning one.
+// Whether the node has joined is not checked, and the steps after kubeadm join are not planned.
+func (op *joinMasterOperation) Plan() error {
+	defer op.machine.Close()
+
+	if err := op.PreDo(); err != nil {
+		return err
+	}
+
+	_, _, err := op.BaseOperation.Do()
+	return err
+}
+
 func (op *joinMasterOperation) PostDo() error {
 
 	if !op.NeedUntaint {
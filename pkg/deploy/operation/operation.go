@@ -37,7 +37,7 @@ func (op *BaseOperation) Do() (stdout, stderr []byte, err error) {
 	for _, cmd := range op.Commands {
 		stdout, stderr, err = cmd.Execute()
 		if err != nil {
-			err = fmt.Errorf("run cmd %v error: %v", cmd, err)
+			err = fmt.Errorf("run cmd %v error: %w", cmd, err)
 			return
 		}
 	}
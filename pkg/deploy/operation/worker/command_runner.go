@@ -15,6 +15,7 @@
 package worker
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -61,6 +62,14 @@ func (runner *CommandRunner) RunCommand(command command.Command, errorTitle, doS
 
 	runner.log(fmt.Sprintf("[end time]: %s\n\n", time.Now().String()))
 
+	if isCommandTimeout(err) {
+		return &pb.Error{
+			Reason:     errorTitle,
+			Detail:     fmt.Sprintf("We tried to %s, but %s, error message: %v", doSomeThing, consts.MsgCommandTimeout, err),
+			FixMethods: consts.MsgCommandTimeoutFixMethods,
+		}
+	}
+
 	if err != nil {
 		return &pb.Error{
 			Reason:     errorTitle,                                                                                // {$errorTitle}
@@ -80,6 +89,12 @@ func (runner *CommandRunner) RunCommand(command command.Command, errorTitle, doS
 	return nil
 }
 
+func isCommandTimeout(err error) bool {
+
+	var timeoutErr *command.TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
 func (runner *CommandRunner) log(data string) {
 
 	if runner.executeLogWriter == nil {
@@ -5,11 +5,15 @@
 Package protos is a generated protocol buffer package.
 
 It is generated from these files:
+
 	deploy_controller.proto
 
 It has these top-level messages:
+
 	Auth
 	SSH
+	Sudo
+	JumpHost
 	Node
 	Error
 	TestConnectionRequest
@@ -33,6 +37,8 @@ It has these top-level messages:
 	NodeDeployConfig
 	DeployRequest
 	DeployReply
+	ResumeDeployRequest
+	ResumeDeployReply
 	GetDeployResultRequest
 	DeployItem
 	DeployItemResult
@@ -41,11 +47,38 @@ It has these top-level messages:
 	GetDeployLogReply
 	FetchKubeConfigRequest
 	FetchKubeConfigReply
+	CancelTaskRequest
+	CancelTaskReply
+	ListTasksRequest
+	TaskSummary
+	ListTasksReply
+	WatchTaskRequest
+	TaskEvent
+	TailCheckNodesLogRequest
+	TailDeployLogRequest
+	LogChunk
+	KnownHost
+	ListKnownHostsRequest
+	ListKnownHostsReply
+	ForgetKnownHostRequest
+	ForgetKnownHostReply
 	CalicoOptions
 	NetworkOptions
 	CheckNetworkRequirementRequest
 	ConnectivityCheckResult
 	CheckNetworkRequirementsReply
+	GetTaskGraphRequest
+	TaskGraphNode
+	TaskGraphEdge
+	GetTaskGraphReply
+	PlanDeployRequest
+	PlanStep
+	ActionPlan
+	TaskPlan
+	PlanDeployReply
+	GetTaskEventsRequest
+	JournalEvent
+	GetTaskEventsReply
 */
 package protos
 
@@ -70,12 +103,19 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Auth struct {
-	// type could be ["password", "privatekey"]
+	// type could be ["password", "privatekey", "keyboardinteractive"]
 	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
-	// credential stores the content of password or privatekey
+	// credential stores the content of password or privatekey, it's the password for keyboardinteractive.
 	Credential string `protobuf:"bytes,2,opt,name=credential" json:"credential,omitempty"`
 	// username is the user name used for password auth.
 	Username string `protobuf:"bytes,3,opt,name=username" json:"username,omitempty"`
+	// passphrase decrypts the privatekey if it's encrypted.
+	Passphrase string `protobuf:"bytes,4,opt,name=passphrase" json:"passphrase,omitempty"`
+	// certificate is the OpenSSH user certificate of the privatekey, in authorized_keys format.
+	Certificate string `protobuf:"bytes,5,opt,name=certificate" json:"certificate,omitempty"`
+	// challengeResponses answer the questions of keyboardinteractive in order, every question
+	// is answered with the credential if it's empty.
+	ChallengeResponses []string `protobuf:"bytes,6,rep,name=challengeResponses" json:"challengeResponses,omitempty"`
 }
 
 func (m *Auth) Reset()                    { *m = Auth{} }
@@ -104,10 +144,36 @@ func (m *Auth) GetUsername() string {
 	return ""
 }
 
+func (m *Auth) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
+func (m *Auth) GetCertificate() string {
+	if m != nil {
+		return m.Certificate
+	}
+	return ""
+}
+
+func (m *Auth) GetChallengeResponses() []string {
+	if m != nil {
+		return m.ChallengeResponses
+	}
+	return nil
+}
+
 // SSH contains the ssh login info.
 type SSH struct {
 	Port uint32 `protobuf:"varint,1,opt,name=port" json:"port,omitempty"`
 	Auth *Auth  `protobuf:"bytes,2,opt,name=auth" json:"auth,omitempty"`
+	// jumpHosts is the chain of bastion hosts which the connection to the node is tunneled through,
+	// the first one is connected directly, and each of the others is connected through the previous one.
+	JumpHosts []*JumpHost `protobuf:"bytes,3,rep,name=jumpHosts" json:"jumpHosts,omitempty"`
+	// sudo is set if the user is not root, the commands are run and the files are put via sudo.
+	Sudo *Sudo `protobuf:"bytes,4,opt,name=sudo" json:"sudo,omitempty"`
 }
 
 func (m *SSH) Reset()                    { *m = SSH{} }
@@ -129,17 +195,93 @@ func (m *SSH) GetAuth() *Auth {
 	return nil
 }
 
+func (m *SSH) GetJumpHosts() []*JumpHost {
+	if m != nil {
+		return m.JumpHosts
+	}
+	return nil
+}
+
+func (m *SSH) GetSudo() *Sudo {
+	if m != nil {
+		return m.Sudo
+	}
+	return nil
+}
+
+// Sudo contains the privilege escalation info of a non-root user.
+type Sudo struct {
+	Enabled bool `protobuf:"varint,1,opt,name=enabled" json:"enabled,omitempty"`
+	// password is the sudo password, leave it empty if NOPASSWD is configured for the user.
+	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+}
+
+func (m *Sudo) Reset()                    { *m = Sudo{} }
+func (m *Sudo) String() string            { return proto.CompactTextString(m) }
+func (*Sudo) ProtoMessage()               {}
+func (*Sudo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Sudo) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *Sudo) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+// JumpHost contains the login info of a bastion host.
+type JumpHost struct {
+	Ip   string `protobuf:"bytes,1,opt,name=ip" json:"ip,omitempty"`
+	Port uint32 `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+	Auth *Auth  `protobuf:"bytes,3,opt,name=auth" json:"auth,omitempty"`
+}
+
+func (m *JumpHost) Reset()                    { *m = JumpHost{} }
+func (m *JumpHost) String() string            { return proto.CompactTextString(m) }
+func (*JumpHost) ProtoMessage()               {}
+func (*JumpHost) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *JumpHost) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *JumpHost) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *JumpHost) GetAuth() *Auth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
 // Node contains the node metadata info
 type Node struct {
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Ip   string `protobuf:"bytes,2,opt,name=ip" json:"ip,omitempty"`
 	Ssh  *SSH   `protobuf:"bytes,3,opt,name=ssh" json:"ssh,omitempty"`
+	// localhost is set if the node is the host running the deploy controller, the commands are run
+	// and the files are copied locally instead of via ssh.
+	Localhost bool `protobuf:"varint,4,opt,name=localhost" json:"localhost,omitempty"`
 }
 
 func (m *Node) Reset()                    { *m = Node{} }
 func (m *Node) String() string            { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()               {}
-func (*Node) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*Node) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *Node) GetName() string {
 	if m != nil {
@@ -162,6 +304,13 @@ func (m *Node) GetSsh() *SSH {
 	return nil
 }
 
+func (m *Node) GetLocalhost() bool {
+	if m != nil {
+		return m.Localhost
+	}
+	return false
+}
+
 type Error struct {
 	Reason     string `protobuf:"bytes,1,opt,name=reason" json:"reason,omitempty"`
 	Detail     string `protobuf:"bytes,2,opt,name=detail" json:"detail,omitempty"`
@@ -171,7 +320,7 @@ type Error struct {
 func (m *Error) Reset()                    { *m = Error{} }
 func (m *Error) String() string            { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()               {}
-func (*Error) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Error) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *Error) GetReason() string {
 	if m != nil {
@@ -196,13 +345,14 @@ func (m *Error) GetFixMethods() string {
 
 // TestConnectionRequest contains the request of node connection testing.
 type TestConnectionRequest struct {
-	Node *Node `protobuf:"bytes,1,opt,name=node" json:"node,omitempty"`
+	Node      *Node  `protobuf:"bytes,1,opt,name=node" json:"node,omitempty"`
+	ClusterId string `protobuf:"bytes,2,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *TestConnectionRequest) Reset()                    { *m = TestConnectionRequest{} }
 func (m *TestConnectionRequest) String() string            { return proto.CompactTextString(m) }
 func (*TestConnectionRequest) ProtoMessage()               {}
-func (*TestConnectionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*TestConnectionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *TestConnectionRequest) GetNode() *Node {
 	if m != nil {
@@ -211,16 +361,24 @@ func (m *TestConnectionRequest) GetNode() *Node {
 	return nil
 }
 
+func (m *TestConnectionRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // TestConnectionReply contains the result of node connection testing.
 type TestConnectionReply struct {
-	Passed bool   `protobuf:"varint,1,opt,name=passed" json:"passed,omitempty"`
-	Err    *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Passed    bool   `protobuf:"varint,1,opt,name=passed" json:"passed,omitempty"`
+	Err       *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	ClusterId string `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *TestConnectionReply) Reset()                    { *m = TestConnectionReply{} }
 func (m *TestConnectionReply) String() string            { return proto.CompactTextString(m) }
 func (*TestConnectionReply) ProtoMessage()               {}
-func (*TestConnectionReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*TestConnectionReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *TestConnectionReply) GetPassed() bool {
 	if m != nil {
@@ -236,6 +394,13 @@ func (m *TestConnectionReply) GetErr() *Error {
 	return nil
 }
 
+func (m *TestConnectionReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // NodeCheckConfig contains the pre-checking configuration for a node
 type NodeCheckConfig struct {
 	Node  *Node    `protobuf:"bytes,1,opt,name=node" json:"node,omitempty"`
@@ -245,7 +410,7 @@ type NodeCheckConfig struct {
 func (m *NodeCheckConfig) Reset()                    { *m = NodeCheckConfig{} }
 func (m *NodeCheckConfig) String() string            { return proto.CompactTextString(m) }
 func (*NodeCheckConfig) ProtoMessage()               {}
-func (*NodeCheckConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*NodeCheckConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *NodeCheckConfig) GetNode() *Node {
 	if m != nil {
@@ -265,12 +430,13 @@ func (m *NodeCheckConfig) GetRoles() []string {
 type CheckNodesRequest struct {
 	Configs        []*NodeCheckConfig `protobuf:"bytes,1,rep,name=configs" json:"configs,omitempty"`
 	NetworkOptions *NetworkOptions    `protobuf:"bytes,2,opt,name=networkOptions" json:"networkOptions,omitempty"`
+	ClusterId      string             `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *CheckNodesRequest) Reset()                    { *m = CheckNodesRequest{} }
 func (m *CheckNodesRequest) String() string            { return proto.CompactTextString(m) }
 func (*CheckNodesRequest) ProtoMessage()               {}
-func (*CheckNodesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*CheckNodesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *CheckNodesRequest) GetConfigs() []*NodeCheckConfig {
 	if m != nil {
@@ -286,16 +452,24 @@ func (m *CheckNodesRequest) GetNetworkOptions() *NetworkOptions {
 	return nil
 }
 
+func (m *CheckNodesRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // CheckNodesReply contains the result of node pre-checking.
 type CheckNodesReply struct {
-	Accepted bool   `protobuf:"varint,1,opt,name=accepted" json:"accepted,omitempty"`
-	Err      *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Accepted  bool   `protobuf:"varint,1,opt,name=accepted" json:"accepted,omitempty"`
+	Err       *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	ClusterId string `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *CheckNodesReply) Reset()                    { *m = CheckNodesReply{} }
 func (m *CheckNodesReply) String() string            { return proto.CompactTextString(m) }
 func (*CheckNodesReply) ProtoMessage()               {}
-func (*CheckNodesReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*CheckNodesReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *CheckNodesReply) GetAccepted() bool {
 	if m != nil {
@@ -311,6 +485,13 @@ func (m *CheckNodesReply) GetErr() *Error {
 	return nil
 }
 
+func (m *CheckNodesReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // CheckItem is a check item of node pre-checking
 type CheckItem struct {
 	Name        string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
@@ -320,7 +501,7 @@ type CheckItem struct {
 func (m *CheckItem) Reset()                    { *m = CheckItem{} }
 func (m *CheckItem) String() string            { return proto.CompactTextString(m) }
 func (*CheckItem) ProtoMessage()               {}
-func (*CheckItem) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*CheckItem) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *CheckItem) GetName() string {
 	if m != nil {
@@ -347,7 +528,7 @@ type ItemCheckResult struct {
 func (m *ItemCheckResult) Reset()                    { *m = ItemCheckResult{} }
 func (m *ItemCheckResult) String() string            { return proto.CompactTextString(m) }
 func (*ItemCheckResult) ProtoMessage()               {}
-func (*ItemCheckResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*ItemCheckResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *ItemCheckResult) GetItem() *CheckItem {
 	if m != nil {
@@ -388,7 +569,7 @@ type NodeCheckResult struct {
 func (m *NodeCheckResult) Reset()                    { *m = NodeCheckResult{} }
 func (m *NodeCheckResult) String() string            { return proto.CompactTextString(m) }
 func (*NodeCheckResult) ProtoMessage()               {}
-func (*NodeCheckResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*NodeCheckResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *NodeCheckResult) GetNodeName() string {
 	if m != nil {
@@ -420,24 +601,33 @@ func (m *NodeCheckResult) GetItems() []*ItemCheckResult {
 
 // GetCheckNodesResultRequest contains the request of getting nodes check result.
 type GetCheckNodesResultRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetCheckNodesResultRequest) Reset()                    { *m = GetCheckNodesResultRequest{} }
 func (m *GetCheckNodesResultRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetCheckNodesResultRequest) ProtoMessage()               {}
-func (*GetCheckNodesResultRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*GetCheckNodesResultRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *GetCheckNodesResultRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
 
 // GetCheckNodesResultReply contains the result of nodes check
 type GetCheckNodesResultReply struct {
-	Status string                      `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
-	Err    *Error                      `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
-	Nodes  map[string]*NodeCheckResult `protobuf:"bytes,3,rep,name=nodes" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Status    string                      `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	Err       *Error                      `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Nodes     map[string]*NodeCheckResult `protobuf:"bytes,3,rep,name=nodes" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ClusterId string                      `protobuf:"bytes,4,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetCheckNodesResultReply) Reset()                    { *m = GetCheckNodesResultReply{} }
 func (m *GetCheckNodesResultReply) String() string            { return proto.CompactTextString(m) }
 func (*GetCheckNodesResultReply) ProtoMessage()               {}
-func (*GetCheckNodesResultReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*GetCheckNodesResultReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *GetCheckNodesResultReply) GetStatus() string {
 	if m != nil {
@@ -460,15 +650,23 @@ func (m *GetCheckNodesResultReply) GetNodes() map[string]*NodeCheckResult {
 	return nil
 }
 
+func (m *GetCheckNodesResultReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // GetCheckNodesLogRequest contains the request of getting nodes check log.
 type GetCheckNodesLogRequest struct {
-	NodeName string `protobuf:"bytes,1,opt,name=nodeName" json:"nodeName,omitempty"`
+	NodeName  string `protobuf:"bytes,1,opt,name=nodeName" json:"nodeName,omitempty"`
+	ClusterId string `protobuf:"bytes,2,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetCheckNodesLogRequest) Reset()                    { *m = GetCheckNodesLogRequest{} }
 func (m *GetCheckNodesLogRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetCheckNodesLogRequest) ProtoMessage()               {}
-func (*GetCheckNodesLogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*GetCheckNodesLogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *GetCheckNodesLogRequest) GetNodeName() string {
 	if m != nil {
@@ -477,15 +675,23 @@ func (m *GetCheckNodesLogRequest) GetNodeName() string {
 	return ""
 }
 
+func (m *GetCheckNodesLogRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // GetCheckNodesLogReply contains the log of nodes check
 type GetCheckNodesLogReply struct {
-	Log []byte `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	Log       []byte `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	ClusterId string `protobuf:"bytes,2,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetCheckNodesLogReply) Reset()                    { *m = GetCheckNodesLogReply{} }
 func (m *GetCheckNodesLogReply) String() string            { return proto.CompactTextString(m) }
 func (*GetCheckNodesLogReply) ProtoMessage()               {}
-func (*GetCheckNodesLogReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*GetCheckNodesLogReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *GetCheckNodesLogReply) GetLog() []byte {
 	if m != nil {
@@ -494,6 +700,13 @@ func (m *GetCheckNodesLogReply) GetLog() []byte {
 	return nil
 }
 
+func (m *GetCheckNodesLogReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 type NodePortRange struct {
 	From uint32 `protobuf:"varint,1,opt,name=from" json:"from,omitempty"`
 	To   uint32 `protobuf:"varint,2,opt,name=to" json:"to,omitempty"`
@@ -502,7 +715,7 @@ type NodePortRange struct {
 func (m *NodePortRange) Reset()                    { *m = NodePortRange{} }
 func (m *NodePortRange) String() string            { return proto.CompactTextString(m) }
 func (*NodePortRange) ProtoMessage()               {}
-func (*NodePortRange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*NodePortRange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *NodePortRange) GetFrom() uint32 {
 	if m != nil {
@@ -526,7 +739,7 @@ type Keepalived struct {
 func (m *Keepalived) Reset()                    { *m = Keepalived{} }
 func (m *Keepalived) String() string            { return proto.CompactTextString(m) }
 func (*Keepalived) ProtoMessage()               {}
-func (*Keepalived) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*Keepalived) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *Keepalived) GetVip() string {
 	if m != nil {
@@ -550,7 +763,7 @@ type Loadbalancer struct {
 func (m *Loadbalancer) Reset()                    { *m = Loadbalancer{} }
 func (m *Loadbalancer) String() string            { return proto.CompactTextString(m) }
 func (*Loadbalancer) ProtoMessage()               {}
-func (*Loadbalancer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*Loadbalancer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *Loadbalancer) GetIp() string {
 	if m != nil {
@@ -577,7 +790,7 @@ type KubeAPIServerConnect struct {
 func (m *KubeAPIServerConnect) Reset()                    { *m = KubeAPIServerConnect{} }
 func (m *KubeAPIServerConnect) String() string            { return proto.CompactTextString(m) }
 func (*KubeAPIServerConnect) ProtoMessage()               {}
-func (*KubeAPIServerConnect) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*KubeAPIServerConnect) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *KubeAPIServerConnect) GetType() string {
 	if m != nil {
@@ -616,7 +829,7 @@ type ClusterConfig struct {
 func (m *ClusterConfig) Reset()                    { *m = ClusterConfig{} }
 func (m *ClusterConfig) String() string            { return proto.CompactTextString(m) }
 func (*ClusterConfig) ProtoMessage()               {}
-func (*ClusterConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*ClusterConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *ClusterConfig) GetClusterName() string {
 	if m != nil {
@@ -690,7 +903,7 @@ type Taint struct {
 func (m *Taint) Reset()                    { *m = Taint{} }
 func (m *Taint) String() string            { return proto.CompactTextString(m) }
 func (*Taint) ProtoMessage()               {}
-func (*Taint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*Taint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *Taint) GetKey() string {
 	if m != nil {
@@ -725,7 +938,7 @@ type NodeDeployConfig struct {
 func (m *NodeDeployConfig) Reset()                    { *m = NodeDeployConfig{} }
 func (m *NodeDeployConfig) String() string            { return proto.CompactTextString(m) }
 func (*NodeDeployConfig) ProtoMessage()               {}
-func (*NodeDeployConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*NodeDeployConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *NodeDeployConfig) GetNode() *Node {
 	if m != nil {
@@ -759,12 +972,13 @@ func (m *NodeDeployConfig) GetTaints() []*Taint {
 type DeployRequest struct {
 	NodeConfigs   []*NodeDeployConfig `protobuf:"bytes,1,rep,name=nodeConfigs" json:"nodeConfigs,omitempty"`
 	ClusterConfig *ClusterConfig      `protobuf:"bytes,2,opt,name=clusterConfig" json:"clusterConfig,omitempty"`
+	ClusterId     string              `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *DeployRequest) Reset()                    { *m = DeployRequest{} }
 func (m *DeployRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeployRequest) ProtoMessage()               {}
-func (*DeployRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*DeployRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func (m *DeployRequest) GetNodeConfigs() []*NodeDeployConfig {
 	if m != nil {
@@ -780,16 +994,24 @@ func (m *DeployRequest) GetClusterConfig() *ClusterConfig {
 	return nil
 }
 
+func (m *DeployRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // DeployReply contains the response of a deploy request.
 type DeployReply struct {
-	Accepted bool   `protobuf:"varint,1,opt,name=accepted" json:"accepted,omitempty"`
-	Err      *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Accepted  bool   `protobuf:"varint,1,opt,name=accepted" json:"accepted,omitempty"`
+	Err       *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	ClusterId string `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *DeployReply) Reset()                    { *m = DeployReply{} }
 func (m *DeployReply) String() string            { return proto.CompactTextString(m) }
 func (*DeployReply) ProtoMessage()               {}
-func (*DeployReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (*DeployReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 func (m *DeployReply) GetAccepted() bool {
 	if m != nil {
@@ -805,14 +1027,79 @@ func (m *DeployReply) GetErr() *Error {
 	return nil
 }
 
+func (m *DeployReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+// ResumeDeployRequest contains the request of resuming the previous deploy.
+type ResumeDeployRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+}
+
+func (m *ResumeDeployRequest) Reset()                    { *m = ResumeDeployRequest{} }
+func (m *ResumeDeployRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResumeDeployRequest) ProtoMessage()               {}
+func (*ResumeDeployRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *ResumeDeployRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+// ResumeDeployReply contains the response of a resume deploy request.
+type ResumeDeployReply struct {
+	Accepted  bool   `protobuf:"varint,1,opt,name=accepted" json:"accepted,omitempty"`
+	Err       *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	ClusterId string `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
+}
+
+func (m *ResumeDeployReply) Reset()                    { *m = ResumeDeployReply{} }
+func (m *ResumeDeployReply) String() string            { return proto.CompactTextString(m) }
+func (*ResumeDeployReply) ProtoMessage()               {}
+func (*ResumeDeployReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *ResumeDeployReply) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *ResumeDeployReply) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *ResumeDeployReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // GetDeployResultRequest contains the request of getting deploy result.
 type GetDeployResultRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetDeployResultRequest) Reset()                    { *m = GetDeployResultRequest{} }
 func (m *GetDeployResultRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetDeployResultRequest) ProtoMessage()               {}
-func (*GetDeployResultRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+func (*GetDeployResultRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *GetDeployResultRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
 
 // DeployItem represents a deploy action in a node for a role.
 type DeployItem struct {
@@ -824,7 +1111,7 @@ type DeployItem struct {
 func (m *DeployItem) Reset()                    { *m = DeployItem{} }
 func (m *DeployItem) String() string            { return proto.CompactTextString(m) }
 func (*DeployItem) ProtoMessage()               {}
-func (*DeployItem) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+func (*DeployItem) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
 func (m *DeployItem) GetRole() string {
 	if m != nil {
@@ -853,12 +1140,14 @@ type DeployItemResult struct {
 	Status     string      `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
 	Err        *Error      `protobuf:"bytes,3,opt,name=err" json:"err,omitempty"`
 	Logs       string      `protobuf:"bytes,4,opt,name=logs" json:"logs,omitempty"`
+	// the number of attempts made by the action of the item, which is more than 1 if it was retried
+	Attempts int32 `protobuf:"varint,5,opt,name=attempts" json:"attempts,omitempty"`
 }
 
 func (m *DeployItemResult) Reset()                    { *m = DeployItemResult{} }
 func (m *DeployItemResult) String() string            { return proto.CompactTextString(m) }
 func (*DeployItemResult) ProtoMessage()               {}
-func (*DeployItemResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*DeployItemResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
 
 func (m *DeployItemResult) GetDeployItem() *DeployItem {
 	if m != nil {
@@ -888,17 +1177,25 @@ func (m *DeployItemResult) GetLogs() string {
 	return ""
 }
 
+func (m *DeployItemResult) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
 // GetDeployResultReply represents the result of a deploy
 type GetDeployResultReply struct {
-	Status string              `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
-	Err    *Error              `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
-	Items  []*DeployItemResult `protobuf:"bytes,3,rep,name=items" json:"items,omitempty"`
+	Status    string              `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	Err       *Error              `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Items     []*DeployItemResult `protobuf:"bytes,3,rep,name=items" json:"items,omitempty"`
+	ClusterId string              `protobuf:"bytes,4,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetDeployResultReply) Reset()                    { *m = GetDeployResultReply{} }
 func (m *GetDeployResultReply) String() string            { return proto.CompactTextString(m) }
 func (*GetDeployResultReply) ProtoMessage()               {}
-func (*GetDeployResultReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (*GetDeployResultReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
 
 func (m *GetDeployResultReply) GetStatus() string {
 	if m != nil {
@@ -921,16 +1218,24 @@ func (m *GetDeployResultReply) GetItems() []*DeployItemResult {
 	return nil
 }
 
+func (m *GetDeployResultReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // GetDeployLogRequest contains the request of getting deploy log.
 type GetDeployLogRequest struct {
-	Role     string `protobuf:"bytes,1,opt,name=role" json:"role,omitempty"`
-	NodeName string `protobuf:"bytes,2,opt,name=nodeName" json:"nodeName,omitempty"`
+	Role      string `protobuf:"bytes,1,opt,name=role" json:"role,omitempty"`
+	NodeName  string `protobuf:"bytes,2,opt,name=nodeName" json:"nodeName,omitempty"`
+	ClusterId string `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetDeployLogRequest) Reset()                    { *m = GetDeployLogRequest{} }
 func (m *GetDeployLogRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetDeployLogRequest) ProtoMessage()               {}
-func (*GetDeployLogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+func (*GetDeployLogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
 
 func (m *GetDeployLogRequest) GetRole() string {
 	if m != nil {
@@ -946,15 +1251,23 @@ func (m *GetDeployLogRequest) GetNodeName() string {
 	return ""
 }
 
+func (m *GetDeployLogRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // GetDeployLogReply represents the response of getting deploy log.
 type GetDeployLogReply struct {
-	Log []byte `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	Log       []byte `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	ClusterId string `protobuf:"bytes,2,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *GetDeployLogReply) Reset()                    { *m = GetDeployLogReply{} }
 func (m *GetDeployLogReply) String() string            { return proto.CompactTextString(m) }
 func (*GetDeployLogReply) ProtoMessage()               {}
-func (*GetDeployLogReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+func (*GetDeployLogReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
 
 func (m *GetDeployLogReply) GetLog() []byte {
 	if m != nil {
@@ -963,15 +1276,23 @@ func (m *GetDeployLogReply) GetLog() []byte {
 	return nil
 }
 
+func (m *GetDeployLogReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // FetchKubeConfigRequest contains the request of getting kube config.
 type FetchKubeConfigRequest struct {
-	Node *Node `protobuf:"bytes,1,opt,name=node" json:"node,omitempty"`
+	Node      *Node  `protobuf:"bytes,1,opt,name=node" json:"node,omitempty"`
+	ClusterId string `protobuf:"bytes,2,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *FetchKubeConfigRequest) Reset()                    { *m = FetchKubeConfigRequest{} }
 func (m *FetchKubeConfigRequest) String() string            { return proto.CompactTextString(m) }
 func (*FetchKubeConfigRequest) ProtoMessage()               {}
-func (*FetchKubeConfigRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+func (*FetchKubeConfigRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
 
 func (m *FetchKubeConfigRequest) GetNode() *Node {
 	if m != nil {
@@ -980,16 +1301,24 @@ func (m *FetchKubeConfigRequest) GetNode() *Node {
 	return nil
 }
 
+func (m *FetchKubeConfigRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
 // FetchKubeConfigReply contains the response of getting kube config.
 type FetchKubeConfigReply struct {
 	KubeConfig []byte `protobuf:"bytes,1,opt,name=kubeConfig,proto3" json:"kubeConfig,omitempty"`
 	Err        *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	ClusterId  string `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
 func (m *FetchKubeConfigReply) Reset()                    { *m = FetchKubeConfigReply{} }
 func (m *FetchKubeConfigReply) String() string            { return proto.CompactTextString(m) }
 func (*FetchKubeConfigReply) ProtoMessage()               {}
-func (*FetchKubeConfigReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+func (*FetchKubeConfigReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
 
 func (m *FetchKubeConfigReply) GetKubeConfig() []byte {
 	if m != nil {
@@ -1005,177 +1334,1306 @@ func (m *FetchKubeConfigReply) GetErr() *Error {
 	return nil
 }
 
-// CalicoOptions options for checking requirements for deploying calico network.
-type CalicoOptions struct {
-	// if checkConnectivityAll = true, check connectivity between each pair of nodes bidirectionally.
-	CheckConnectivityAll bool `protobuf:"varint,1,opt,name=checkConnectivityAll" json:"checkConnectivityAll,omitempty"`
-	// EncapsulationMode could be ["vxlan","ipip","none"].
-	EncapsulationMode string `protobuf:"bytes,2,opt,name=encapsulationMode" json:"encapsulationMode,omitempty"`
-	VxlanPort         uint32 `protobuf:"varint,10,opt,name=vxlanPort" json:"vxlanPort,omitempty"`
-}
-
-func (m *CalicoOptions) Reset()                    { *m = CalicoOptions{} }
-func (m *CalicoOptions) String() string            { return proto.CompactTextString(m) }
-func (*CalicoOptions) ProtoMessage()               {}
-func (*CalicoOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
-
-func (m *CalicoOptions) GetCheckConnectivityAll() bool {
+func (m *FetchKubeConfigReply) GetClusterId() string {
 	if m != nil {
-		return m.CheckConnectivityAll
+		return m.ClusterId
 	}
-	return false
+	return ""
 }
 
-func (m *CalicoOptions) GetEncapsulationMode() string {
+// CancelTaskRequest contains the request of cancelling a running task,
+// taskType is the type of the task to be cancelled: "Deploy" or "NodeCheck".
+type CancelTaskRequest struct {
+	TaskType  string `protobuf:"bytes,1,opt,name=taskType" json:"taskType,omitempty"`
+	ClusterId string `protobuf:"bytes,2,opt,name=clusterId" json:"clusterId,omitempty"`
+}
+
+func (m *CancelTaskRequest) Reset()                    { *m = CancelTaskRequest{} }
+func (m *CancelTaskRequest) String() string            { return proto.CompactTextString(m) }
+func (*CancelTaskRequest) ProtoMessage()               {}
+func (*CancelTaskRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *CancelTaskRequest) GetTaskType() string {
 	if m != nil {
-		return m.EncapsulationMode
+		return m.TaskType
 	}
 	return ""
 }
 
-func (m *CalicoOptions) GetVxlanPort() uint32 {
+func (m *CancelTaskRequest) GetClusterId() string {
 	if m != nil {
-		return m.VxlanPort
+		return m.ClusterId
 	}
-	return 0
+	return ""
 }
 
-// NetworkOptions options for deploying network. affects checked items.
-type NetworkOptions struct {
-	NetworkType string `protobuf:"bytes,1,opt,name=networkType" json:"networkType,omitempty"`
-	// options for a specified network type. Starts from 10 to reserve field 2-9.
-	CalicoOptions *CalicoOptions `protobuf:"bytes,10,opt,name=calicoOptions" json:"calicoOptions,omitempty"`
+// CancelTaskReply contains the response of a cancel task request.
+type CancelTaskReply struct {
+	Cancelled bool   `protobuf:"varint,1,opt,name=cancelled" json:"cancelled,omitempty"`
+	Err       *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	ClusterId string `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
-func (m *NetworkOptions) Reset()                    { *m = NetworkOptions{} }
-func (m *NetworkOptions) String() string            { return proto.CompactTextString(m) }
-func (*NetworkOptions) ProtoMessage()               {}
-func (*NetworkOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+func (m *CancelTaskReply) Reset()                    { *m = CancelTaskReply{} }
+func (m *CancelTaskReply) String() string            { return proto.CompactTextString(m) }
+func (*CancelTaskReply) ProtoMessage()               {}
+func (*CancelTaskReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
 
-func (m *NetworkOptions) GetNetworkType() string {
+func (m *CancelTaskReply) GetCancelled() bool {
 	if m != nil {
-		return m.NetworkType
+		return m.Cancelled
 	}
-	return ""
+	return false
 }
 
-func (m *NetworkOptions) GetCalicoOptions() *CalicoOptions {
+func (m *CancelTaskReply) GetErr() *Error {
 	if m != nil {
-		return m.CalicoOptions
+		return m.Err
 	}
 	return nil
 }
 
-// CheckNetworkRequirementRequest nodes and network options when checking
-type CheckNetworkRequirementRequest struct {
-	Nodes   []*Node         `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
-	Options *NetworkOptions `protobuf:"bytes,2,opt,name=options" json:"options,omitempty"`
-}
-
-func (m *CheckNetworkRequirementRequest) Reset()                    { *m = CheckNetworkRequirementRequest{} }
-func (m *CheckNetworkRequirementRequest) String() string            { return proto.CompactTextString(m) }
-func (*CheckNetworkRequirementRequest) ProtoMessage()               {}
-func (*CheckNetworkRequirementRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
-
-func (m *CheckNetworkRequirementRequest) GetNodes() []*Node {
+func (m *CancelTaskReply) GetClusterId() string {
 	if m != nil {
-		return m.Nodes
+		return m.ClusterId
 	}
-	return nil
+	return ""
 }
 
-func (m *CheckNetworkRequirementRequest) GetOptions() *NetworkOptions {
+// ListTasksRequest contains the request of listing tasks, all clusters' tasks
+// are listed if clusterId is empty.
+type ListTasksRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+}
+
+func (m *ListTasksRequest) Reset()                    { *m = ListTasksRequest{} }
+func (m *ListTasksRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListTasksRequest) ProtoMessage()               {}
+func (*ListTasksRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *ListTasksRequest) GetClusterId() string {
 	if m != nil {
-		return m.Options
+		return m.ClusterId
 	}
-	return nil
+	return ""
 }
 
-type ConnectivityCheckResult struct {
-	SourceNodeName      string             `protobuf:"bytes,1,opt,name=SourceNodeName" json:"SourceNodeName,omitempty"`
-	DestinationNodeName string             `protobuf:"bytes,2,opt,name=DestinationNodeName" json:"DestinationNodeName,omitempty"`
-	Status              string             `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
-	Err                 *Error             `protobuf:"bytes,4,opt,name=err" json:"err,omitempty"`
-	Items               []*ItemCheckResult `protobuf:"bytes,5,rep,name=items" json:"items,omitempty"`
+// TaskSummary contains the brief info of a task.
+type TaskSummary struct {
+	Name              string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type              string `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	Status            string `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	Err               *Error `protobuf:"bytes,4,opt,name=err" json:"err,omitempty"`
+	CreationTimestamp int64  `protobuf:"varint,5,opt,name=creationTimestamp" json:"creationTimestamp,omitempty"`
+	ClusterId         string `protobuf:"bytes,6,opt,name=clusterId" json:"clusterId,omitempty"`
 }
 
-func (m *ConnectivityCheckResult) Reset()                    { *m = ConnectivityCheckResult{} }
-func (m *ConnectivityCheckResult) String() string            { return proto.CompactTextString(m) }
-func (*ConnectivityCheckResult) ProtoMessage()               {}
-func (*ConnectivityCheckResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+func (m *TaskSummary) Reset()                    { *m = TaskSummary{} }
+func (m *TaskSummary) String() string            { return proto.CompactTextString(m) }
+func (*TaskSummary) ProtoMessage()               {}
+func (*TaskSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
 
-func (m *ConnectivityCheckResult) GetSourceNodeName() string {
+func (m *TaskSummary) GetName() string {
 	if m != nil {
-		return m.SourceNodeName
+		return m.Name
 	}
 	return ""
 }
 
-func (m *ConnectivityCheckResult) GetDestinationNodeName() string {
+func (m *TaskSummary) GetType() string {
 	if m != nil {
-		return m.DestinationNodeName
+		return m.Type
 	}
 	return ""
 }
 
-func (m *ConnectivityCheckResult) GetStatus() string {
+func (m *TaskSummary) GetStatus() string {
 	if m != nil {
 		return m.Status
 	}
 	return ""
 }
 
-func (m *ConnectivityCheckResult) GetErr() *Error {
+func (m *TaskSummary) GetErr() *Error {
 	if m != nil {
 		return m.Err
 	}
 	return nil
 }
 
-func (m *ConnectivityCheckResult) GetItems() []*ItemCheckResult {
+func (m *TaskSummary) GetCreationTimestamp() int64 {
 	if m != nil {
-		return m.Items
+		return m.CreationTimestamp
 	}
-	return nil
+	return 0
+}
+
+func (m *TaskSummary) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+// ListTasksReply contains the tasks in the deploy controller.
+type ListTasksReply struct {
+	Tasks     []*TaskSummary `protobuf:"bytes,1,rep,name=tasks" json:"tasks,omitempty"`
+	ClusterId string         `protobuf:"bytes,2,opt,name=clusterId" json:"clusterId,omitempty"`
+}
+
+func (m *ListTasksReply) Reset()                    { *m = ListTasksReply{} }
+func (m *ListTasksReply) String() string            { return proto.CompactTextString(m) }
+func (*ListTasksReply) ProtoMessage()               {}
+func (*ListTasksReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ListTasksReply) GetTasks() []*TaskSummary {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+func (m *ListTasksReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+// WatchTaskRequest contains the request of watching the status transitions of tasks. If taskName
+// is empty, all tasks of the cluster are watched until the client cancels, otherwise the stream
+// ends when the task finishes.
+type WatchTaskRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	TaskName  string `protobuf:"bytes,2,opt,name=taskName" json:"taskName,omitempty"`
+}
+
+func (m *WatchTaskRequest) Reset()                    { *m = WatchTaskRequest{} }
+func (m *WatchTaskRequest) String() string            { return proto.CompactTextString(m) }
+func (*WatchTaskRequest) ProtoMessage()               {}
+func (*WatchTaskRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *WatchTaskRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *WatchTaskRequest) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+// TaskEvent is a status transition of a task, one of its sub tasks, or one of its actions.
+type TaskEvent struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	// taskName and taskType are of the top level task.
+	TaskName string `protobuf:"bytes,2,opt,name=taskName" json:"taskName,omitempty"`
+	TaskType string `protobuf:"bytes,3,opt,name=taskType" json:"taskType,omitempty"`
+	// kind is "task" or "action".
+	Kind string `protobuf:"bytes,4,opt,name=kind" json:"kind,omitempty"`
+	// name of the task, sub task or action whose status was changed.
+	Name string `protobuf:"bytes,5,opt,name=name" json:"name,omitempty"`
+	// parent is the name of the task which the sub task or action belongs to.
+	Parent string `protobuf:"bytes,6,opt,name=parent" json:"parent,omitempty"`
+	// actionType and nodeName are set only for actions.
+	ActionType string `protobuf:"bytes,7,opt,name=actionType" json:"actionType,omitempty"`
+	NodeName   string `protobuf:"bytes,8,opt,name=nodeName" json:"nodeName,omitempty"`
+	Status     string `protobuf:"bytes,9,opt,name=status" json:"status,omitempty"`
+	Err        *Error `protobuf:"bytes,10,opt,name=err" json:"err,omitempty"`
+	Timestamp  int64  `protobuf:"varint,11,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *TaskEvent) Reset()                    { *m = TaskEvent{} }
+func (m *TaskEvent) String() string            { return proto.CompactTextString(m) }
+func (*TaskEvent) ProtoMessage()               {}
+func (*TaskEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *TaskEvent) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetTaskType() string {
+	if m != nil {
+		return m.TaskType
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetActionType() string {
+	if m != nil {
+		return m.ActionType
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *TaskEvent) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *TaskEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+// TailCheckNodesLogRequest contains the request of following the nodes check log of a node,
+// offset is the position in the log to start from, it is used to resume a broken stream.
+type TailCheckNodesLogRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	NodeName  string `protobuf:"bytes,2,opt,name=nodeName" json:"nodeName,omitempty"`
+	Offset    int64  `protobuf:"varint,3,opt,name=offset" json:"offset,omitempty"`
+}
+
+func (m *TailCheckNodesLogRequest) Reset()                    { *m = TailCheckNodesLogRequest{} }
+func (m *TailCheckNodesLogRequest) String() string            { return proto.CompactTextString(m) }
+func (*TailCheckNodesLogRequest) ProtoMessage()               {}
+func (*TailCheckNodesLogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *TailCheckNodesLogRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *TailCheckNodesLogRequest) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *TailCheckNodesLogRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+// TailDeployLogRequest contains the request of following the deploy log of a node and role,
+// offset is the position in the log to start from, it is used to resume a broken stream.
+type TailDeployLogRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	Role      string `protobuf:"bytes,2,opt,name=role" json:"role,omitempty"`
+	NodeName  string `protobuf:"bytes,3,opt,name=nodeName" json:"nodeName,omitempty"`
+	Offset    int64  `protobuf:"varint,4,opt,name=offset" json:"offset,omitempty"`
+}
+
+func (m *TailDeployLogRequest) Reset()                    { *m = TailDeployLogRequest{} }
+func (m *TailDeployLogRequest) String() string            { return proto.CompactTextString(m) }
+func (*TailDeployLogRequest) ProtoMessage()               {}
+func (*TailDeployLogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *TailDeployLogRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *TailDeployLogRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *TailDeployLogRequest) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *TailDeployLogRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+// LogChunk is a piece of the followed log, the log is the same as the one got by GetCheckNodesLog
+// or GetDeployLog once the task finished.
+type LogChunk struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// offset is the position of the data in the log.
+	Offset int64 `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+}
+
+func (m *LogChunk) Reset()                    { *m = LogChunk{} }
+func (m *LogChunk) String() string            { return proto.CompactTextString(m) }
+func (*LogChunk) ProtoMessage()               {}
+func (*LogChunk) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *LogChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *LogChunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+// KnownHost is a ssh host key pinned on the first connection to the host.
+type KnownHost struct {
+	// host is the normalized address, the port is omitted if it's 22, like "192.168.0.1" or "[192.168.0.1]:2222".
+	Host    string `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+	KeyType string `protobuf:"bytes,2,opt,name=keyType" json:"keyType,omitempty"`
+	// fingerprint is the SHA256 fingerprint of the host key.
+	Fingerprint     string `protobuf:"bytes,3,opt,name=fingerprint" json:"fingerprint,omitempty"`
+	PinnedTimestamp int64  `protobuf:"varint,4,opt,name=pinnedTimestamp" json:"pinnedTimestamp,omitempty"`
+}
+
+func (m *KnownHost) Reset()                    { *m = KnownHost{} }
+func (m *KnownHost) String() string            { return proto.CompactTextString(m) }
+func (*KnownHost) ProtoMessage()               {}
+func (*KnownHost) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *KnownHost) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *KnownHost) GetKeyType() string {
+	if m != nil {
+		return m.KeyType
+	}
+	return ""
+}
+
+func (m *KnownHost) GetFingerprint() string {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return ""
+}
+
+func (m *KnownHost) GetPinnedTimestamp() int64 {
+	if m != nil {
+		return m.PinnedTimestamp
+	}
+	return 0
+}
+
+// ListKnownHostsRequest contains the request of listing the pinned ssh host keys.
+type ListKnownHostsRequest struct {
+}
+
+func (m *ListKnownHostsRequest) Reset()                    { *m = ListKnownHostsRequest{} }
+func (m *ListKnownHostsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListKnownHostsRequest) ProtoMessage()               {}
+func (*ListKnownHostsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+// ListKnownHostsReply contains the pinned ssh host keys.
+type ListKnownHostsReply struct {
+	KnownHosts []*KnownHost `protobuf:"bytes,1,rep,name=knownHosts" json:"knownHosts,omitempty"`
+}
+
+func (m *ListKnownHostsReply) Reset()                    { *m = ListKnownHostsReply{} }
+func (m *ListKnownHostsReply) String() string            { return proto.CompactTextString(m) }
+func (*ListKnownHostsReply) ProtoMessage()               {}
+func (*ListKnownHostsReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *ListKnownHostsReply) GetKnownHosts() []*KnownHost {
+	if m != nil {
+		return m.KnownHosts
+	}
+	return nil
+}
+
+// ForgetKnownHostRequest contains the request of removing the pinned ssh host key of a host,
+// the host key will be pinned again on the next connection. host is an ip or "ip:port".
+type ForgetKnownHostRequest struct {
+	Host string `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+}
+
+func (m *ForgetKnownHostRequest) Reset()                    { *m = ForgetKnownHostRequest{} }
+func (m *ForgetKnownHostRequest) String() string            { return proto.CompactTextString(m) }
+func (*ForgetKnownHostRequest) ProtoMessage()               {}
+func (*ForgetKnownHostRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *ForgetKnownHostRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+// ForgetKnownHostReply contains the response of a forget known host request, forgotten is false
+// if the host key was not pinned.
+type ForgetKnownHostReply struct {
+	Forgotten bool   `protobuf:"varint,1,opt,name=forgotten" json:"forgotten,omitempty"`
+	Err       *Error `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *ForgetKnownHostReply) Reset()                    { *m = ForgetKnownHostReply{} }
+func (m *ForgetKnownHostReply) String() string            { return proto.CompactTextString(m) }
+func (*ForgetKnownHostReply) ProtoMessage()               {}
+func (*ForgetKnownHostReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *ForgetKnownHostReply) GetForgotten() bool {
+	if m != nil {
+		return m.Forgotten
+	}
+	return false
+}
+
+func (m *ForgetKnownHostReply) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+// CalicoOptions options for checking requirements for deploying calico network.
+type CalicoOptions struct {
+	// if checkConnectivityAll = true, check connectivity between each pair of nodes bidirectionally.
+	CheckConnectivityAll bool `protobuf:"varint,1,opt,name=checkConnectivityAll" json:"checkConnectivityAll,omitempty"`
+	// EncapsulationMode could be ["vxlan","ipip","none"].
+	EncapsulationMode string `protobuf:"bytes,2,opt,name=encapsulationMode" json:"encapsulationMode,omitempty"`
+	VxlanPort         uint32 `protobuf:"varint,10,opt,name=vxlanPort" json:"vxlanPort,omitempty"`
+}
+
+func (m *CalicoOptions) Reset()                    { *m = CalicoOptions{} }
+func (m *CalicoOptions) String() string            { return proto.CompactTextString(m) }
+func (*CalicoOptions) ProtoMessage()               {}
+func (*CalicoOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *CalicoOptions) GetCheckConnectivityAll() bool {
+	if m != nil {
+		return m.CheckConnectivityAll
+	}
+	return false
+}
+
+func (m *CalicoOptions) GetEncapsulationMode() string {
+	if m != nil {
+		return m.EncapsulationMode
+	}
+	return ""
+}
+
+func (m *CalicoOptions) GetVxlanPort() uint32 {
+	if m != nil {
+		return m.VxlanPort
+	}
+	return 0
+}
+
+// NetworkOptions options for deploying network. affects checked items.
+type NetworkOptions struct {
+	NetworkType string `protobuf:"bytes,1,opt,name=networkType" json:"networkType,omitempty"`
+	// options for a specified network type. Starts from 10 to reserve field 2-9.
+	CalicoOptions *CalicoOptions `protobuf:"bytes,10,opt,name=calicoOptions" json:"calicoOptions,omitempty"`
+}
+
+func (m *NetworkOptions) Reset()                    { *m = NetworkOptions{} }
+func (m *NetworkOptions) String() string            { return proto.CompactTextString(m) }
+func (*NetworkOptions) ProtoMessage()               {}
+func (*NetworkOptions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *NetworkOptions) GetNetworkType() string {
+	if m != nil {
+		return m.NetworkType
+	}
+	return ""
+}
+
+func (m *NetworkOptions) GetCalicoOptions() *CalicoOptions {
+	if m != nil {
+		return m.CalicoOptions
+	}
+	return nil
+}
+
+// CheckNetworkRequirementRequest nodes and network options when checking
+type CheckNetworkRequirementRequest struct {
+	Nodes     []*Node         `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+	Options   *NetworkOptions `protobuf:"bytes,2,opt,name=options" json:"options,omitempty"`
+	ClusterId string          `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
+}
+
+func (m *CheckNetworkRequirementRequest) Reset()         { *m = CheckNetworkRequirementRequest{} }
+func (m *CheckNetworkRequirementRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckNetworkRequirementRequest) ProtoMessage()    {}
+func (*CheckNetworkRequirementRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{54}
+}
+
+func (m *CheckNetworkRequirementRequest) GetNodes() []*Node {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *CheckNetworkRequirementRequest) GetOptions() *NetworkOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *CheckNetworkRequirementRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+type ConnectivityCheckResult struct {
+	SourceNodeName      string             `protobuf:"bytes,1,opt,name=SourceNodeName" json:"SourceNodeName,omitempty"`
+	DestinationNodeName string             `protobuf:"bytes,2,opt,name=DestinationNodeName" json:"DestinationNodeName,omitempty"`
+	Status              string             `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	Err                 *Error             `protobuf:"bytes,4,opt,name=err" json:"err,omitempty"`
+	Items               []*ItemCheckResult `protobuf:"bytes,5,rep,name=items" json:"items,omitempty"`
+}
+
+func (m *ConnectivityCheckResult) Reset()                    { *m = ConnectivityCheckResult{} }
+func (m *ConnectivityCheckResult) String() string            { return proto.CompactTextString(m) }
+func (*ConnectivityCheckResult) ProtoMessage()               {}
+func (*ConnectivityCheckResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+func (m *ConnectivityCheckResult) GetSourceNodeName() string {
+	if m != nil {
+		return m.SourceNodeName
+	}
+	return ""
+}
+
+func (m *ConnectivityCheckResult) GetDestinationNodeName() string {
+	if m != nil {
+		return m.DestinationNodeName
+	}
+	return ""
+}
+
+func (m *ConnectivityCheckResult) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ConnectivityCheckResult) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *ConnectivityCheckResult) GetItems() []*ItemCheckResult {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+// CheckNetworkRequirementsReply
+type CheckNetworkRequirementsReply struct {
+	Passed         bool                       `protobuf:"varint,1,opt,name=passed" json:"passed,omitempty"`
+	Err            *Error                     `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	Nodes          []*NodeCheckResult         `protobuf:"bytes,3,rep,name=nodes" json:"nodes,omitempty"`
+	Connectivities []*ConnectivityCheckResult `protobuf:"bytes,4,rep,name=connectivities" json:"connectivities,omitempty"`
+	ClusterId      string                     `protobuf:"bytes,5,opt,name=clusterId" json:"clusterId,omitempty"`
+}
+
+func (m *CheckNetworkRequirementsReply) Reset()                    { *m = CheckNetworkRequirementsReply{} }
+func (m *CheckNetworkRequirementsReply) String() string            { return proto.CompactTextString(m) }
+func (*CheckNetworkRequirementsReply) ProtoMessage()               {}
+func (*CheckNetworkRequirementsReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *CheckNetworkRequirementsReply) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *CheckNetworkRequirementsReply) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *CheckNetworkRequirementsReply) GetNodes() []*NodeCheckResult {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *CheckNetworkRequirementsReply) GetConnectivities() []*ConnectivityCheckResult {
+	if m != nil {
+		return m.Connectivities
+	}
+	return nil
+}
+
+func (m *CheckNetworkRequirementsReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+// GetTaskGraphRequest contains the request of getting the dependency graph of a task. If taskName
+// is empty, the deploy task of the cluster is used.
+type GetTaskGraphRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	TaskName  string `protobuf:"bytes,2,opt,name=taskName" json:"taskName,omitempty"`
+}
+
+func (m *GetTaskGraphRequest) Reset()                    { *m = GetTaskGraphRequest{} }
+func (m *GetTaskGraphRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetTaskGraphRequest) ProtoMessage()               {}
+func (*GetTaskGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+func (m *GetTaskGraphRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *GetTaskGraphRequest) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+// TaskGraphNode is the task itself, one of its sub tasks, or one of its actions in the graph.
+type TaskGraphNode struct {
+	// kind is "task" or "action".
+	Kind string `protobuf:"bytes,1,opt,name=kind" json:"kind,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// type is the task type or the action type.
+	Type string `protobuf:"bytes,3,opt,name=type" json:"type,omitempty"`
+	// parent is the name of the task which the sub task or action belongs to.
+	Parent string `protobuf:"bytes,4,opt,name=parent" json:"parent,omitempty"`
+	// nodeName is set only for actions.
+	NodeName string `protobuf:"bytes,5,opt,name=nodeName" json:"nodeName,omitempty"`
+	Status   string `protobuf:"bytes,6,opt,name=status" json:"status,omitempty"`
+}
+
+func (m *TaskGraphNode) Reset()                    { *m = TaskGraphNode{} }
+func (m *TaskGraphNode) String() string            { return proto.CompactTextString(m) }
+func (*TaskGraphNode) ProtoMessage()               {}
+func (*TaskGraphNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *TaskGraphNode) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *TaskGraphNode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TaskGraphNode) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *TaskGraphNode) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *TaskGraphNode) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *TaskGraphNode) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+// TaskGraphEdge means the task named to depends on the task named from, which are sub tasks of the same task.
+type TaskGraphEdge struct {
+	From string `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	To   string `protobuf:"bytes,2,opt,name=to" json:"to,omitempty"`
+}
+
+func (m *TaskGraphEdge) Reset()                    { *m = TaskGraphEdge{} }
+func (m *TaskGraphEdge) String() string            { return proto.CompactTextString(m) }
+func (*TaskGraphEdge) ProtoMessage()               {}
+func (*TaskGraphEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *TaskGraphEdge) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *TaskGraphEdge) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+// GetTaskGraphReply represents the dependency graph of a task, the sub tasks are split only after the task starts.
+type GetTaskGraphReply struct {
+	ClusterId string           `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	TaskName  string           `protobuf:"bytes,2,opt,name=taskName" json:"taskName,omitempty"`
+	Nodes     []*TaskGraphNode `protobuf:"bytes,3,rep,name=nodes" json:"nodes,omitempty"`
+	Edges     []*TaskGraphEdge `protobuf:"bytes,4,rep,name=edges" json:"edges,omitempty"`
+	Err       *Error           `protobuf:"bytes,5,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *GetTaskGraphReply) Reset()                    { *m = GetTaskGraphReply{} }
+func (m *GetTaskGraphReply) String() string            { return proto.CompactTextString(m) }
+func (*GetTaskGraphReply) ProtoMessage()               {}
+func (*GetTaskGraphReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *GetTaskGraphReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *GetTaskGraphReply) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *GetTaskGraphReply) GetNodes() []*TaskGraphNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *GetTaskGraphReply) GetEdges() []*TaskGraphEdge {
+	if m != nil {
+		return m.Edges
+	}
+	return nil
 }
 
-// CheckNetworkRequirementsReply
-type CheckNetworkRequirementsReply struct {
-	Passed         bool                       `protobuf:"varint,1,opt,name=passed" json:"passed,omitempty"`
-	Err            *Error                     `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
-	Nodes          []*NodeCheckResult         `protobuf:"bytes,3,rep,name=nodes" json:"nodes,omitempty"`
-	Connectivities []*ConnectivityCheckResult `protobuf:"bytes,4,rep,name=connectivities" json:"connectivities,omitempty"`
+func (m *GetTaskGraphReply) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
 }
 
-func (m *CheckNetworkRequirementsReply) Reset()                    { *m = CheckNetworkRequirementsReply{} }
-func (m *CheckNetworkRequirementsReply) String() string            { return proto.CompactTextString(m) }
-func (*CheckNetworkRequirementsReply) ProtoMessage()               {}
-func (*CheckNetworkRequirementsReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+// PlanDeployRequest contains the same deployment as the DeployRequest, which is planned but not executed.
+type PlanDeployRequest struct {
+	NodeConfigs   []*NodeDeployConfig `protobuf:"bytes,1,rep,name=nodeConfigs" json:"nodeConfigs,omitempty"`
+	ClusterConfig *ClusterConfig      `protobuf:"bytes,2,opt,name=clusterConfig" json:"clusterConfig,omitempty"`
+	ClusterId     string              `protobuf:"bytes,3,opt,name=clusterId" json:"clusterId,omitempty"`
+}
 
-func (m *CheckNetworkRequirementsReply) GetPassed() bool {
+func (m *PlanDeployRequest) Reset()                    { *m = PlanDeployRequest{} }
+func (m *PlanDeployRequest) String() string            { return proto.CompactTextString(m) }
+func (*PlanDeployRequest) ProtoMessage()               {}
+func (*PlanDeployRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *PlanDeployRequest) GetNodeConfigs() []*NodeDeployConfig {
 	if m != nil {
-		return m.Passed
+		return m.NodeConfigs
+	}
+	return nil
+}
+
+func (m *PlanDeployRequest) GetClusterConfig() *ClusterConfig {
+	if m != nil {
+		return m.ClusterConfig
+	}
+	return nil
+}
+
+func (m *PlanDeployRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+// PlanStep is a step planned on a node, a command to run or a file to put.
+type PlanStep struct {
+	NodeName string `protobuf:"bytes,1,opt,name=nodeName" json:"nodeName,omitempty"`
+	// action is "run" or "putFile", the same as the steps of the machine transcripts.
+	Action string `protobuf:"bytes,2,opt,name=action" json:"action,omitempty"`
+	// command is set only for running a command.
+	Command string `protobuf:"bytes,3,opt,name=command" json:"command,omitempty"`
+	// path, mode and content are set only for putting a file, the content is hidden for the
+	// scripts shipped with the deploy controller and the certs and keys.
+	Path          string `protobuf:"bytes,4,opt,name=path" json:"path,omitempty"`
+	Mode          string `protobuf:"bytes,5,opt,name=mode" json:"mode,omitempty"`
+	Content       string `protobuf:"bytes,6,opt,name=content" json:"content,omitempty"`
+	ContentHidden bool   `protobuf:"varint,7,opt,name=contentHidden" json:"contentHidden,omitempty"`
+}
+
+func (m *PlanStep) Reset()                    { *m = PlanStep{} }
+func (m *PlanStep) String() string            { return proto.CompactTextString(m) }
+func (*PlanStep) ProtoMessage()               {}
+func (*PlanStep) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *PlanStep) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *PlanStep) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *PlanStep) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *PlanStep) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *PlanStep) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *PlanStep) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *PlanStep) GetContentHidden() bool {
+	if m != nil {
+		return m.ContentHidden
 	}
 	return false
 }
 
-func (m *CheckNetworkRequirementsReply) GetErr() *Error {
+// ActionPlan represents what an action would do on the nodes.
+type ActionPlan struct {
+	Name     string      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type     string      `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	NodeName string      `protobuf:"bytes,3,opt,name=nodeName" json:"nodeName,omitempty"`
+	Steps    []*PlanStep `protobuf:"bytes,4,rep,name=steps" json:"steps,omitempty"`
+	// err is set if the action can't be planned, the steps may be incomplete then.
+	Err *Error `protobuf:"bytes,5,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *ActionPlan) Reset()                    { *m = ActionPlan{} }
+func (m *ActionPlan) String() string            { return proto.CompactTextString(m) }
+func (*ActionPlan) ProtoMessage()               {}
+func (*ActionPlan) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *ActionPlan) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ActionPlan) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ActionPlan) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *ActionPlan) GetSteps() []*PlanStep {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
+func (m *ActionPlan) GetErr() *Error {
 	if m != nil {
 		return m.Err
 	}
 	return nil
 }
 
-func (m *CheckNetworkRequirementsReply) GetNodes() []*NodeCheckResult {
+// TaskPlan represents a task split into its sub tasks and actions.
+type TaskPlan struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type   string `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	Parent string `protobuf:"bytes,3,opt,name=parent" json:"parent,omitempty"`
+	// dependencies are the names of the sibling sub tasks which the task depends on.
+	Dependencies []string      `protobuf:"bytes,4,rep,name=dependencies" json:"dependencies,omitempty"`
+	SubTasks     []*TaskPlan   `protobuf:"bytes,5,rep,name=subTasks" json:"subTasks,omitempty"`
+	Actions      []*ActionPlan `protobuf:"bytes,6,rep,name=actions" json:"actions,omitempty"`
+	// err is set if the task can't be split.
+	Err *Error `protobuf:"bytes,7,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *TaskPlan) Reset()                    { *m = TaskPlan{} }
+func (m *TaskPlan) String() string            { return proto.CompactTextString(m) }
+func (*TaskPlan) ProtoMessage()               {}
+func (*TaskPlan) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+func (m *TaskPlan) GetName() string {
 	if m != nil {
-		return m.Nodes
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TaskPlan) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *TaskPlan) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *TaskPlan) GetDependencies() []string {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+func (m *TaskPlan) GetSubTasks() []*TaskPlan {
+	if m != nil {
+		return m.SubTasks
+	}
+	return nil
+}
+
+func (m *TaskPlan) GetActions() []*ActionPlan {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *TaskPlan) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+// PlanDeployReply contains the plan of the deployment.
+type PlanDeployReply struct {
+	ClusterId string    `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	Plan      *TaskPlan `protobuf:"bytes,2,opt,name=plan" json:"plan,omitempty"`
+	Err       *Error    `protobuf:"bytes,3,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *PlanDeployReply) Reset()                    { *m = PlanDeployReply{} }
+func (m *PlanDeployReply) String() string            { return proto.CompactTextString(m) }
+func (*PlanDeployReply) ProtoMessage()               {}
+func (*PlanDeployReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+func (m *PlanDeployReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *PlanDeployReply) GetPlan() *TaskPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+func (m *PlanDeployReply) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+// GetTaskEventsRequest contains the request of getting the journal of a task. If taskName
+// is empty, the deploy task of the cluster is used.
+type GetTaskEventsRequest struct {
+	ClusterId string `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	TaskName  string `protobuf:"bytes,2,opt,name=taskName" json:"taskName,omitempty"`
+}
+
+func (m *GetTaskEventsRequest) Reset()                    { *m = GetTaskEventsRequest{} }
+func (m *GetTaskEventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetTaskEventsRequest) ProtoMessage()               {}
+func (*GetTaskEventsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *GetTaskEventsRequest) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *GetTaskEventsRequest) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+// JournalEvent is a status transition of the task, one of its sub tasks, or one of its actions.
+type JournalEvent struct {
+	// seq is the sequence number of the event in the journal, starting from 1.
+	Seq uint64 `protobuf:"varint,1,opt,name=seq" json:"seq,omitempty"`
+	// kind is "task" or "action".
+	Kind string `protobuf:"bytes,2,opt,name=kind" json:"kind,omitempty"`
+	Name string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	// parent is the name of the task which the sub task or action belongs to.
+	Parent string `protobuf:"bytes,4,opt,name=parent" json:"parent,omitempty"`
+	// actionType and nodeName are set only for actions.
+	ActionType string `protobuf:"bytes,5,opt,name=actionType" json:"actionType,omitempty"`
+	NodeName   string `protobuf:"bytes,6,opt,name=nodeName" json:"nodeName,omitempty"`
+	FromStatus string `protobuf:"bytes,7,opt,name=fromStatus" json:"fromStatus,omitempty"`
+	ToStatus   string `protobuf:"bytes,8,opt,name=toStatus" json:"toStatus,omitempty"`
+	Err        *Error `protobuf:"bytes,9,opt,name=err" json:"err,omitempty"`
+	// timestamp is the unix time in milliseconds when the transition happened.
+	Timestamp int64 `protobuf:"varint,10,opt,name=timestamp" json:"timestamp,omitempty"`
+	// duration is how long the task or action stayed in fromStatus, in milliseconds.
+	Duration int64 `protobuf:"varint,11,opt,name=duration" json:"duration,omitempty"`
+	// causeSeq is set only for a failed task, it's the seq of the first failure of its sub tasks or actions.
+	CauseSeq uint64 `protobuf:"varint,12,opt,name=causeSeq" json:"causeSeq,omitempty"`
+}
+
+func (m *JournalEvent) Reset()                    { *m = JournalEvent{} }
+func (m *JournalEvent) String() string            { return proto.CompactTextString(m) }
+func (*JournalEvent) ProtoMessage()               {}
+func (*JournalEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+func (m *JournalEvent) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *JournalEvent) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *JournalEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *JournalEvent) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *JournalEvent) GetActionType() string {
+	if m != nil {
+		return m.ActionType
+	}
+	return ""
+}
+
+func (m *JournalEvent) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *JournalEvent) GetFromStatus() string {
+	if m != nil {
+		return m.FromStatus
+	}
+	return ""
+}
+
+func (m *JournalEvent) GetToStatus() string {
+	if m != nil {
+		return m.ToStatus
+	}
+	return ""
+}
+
+func (m *JournalEvent) GetErr() *Error {
+	if m != nil {
+		return m.Err
+	}
+	return nil
+}
+
+func (m *JournalEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *JournalEvent) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *JournalEvent) GetCauseSeq() uint64 {
+	if m != nil {
+		return m.CauseSeq
+	}
+	return 0
+}
+
+// GetTaskEventsReply contains all the status transitions recorded in the journal of a task in order.
+type GetTaskEventsReply struct {
+	ClusterId string          `protobuf:"bytes,1,opt,name=clusterId" json:"clusterId,omitempty"`
+	TaskName  string          `protobuf:"bytes,2,opt,name=taskName" json:"taskName,omitempty"`
+	TaskType  string          `protobuf:"bytes,3,opt,name=taskType" json:"taskType,omitempty"`
+	Events    []*JournalEvent `protobuf:"bytes,4,rep,name=events" json:"events,omitempty"`
+	Err       *Error          `protobuf:"bytes,5,opt,name=err" json:"err,omitempty"`
+}
+
+func (m *GetTaskEventsReply) Reset()                    { *m = GetTaskEventsReply{} }
+func (m *GetTaskEventsReply) String() string            { return proto.CompactTextString(m) }
+func (*GetTaskEventsReply) ProtoMessage()               {}
+func (*GetTaskEventsReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+func (m *GetTaskEventsReply) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *GetTaskEventsReply) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *GetTaskEventsReply) GetTaskType() string {
+	if m != nil {
+		return m.TaskType
+	}
+	return ""
+}
+
+func (m *GetTaskEventsReply) GetEvents() []*JournalEvent {
+	if m != nil {
+		return m.Events
 	}
 	return nil
 }
 
-func (m *CheckNetworkRequirementsReply) GetConnectivities() []*ConnectivityCheckResult {
+func (m *GetTaskEventsReply) GetErr() *Error {
 	if m != nil {
-		return m.Connectivities
+		return m.Err
 	}
 	return nil
 }
@@ -1183,6 +2641,8 @@ func (m *CheckNetworkRequirementsReply) GetConnectivities() []*ConnectivityCheck
 func init() {
 	proto.RegisterType((*Auth)(nil), "protos.Auth")
 	proto.RegisterType((*SSH)(nil), "protos.SSH")
+	proto.RegisterType((*Sudo)(nil), "protos.Sudo")
+	proto.RegisterType((*JumpHost)(nil), "protos.JumpHost")
 	proto.RegisterType((*Node)(nil), "protos.Node")
 	proto.RegisterType((*Error)(nil), "protos.Error")
 	proto.RegisterType((*TestConnectionRequest)(nil), "protos.TestConnectionRequest")
@@ -1206,6 +2666,8 @@ func init() {
 	proto.RegisterType((*NodeDeployConfig)(nil), "protos.NodeDeployConfig")
 	proto.RegisterType((*DeployRequest)(nil), "protos.DeployRequest")
 	proto.RegisterType((*DeployReply)(nil), "protos.DeployReply")
+	proto.RegisterType((*ResumeDeployRequest)(nil), "protos.ResumeDeployRequest")
+	proto.RegisterType((*ResumeDeployReply)(nil), "protos.ResumeDeployReply")
 	proto.RegisterType((*GetDeployResultRequest)(nil), "protos.GetDeployResultRequest")
 	proto.RegisterType((*DeployItem)(nil), "protos.DeployItem")
 	proto.RegisterType((*DeployItemResult)(nil), "protos.DeployItemResult")
@@ -1214,11 +2676,38 @@ func init() {
 	proto.RegisterType((*GetDeployLogReply)(nil), "protos.GetDeployLogReply")
 	proto.RegisterType((*FetchKubeConfigRequest)(nil), "protos.FetchKubeConfigRequest")
 	proto.RegisterType((*FetchKubeConfigReply)(nil), "protos.FetchKubeConfigReply")
+	proto.RegisterType((*CancelTaskRequest)(nil), "protos.CancelTaskRequest")
+	proto.RegisterType((*CancelTaskReply)(nil), "protos.CancelTaskReply")
+	proto.RegisterType((*ListTasksRequest)(nil), "protos.ListTasksRequest")
+	proto.RegisterType((*TaskSummary)(nil), "protos.TaskSummary")
+	proto.RegisterType((*ListTasksReply)(nil), "protos.ListTasksReply")
+	proto.RegisterType((*WatchTaskRequest)(nil), "protos.WatchTaskRequest")
+	proto.RegisterType((*TaskEvent)(nil), "protos.TaskEvent")
+	proto.RegisterType((*TailCheckNodesLogRequest)(nil), "protos.TailCheckNodesLogRequest")
+	proto.RegisterType((*TailDeployLogRequest)(nil), "protos.TailDeployLogRequest")
+	proto.RegisterType((*LogChunk)(nil), "protos.LogChunk")
+	proto.RegisterType((*KnownHost)(nil), "protos.KnownHost")
+	proto.RegisterType((*ListKnownHostsRequest)(nil), "protos.ListKnownHostsRequest")
+	proto.RegisterType((*ListKnownHostsReply)(nil), "protos.ListKnownHostsReply")
+	proto.RegisterType((*ForgetKnownHostRequest)(nil), "protos.ForgetKnownHostRequest")
+	proto.RegisterType((*ForgetKnownHostReply)(nil), "protos.ForgetKnownHostReply")
 	proto.RegisterType((*CalicoOptions)(nil), "protos.CalicoOptions")
 	proto.RegisterType((*NetworkOptions)(nil), "protos.NetworkOptions")
 	proto.RegisterType((*CheckNetworkRequirementRequest)(nil), "protos.CheckNetworkRequirementRequest")
 	proto.RegisterType((*ConnectivityCheckResult)(nil), "protos.ConnectivityCheckResult")
 	proto.RegisterType((*CheckNetworkRequirementsReply)(nil), "protos.CheckNetworkRequirementsReply")
+	proto.RegisterType((*GetTaskGraphRequest)(nil), "protos.GetTaskGraphRequest")
+	proto.RegisterType((*TaskGraphNode)(nil), "protos.TaskGraphNode")
+	proto.RegisterType((*TaskGraphEdge)(nil), "protos.TaskGraphEdge")
+	proto.RegisterType((*GetTaskGraphReply)(nil), "protos.GetTaskGraphReply")
+	proto.RegisterType((*PlanDeployRequest)(nil), "protos.PlanDeployRequest")
+	proto.RegisterType((*PlanStep)(nil), "protos.PlanStep")
+	proto.RegisterType((*ActionPlan)(nil), "protos.ActionPlan")
+	proto.RegisterType((*TaskPlan)(nil), "protos.TaskPlan")
+	proto.RegisterType((*PlanDeployReply)(nil), "protos.PlanDeployReply")
+	proto.RegisterType((*GetTaskEventsRequest)(nil), "protos.GetTaskEventsRequest")
+	proto.RegisterType((*JournalEvent)(nil), "protos.JournalEvent")
+	proto.RegisterType((*GetTaskEventsReply)(nil), "protos.GetTaskEventsReply")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1237,10 +2726,21 @@ type DeployContollerClient interface {
 	GetCheckNodesResult(ctx context.Context, in *GetCheckNodesResultRequest, opts ...grpc.CallOption) (*GetCheckNodesResultReply, error)
 	GetCheckNodesLog(ctx context.Context, in *GetCheckNodesLogRequest, opts ...grpc.CallOption) (*GetCheckNodesLogReply, error)
 	Deploy(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*DeployReply, error)
+	ResumeDeploy(ctx context.Context, in *ResumeDeployRequest, opts ...grpc.CallOption) (*ResumeDeployReply, error)
 	GetDeployResult(ctx context.Context, in *GetDeployResultRequest, opts ...grpc.CallOption) (*GetDeployResultReply, error)
 	GetDeployLog(ctx context.Context, in *GetDeployLogRequest, opts ...grpc.CallOption) (*GetDeployLogReply, error)
 	FetchKubeConfig(ctx context.Context, in *FetchKubeConfigRequest, opts ...grpc.CallOption) (*FetchKubeConfigReply, error)
 	CheckNetworkRequirements(ctx context.Context, in *CheckNetworkRequirementRequest, opts ...grpc.CallOption) (*CheckNetworkRequirementsReply, error)
+	CancelTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*CancelTaskReply, error)
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksReply, error)
+	WatchTask(ctx context.Context, in *WatchTaskRequest, opts ...grpc.CallOption) (DeployContoller_WatchTaskClient, error)
+	TailCheckNodesLog(ctx context.Context, in *TailCheckNodesLogRequest, opts ...grpc.CallOption) (DeployContoller_TailCheckNodesLogClient, error)
+	TailDeployLog(ctx context.Context, in *TailDeployLogRequest, opts ...grpc.CallOption) (DeployContoller_TailDeployLogClient, error)
+	ListKnownHosts(ctx context.Context, in *ListKnownHostsRequest, opts ...grpc.CallOption) (*ListKnownHostsReply, error)
+	ForgetKnownHost(ctx context.Context, in *ForgetKnownHostRequest, opts ...grpc.CallOption) (*ForgetKnownHostReply, error)
+	GetTaskGraph(ctx context.Context, in *GetTaskGraphRequest, opts ...grpc.CallOption) (*GetTaskGraphReply, error)
+	PlanDeploy(ctx context.Context, in *PlanDeployRequest, opts ...grpc.CallOption) (*PlanDeployReply, error)
+	GetTaskEvents(ctx context.Context, in *GetTaskEventsRequest, opts ...grpc.CallOption) (*GetTaskEventsReply, error)
 }
 
 type deployContollerClient struct {
@@ -1296,6 +2796,15 @@ func (c *deployContollerClient) Deploy(ctx context.Context, in *DeployRequest, o
 	return out, nil
 }
 
+func (c *deployContollerClient) ResumeDeploy(ctx context.Context, in *ResumeDeployRequest, opts ...grpc.CallOption) (*ResumeDeployReply, error) {
+	out := new(ResumeDeployReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/ResumeDeploy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *deployContollerClient) GetDeployResult(ctx context.Context, in *GetDeployResultRequest, opts ...grpc.CallOption) (*GetDeployResultReply, error) {
 	out := new(GetDeployResultReply)
 	err := grpc.Invoke(ctx, "/protos.DeployContoller/GetDeployResult", in, out, c.cc, opts...)
@@ -1332,6 +2841,165 @@ func (c *deployContollerClient) CheckNetworkRequirements(ctx context.Context, in
 	return out, nil
 }
 
+func (c *deployContollerClient) CancelTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*CancelTaskReply, error) {
+	out := new(CancelTaskReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/CancelTask", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployContollerClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksReply, error) {
+	out := new(ListTasksReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/ListTasks", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployContollerClient) WatchTask(ctx context.Context, in *WatchTaskRequest, opts ...grpc.CallOption) (DeployContoller_WatchTaskClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_DeployContoller_serviceDesc.Streams[0], c.cc, "/protos.DeployContoller/WatchTask", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deployContollerWatchTaskClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DeployContoller_WatchTaskClient interface {
+	Recv() (*TaskEvent, error)
+	grpc.ClientStream
+}
+
+type deployContollerWatchTaskClient struct {
+	grpc.ClientStream
+}
+
+func (x *deployContollerWatchTaskClient) Recv() (*TaskEvent, error) {
+	m := new(TaskEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deployContollerClient) TailCheckNodesLog(ctx context.Context, in *TailCheckNodesLogRequest, opts ...grpc.CallOption) (DeployContoller_TailCheckNodesLogClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_DeployContoller_serviceDesc.Streams[1], c.cc, "/protos.DeployContoller/TailCheckNodesLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deployContollerTailCheckNodesLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DeployContoller_TailCheckNodesLogClient interface {
+	Recv() (*LogChunk, error)
+	grpc.ClientStream
+}
+
+type deployContollerTailCheckNodesLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *deployContollerTailCheckNodesLogClient) Recv() (*LogChunk, error) {
+	m := new(LogChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deployContollerClient) TailDeployLog(ctx context.Context, in *TailDeployLogRequest, opts ...grpc.CallOption) (DeployContoller_TailDeployLogClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_DeployContoller_serviceDesc.Streams[2], c.cc, "/protos.DeployContoller/TailDeployLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deployContollerTailDeployLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DeployContoller_TailDeployLogClient interface {
+	Recv() (*LogChunk, error)
+	grpc.ClientStream
+}
+
+type deployContollerTailDeployLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *deployContollerTailDeployLogClient) Recv() (*LogChunk, error) {
+	m := new(LogChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deployContollerClient) ListKnownHosts(ctx context.Context, in *ListKnownHostsRequest, opts ...grpc.CallOption) (*ListKnownHostsReply, error) {
+	out := new(ListKnownHostsReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/ListKnownHosts", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployContollerClient) ForgetKnownHost(ctx context.Context, in *ForgetKnownHostRequest, opts ...grpc.CallOption) (*ForgetKnownHostReply, error) {
+	out := new(ForgetKnownHostReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/ForgetKnownHost", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployContollerClient) GetTaskGraph(ctx context.Context, in *GetTaskGraphRequest, opts ...grpc.CallOption) (*GetTaskGraphReply, error) {
+	out := new(GetTaskGraphReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/GetTaskGraph", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployContollerClient) PlanDeploy(ctx context.Context, in *PlanDeployRequest, opts ...grpc.CallOption) (*PlanDeployReply, error) {
+	out := new(PlanDeployReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/PlanDeploy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployContollerClient) GetTaskEvents(ctx context.Context, in *GetTaskEventsRequest, opts ...grpc.CallOption) (*GetTaskEventsReply, error) {
+	out := new(GetTaskEventsReply)
+	err := grpc.Invoke(ctx, "/protos.DeployContoller/GetTaskEvents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for DeployContoller service
 
 type DeployContollerServer interface {
@@ -1340,10 +3008,21 @@ type DeployContollerServer interface {
 	GetCheckNodesResult(context.Context, *GetCheckNodesResultRequest) (*GetCheckNodesResultReply, error)
 	GetCheckNodesLog(context.Context, *GetCheckNodesLogRequest) (*GetCheckNodesLogReply, error)
 	Deploy(context.Context, *DeployRequest) (*DeployReply, error)
+	ResumeDeploy(context.Context, *ResumeDeployRequest) (*ResumeDeployReply, error)
 	GetDeployResult(context.Context, *GetDeployResultRequest) (*GetDeployResultReply, error)
 	GetDeployLog(context.Context, *GetDeployLogRequest) (*GetDeployLogReply, error)
 	FetchKubeConfig(context.Context, *FetchKubeConfigRequest) (*FetchKubeConfigReply, error)
 	CheckNetworkRequirements(context.Context, *CheckNetworkRequirementRequest) (*CheckNetworkRequirementsReply, error)
+	CancelTask(context.Context, *CancelTaskRequest) (*CancelTaskReply, error)
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksReply, error)
+	WatchTask(*WatchTaskRequest, DeployContoller_WatchTaskServer) error
+	TailCheckNodesLog(*TailCheckNodesLogRequest, DeployContoller_TailCheckNodesLogServer) error
+	TailDeployLog(*TailDeployLogRequest, DeployContoller_TailDeployLogServer) error
+	ListKnownHosts(context.Context, *ListKnownHostsRequest) (*ListKnownHostsReply, error)
+	ForgetKnownHost(context.Context, *ForgetKnownHostRequest) (*ForgetKnownHostReply, error)
+	GetTaskGraph(context.Context, *GetTaskGraphRequest) (*GetTaskGraphReply, error)
+	PlanDeploy(context.Context, *PlanDeployRequest) (*PlanDeployReply, error)
+	GetTaskEvents(context.Context, *GetTaskEventsRequest) (*GetTaskEventsReply, error)
 }
 
 func RegisterDeployContollerServer(s *grpc.Server, srv DeployContollerServer) {
@@ -1440,6 +3119,24 @@ func _DeployContoller_Deploy_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeployContoller_ResumeDeploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeDeployRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).ResumeDeploy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/ResumeDeploy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).ResumeDeploy(ctx, req.(*ResumeDeployRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DeployContoller_GetDeployResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetDeployResultRequest)
 	if err := dec(in); err != nil {
@@ -1512,6 +3209,195 @@ func _DeployContoller_CheckNetworkRequirements_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeployContoller_CancelTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).CancelTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/CancelTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).CancelTask(ctx, req.(*CancelTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployContoller_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/ListTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployContoller_WatchTask_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeployContollerServer).WatchTask(m, &deployContollerWatchTaskServer{stream})
+}
+
+type DeployContoller_WatchTaskServer interface {
+	Send(*TaskEvent) error
+	grpc.ServerStream
+}
+
+type deployContollerWatchTaskServer struct {
+	grpc.ServerStream
+}
+
+func (x *deployContollerWatchTaskServer) Send(m *TaskEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DeployContoller_TailCheckNodesLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailCheckNodesLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeployContollerServer).TailCheckNodesLog(m, &deployContollerTailCheckNodesLogServer{stream})
+}
+
+type DeployContoller_TailCheckNodesLogServer interface {
+	Send(*LogChunk) error
+	grpc.ServerStream
+}
+
+type deployContollerTailCheckNodesLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *deployContollerTailCheckNodesLogServer) Send(m *LogChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DeployContoller_TailDeployLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailDeployLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeployContollerServer).TailDeployLog(m, &deployContollerTailDeployLogServer{stream})
+}
+
+type DeployContoller_TailDeployLogServer interface {
+	Send(*LogChunk) error
+	grpc.ServerStream
+}
+
+type deployContollerTailDeployLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *deployContollerTailDeployLogServer) Send(m *LogChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DeployContoller_ListKnownHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKnownHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).ListKnownHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/ListKnownHosts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).ListKnownHosts(ctx, req.(*ListKnownHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployContoller_ForgetKnownHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForgetKnownHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).ForgetKnownHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/ForgetKnownHost",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).ForgetKnownHost(ctx, req.(*ForgetKnownHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployContoller_GetTaskGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).GetTaskGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/GetTaskGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).GetTaskGraph(ctx, req.(*GetTaskGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployContoller_PlanDeploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanDeployRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).PlanDeploy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/PlanDeploy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).PlanDeploy(ctx, req.(*PlanDeployRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployContoller_GetTaskEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployContollerServer).GetTaskEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.DeployContoller/GetTaskEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployContollerServer).GetTaskEvents(ctx, req.(*GetTaskEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _DeployContoller_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.DeployContoller",
 	HandlerType: (*DeployContollerServer)(nil),
@@ -1536,6 +3422,10 @@ var _DeployContoller_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Deploy",
 			Handler:    _DeployContoller_Deploy_Handler,
 		},
+		{
+			MethodName: "ResumeDeploy",
+			Handler:    _DeployContoller_ResumeDeploy_Handler,
+		},
 		{
 			MethodName: "GetDeployResult",
 			Handler:    _DeployContoller_GetDeployResult_Handler,
@@ -1552,116 +3442,239 @@ var _DeployContoller_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CheckNetworkRequirements",
 			Handler:    _DeployContoller_CheckNetworkRequirements_Handler,
 		},
+		{
+			MethodName: "CancelTask",
+			Handler:    _DeployContoller_CancelTask_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _DeployContoller_ListTasks_Handler,
+		},
+		{
+			MethodName: "ListKnownHosts",
+			Handler:    _DeployContoller_ListKnownHosts_Handler,
+		},
+		{
+			MethodName: "ForgetKnownHost",
+			Handler:    _DeployContoller_ForgetKnownHost_Handler,
+		},
+		{
+			MethodName: "GetTaskGraph",
+			Handler:    _DeployContoller_GetTaskGraph_Handler,
+		},
+		{
+			MethodName: "PlanDeploy",
+			Handler:    _DeployContoller_PlanDeploy_Handler,
+		},
+		{
+			MethodName: "GetTaskEvents",
+			Handler:    _DeployContoller_GetTaskEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTask",
+			Handler:       _DeployContoller_WatchTask_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TailCheckNodesLog",
+			Handler:       _DeployContoller_TailCheckNodesLog_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TailDeployLog",
+			Handler:       _DeployContoller_TailDeployLog_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "deploy_controller.proto",
 }
 
 func init() { proto.RegisterFile("deploy_controller.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1644 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcb, 0x4f, 0x1c, 0x47,
-	0x13, 0xf7, 0xec, 0x03, 0x43, 0x2d, 0xcb, 0xa3, 0x59, 0x60, 0xbd, 0x06, 0x8c, 0x5a, 0xc6, 0xe2,
-	0xf3, 0xf7, 0x7d, 0xc8, 0x59, 0x2b, 0x91, 0x1f, 0x49, 0x24, 0x8c, 0x09, 0xc6, 0x0f, 0x62, 0x0f,
-	0xc8, 0x3e, 0x45, 0xd1, 0x30, 0xdb, 0xc0, 0x88, 0x61, 0x7a, 0xd2, 0xd3, 0xbb, 0xf1, 0x9e, 0x72,
-	0x4a, 0x94, 0x5b, 0x0e, 0x51, 0xa4, 0xfc, 0x59, 0xb9, 0xe7, 0x2f, 0x48, 0x2e, 0xf9, 0x07, 0x72,
-	0x88, 0xfa, 0x35, 0xdb, 0x33, 0x3b, 0x6b, 0xb0, 0xc9, 0x69, 0xa7, 0xeb, 0xd5, 0xbf, 0xaa, 0xae,
-	0xae, 0xae, 0x5a, 0x58, 0xec, 0x90, 0x38, 0xa4, 0xfd, 0xaf, 0x7d, 0x1a, 0x71, 0x46, 0xc3, 0x90,
-	0xb0, 0x8d, 0x98, 0x51, 0x4e, 0xd1, 0x98, 0xfc, 0x49, 0xf0, 0x6b, 0xa8, 0x6c, 0x76, 0xf9, 0x09,
-	0x42, 0x50, 0xe1, 0xfd, 0x98, 0x34, 0x9d, 0x55, 0x67, 0x7d, 0xc2, 0x95, 0xdf, 0x68, 0x05, 0xc0,
-	0x67, 0xa4, 0x43, 0x22, 0x1e, 0x78, 0x61, 0xb3, 0x24, 0x39, 0x16, 0x05, 0xb5, 0x60, 0xbc, 0x9b,
-	0x10, 0x16, 0x79, 0x67, 0xa4, 0x59, 0x96, 0xdc, 0x74, 0x8d, 0x1f, 0x42, 0x79, 0x7f, 0xff, 0x89,
-	0x30, 0x1b, 0x53, 0xc6, 0xa5, 0xd9, 0xba, 0x2b, 0xbf, 0xd1, 0x2a, 0x54, 0xbc, 0x2e, 0x3f, 0x91,
-	0x06, 0x6b, 0xed, 0x49, 0x05, 0x28, 0xd9, 0x10, 0x30, 0x5c, 0xc9, 0xc1, 0xbb, 0x50, 0xd9, 0xa3,
-	0x1d, 0x22, 0xb4, 0xa5, 0x71, 0x0d, 0x4a, 0x7c, 0xa3, 0x29, 0x28, 0x05, 0xb1, 0x06, 0x53, 0x0a,
-	0x62, 0xb4, 0x0c, 0xe5, 0x24, 0x39, 0x91, 0xfb, 0xd7, 0xda, 0x35, 0x63, 0x6c, 0x7f, 0xff, 0x89,
-	0x2b, 0xe8, 0xf8, 0x0d, 0x54, 0xb7, 0x19, 0xa3, 0x0c, 0x2d, 0xc0, 0x18, 0x23, 0x5e, 0x42, 0x23,
-	0x6d, 0x4d, 0xaf, 0x04, 0xbd, 0x43, 0xb8, 0x17, 0x18, 0x07, 0xf5, 0x4a, 0x38, 0x7f, 0x14, 0xbc,
-	0x7d, 0x41, 0xf8, 0x09, 0xed, 0x24, 0xda, 0x3d, 0x8b, 0x82, 0xef, 0xc3, 0xfc, 0x01, 0x49, 0xf8,
-	0x16, 0x8d, 0x22, 0xe2, 0xf3, 0x80, 0x46, 0x2e, 0xf9, 0xa6, 0x4b, 0x12, 0xe9, 0x5e, 0x44, 0x3b,
-	0x0a, 0xb4, 0xe5, 0x9e, 0x70, 0xc8, 0x95, 0x1c, 0xbc, 0x07, 0x73, 0x79, 0xd5, 0x38, 0xec, 0x0b,
-	0x24, 0xb1, 0x97, 0x24, 0xa4, 0x23, 0x55, 0xc7, 0x5d, 0xbd, 0x42, 0x37, 0xa0, 0x4c, 0x18, 0xd3,
-	0xe1, 0xaa, 0x1b, 0x7b, 0xd2, 0x2b, 0x57, 0x70, 0xf0, 0x2e, 0x4c, 0x0b, 0xeb, 0x5b, 0x27, 0xc4,
-	0x3f, 0xdd, 0xa2, 0xd1, 0x51, 0x70, 0x7c, 0x3e, 0x08, 0xd4, 0x80, 0x2a, 0xa3, 0x21, 0x49, 0x9a,
-	0xa5, 0xd5, 0xf2, 0xfa, 0x84, 0xab, 0x16, 0xf8, 0x07, 0x07, 0x66, 0xa5, 0x1d, 0x21, 0x99, 0x18,
-	0x97, 0x3e, 0x82, 0xab, 0xbe, 0xb4, 0x9b, 0x34, 0x9d, 0xd5, 0xf2, 0x7a, 0xad, 0xbd, 0x68, 0x1b,
-	0xb4, 0xf6, 0x75, 0x8d, 0x1c, 0xfa, 0x1c, 0xa6, 0x22, 0xc2, 0xbf, 0xa5, 0xec, 0xf4, 0xcb, 0x58,
-	0xb8, 0x98, 0x68, 0xfc, 0x0b, 0xa9, 0x66, 0x86, 0xeb, 0xe6, 0xa4, 0xf1, 0x1e, 0x4c, 0xdb, 0x38,
-	0x44, 0x7c, 0x5a, 0x30, 0xee, 0xf9, 0x3e, 0x89, 0x79, 0x1a, 0xa1, 0x74, 0x7d, 0x7e, 0x8c, 0x36,
-	0x61, 0x42, 0xda, 0xdb, 0xe5, 0xe4, 0xac, 0x30, 0xaf, 0x56, 0xa1, 0xd6, 0x21, 0x89, 0xcf, 0x02,
-	0x09, 0x40, 0x27, 0x83, 0x4d, 0xc2, 0xdf, 0x3b, 0x30, 0x2d, 0xd4, 0xa5, 0x1d, 0x97, 0x24, 0xdd,
-	0x90, 0xa3, 0x35, 0xa8, 0x04, 0x9c, 0x9c, 0xe9, 0x38, 0xcf, 0x9a, 0x8d, 0xd3, 0xad, 0x5c, 0xc9,
-	0x16, 0x47, 0x9b, 0x70, 0x8f, 0x77, 0x13, 0x93, 0x64, 0x6a, 0x65, 0x60, 0x97, 0x47, 0xc1, 0x16,
-	0x48, 0x43, 0x7a, 0x9c, 0x34, 0x2b, 0x0a, 0xa9, 0xf8, 0xc6, 0xbf, 0x38, 0xd6, 0x79, 0x6b, 0x1c,
-	0x2d, 0x18, 0x17, 0xa7, 0xba, 0x37, 0xf0, 0x2a, 0x5d, 0x7f, 0xf8, 0xe6, 0xff, 0x87, 0xaa, 0x40,
-	0x2f, 0x76, 0xcf, 0x1c, 0x7a, 0x2e, 0x08, 0xae, 0x92, 0xc2, 0x4b, 0xd0, 0xda, 0x21, 0xdc, 0x3e,
-	0x35, 0xc9, 0x55, 0x39, 0x84, 0xff, 0x70, 0xa0, 0x59, 0xc8, 0xd6, 0xa9, 0xaf, 0x21, 0x3a, 0x45,
-	0x10, 0x47, 0x1e, 0x2b, 0xda, 0x84, 0xaa, 0xf0, 0x53, 0x5c, 0x50, 0x01, 0xf1, 0xbf, 0x46, 0x64,
-	0xd4, 0x4e, 0x32, 0x61, 0x93, 0xed, 0x88, 0xb3, 0xbe, 0xab, 0x34, 0x5b, 0xaf, 0x00, 0x06, 0x44,
-	0x34, 0x03, 0xe5, 0x53, 0xd2, 0xd7, 0x30, 0xc4, 0xa7, 0x88, 0x42, 0xcf, 0x0b, 0xbb, 0x44, 0xa3,
-	0x18, 0x4e, 0x7d, 0x13, 0x05, 0x29, 0xf5, 0xa0, 0x74, 0xcf, 0xc1, 0x1f, 0xc3, 0x62, 0x06, 0xc0,
-	0x73, 0x7a, 0x6c, 0xae, 0xd2, 0x3b, 0x0e, 0x0a, 0xff, 0x07, 0xe6, 0x87, 0xd5, 0x44, 0x78, 0x66,
-	0xa0, 0x1c, 0xd2, 0x63, 0x29, 0x3f, 0xe9, 0x8a, 0x4f, 0x7c, 0x17, 0xea, 0x42, 0xe4, 0x25, 0x65,
-	0xdc, 0xf5, 0xa2, 0x63, 0x59, 0x2a, 0x8f, 0x18, 0x3d, 0x33, 0x85, 0x56, 0x7c, 0x8b, 0x52, 0xc9,
-	0xa9, 0x84, 0x5d, 0x77, 0x4b, 0x9c, 0xe2, 0xa7, 0x00, 0xcf, 0x08, 0x89, 0xbd, 0x30, 0xe8, 0x91,
-	0x8e, 0x30, 0xda, 0x0b, 0x62, 0xe3, 0x69, 0x2f, 0x88, 0xd1, 0x6d, 0x98, 0x89, 0x08, 0xdf, 0x8d,
-	0x38, 0x61, 0x47, 0x9e, 0xaf, 0x30, 0xaa, 0x94, 0x19, 0xa2, 0xe3, 0x36, 0x4c, 0x3e, 0xa7, 0x5e,
-	0xe7, 0xd0, 0x0b, 0xbd, 0xc8, 0x27, 0x4c, 0x97, 0x65, 0x27, 0x2d, 0xcb, 0xa6, 0xf0, 0x97, 0x06,
-	0x85, 0x1f, 0xff, 0xea, 0x40, 0xe3, 0x59, 0xf7, 0x90, 0x6c, 0xbe, 0xdc, 0xdd, 0x27, 0xac, 0x47,
-	0x98, 0xae, 0x80, 0x85, 0x8f, 0x4f, 0x1b, 0xe0, 0x34, 0x05, 0xab, 0x63, 0x8f, 0x4c, 0xec, 0x07,
-	0x6e, 0xb8, 0x96, 0x14, 0xba, 0x07, 0x93, 0xa1, 0x05, 0x4a, 0xa7, 0x76, 0xc3, 0x68, 0xd9, 0x80,
-	0xdd, 0x8c, 0x24, 0xfe, 0xbb, 0x02, 0xf5, 0xad, 0xb0, 0x9b, 0x70, 0x09, 0x4a, 0x55, 0xd0, 0x9a,
-	0xaf, 0x08, 0xd6, 0x59, 0xd9, 0x24, 0xf4, 0x12, 0x1a, 0xa7, 0x05, 0xde, 0x68, 0xac, 0x4b, 0x29,
-	0xd6, 0x02, 0x19, 0xb7, 0x50, 0x13, 0x3d, 0x84, 0x7a, 0x64, 0x9f, 0xaa, 0x76, 0x60, 0xde, 0x4e,
-	0xb9, 0x94, 0xe9, 0x66, 0x65, 0xd1, 0x36, 0x80, 0x20, 0x3c, 0xf7, 0x0e, 0x49, 0x68, 0xae, 0xec,
-	0x5a, 0x5a, 0x90, 0x6c, 0xdf, 0xa4, 0x1d, 0x25, 0xa7, 0x6e, 0x82, 0xa5, 0x88, 0x0e, 0x60, 0x5a,
-	0xac, 0x36, 0xa3, 0x88, 0x72, 0x4f, 0x55, 0xee, 0xaa, 0xb4, 0x75, 0x7b, 0xb4, 0x2d, 0x4b, 0x58,
-	0x19, 0xcc, 0x9b, 0x40, 0xeb, 0x30, 0x1d, 0x9c, 0x79, 0xc7, 0xc4, 0x25, 0x31, 0x4d, 0x02, 0x4e,
-	0x59, 0xbf, 0x39, 0x26, 0x23, 0x9a, 0x27, 0xa3, 0x25, 0x98, 0x88, 0x69, 0x67, 0xbf, 0x7b, 0x18,
-	0x11, 0xde, 0xbc, 0x2a, 0x65, 0x06, 0x04, 0x74, 0x13, 0xea, 0x09, 0x61, 0xbd, 0xc0, 0x27, 0x5a,
-	0x62, 0x5c, 0x4a, 0x64, 0x89, 0xe8, 0x7f, 0x30, 0x2b, 0xe2, 0xcb, 0x22, 0xc2, 0x49, 0xf2, 0x9a,
-	0xb0, 0x44, 0x54, 0xf4, 0x09, 0x29, 0x39, 0xcc, 0x68, 0x7d, 0xa6, 0xca, 0xa9, 0x15, 0x90, 0x82,
-	0x2a, 0xd0, 0xb0, 0xab, 0xc0, 0x84, 0x75, 0xd9, 0x5b, 0x8f, 0xa0, 0x51, 0x14, 0x83, 0xf7, 0xb1,
-	0x81, 0x77, 0xa0, 0x7a, 0xe0, 0x05, 0x11, 0xbf, 0xa8, 0x92, 0x28, 0x98, 0xe4, 0xe8, 0x48, 0x64,
-	0x9b, 0xea, 0x4c, 0xf4, 0x0a, 0xff, 0xe9, 0xc0, 0x8c, 0x40, 0xf3, 0x58, 0xb6, 0x7d, 0x97, 0x6b,
-	0x06, 0xd0, 0xa7, 0x30, 0x16, 0xaa, 0x6c, 0x52, 0xd5, 0xf5, 0xa6, 0xad, 0x69, 0xef, 0xb0, 0x61,
-	0x27, 0x93, 0xd6, 0x41, 0x6b, 0x30, 0xc6, 0x85, 0x4f, 0x26, 0x17, 0xd3, 0xf2, 0x2d, 0x3d, 0x75,
-	0x35, 0xb3, 0x75, 0x1f, 0x6a, 0x1f, 0x18, 0x79, 0xfc, 0xa3, 0x03, 0x75, 0x05, 0xc3, 0x54, 0xd7,
-	0x07, 0x50, 0x13, 0xfe, 0x6c, 0x65, 0x9a, 0x95, 0xe6, 0x28, 0xd8, 0xae, 0x2d, 0x2c, 0x2e, 0x9f,
-	0x6f, 0x67, 0xb6, 0xbe, 0xc7, 0xf3, 0x85, 0x69, 0xef, 0x66, 0x65, 0xf1, 0x53, 0xa8, 0x19, 0x24,
-	0x97, 0x6e, 0x55, 0x9a, 0xb0, 0xb0, 0x43, 0xb8, 0x31, 0x67, 0xbf, 0xa1, 0x11, 0x80, 0x22, 0x9b,
-	0x2e, 0x46, 0x9c, 0x93, 0xa9, 0x9a, 0xe2, 0x3b, 0xf3, 0xbc, 0x94, 0x72, 0x7d, 0xc0, 0x1d, 0x98,
-	0x3b, 0xf2, 0x82, 0xb0, 0xcb, 0xc8, 0x96, 0x17, 0x3d, 0x22, 0xbb, 0xc7, 0x11, 0x65, 0xa4, 0x23,
-	0x13, 0x68, 0xdc, 0x2d, 0x62, 0xe1, 0x9f, 0x1d, 0x98, 0x19, 0x6c, 0xa8, 0x5b, 0x8d, 0x36, 0x40,
-	0x27, 0xa5, 0xe9, 0x9c, 0x4a, 0x0b, 0xb3, 0x25, 0x6d, 0x49, 0xfd, 0xbb, 0xfd, 0xcf, 0x77, 0xd0,
-	0x18, 0x8a, 0xcf, 0xa5, 0x9a, 0x88, 0x0d, 0xd3, 0xe7, 0x94, 0xb3, 0xf9, 0x92, 0x77, 0xdd, 0x34,
-	0x3a, 0xdb, 0x30, 0x97, 0x02, 0xb0, 0x9e, 0xf6, 0xf7, 0x3c, 0x0f, 0xbc, 0x06, 0xb3, 0x59, 0x33,
-	0xc5, 0x4f, 0xfd, 0x03, 0x58, 0xf8, 0x82, 0x70, 0xff, 0x44, 0xbc, 0x23, 0x3a, 0xf9, 0x2e, 0x3c,
-	0x69, 0xbc, 0x81, 0xc6, 0x90, 0xae, 0xd8, 0x65, 0x05, 0xe0, 0x34, 0x25, 0xe9, 0xcd, 0x2c, 0xca,
-	0xf9, 0x39, 0xfa, 0x93, 0x03, 0xf5, 0x2d, 0x2f, 0x0c, 0x7c, 0xaa, 0x1b, 0x76, 0xd4, 0x86, 0x86,
-	0xaf, 0x07, 0x01, 0x39, 0xd5, 0xf4, 0x02, 0xde, 0xdf, 0x0c, 0x43, 0x9d, 0xfe, 0x85, 0x3c, 0x51,
-	0xa7, 0x49, 0xe4, 0x7b, 0x71, 0xd2, 0x0d, 0x65, 0xe5, 0x7c, 0x21, 0xbc, 0x51, 0x61, 0x1a, 0x66,
-	0x88, 0x97, 0xa1, 0xf7, 0x36, 0xf4, 0x22, 0xf1, 0xe4, 0x35, 0x41, 0xf6, 0x15, 0x03, 0x02, 0xa6,
-	0x30, 0x95, 0x1d, 0x29, 0xc4, 0x0b, 0xae, 0x87, 0x8a, 0x83, 0x41, 0x73, 0x61, 0x93, 0xe4, 0x95,
-	0xb7, 0x9d, 0x90, 0x56, 0xed, 0x2b, 0x6f, 0x33, 0xdd, 0xac, 0x2c, 0xee, 0xc1, 0x8a, 0x6a, 0xd5,
-	0x94, 0x41, 0x71, 0x28, 0x01, 0x23, 0x67, 0x24, 0x32, 0xd7, 0x15, 0x61, 0xd3, 0x9c, 0xaa, 0x3a,
-	0x94, 0x3d, 0x20, 0xc5, 0x42, 0x77, 0xe0, 0x2a, 0xbd, 0xd0, 0x80, 0x64, 0xc4, 0xf0, 0xef, 0x0e,
-	0x2c, 0xda, 0x81, 0xb4, 0xc7, 0x80, 0x5b, 0x30, 0xb5, 0x4f, 0xbb, 0xcc, 0x27, 0x7b, 0xd9, 0x1e,
-	0x33, 0x47, 0x15, 0xa5, 0xe0, 0x31, 0x49, 0x78, 0x10, 0xc9, 0xe8, 0xee, 0x65, 0x33, 0xb4, 0x88,
-	0x65, 0x5d, 0xae, 0x72, 0xd1, 0xe5, 0xaa, 0x9c, 0x3f, 0x44, 0x54, 0x2f, 0x34, 0x44, 0xfc, 0xe6,
-	0xc0, 0xf2, 0x88, 0xb0, 0x26, 0x97, 0x1b, 0x93, 0x05, 0x12, 0x7b, 0x56, 0x18, 0xdd, 0xc8, 0xab,
-	0x93, 0xd9, 0x81, 0x29, 0x7f, 0x10, 0xe6, 0x80, 0x98, 0x77, 0xec, 0x46, 0x9a, 0x1d, 0xc5, 0x87,
-	0xe0, 0xe6, 0xd4, 0xda, 0x7f, 0x55, 0x61, 0x3a, 0x7d, 0x76, 0xb8, 0xfc, 0x13, 0x06, 0xed, 0xc1,
-	0x54, 0xf6, 0x2f, 0x00, 0xb4, 0x9c, 0x3e, 0x8f, 0x45, 0xff, 0x2a, 0xb4, 0xae, 0x8f, 0x62, 0xc7,
-	0x61, 0x1f, 0x5f, 0x41, 0x8f, 0x00, 0x06, 0x73, 0x03, 0xba, 0x96, 0x99, 0x43, 0xed, 0x51, 0xbe,
-	0xb5, 0x58, 0xc4, 0x52, 0x36, 0xbe, 0x92, 0x65, 0x2d, 0x3f, 0x36, 0x21, 0xfc, 0xce, 0x99, 0x4a,
-	0x59, 0x5d, 0x3d, 0x6f, 0xee, 0xc2, 0x57, 0xd0, 0x01, 0xcc, 0xe4, 0xa7, 0x1b, 0x74, 0xa3, 0x50,
-	0x6f, 0x50, 0x53, 0x5b, 0xcb, 0xa3, 0x05, 0x94, 0xd5, 0x4f, 0x60, 0x4c, 0xc5, 0x16, 0xcd, 0x67,
-	0xcb, 0xb6, 0xb1, 0x30, 0x97, 0x27, 0x2b, 0xbd, 0x57, 0x30, 0x9d, 0x7b, 0x44, 0xd0, 0x8a, 0xb5,
-	0x57, 0xc1, 0xeb, 0xdb, 0x5a, 0x1a, 0xc9, 0x57, 0x26, 0x9f, 0xc0, 0xa4, 0x5d, 0xcf, 0xd1, 0xf5,
-	0x21, 0x79, 0xcb, 0xb1, 0x6b, 0xc5, 0xcc, 0x14, 0x5c, 0xae, 0x6c, 0x0f, 0xc0, 0x15, 0xbf, 0x05,
-	0x03, 0x70, 0x45, 0xf5, 0x1e, 0x5f, 0x41, 0xa7, 0xd0, 0x1c, 0x75, 0xad, 0xd0, 0xad, 0x6c, 0x4e,
-	0x8c, 0xaa, 0x67, 0xad, 0xb5, 0x73, 0xe4, 0x4c, 0x26, 0x1d, 0xaa, 0x3f, 0x17, 0xef, 0xfe, 0x13,
-	0x00, 0x00, 0xff, 0xff, 0x8d, 0xed, 0xa0, 0xdb, 0x7e, 0x14, 0x00, 0x00,
+	// 2903 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x3a, 0x4b, 0x6f, 0x1c, 0xc7,
+	0xd1, 0x9e, 0x7d, 0x50, 0xbb, 0xc5, 0x77, 0x93, 0x22, 0x57, 0x63, 0x4a, 0x26, 0x06, 0x96, 0xa1,
+	0xcf, 0x96, 0x09, 0x99, 0x02, 0x0c, 0x7f, 0xfe, 0xfc, 0x25, 0xa0, 0x69, 0x59, 0x92, 0x25, 0xd3,
+	0xf2, 0x90, 0x89, 0x93, 0x43, 0x10, 0x0c, 0x67, 0x9b, 0xbb, 0x93, 0x9d, 0xed, 0x19, 0x4f, 0xf7,
+	0xd2, 0x26, 0x90, 0xab, 0x91, 0x4b, 0x82, 0x5c, 0x12, 0x24, 0x39, 0xe4, 0x16, 0xe4, 0x90, 0x4b,
+	0x2e, 0xb9, 0x24, 0xa7, 0x1c, 0x7d, 0xcb, 0x0f, 0xc8, 0x3f, 0x48, 0x80, 0xfc, 0x82, 0x1c, 0x82,
+	0x7e, 0x4e, 0xcf, 0xec, 0xcc, 0x2e, 0x2d, 0xda, 0x87, 0x9c, 0x38, 0x5d, 0x55, 0x5d, 0x5d, 0xaf,
+	0xae, 0xaa, 0xae, 0x25, 0x6c, 0xf7, 0x71, 0x1a, 0x27, 0x17, 0x3f, 0x0c, 0x13, 0xc2, 0xb2, 0x24,
+	0x8e, 0x71, 0xb6, 0x97, 0x66, 0x09, 0x4b, 0xd0, 0x82, 0xf8, 0x43, 0xbd, 0x2f, 0x1d, 0x68, 0x1d,
+	0x4c, 0xd8, 0x10, 0x21, 0x68, 0xb1, 0x8b, 0x14, 0xf7, 0x9c, 0x5d, 0xe7, 0x4e, 0xd7, 0x17, 0xdf,
+	0xe8, 0x16, 0x40, 0x98, 0xe1, 0x3e, 0x26, 0x2c, 0x0a, 0xe2, 0x5e, 0x43, 0x60, 0x2c, 0x08, 0x72,
+	0xa1, 0x33, 0xa1, 0x38, 0x23, 0xc1, 0x18, 0xf7, 0x9a, 0x02, 0x6b, 0xd6, 0x7c, 0x6f, 0x1a, 0x50,
+	0x9a, 0x0e, 0xb3, 0x80, 0xe2, 0x5e, 0x4b, 0xee, 0xcd, 0x21, 0x68, 0x17, 0x16, 0x43, 0x9c, 0xb1,
+	0xe8, 0x2c, 0x0a, 0x03, 0x86, 0x7b, 0x6d, 0x41, 0x60, 0x83, 0xd0, 0x1e, 0xa0, 0x70, 0x18, 0xc4,
+	0x31, 0x26, 0x03, 0xec, 0x63, 0x9a, 0x26, 0x84, 0x62, 0xda, 0x5b, 0xd8, 0x6d, 0xde, 0xe9, 0xfa,
+	0x15, 0x18, 0xef, 0x67, 0x0e, 0x34, 0x8f, 0x8f, 0x1f, 0x71, 0x4d, 0xd2, 0x24, 0x63, 0x42, 0x93,
+	0x65, 0x5f, 0x7c, 0xa3, 0x5d, 0x68, 0x05, 0x13, 0x36, 0x14, 0x3a, 0x2c, 0xee, 0x2f, 0x49, 0x23,
+	0xd0, 0x3d, 0xae, 0xb9, 0x2f, 0x30, 0x68, 0x0f, 0xba, 0x3f, 0x9a, 0x8c, 0xd3, 0x47, 0x09, 0x65,
+	0xb4, 0xd7, 0xdc, 0x6d, 0xde, 0x59, 0xdc, 0x5f, 0xd3, 0x64, 0x1f, 0x28, 0x84, 0x9f, 0x93, 0x70,
+	0x8e, 0x74, 0xd2, 0x4f, 0x7a, 0xad, 0x22, 0xc7, 0xe3, 0x49, 0x3f, 0xf1, 0x05, 0xc6, 0x7b, 0x07,
+	0x5a, 0x7c, 0x85, 0x7a, 0x70, 0x0d, 0x93, 0xe0, 0x34, 0xc6, 0x7d, 0x21, 0x52, 0xc7, 0xd7, 0x4b,
+	0x6e, 0x3f, 0x6e, 0x91, 0xcf, 0x92, 0xac, 0xaf, 0xac, 0x6b, 0xd6, 0xde, 0x33, 0xe8, 0xe8, 0x63,
+	0xd1, 0x0a, 0x34, 0xa2, 0x54, 0x79, 0xa6, 0x11, 0xa5, 0x46, 0xc3, 0x46, 0x85, 0x86, 0xcd, 0x3a,
+	0x0d, 0xbd, 0x01, 0xb4, 0x8e, 0x92, 0x3e, 0xe6, 0xbb, 0x85, 0xc7, 0x94, 0xa7, 0xf9, 0xb7, 0x3a,
+	0xa1, 0x61, 0x4e, 0xb8, 0x09, 0x4d, 0x4a, 0x35, 0xb3, 0x45, 0xa3, 0xdc, 0xf1, 0x23, 0x9f, 0xc3,
+	0xd1, 0x0e, 0x74, 0xe3, 0x24, 0x0c, 0xe2, 0x61, 0x42, 0x99, 0xb0, 0x40, 0xc7, 0xcf, 0x01, 0xde,
+	0x27, 0xd0, 0x7e, 0x90, 0x65, 0x49, 0x86, 0xb6, 0x60, 0x21, 0xc3, 0x01, 0x4d, 0x88, 0x3a, 0x4b,
+	0xad, 0x38, 0xbc, 0x8f, 0x59, 0x10, 0xe9, 0x98, 0x52, 0x2b, 0x1e, 0x33, 0x67, 0xd1, 0xe7, 0x1f,
+	0x62, 0x36, 0x4c, 0xfa, 0x54, 0x45, 0x94, 0x05, 0xf1, 0x3e, 0x81, 0xeb, 0x27, 0x98, 0xb2, 0xc3,
+	0x84, 0x10, 0x1c, 0xb2, 0x28, 0x21, 0x3e, 0xfe, 0x74, 0x82, 0xa9, 0x50, 0x9e, 0x24, 0x7d, 0xa9,
+	0x92, 0xa5, 0x3c, 0x57, 0xd7, 0x17, 0x18, 0x2e, 0x71, 0x18, 0x4f, 0x28, 0xc3, 0xd9, 0x63, 0x6d,
+	0xeb, 0x1c, 0xe0, 0xc5, 0xb0, 0x51, 0x66, 0x9c, 0xc6, 0x17, 0x5c, 0x4e, 0xee, 0x0f, 0xe3, 0x38,
+	0xb5, 0x42, 0x2f, 0x41, 0x13, 0x67, 0x99, 0x0a, 0xa6, 0x65, 0x7d, 0x9a, 0xd0, 0xd9, 0xe7, 0x98,
+	0xe2, 0x69, 0xcd, 0xf2, 0x69, 0x8f, 0x61, 0x95, 0x4b, 0x76, 0x38, 0xc4, 0xe1, 0xe8, 0x30, 0x21,
+	0x67, 0xd1, 0xe0, 0x12, 0x0a, 0x6c, 0x42, 0x3b, 0x4b, 0x62, 0x4c, 0x7b, 0x0d, 0x71, 0x01, 0xe4,
+	0xc2, 0xfb, 0x9d, 0x03, 0xeb, 0x82, 0x0f, 0xa7, 0xa4, 0xda, 0x1c, 0x6f, 0xc0, 0xb5, 0x50, 0xf0,
+	0xa5, 0x3d, 0x47, 0x44, 0xf2, 0xb6, 0xcd, 0xd0, 0x3a, 0xd7, 0xd7, 0x74, 0xe8, 0x5b, 0xb0, 0x42,
+	0x30, 0xfb, 0x2c, 0xc9, 0x46, 0x1f, 0xa5, 0xdc, 0x00, 0x54, 0x69, 0xb7, 0x65, 0x76, 0x16, 0xb0,
+	0x7e, 0x89, 0x7a, 0x8e, 0xc6, 0x31, 0xac, 0xda, 0x52, 0x72, 0xdb, 0xba, 0xd0, 0x09, 0xc2, 0x10,
+	0xa7, 0xcc, 0x58, 0xd7, 0xac, 0xaf, 0x6a, 0xdf, 0x03, 0xe8, 0x8a, 0xd3, 0x1e, 0x33, 0x3c, 0xae,
+	0x8c, 0xf6, 0x5d, 0x58, 0xec, 0x63, 0x1a, 0x66, 0x91, 0x10, 0x5e, 0x85, 0x83, 0x0d, 0xf2, 0xbe,
+	0x70, 0x60, 0x95, 0x6f, 0x17, 0x7c, 0x7c, 0x4c, 0x27, 0x31, 0x43, 0xb7, 0xa1, 0x15, 0x31, 0x3c,
+	0x56, 0x3e, 0x5a, 0xd7, 0x62, 0x99, 0xa3, 0x7c, 0x81, 0xe6, 0x41, 0x43, 0x59, 0xc0, 0x26, 0x54,
+	0x07, 0xb7, 0x5c, 0x69, 0xa5, 0x9a, 0xb5, 0x4a, 0x21, 0x68, 0xc5, 0xc9, 0x80, 0xaa, 0x5c, 0x29,
+	0xbe, 0xbd, 0x5f, 0x3a, 0x56, 0xac, 0x28, 0x39, 0x5c, 0xe8, 0xf0, 0x88, 0x38, 0xca, 0xb5, 0x32,
+	0xeb, 0xe7, 0x3f, 0xfc, 0x75, 0x68, 0x73, 0xe9, 0xf9, 0xe9, 0x85, 0x80, 0x29, 0x19, 0xc1, 0x97,
+	0x54, 0xde, 0xdb, 0xe0, 0x3e, 0xc4, 0xcc, 0xf6, 0xa9, 0xc0, 0xaa, 0xf8, 0x2b, 0xb8, 0xc7, 0x29,
+	0xbb, 0xe7, 0x27, 0x0d, 0xe8, 0x55, 0x6e, 0x56, 0x57, 0x4e, 0x29, 0xe0, 0x54, 0x29, 0x50, 0x1f,
+	0x12, 0x07, 0xd0, 0xe6, 0x56, 0xd0, 0xb9, 0xfb, 0x35, 0x4d, 0x52, 0x77, 0x92, 0xb8, 0x0a, 0xf4,
+	0x01, 0x61, 0xd9, 0x85, 0x2f, 0x77, 0x16, 0xc5, 0x6e, 0x95, 0xc4, 0x76, 0x3f, 0x06, 0xc8, 0xb7,
+	0xa0, 0x35, 0x68, 0x8e, 0xf0, 0x85, 0x12, 0x92, 0x7f, 0x72, 0x0b, 0x9e, 0x07, 0xf1, 0x04, 0x2b,
+	0x19, 0xa7, 0xaf, 0x9c, 0xb6, 0xa0, 0xa0, 0x7a, 0xbb, 0xf1, 0x96, 0xe3, 0x1d, 0xc3, 0x76, 0x41,
+	0xbc, 0xa7, 0xc9, 0x40, 0x9b, 0x70, 0x96, 0x93, 0x67, 0xe7, 0xb2, 0x87, 0x70, 0x7d, 0x9a, 0x29,
+	0x37, 0xed, 0x1a, 0x34, 0xe3, 0x64, 0x20, 0xb8, 0x2d, 0xf9, 0xfc, 0x73, 0x0e, 0xa3, 0xfb, 0xb0,
+	0xcc, 0x19, 0x3c, 0x4b, 0x32, 0xe6, 0x07, 0x64, 0x20, 0x0a, 0xc7, 0x59, 0x96, 0x8c, 0x75, 0x61,
+	0xe5, 0xdf, 0xbc, 0x70, 0xb0, 0x44, 0x15, 0xa2, 0x06, 0x4b, 0xbc, 0x0f, 0x00, 0x9e, 0x60, 0x9c,
+	0x06, 0x71, 0x74, 0x8e, 0xfb, 0xfc, 0xc8, 0x73, 0x53, 0xb9, 0xf8, 0x27, 0x7a, 0x15, 0xd6, 0x08,
+	0x66, 0x8f, 0x09, 0xc3, 0xd9, 0x59, 0x10, 0x4a, 0xfd, 0xe4, 0xc9, 0x53, 0x70, 0x6f, 0x1f, 0x96,
+	0x9e, 0x26, 0x41, 0xff, 0x34, 0x88, 0x03, 0x12, 0xe2, 0xec, 0x32, 0x65, 0xd0, 0xfb, 0xb5, 0x03,
+	0x9b, 0x4f, 0x26, 0xa7, 0xf8, 0xe0, 0xd9, 0xe3, 0x63, 0x9c, 0x9d, 0xe3, 0x4c, 0xe5, 0xf4, 0xca,
+	0xfe, 0x66, 0x1f, 0x60, 0x64, 0x84, 0x55, 0x7e, 0x43, 0xda, 0x6f, 0xb9, 0x1a, 0xbe, 0x45, 0x85,
+	0xde, 0x82, 0xa5, 0xd8, 0x12, 0x4a, 0x5d, 0xa9, 0x4d, 0xbd, 0xcb, 0x16, 0xd8, 0x2f, 0x50, 0x7a,
+	0xff, 0x6e, 0xc1, 0xf2, 0xa1, 0xb4, 0xae, 0xc9, 0xfa, 0x8b, 0xca, 0xdc, 0x96, 0x9f, 0x6d, 0x10,
+	0x7a, 0x06, 0x9b, 0xa3, 0x0a, 0x6d, 0x94, 0xac, 0x3b, 0x46, 0xd6, 0x0a, 0x1a, 0xbf, 0x72, 0x27,
+	0xfa, 0x3f, 0x58, 0x26, 0xb6, 0x57, 0x95, 0x02, 0xd7, 0xed, 0x70, 0x35, 0x48, 0xbf, 0x48, 0x8b,
+	0x1e, 0x00, 0x70, 0xc0, 0xd3, 0xe0, 0x14, 0xc7, 0x3a, 0x55, 0xdc, 0x36, 0x89, 0xd0, 0xd6, 0x6d,
+	0xef, 0xc8, 0xd0, 0xc9, 0x3b, 0x66, 0x6d, 0x44, 0x27, 0xb0, 0xca, 0x57, 0x07, 0x84, 0x24, 0x2c,
+	0x90, 0xd5, 0xa6, 0x2d, 0x78, 0xbd, 0x5a, 0xcf, 0xcb, 0x22, 0x96, 0x0c, 0xcb, 0x2c, 0xd0, 0x1d,
+	0x58, 0x8d, 0xc6, 0x01, 0xef, 0x08, 0xd3, 0x84, 0x46, 0x2c, 0xc9, 0x2e, 0x7a, 0x0b, 0xc2, 0xa2,
+	0x65, 0x30, 0x8f, 0xfb, 0x34, 0xe9, 0x1f, 0x4f, 0x4e, 0x09, 0x66, 0xbd, 0x6b, 0x32, 0xee, 0x0d,
+	0x00, 0xbd, 0x0c, 0xcb, 0x14, 0x67, 0xe7, 0x51, 0x88, 0x15, 0x45, 0x47, 0x50, 0x14, 0x81, 0xe8,
+	0x2e, 0xac, 0x73, 0xfb, 0x66, 0x04, 0x33, 0x4c, 0xbf, 0x8b, 0x33, 0xca, 0x2b, 0x49, 0x57, 0x50,
+	0x4e, 0x23, 0xdc, 0xff, 0x97, 0x69, 0xdc, 0x32, 0x48, 0x45, 0x06, 0xd9, 0xb4, 0x33, 0x48, 0xd7,
+	0x4a, 0x14, 0xee, 0xbb, 0xb0, 0x59, 0x65, 0x83, 0xaf, 0xc2, 0xc3, 0x7b, 0x08, 0xed, 0x93, 0x20,
+	0x22, 0xec, 0xb2, 0x9b, 0x78, 0x2a, 0xc6, 0x67, 0x67, 0x3c, 0xda, 0x64, 0x85, 0x55, 0x2b, 0xef,
+	0x1f, 0x0e, 0xac, 0x71, 0x69, 0xde, 0x13, 0x4f, 0x8b, 0xab, 0x35, 0x30, 0xe8, 0x1d, 0x58, 0x88,
+	0x65, 0x34, 0xc9, 0xbc, 0xfd, 0xb2, 0xbd, 0xd3, 0x3e, 0x61, 0xcf, 0x0e, 0x26, 0xb5, 0x07, 0xdd,
+	0x86, 0x05, 0xc6, 0x75, 0xd2, 0xb1, 0x68, 0x0a, 0x83, 0xd0, 0xd4, 0x57, 0x48, 0xf7, 0x7f, 0x61,
+	0xf1, 0x39, 0x2d, 0xef, 0xfd, 0xde, 0x81, 0x65, 0x29, 0x86, 0xce, 0xcc, 0x6f, 0xc3, 0x22, 0xd7,
+	0xe7, 0xb0, 0xd0, 0x60, 0xf5, 0xea, 0xc4, 0xf6, 0x6d, 0x62, 0x7e, 0xf9, 0x42, 0x3b, 0xb2, 0x7b,
+	0x8d, 0xe2, 0xe5, 0x2b, 0x84, 0xbd, 0x5f, 0xa4, 0x9d, 0xd3, 0xf4, 0x0c, 0x61, 0x51, 0xcb, 0xf9,
+	0x0d, 0xb7, 0x57, 0xf7, 0x61, 0x83, 0x97, 0xb2, 0x31, 0x2e, 0xda, 0x65, 0x76, 0xd1, 0x27, 0xb0,
+	0x5e, 0xdc, 0xf4, 0x0d, 0x0b, 0xf9, 0x26, 0x6c, 0x3d, 0xc4, 0x4c, 0x1f, 0x76, 0xf9, 0xe6, 0x84,
+	0x00, 0xc8, 0x4d, 0xba, 0x79, 0xe4, 0x61, 0xaa, 0x8b, 0x06, 0xff, 0x2e, 0x54, 0xe6, 0x46, 0xa9,
+	0x32, 0xdf, 0x83, 0x8d, 0xb3, 0x20, 0x8a, 0x27, 0x19, 0x3e, 0x0c, 0xc8, 0xbb, 0xf8, 0xf1, 0x80,
+	0x24, 0x19, 0x96, 0xd2, 0x75, 0xfc, 0x2a, 0x94, 0xf7, 0x47, 0x07, 0xd6, 0xf2, 0x03, 0x55, 0x87,
+	0xb7, 0x0f, 0xd0, 0x37, 0xb0, 0x9e, 0x53, 0xac, 0x4b, 0x16, 0xb5, 0x45, 0xf5, 0xb5, 0xb6, 0x9d,
+	0xc2, 0x31, 0x8c, 0xe1, 0x71, 0xca, 0xa8, 0x78, 0x99, 0xb7, 0x7d, 0xb3, 0xf6, 0x7e, 0xeb, 0xc0,
+	0xe6, 0x94, 0x69, 0xaf, 0xd4, 0xba, 0xed, 0xe9, 0xde, 0xb3, 0x59, 0xbc, 0x4b, 0x65, 0xbb, 0xa8,
+	0xe6, 0x73, 0x76, 0x9f, 0xe6, 0x85, 0xb0, 0x61, 0xc4, 0xb3, 0x1a, 0xaa, 0xaf, 0xea, 0xca, 0xd9,
+	0xe1, 0x75, 0x08, 0xeb, 0xc5, 0x43, 0x9e, 0xa7, 0xc1, 0xfa, 0x1e, 0x6c, 0xbd, 0x8f, 0x59, 0x38,
+	0xe4, 0xd5, 0x5b, 0x5d, 0xf9, 0xaf, 0xe9, 0x3d, 0x3b, 0x81, 0xcd, 0x29, 0xce, 0x5c, 0xc2, 0x5b,
+	0x00, 0x23, 0x03, 0x52, 0x82, 0x5a, 0x90, 0xab, 0x5e, 0xba, 0x0f, 0x61, 0xfd, 0x90, 0xf7, 0x3a,
+	0xf1, 0x49, 0x40, 0x47, 0x56, 0x27, 0xcb, 0x02, 0x3a, 0x3a, 0xc9, 0x9b, 0x2f, 0xb3, 0x9e, 0xa3,
+	0x05, 0x81, 0x55, 0x9b, 0x1d, 0x57, 0x80, 0x6f, 0x10, 0xa0, 0x7c, 0x9a, 0x92, 0x03, 0xae, 0x2a,
+	0xfe, 0x3d, 0x58, 0x7b, 0x1a, 0x51, 0xc6, 0x4f, 0xa3, 0x97, 0xcb, 0x16, 0x7f, 0x71, 0x60, 0x91,
+	0x93, 0x1f, 0x4f, 0xc6, 0xe3, 0x20, 0xbb, 0xa8, 0x7c, 0x6c, 0xea, 0xc6, 0xb3, 0x61, 0x35, 0x9e,
+	0xf9, 0x55, 0x69, 0x56, 0x5d, 0x95, 0x56, 0xad, 0x02, 0x77, 0x61, 0x3d, 0xcc, 0xb0, 0x68, 0x01,
+	0x4e, 0xa2, 0x31, 0xa6, 0x2c, 0x18, 0xa7, 0xe2, 0x86, 0x36, 0xfd, 0x69, 0x44, 0x51, 0xf8, 0x85,
+	0xb2, 0xf0, 0xdf, 0x87, 0x15, 0x4b, 0x5d, 0x6e, 0xdd, 0xff, 0x81, 0x36, 0x77, 0x8d, 0x2e, 0x6a,
+	0x1b, 0x79, 0x35, 0x35, 0x2a, 0xfa, 0x92, 0x62, 0x8e, 0xe7, 0x9e, 0xc2, 0xda, 0x27, 0x01, 0x0b,
+	0x87, 0x76, 0x1c, 0xcc, 0xb4, 0xa4, 0x8e, 0x12, 0xfb, 0x2a, 0xea, 0xb5, 0xf7, 0xa7, 0x06, 0x74,
+	0x39, 0xa7, 0x07, 0xe7, 0x98, 0x5c, 0x81, 0x4f, 0x21, 0x12, 0x9b, 0xa5, 0x48, 0x44, 0xd0, 0x1a,
+	0x45, 0x44, 0xa7, 0x13, 0xf1, 0x6d, 0xbc, 0xd9, 0xb6, 0xbc, 0x29, 0x46, 0x42, 0x19, 0x26, 0x4c,
+	0xd9, 0x53, 0xad, 0xf8, 0xcd, 0x0a, 0xc4, 0xe4, 0x48, 0x70, 0x97, 0x3d, 0xa5, 0x05, 0x29, 0xa4,
+	0x9a, 0x4e, 0xed, 0xa3, 0xbd, 0x5b, 0x15, 0x0d, 0x30, 0x2b, 0x9c, 0x99, 0x89, 0x82, 0x45, 0x11,
+	0x05, 0x39, 0xc0, 0x8b, 0xa1, 0x77, 0x12, 0x44, 0x71, 0xe5, 0xf3, 0x72, 0xae, 0x11, 0x6b, 0xf3,
+	0xe2, 0x16, 0x2c, 0x24, 0x67, 0x67, 0x14, 0xcb, 0xae, 0xb0, 0xe9, 0xab, 0x95, 0xf7, 0x63, 0xd8,
+	0xe4, 0xa7, 0x4d, 0xe5, 0xdd, 0xd9, 0x27, 0xe9, 0xac, 0xdc, 0xa8, 0xc9, 0xca, 0xcd, 0xda, 0xd3,
+	0x5b, 0x85, 0xd3, 0xdf, 0x84, 0xce, 0xd3, 0x64, 0x70, 0x38, 0x9c, 0x90, 0x11, 0xe7, 0xd9, 0x0f,
+	0x58, 0xa0, 0xd2, 0x9b, 0xf8, 0xb6, 0xf6, 0x35, 0x0a, 0xfb, 0xbe, 0x70, 0xa0, 0xfb, 0x84, 0x24,
+	0x9f, 0x11, 0x31, 0x67, 0x45, 0xd0, 0x12, 0x13, 0x4d, 0x75, 0x7d, 0xf9, 0x37, 0x9f, 0xde, 0x8e,
+	0xf0, 0xc5, 0x49, 0x7e, 0x83, 0xf5, 0x92, 0xbf, 0xde, 0xce, 0x22, 0x32, 0xc0, 0x59, 0x9a, 0x45,
+	0x44, 0x37, 0xc9, 0x36, 0x88, 0xbf, 0x48, 0xd2, 0x88, 0x10, 0xdc, 0xcf, 0xef, 0xaa, 0x14, 0xbb,
+	0x0c, 0xf6, 0xb6, 0xe1, 0x3a, 0xbf, 0x8b, 0x46, 0x14, 0x9d, 0x7f, 0xbc, 0x47, 0xb0, 0x51, 0x46,
+	0xf0, 0x9b, 0xfa, 0x06, 0xc0, 0xc8, 0x80, 0xd4, 0x75, 0x35, 0x13, 0x29, 0x43, 0xec, 0x5b, 0x44,
+	0xde, 0x5d, 0xd8, 0x7a, 0x3f, 0xc9, 0x06, 0x38, 0xe7, 0x65, 0x95, 0xc6, 0xb2, 0xda, 0xde, 0x77,
+	0x60, 0x73, 0x8a, 0x5a, 0x25, 0xe0, 0xb3, 0x24, 0x1b, 0x24, 0x8c, 0x61, 0xa2, 0x13, 0xb0, 0x01,
+	0xcc, 0x4d, 0xc0, 0xde, 0xcf, 0x1d, 0x58, 0x3e, 0x0c, 0xe2, 0x28, 0x4c, 0xf4, 0xe0, 0x70, 0x1f,
+	0x36, 0x43, 0x35, 0x90, 0x14, 0xb3, 0xd7, 0xf3, 0x88, 0x5d, 0x1c, 0xc4, 0xb1, 0xe2, 0x5d, 0x89,
+	0xe3, 0x59, 0x10, 0x93, 0x30, 0x48, 0xe9, 0x24, 0x16, 0x19, 0xef, 0x43, 0x5e, 0x2b, 0xa5, 0x77,
+	0xa6, 0x11, 0x5c, 0xe4, 0xf3, 0xcf, 0xe3, 0x80, 0xf0, 0x67, 0xac, 0xb8, 0x4c, 0xcb, 0x7e, 0x0e,
+	0xf0, 0x12, 0x58, 0x29, 0x8e, 0x36, 0xb9, 0x5f, 0xd5, 0x70, 0xd3, 0xaa, 0x59, 0x36, 0x48, 0xb4,
+	0xf1, 0xb6, 0x12, 0x3d, 0x28, 0xb5, 0xf1, 0x36, 0xd2, 0x2f, 0xd2, 0x7a, 0xbf, 0x70, 0xe0, 0x96,
+	0xbc, 0x93, 0x92, 0x23, 0xf7, 0x42, 0x94, 0xe1, 0x31, 0x26, 0xc6, 0x21, 0x9e, 0x9e, 0x65, 0x49,
+	0xc7, 0x16, 0xeb, 0xbf, 0x44, 0xa1, 0x7b, 0x70, 0x2d, 0xb9, 0xd4, 0xa4, 0x56, 0x93, 0xcd, 0x29,
+	0x7e, 0x7f, 0x77, 0x60, 0xdb, 0xb6, 0xb3, 0x3d, 0x71, 0x7c, 0x05, 0x56, 0x8e, 0x93, 0x49, 0x16,
+	0xe2, 0xa3, 0xe2, 0x48, 0xaa, 0x04, 0xe5, 0xed, 0xef, 0x7b, 0x98, 0xb2, 0x88, 0x08, 0xe3, 0x1f,
+	0x15, 0x53, 0x48, 0x15, 0xea, 0xf9, 0x0b, 0xa1, 0x99, 0x57, 0xb6, 0x2f, 0x35, 0xaf, 0xfc, 0x97,
+	0x03, 0x37, 0x6b, 0x8c, 0x4e, 0xaf, 0x38, 0xeb, 0x7f, 0xbd, 0x38, 0x78, 0xac, 0x9f, 0xfb, 0x49,
+	0xbf, 0x3d, 0x84, 0x95, 0x30, 0x37, 0x73, 0x84, 0xf5, 0xd3, 0xf5, 0x25, 0x13, 0x3c, 0xd5, 0x4e,
+	0xf0, 0x4b, 0xdb, 0x8a, 0xee, 0x6c, 0x97, 0xdd, 0xf9, 0x91, 0xe8, 0x82, 0x79, 0xd5, 0x7c, 0x98,
+	0x05, 0xe9, 0xf0, 0xea, 0x45, 0xf8, 0x57, 0x0e, 0x2c, 0x1b, 0x76, 0xfa, 0x77, 0x24, 0x51, 0x32,
+	0x9d, 0x8a, 0x92, 0xd9, 0xa8, 0x68, 0x80, 0x9a, 0xc5, 0x06, 0x48, 0x95, 0xd1, 0x56, 0xa1, 0x8c,
+	0xda, 0xb9, 0xbf, 0x5d, 0x5b, 0x26, 0x17, 0xec, 0x58, 0xf1, 0xee, 0x5b, 0x82, 0x3d, 0xe8, 0x97,
+	0xe6, 0x94, 0xdd, 0xa9, 0x39, 0x65, 0x57, 0xcc, 0x29, 0xbf, 0x74, 0x44, 0x07, 0x6f, 0x19, 0x48,
+	0xb7, 0x97, 0xcf, 0xd7, 0x5b, 0xbc, 0x56, 0x8c, 0x82, 0xeb, 0x76, 0xeb, 0x64, 0x4c, 0xa6, 0x63,
+	0xe0, 0x35, 0x68, 0xe3, 0xfe, 0xc0, 0xb8, 0x7e, 0x9a, 0x98, 0xab, 0xe1, 0x4b, 0x1a, 0x1d, 0x80,
+	0xed, 0xda, 0x9c, 0xfa, 0x07, 0x07, 0xd6, 0x9f, 0xc5, 0x01, 0xf9, 0xaf, 0x18, 0x53, 0xfc, 0xd5,
+	0x81, 0x0e, 0x17, 0xf6, 0x98, 0xe1, 0x74, 0xde, 0x2f, 0x19, 0xb2, 0x7d, 0xd2, 0xef, 0x59, 0xb9,
+	0xe2, 0xf5, 0x38, 0x4c, 0xc6, 0xe3, 0x80, 0x68, 0xe6, 0x7a, 0x29, 0xc6, 0xc1, 0x01, 0x1b, 0xea,
+	0x16, 0x8e, 0x7f, 0x73, 0xd8, 0x98, 0x17, 0x07, 0xd5, 0xc2, 0xf1, 0x6f, 0xc9, 0x81, 0xb0, 0xbc,
+	0x87, 0xd3, 0x4b, 0x3e, 0xf9, 0x53, 0x9f, 0x8f, 0xa2, 0x7e, 0x1f, 0x13, 0xd1, 0xc7, 0x75, 0xfc,
+	0x22, 0xd0, 0xfb, 0x8d, 0x03, 0x70, 0x20, 0x84, 0xe1, 0x8a, 0x5c, 0xba, 0xe7, 0x9f, 0xd5, 0xd6,
+	0xbc, 0x02, 0x6d, 0xca, 0x70, 0xaa, 0x03, 0xc2, 0xfc, 0xf0, 0xac, 0x2d, 0xe5, 0x4b, 0xf4, 0xfc,
+	0x58, 0xf8, 0xa7, 0x03, 0x1d, 0x1e, 0x45, 0x5f, 0x49, 0xb2, 0xfc, 0x32, 0x36, 0x0b, 0x97, 0xd1,
+	0x83, 0xa5, 0x3e, 0x4e, 0x31, 0xe9, 0x63, 0x12, 0xea, 0x44, 0xd5, 0xf5, 0x0b, 0x30, 0x74, 0x17,
+	0x3a, 0x74, 0x72, 0x7a, 0x22, 0x5e, 0x0d, 0xed, 0xa2, 0xf0, 0x5a, 0x0e, 0xdf, 0x50, 0xa0, 0xbb,
+	0x70, 0x4d, 0xba, 0x51, 0xfe, 0x8e, 0x6f, 0x4d, 0x35, 0x72, 0x83, 0xfa, 0x9a, 0x44, 0x6b, 0x7b,
+	0xad, 0x56, 0xdb, 0x73, 0x58, 0xb5, 0x03, 0x7f, 0xfe, 0x0d, 0x7e, 0x19, 0x5a, 0x69, 0x1c, 0x10,
+	0x15, 0xcf, 0xd3, 0x92, 0x0a, 0xec, 0xdc, 0x91, 0x89, 0xf7, 0x4c, 0x4c, 0x40, 0xcc, 0x93, 0x84,
+	0x5e, 0x3d, 0xbb, 0xfe, 0xad, 0x01, 0x4b, 0x1f, 0x24, 0x93, 0x8c, 0x04, 0xb1, 0x60, 0xc9, 0x67,
+	0x09, 0x14, 0x7f, 0x2a, 0x98, 0xb4, 0x7c, 0xfe, 0x69, 0xd2, 0x6d, 0xa3, 0x22, 0xdd, 0x36, 0x2b,
+	0x5f, 0x28, 0xad, 0x19, 0x2f, 0x94, 0xf6, 0xcc, 0x17, 0xca, 0x42, 0x29, 0x3e, 0xf9, 0x0f, 0xf3,
+	0x59, 0x32, 0x3e, 0x96, 0xe9, 0x57, 0xbd, 0x6e, 0x72, 0x88, 0x50, 0x2d, 0x51, 0x58, 0xf5, 0xba,
+	0xd1, 0x6b, 0x6d, 0xcd, 0xee, 0xe5, 0x5e, 0x31, 0x50, 0x7a, 0xc5, 0x70, 0xd6, 0xfd, 0x49, 0x26,
+	0xba, 0x06, 0xf5, 0xc4, 0x31, 0x6b, 0x8e, 0x0b, 0x83, 0x09, 0xc5, 0xc7, 0xf8, 0xd3, 0xde, 0x92,
+	0xb0, 0x94, 0x59, 0x7b, 0x7f, 0x76, 0x00, 0x95, 0x9c, 0x74, 0xb5, 0x0c, 0x3f, 0xeb, 0xf5, 0x78,
+	0x17, 0x16, 0xb0, 0x38, 0x44, 0x5d, 0x60, 0xf3, 0x73, 0x90, 0xed, 0x53, 0x5f, 0xd1, 0xcc, 0xbd,
+	0xc5, 0xfb, 0x3f, 0x5d, 0x82, 0x55, 0x93, 0x9d, 0x99, 0xf8, 0xb7, 0x1d, 0x74, 0x04, 0x2b, 0xc5,
+	0x7f, 0x51, 0x40, 0x37, 0x4d, 0xf8, 0x56, 0xfd, 0x4f, 0x84, 0xfb, 0x62, 0x1d, 0x3a, 0x8d, 0x2f,
+	0xbc, 0x17, 0xd0, 0xbb, 0x00, 0xf9, 0xcb, 0x10, 0xdd, 0x28, 0xfc, 0x9a, 0x6d, 0xff, 0x33, 0x81,
+	0xbb, 0x5d, 0x85, 0x92, 0x3c, 0x7e, 0x20, 0x9a, 0x8c, 0xf2, 0xcf, 0xab, 0xc8, 0x9b, 0xf9, 0xdb,
+	0xab, 0xe4, 0xba, 0x3b, 0xef, 0xf7, 0x59, 0xef, 0x05, 0x74, 0x02, 0x6b, 0xe5, 0x5f, 0x32, 0xd1,
+	0x4b, 0x95, 0xfb, 0xf2, 0xf7, 0xa6, 0x7b, 0xb3, 0x9e, 0x40, 0x72, 0x7d, 0x13, 0x16, 0xa4, 0x6d,
+	0xd1, 0xf5, 0xe2, 0xa0, 0x51, 0x73, 0xd8, 0x28, 0x83, 0xe5, 0xbe, 0x47, 0xb0, 0x64, 0x4f, 0xb0,
+	0x91, 0xb1, 0x6f, 0xc5, 0x30, 0xdc, 0xbd, 0x51, 0x8d, 0x94, 0x9c, 0x3e, 0x86, 0xd5, 0xd2, 0x00,
+	0x15, 0xdd, 0xb2, 0xa4, 0xae, 0x18, 0x5a, 0xbb, 0x3b, 0xb5, 0x78, 0x23, 0x9c, 0x3d, 0x8f, 0xcc,
+	0x85, 0xab, 0x18, 0x85, 0xba, 0x37, 0xaa, 0x91, 0x46, 0xb8, 0xd2, 0xe8, 0x30, 0x17, 0xae, 0x7a,
+	0x5a, 0xe9, 0xee, 0xd4, 0xe2, 0x25, 0xcb, 0x11, 0xf4, 0xea, 0x7a, 0x6f, 0xf4, 0x4a, 0x31, 0xba,
+	0xea, 0x9e, 0x44, 0xee, 0xed, 0x39, 0x74, 0xd4, 0x8e, 0x6b, 0x33, 0x34, 0xb4, 0xe2, 0xba, 0x3c,
+	0x97, 0x74, 0xb7, 0xab, 0x50, 0x92, 0xc7, 0xb7, 0xa1, 0x6b, 0x26, 0x63, 0xc8, 0xf4, 0x4c, 0xe5,
+	0xd9, 0xa0, 0xbb, 0x55, 0x81, 0x91, 0x0c, 0xde, 0x81, 0xae, 0x99, 0x7f, 0xe5, 0x0c, 0xca, 0x23,
+	0x31, 0x77, 0xdd, 0x2e, 0x40, 0x22, 0x47, 0x78, 0x2f, 0xdc, 0x73, 0xd0, 0x13, 0x58, 0x9f, 0x1a,
+	0xdc, 0xa0, 0xdd, 0x9c, 0xb6, 0x7a, 0xa6, 0xe3, 0x9a, 0x72, 0xa6, 0x27, 0x21, 0x82, 0xd9, 0x21,
+	0x2c, 0x17, 0xe6, 0x32, 0x68, 0xc7, 0x66, 0x34, 0x15, 0x1b, 0xd5, 0x4c, 0x8e, 0xe4, 0xa8, 0x30,
+	0x9f, 0x42, 0xe4, 0xc9, 0xa7, 0x72, 0x6c, 0xe1, 0xbe, 0x58, 0x87, 0xce, 0x83, 0xac, 0x38, 0x5d,
+	0xb0, 0x82, 0xac, 0x72, 0x48, 0xe1, 0xee, 0xd4, 0xe2, 0xed, 0x1b, 0x60, 0x3a, 0xe8, 0xc2, 0x0d,
+	0x28, 0x3f, 0x83, 0xdc, 0x1b, 0xd5, 0x48, 0x13, 0x41, 0x79, 0x57, 0x91, 0x47, 0xd0, 0x54, 0x8b,
+	0xed, 0x6e, 0x57, 0xa1, 0x24, 0x8f, 0x27, 0xb0, 0x5c, 0x28, 0x3e, 0x68, 0xa7, 0x74, 0x62, 0xa1,
+	0x71, 0x70, 0xdd, 0x1a, 0xac, 0x60, 0x76, 0x2a, 0xff, 0x57, 0xf3, 0xfe, 0x7f, 0x06, 0x00, 0x68,
+	0xa6, 0x78, 0xf3, 0xcd, 0x29, 0x00, 0x00,
 }
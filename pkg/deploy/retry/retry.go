@@ -0,0 +1,184 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMultiplier = 2
+)
+
+// transientMessages are the messages of the transient errors, which are matched if the errors are
+// formatted into the ones returned instead of wrapped.
+var transientMessages = regexp.MustCompile(`connection reset by peer|broken pipe|connection refused|` +
+	`i/o timeout|no route to host|network is unreachable|unexpected EOF|: EOF$`)
+
+// Policy declares how a failed attempt is retried. The zero value or a nil policy means no retry.
+type Policy struct {
+	// MaxAttempts is the max number of attempts including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt, which is multiplied by Multiplier
+	// before each of the following attempts, and limited by MaxBackoff if it's set.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Retryable classifies whether the error of an attempt is worth retrying,
+	// all the errors are retried if it's nil.
+	Retryable func(err error) bool
+	// RetryableOutputs are the patterns of the stderr of a failed command, the command is retried if
+	// the stderr matches any of them even if the error isn't retryable, like a package mirror hiccup.
+	RetryableOutputs []string
+}
+
+// Attempts returns the max number of attempts, which is at least 1.
+func (p *Policy) Attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff returns the wait after the attempt, which counts from 1.
+func (p *Policy) Backoff(attempt int) time.Duration {
+	if p == nil || attempt < 1 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultMultiplier
+	}
+
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		backoff *= multiplier
+		if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
+
+// ShouldRetry returns true if the failed attempt is retryable and there are attempts left.
+func (p *Policy) ShouldRetry(attempt int, err error) bool {
+	if err == nil || attempt >= p.Attempts() {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// ShouldRetryOutput returns true if the stderr of the attempt matches the retryable outputs and
+// there are attempts left.
+func (p *Policy) ShouldRetryOutput(attempt int, stderr []byte) bool {
+	if attempt >= p.Attempts() || len(stderr) == 0 {
+		return false
+	}
+
+	for _, pattern := range p.RetryableOutputs {
+		if matched, _ := regexp.Match(pattern, stderr); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Wait waits for the backoff after the attempt, it returns the error of the context if the context is done before.
+func (p *Policy) Wait(ctx context.Context, attempt int) error {
+	backoff := p.Backoff(attempt)
+	if backoff <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Do runs fn until it succeeds, or the error is not retryable, or the attempts run out.
+// It returns the number of attempts made and the error of the last one.
+func (p *Policy) Do(ctx context.Context, fn func(attempt int) error) (attempts int, err error) {
+	for attempts = 1; ; attempts++ {
+		if err = fn(attempts); !p.ShouldRetry(attempts, err) {
+			return
+		}
+		if waitErr := p.Wait(ctx, attempts); waitErr != nil {
+			return
+		}
+	}
+}
+
+// TransientError marks an error as transient, which is worth retrying.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient returns true if the error may go away on retry, like the connection to the node is reset.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var transientErr *TransientError
+	if errors.As(err, &transientErr) {
+		return true
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	for _, errno := range []syscall.Errno{syscall.ECONNRESET, syscall.ECONNREFUSED, syscall.ECONNABORTED,
+		syscall.EPIPE, syscall.ETIMEDOUT, syscall.EHOSTUNREACH, syscall.ENETUNREACH} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return transientMessages.MatchString(err.Error())
+}
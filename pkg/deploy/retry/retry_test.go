@@ -0,0 +1,122 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyBackoff(t *testing.T) {
+	policy := &Policy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	assert.Equal(t, 5, policy.Attempts())
+	assert.Equal(t, time.Second, policy.Backoff(1))
+	assert.Equal(t, 2*time.Second, policy.Backoff(2))
+	assert.Equal(t, 4*time.Second, policy.Backoff(3))
+	assert.Equal(t, 5*time.Second, policy.Backoff(4))
+	assert.Equal(t, 5*time.Second, policy.Backoff(100))
+
+	policy = &Policy{MaxAttempts: 3, InitialBackoff: time.Second, Multiplier: 3}
+	assert.Equal(t, 9*time.Second, policy.Backoff(3))
+
+	var nilPolicy *Policy
+	assert.Equal(t, 1, nilPolicy.Attempts())
+	assert.Equal(t, time.Duration(0), nilPolicy.Backoff(1))
+	assert.False(t, nilPolicy.ShouldRetry(1, io.EOF))
+}
+
+func TestPolicyDo(t *testing.T) {
+	policy := &Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Retryable: IsTransient}
+
+	attempts, err := policy.Do(context.Background(), func(attempt int) error {
+		if attempt < 2 {
+			return io.EOF
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	// the attempts run out
+	attempts, err = policy.Do(context.Background(), func(attempt int) error {
+		return fmt.Errorf("attempt %d: %w", attempt, syscall.ECONNRESET)
+	})
+	assert.EqualError(t, err, "attempt 3: connection reset by peer")
+	assert.Equal(t, 3, attempts)
+
+	// the error is not retryable
+	attempts, err = policy.Do(context.Background(), func(attempt int) error {
+		return errors.New("permission denied")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// the context is cancelled during the backoff
+	ctx, cancel := context.WithCancel(context.Background())
+	policy.InitialBackoff = time.Hour
+	attempts, err = policy.Do(ctx, func(attempt int) error {
+		cancel()
+		return io.EOF
+	})
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestShouldRetryOutput(t *testing.T) {
+	policy := &Policy{MaxAttempts: 2, RetryableOutputs: []string{`Failed to fetch`, `Could not resolve`}}
+
+	assert.True(t, policy.ShouldRetryOutput(1, []byte("E: Failed to fetch http://mirror/pool/kubelet.deb")))
+	assert.False(t, policy.ShouldRetryOutput(2, []byte("E: Failed to fetch http://mirror/pool/kubelet.deb")))
+	assert.False(t, policy.ShouldRetryOutput(1, []byte("E: Unable to locate package kubelet")))
+	assert.False(t, policy.ShouldRetryOutput(1, nil))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTransient(t *testing.T) {
+	var _ net.Error = timeoutError{}
+
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{err: nil, transient: false},
+		{err: io.EOF, transient: true},
+		{err: fmt.Errorf("read: %w", syscall.ECONNRESET), transient: true},
+		{err: &net.OpError{Op: "dial", Err: timeoutError{}}, transient: true},
+		{err: fmt.Errorf("failed to connect, error: dial tcp 10.0.0.1:22: connect: connection refused"), transient: true},
+		{err: fmt.Errorf("failed to fetch file, error: EOF"), transient: true},
+		{err: &TransientError{Err: errors.New("apt lock is held")}, transient: true},
+		{err: errors.New("ssh: handshake failed: ssh: unable to authenticate"), transient: false},
+		{err: context.Canceled, transient: false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.transient, IsTransient(test.err), "%v", test.err)
+	}
+}
@@ -17,6 +17,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -31,6 +32,9 @@ import (
 type controller struct {
 	store      task.Store
 	logFileLoc string
+	// launchLock serializes the launches of the background tasks, so a running task is
+	// never replaced in the store by another launch with the same name.
+	launchLock sync.Mutex
 }
 
 func (c *controller) TestConnection(ctx context.Context, req *pb.TestConnectionRequest) (*pb.TestConnectionReply, error) {
@@ -43,7 +47,7 @@ func (c *controller) TestConnection(ctx context.Context, req *pb.TestConnectionR
 		return nil, fmt.Errorf("invalid request: 'Node' in request paramter is nil")
 	}
 
-	taskName := getTestConnectionTaskName(req.Node.Name)
+	taskName := getTestConnectionTaskName(req.GetClusterId(), req.Node.Name)
 	taskConfig := &task.TestConnectionTaskConfig{
 		Node:            req.Node,
 		LogFileBasePath: c.logFileLoc,
@@ -54,8 +58,9 @@ func (c *controller) TestConnection(ctx context.Context, req *pb.TestConnectionR
 		logrus.Errorf("request failed: %s", err)
 		return nil, err
 	}
+	testConnTask.SetClusterID(req.GetClusterId())
 
-	if err = c.storeAndExecuteTask(testConnTask); err != nil {
+	if err = c.storeAndExecuteTask(ctx, testConnTask); err != nil {
 		logrus.Errorf("request failed: %s", err)
 		return nil, err
 	}
@@ -64,13 +69,15 @@ func (c *controller) TestConnection(ctx context.Context, req *pb.TestConnectionR
 	taskErr := testConnTask.GetErr()
 	if taskErr != nil {
 		reply = &pb.TestConnectionReply{
-			Passed: false,
-			Err:    taskErr,
+			Passed:    false,
+			Err:       taskErr,
+			ClusterId: req.GetClusterId(),
 		}
 	} else {
 		reply = &pb.TestConnectionReply{
-			Passed: true,
-			Err:    nil,
+			Passed:    true,
+			Err:       nil,
+			ClusterId: req.GetClusterId(),
 		}
 	}
 
@@ -81,7 +88,7 @@ func (c *controller) TestConnection(ctx context.Context, req *pb.TestConnectionR
 func (c *controller) CheckNodes(ctx context.Context, req *pb.CheckNodesRequest) (*pb.CheckNodesReply, error) {
 	logrus.Info("Begins CheckNodes request")
 
-	taskName := getCheckNodeTaskName()
+	taskName := getCheckNodeTaskName(req.GetClusterId())
 	taskConfig := &task.NodeCheckTaskConfig{
 		NodeConfigs:     req.GetConfigs(),
 		NetworkOptions:  req.GetNetworkOptions(),
@@ -90,6 +97,7 @@ func (c *controller) CheckNodes(ctx context.Context, req *pb.CheckNodesRequest)
 
 	nodeCheckTask, err := task.NewNodeCheckTask(taskName, taskConfig)
 	if err == nil {
+		nodeCheckTask.SetClusterID(req.GetClusterId())
 		// store and launch the task
 		err = c.storeAndLanuchTask(nodeCheckTask)
 	}
@@ -101,13 +109,15 @@ func (c *controller) CheckNodes(ctx context.Context, req *pb.CheckNodesRequest)
 				Reason: consts.MsgRequestFailed,
 				Detail: err.Error(),
 			},
+			ClusterId: req.GetClusterId(),
 		}, err
 	}
 
 	logrus.Info("CheckNodes request succeeded")
 	return &pb.CheckNodesReply{
-		Accepted: true,
-		Err:      nil,
+		Accepted:  true,
+		Err:       nil,
+		ClusterId: req.GetClusterId(),
 	}, nil
 }
 
@@ -123,12 +133,15 @@ func (c *controller) GetCheckNodesResult(ctx context.Context, req *pb.GetCheckNo
 		}
 	}()
 
-	tsk, err := c.getTask(getCheckNodeTaskName())
+	tsk, err := c.getTask(getCheckNodeTaskName(req.GetClusterId()))
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := c.getCheckNodesResult(tsk)
+	if resp != nil {
+		resp.ClusterId = req.GetClusterId()
+	}
 	return resp, err
 }
 
@@ -144,19 +157,38 @@ func (c *controller) GetCheckNodesLog(ctx context.Context, req *pb.GetCheckNodes
 		}
 	}()
 
-	tsk, err := c.getTask(getCheckNodeTaskName())
+	tsk, err := c.getTask(getCheckNodeTaskName(req.GetClusterId()))
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := c.getCheckNodesLog(tsk, req.NodeName)
+	if resp != nil {
+		resp.ClusterId = req.GetClusterId()
+	}
 	return resp, err
 }
 
+func (c *controller) TailCheckNodesLog(req *pb.TailCheckNodesLogRequest, stream pb.DeployContoller_TailCheckNodesLogServer) error {
+	logrus.Infof("Begins TailCheckNodesLog request, node: %q, offset: %d", req.GetNodeName(), req.GetOffset())
+
+	tsk, err := c.getTask(getCheckNodeTaskName(req.GetClusterId()))
+	if err == nil {
+		err = tailLog(stream.Context(), tsk, checkNodesLogFilter(req.GetNodeName()), req.GetOffset(), stream.Send)
+	}
+
+	if err != nil {
+		logrus.Errorf("Failed to reply TailCheckNodesLog request, error: %v", err)
+		return err
+	}
+	logrus.Info("Ends TailCheckNodesLog request")
+	return nil
+}
+
 func (c *controller) Deploy(ctx context.Context, req *pb.DeployRequest) (*pb.DeployReply, error) {
 	logrus.Info("Begins Deploy request")
 
-	taskName := getDeployTaskName()
+	taskName := getDeployTaskName(req.GetClusterId())
 	taskConfig := &task.DeployTaskConfig{
 		NodeConfigs:     req.NodeConfigs,
 		ClusterConfig:   req.ClusterConfig,
@@ -165,6 +197,7 @@ func (c *controller) Deploy(ctx context.Context, req *pb.DeployRequest) (*pb.Dep
 
 	deployTask, err := task.NewDeployTask(taskName, taskConfig)
 	if err == nil {
+		deployTask.SetClusterID(req.GetClusterId())
 		// store and launch the task
 		err = c.storeAndLanuchTask(deployTask)
 	}
@@ -176,13 +209,43 @@ func (c *controller) Deploy(ctx context.Context, req *pb.DeployRequest) (*pb.Dep
 				Reason: consts.MsgRequestFailed,
 				Detail: err.Error(),
 			},
+			ClusterId: req.GetClusterId(),
 		}, err
 	}
 
 	logrus.Info("Deploy request succeeded")
 	return &pb.DeployReply{
-		Accepted: true,
-		Err:      nil,
+		Accepted:  true,
+		Err:       nil,
+		ClusterId: req.GetClusterId(),
+	}, nil
+}
+
+func (c *controller) ResumeDeploy(ctx context.Context, req *pb.ResumeDeployRequest) (*pb.ResumeDeployReply, error) {
+	logrus.Info("Begins ResumeDeploy request")
+
+	deployTask, err := c.getTask(getDeployTaskName(req.GetClusterId()))
+	if err == nil {
+		// launch the task again, the successful sub tasks and actions will be skipped.
+		err = c.resumeTask(deployTask)
+	}
+	if err != nil {
+		logrus.Errorf("ResumeDeploy request failed: %s", err)
+		return &pb.ResumeDeployReply{
+			Accepted: false,
+			Err: &pb.Error{
+				Reason: consts.MsgRequestFailed,
+				Detail: err.Error(),
+			},
+			ClusterId: req.GetClusterId(),
+		}, err
+	}
+
+	logrus.Info("ResumeDeploy request succeeded")
+	return &pb.ResumeDeployReply{
+		Accepted:  true,
+		Err:       nil,
+		ClusterId: req.GetClusterId(),
 	}, nil
 }
 
@@ -198,12 +261,16 @@ func (c *controller) GetDeployResult(ctx context.Context, req *pb.GetDeployResul
 		}
 	}()
 
-	tsk, err := c.getTask(getDeployTaskName())
+	tsk, err := c.getTask(getDeployTaskName(req.GetClusterId()))
 	if err != nil {
 		return nil, err
 	}
 
-	return c.getDeployResult(tsk)
+	resp, err := c.getDeployResult(tsk)
+	if resp != nil {
+		resp.ClusterId = req.GetClusterId()
+	}
+	return resp, err
 }
 
 func (c *controller) GetDeployLog(ctx context.Context, req *pb.GetDeployLogRequest) (*pb.GetDeployLogReply, error) {
@@ -218,15 +285,35 @@ func (c *controller) GetDeployLog(ctx context.Context, req *pb.GetDeployLogReque
 		}
 	}()
 
-	tsk, err := c.getTask(getDeployTaskName())
+	tsk, err := c.getTask(getDeployTaskName(req.GetClusterId()))
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := c.getDeployLog(tsk, constant.MachineRole(req.Role), req.NodeName)
+	if resp != nil {
+		resp.ClusterId = req.GetClusterId()
+	}
 	return resp, err
 }
 
+func (c *controller) TailDeployLog(req *pb.TailDeployLogRequest, stream pb.DeployContoller_TailDeployLogServer) error {
+	logrus.Infof("Begins TailDeployLog request, role: %q, node: %q, offset: %d", req.GetRole(), req.GetNodeName(), req.GetOffset())
+
+	tsk, err := c.getTask(getDeployTaskName(req.GetClusterId()))
+	if err == nil {
+		filter := deployLogFilter(constant.MachineRole(req.GetRole()), req.GetNodeName())
+		err = tailLog(stream.Context(), tsk, filter, req.GetOffset(), stream.Send)
+	}
+
+	if err != nil {
+		logrus.Errorf("Failed to reply TailDeployLog request, error: %v", err)
+		return err
+	}
+	logrus.Info("Ends TailDeployLog request")
+	return nil
+}
+
 func (c *controller) FetchKubeConfig(ctx context.Context, req *pb.FetchKubeConfigRequest) (*pb.FetchKubeConfigReply, error) {
 	logrus.Info("Begins FetchKubeConfig request")
 
@@ -247,8 +334,9 @@ func (c *controller) FetchKubeConfig(ctx context.Context, req *pb.FetchKubeConfi
 	if err != nil {
 		return nil, err
 	}
+	kubeConfigTask.SetClusterID(req.GetClusterId())
 
-	if err = c.storeAndExecuteTask(kubeConfigTask); err != nil {
+	if err = c.storeAndExecuteTask(ctx, kubeConfigTask); err != nil {
 		return nil, err
 	}
 
@@ -256,13 +344,15 @@ func (c *controller) FetchKubeConfig(ctx context.Context, req *pb.FetchKubeConfi
 	if taskErr != nil {
 		err = fmt.Errorf(taskErr.String())
 		return &pb.FetchKubeConfigReply{
-			Err: taskErr,
+			Err:       taskErr,
+			ClusterId: req.GetClusterId(),
 		}, err
 	}
 
 	logrus.Info("Ends FetchKubeConfig request: succeeded")
 	return &pb.FetchKubeConfigReply{
 		KubeConfig: kubeConfigTask.(*task.FetchKubeConfigTask).KubeConfig,
+		ClusterId:  req.GetClusterId(),
 	}, nil
 }
 
@@ -276,14 +366,15 @@ func (c *controller) CheckNetworkRequirements(
 		LogFileBasePath: c.logFileLoc,
 	}
 
-	taskName := "check-network-requirements"
+	taskName := getCheckNetworkRequirementsTaskName(req.GetClusterId())
 	if taskConfig.NetworkOptions != nil {
 		taskName = taskName + "-" + taskConfig.NetworkOptions.GetNetworkType()
 	}
 
 	checkTask, err := task.NewCheckNetworkRequirementsTask(taskName, taskConfig)
 	if err == nil {
-		err = c.storeAndExecuteTask(checkTask)
+		checkTask.SetClusterID(req.GetClusterId())
+		err = c.storeAndExecuteTask(context, checkTask)
 	}
 	if err != nil {
 		logrus.Errorf("failed to create task for CheckNetworkRequirements, error %v", err)
@@ -293,23 +384,91 @@ func (c *controller) CheckNetworkRequirements(
 				Reason: consts.MsgRequestFailed,
 				Detail: err.Error(),
 			},
+			ClusterId: req.GetClusterId(),
 		}, err
 	}
 	logrus.Info("CheckNetworkRequirements request succeeded")
 	return &pb.CheckNetworkRequirementsReply{
-		Passed: true,
+		Passed:    true,
+		ClusterId: req.GetClusterId(),
 	}, nil
 }
 
+func (c *controller) CancelTask(ctx context.Context, req *pb.CancelTaskRequest) (*pb.CancelTaskReply, error) {
+	logrus.Infof("Begins CancelTask request, task type: %s", req.GetTaskType())
+
+	var taskName string
+	var err error
+	switch task.Type(req.GetTaskType()) {
+	case task.TaskTypeDeploy:
+		taskName = getDeployTaskName(req.GetClusterId())
+	case task.TaskTypeNodeCheck:
+		taskName = getCheckNodeTaskName(req.GetClusterId())
+	default:
+		err = fmt.Errorf("%s: %s", consts.MsgTaskTypeUnsupported, req.GetTaskType())
+	}
+	if err == nil {
+		err = task.CancelTask(taskName)
+	}
+	if err != nil {
+		logrus.Errorf("CancelTask request failed: %s", err)
+		return &pb.CancelTaskReply{
+			Cancelled: false,
+			Err: &pb.Error{
+				Reason: consts.MsgRequestFailed,
+				Detail: err.Error(),
+			},
+			ClusterId: req.GetClusterId(),
+		}, err
+	}
+
+	logrus.Info("CancelTask request succeeded")
+	return &pb.CancelTaskReply{
+		Cancelled: true,
+		Err:       nil,
+		ClusterId: req.GetClusterId(),
+	}, nil
+}
+
+func (c *controller) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksReply, error) {
+	logrus.Infof("Begins ListTasks request, cluster id: %q", req.GetClusterId())
+
+	if c.store == nil {
+		err := fmt.Errorf("no task store")
+		logrus.Errorf("ListTasks request failed: %s", err)
+		return nil, err
+	}
+
+	reply := &pb.ListTasksReply{
+		ClusterId: req.GetClusterId(),
+	}
+	for _, tsk := range c.store.ListTasks() {
+		// list all the tasks if no cluster id specified.
+		if req.GetClusterId() != "" && tsk.GetClusterID() != req.GetClusterId() {
+			continue
+		}
+		reply.Tasks = append(reply.Tasks, &pb.TaskSummary{
+			Name:              tsk.GetName(),
+			Type:              string(tsk.GetType()),
+			Status:            string(taskStatusToOperationStatus(tsk.GetStatus())),
+			Err:               tsk.GetErr(),
+			CreationTimestamp: tsk.GetCreationTimestamp().Unix(),
+			ClusterId:         tsk.GetClusterID(),
+		})
+	}
+
+	logrus.Infof("Ends ListTasks request, %d tasks found", len(reply.Tasks))
+	return reply, nil
+}
+
 func (c *controller) storeTask(task task.Task) error {
 	if c.store == nil {
 		return fmt.Errorf("no task store")
 	}
 
-	// Currently, we have to use the same task name for some kinds of repeated requests,
-	// for example, multiple check nodes and deploy requests. That means we only keep the latest
-	// result for the same kind request.
-	// TODO: review this design when support multiple clusters.
+	// The same task name is used for some kinds of repeated requests of a cluster, for example,
+	// multiple check nodes and deploy requests. That means we only keep the latest result for
+	// the same kind request of a cluster.
 	return c.store.UpdateOrAddTask(task)
 }
 
@@ -326,7 +485,15 @@ func (c *controller) getTask(name string) (task.Task, error) {
 }
 
 // Store the task and start the task, will not wait task to finish execution.
+// It's refused if a task with the same name is still running.
 func (c *controller) storeAndLanuchTask(aTask task.Task) error {
+	c.launchLock.Lock()
+	defer c.launchLock.Unlock()
+
+	if task.IsTaskRunning(aTask.GetName()) {
+		return fmt.Errorf("%s: %s", consts.MsgTaskAlreadyRunning, aTask.GetName())
+	}
+
 	// store the task
 	if err := c.storeTask(aTask); err != nil {
 		return err
@@ -336,42 +503,58 @@ func (c *controller) storeAndLanuchTask(aTask task.Task) error {
 	return task.StartTask(aTask)
 }
 
+// Resume the stored task, will not wait task to finish execution.
+func (c *controller) resumeTask(aTask task.Task) error {
+	c.launchLock.Lock()
+	defer c.launchLock.Unlock()
+
+	return task.StartResumeTask(aTask)
+}
+
 // Store the task and wait the task to finish execution.
-func (c *controller) storeAndExecuteTask(aTask task.Task) error {
+func (c *controller) storeAndExecuteTask(ctx context.Context, aTask task.Task) error {
 	// store the task
 	if err := c.storeTask(aTask); err != nil {
 		return err
 	}
 
 	// execute the task
-	return task.ExecuteTask(aTask)
+	return task.ExecuteTask(ctx, aTask)
 }
 
-func getCheckNodeTaskName() string {
-	// use a fixed name for checknode task, it may be changed in the future
-	return "node-check"
+// The tasks are keyed by the cluster id, the default one is used if it's not specified.
+const defaultClusterID = "unknown"
+
+func getClusterID(clusterID string) string {
+	if clusterID == "" {
+		return defaultClusterID
+	}
+	return clusterID
 }
 
-func getDeployTaskName() string {
-	// use "<cluster name>-deploy" as the deploy task name
-	clusterName := "unknown"
-	// TODO: review this later
-	// if req.ClusterConfig != nil && reg.ClusterConfig.ClusterName != "" {
-	// 	clusterName = reg.ClusterConfig.ClusterName
-	// }
+func getCheckNodeTaskName(clusterID string) string {
+	// use "<cluster id>-node-check" as the check node task name
+	return fmt.Sprintf("%s-%s", getClusterID(clusterID), "node-check")
+}
 
-	return fmt.Sprintf("%s-%s", clusterName, "deploy")
+func getDeployTaskName(clusterID string) string {
+	// use "<cluster id>-deploy" as the deploy task name
+	return fmt.Sprintf("%s-%s", getClusterID(clusterID), "deploy")
 }
 
 func getFetchKubeConfigTaskName(req *pb.FetchKubeConfigRequest) string {
-	// use a fixed name for now, it may be changed in the future
-	return "fetch-kube-config"
+	// use "<cluster id>-fetch-kube-config" as the fetch kube config task name
+	return fmt.Sprintf("%s-%s", getClusterID(req.GetClusterId()), "fetch-kube-config")
+}
+
+func getCheckNetworkRequirementsTaskName(clusterID string) string {
+	return fmt.Sprintf("%s-%s", getClusterID(clusterID), "check-network-requirements")
 }
 
-func getTestConnectionTaskName(nodeName string) string {
+func getTestConnectionTaskName(clusterID, nodeName string) string {
 	// User may test a node's connection repeatly, so create a unique task name
 	// for each request
-	return fmt.Sprintf("testconnection-%v-%v", nodeName, idcreator.NextString())
+	return fmt.Sprintf("%s-testconnection-%v-%v", getClusterID(clusterID), nodeName, idcreator.NextString())
 }
 
 func taskStatusToOperationStatus(status task.Status) constant.OperationStatus {
@@ -399,6 +582,8 @@ func actionStatusToOperationStatus(status action.Status) constant.OperationStatu
 		return constant.OperationStatusSuccessful
 	case action.ActionFailed:
 		return constant.OperationStatusFailed
+	case action.ActionAborted:
+		return constant.OperationStatusAborted
 	default:
 		return constant.OperationStatusUnknown
 	}
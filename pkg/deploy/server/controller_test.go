@@ -0,0 +1,108 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/constant"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+func TestGetTaskName(t *testing.T) {
+	assert.Equal(t, "unknown-deploy", getDeployTaskName(""))
+	assert.Equal(t, "cluster1-deploy", getDeployTaskName("cluster1"))
+	assert.Equal(t, "unknown-node-check", getCheckNodeTaskName(""))
+	assert.Equal(t, "cluster1-node-check", getCheckNodeTaskName("cluster1"))
+	assert.NotEqual(t, getDeployTaskName("cluster1"), getDeployTaskName("cluster2"))
+}
+
+func TestListTasks(t *testing.T) {
+	store := task.NewCacheStore()
+	creationTime := time.Unix(1570000000, 0)
+	for _, clusterID := range []string{"cluster1", "cluster2"} {
+		deployTask := &task.DeployTask{
+			Base: task.Base{
+				Name:              getDeployTaskName(clusterID),
+				TaskType:          task.TaskTypeDeploy,
+				Status:            task.TaskDoing,
+				CreationTimestamp: creationTime,
+				ClusterID:         clusterID,
+			},
+		}
+		assert.NoError(t, store.AddTask(deployTask))
+	}
+	c := &controller{store: store}
+
+	reply, err := c.ListTasks(context.Background(), &pb.ListTasksRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, reply.Tasks, 2)
+
+	reply, err = c.ListTasks(context.Background(), &pb.ListTasksRequest{ClusterId: "cluster2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster2", reply.ClusterId)
+	assert.Equal(t, []*pb.TaskSummary{
+		{
+			Name:              "cluster2-deploy",
+			Type:              string(task.TaskTypeDeploy),
+			Status:            string(constant.OperationStatusRunning),
+			CreationTimestamp: creationTime.Unix(),
+			ClusterId:         "cluster2",
+		},
+	}, reply.Tasks)
+
+	reply, err = c.ListTasks(context.Background(), &pb.ListTasksRequest{ClusterId: "cluster3"})
+	assert.NoError(t, err)
+	assert.Len(t, reply.Tasks, 0)
+}
+
+// blockingProcessor blocks the task splitting until it's released
+type blockingProcessor struct {
+	released chan struct{}
+}
+
+func (p *blockingProcessor) SplitTask(t task.Task) error {
+	<-p.released
+	return nil
+}
+
+func TestStoreAndLaunchRunningTask(t *testing.T) {
+	const taskType task.Type = "TaskTypeMockupForLaunchTest"
+	proc := &blockingProcessor{released: make(chan struct{})}
+	assert.NoError(t, task.RegisterProcessor(taskType, proc))
+
+	store := task.NewCacheStore()
+	c := &controller{store: store}
+	first := &task.Base{Name: "cluster1-deploy", TaskType: taskType, Status: task.TaskPending}
+	second := &task.Base{Name: "cluster1-deploy", TaskType: taskType, Status: task.TaskPending}
+
+	assert.NoError(t, c.storeAndLanuchTask(first))
+	// the running task is neither launched again nor replaced in the store
+	assert.Error(t, c.storeAndLanuchTask(second))
+	assert.Equal(t, first, store.GetTask("cluster1-deploy"))
+	assert.Error(t, c.resumeTask(first))
+
+	close(proc.released)
+	for i := 0; i < 500 && task.IsTaskRunning(first.GetName()); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoError(t, c.storeAndLanuchTask(second))
+	assert.Equal(t, second, store.GetTask("cluster1-deploy"))
+}
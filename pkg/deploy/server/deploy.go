@@ -91,12 +91,22 @@ func (c *controller) getDeployResult(aTask task.Task) (*pb.GetDeployResultReply,
 			itemResult := roleNodeDeployItemResult[role][node.Name]
 			itemResult.Status = string(actionStatusToOperationStatus(act.GetStatus()))
 			itemResult.Err = act.GetErr()
+			itemResult.Attempts = int32(act.GetAttempts())
 		}
 
 	}
 
 	// If all node init action are done, update deploy item results with non node init actions
 	if !initNotDone {
+		// The worker is deployed after it's prepared, so the prepare worker action which is not done
+		// is reported for the worker instead of the deploy worker action.
+		preparingNodes := make(map[string]bool)
+		for _, act := range actions {
+			if act.GetType() == action.ActionTypePrepareWorker && act.GetStatus() != action.ActionDone && act.GetNode() != nil {
+				preparingNodes[act.GetNode().GetName()] = true
+			}
+		}
+
 		for _, act := range actions {
 			if act.GetType() == action.ActionTypeNodeInit {
 				continue
@@ -108,6 +118,11 @@ func (c *controller) getDeployResult(aTask task.Task) (*pb.GetDeployResultReply,
 				continue
 			}
 
+			if act.GetType() == action.ActionTypePrepareWorker && !preparingNodes[node.Name] ||
+				act.GetType() == action.ActionTypeDeployWorker && preparingNodes[node.Name] {
+				continue
+			}
+
 			role := actionTypeToRole(act.GetType())
 			if _, ok := roleNodeDeployItemResult[role]; !ok {
 				logrus.Warnf("Didn't find the role %q in the map", role)
@@ -120,6 +135,7 @@ func (c *controller) getDeployResult(aTask task.Task) (*pb.GetDeployResultReply,
 			itemResult := roleNodeDeployItemResult[role][node.Name]
 			itemResult.Status = string(actionStatusToOperationStatus(act.GetStatus()))
 			itemResult.Err = act.GetErr()
+			itemResult.Attempts = int32(act.GetAttempts())
 		}
 	}
 
@@ -182,7 +198,7 @@ func actionTypeToRole(actionType action.Type) constant.MachineRole {
 		return constant.MachineRoleEtcd
 	case action.ActionTypeInitMaster, action.ActionTypeJoinMaster:
 		return constant.MachineRoleMaster
-	case action.ActionTypeDeployWorker:
+	case action.ActionTypePrepareWorker, action.ActionTypeDeployWorker:
 		return constant.MachineRoleWorker
 	// treat node init action as ectd role
 	case action.ActionTypeNodeInit:
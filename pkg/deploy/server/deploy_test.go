@@ -0,0 +1,72 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/constant"
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+func TestGetDeployResultPreparingWorker(t *testing.T) {
+	workerCfg := &pb.NodeDeployConfig{Node: &pb.Node{Name: "worker1"}, Roles: []string{"worker"}}
+	initAction, err := action.NewNodeInitAction(&action.NodeInitActionConfig{NodeInitConfig: workerCfg})
+	assert.NoError(t, err)
+	prepareAction, err := action.NewPrepareWorkerAction(&action.PrepareWorkerActionConfig{NodeCfg: workerCfg})
+	assert.NoError(t, err)
+	deployAction, err := action.NewDeployWorkerAction(&action.DeployWorkerActionConfig{NodeCfg: workerCfg})
+	assert.NoError(t, err)
+
+	deployTask := &task.DeployTask{
+		Base: task.Base{
+			Name:   "cluster1-deploy",
+			Status: task.TaskDoing,
+			SubTasks: []task.Task{
+				&task.Base{Name: "init-worker1", Actions: []action.Action{initAction}},
+				&task.Base{Name: "prepare-worker-worker1", Actions: []action.Action{prepareAction}},
+				&task.Base{Name: "deploy-worker", Actions: []action.Action{deployAction}},
+			},
+		},
+		NodeConfigs: []*pb.NodeDeployConfig{workerCfg},
+	}
+	initAction.SetStatus(action.ActionDone)
+
+	getWorkerStatus := func() string {
+		result, err := new(controller).getDeployResult(deployTask)
+		assert.NoError(t, err)
+		if !assert.Len(t, result.Items, 1) {
+			return ""
+		}
+		assert.Equal(t, string(constant.MachineRoleWorker), result.Items[0].DeployItem.Role)
+		return result.Items[0].Status
+	}
+
+	// the preparation is reported while the deploy worker action is pending
+	prepareAction.SetStatus(action.ActionDoing)
+	assert.Equal(t, string(constant.OperationStatusRunning), getWorkerStatus())
+	prepareAction.SetStatus(action.ActionFailed)
+	assert.Equal(t, string(constant.OperationStatusFailed), getWorkerStatus())
+
+	// the deployment is reported once the worker is prepared
+	prepareAction.SetStatus(action.ActionDone)
+	assert.Equal(t, string(constant.OperationStatusPending), getWorkerStatus())
+	deployAction.SetStatus(action.ActionDone)
+	assert.Equal(t, string(constant.OperationStatusSuccessful), getWorkerStatus())
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+func (c *controller) GetTaskGraph(ctx context.Context, req *pb.GetTaskGraphRequest) (*pb.GetTaskGraphReply, error) {
+	logrus.Infof("Begins GetTaskGraph request, cluster id: %q, task name: %q", req.GetClusterId(), req.GetTaskName())
+
+	taskName := req.GetTaskName()
+	if taskName == "" {
+		taskName = getDeployTaskName(req.GetClusterId())
+	}
+
+	reply, err := c.getTaskGraph(taskName)
+	if err != nil {
+		logrus.Errorf("GetTaskGraph request failed: %s", err)
+		return &pb.GetTaskGraphReply{
+			ClusterId: req.GetClusterId(),
+			TaskName:  taskName,
+			Err: &pb.Error{
+				Reason: consts.MsgRequestFailed,
+				Detail: err.Error(),
+			},
+		}, err
+	}
+
+	logrus.Infof("Ends GetTaskGraph request, %d nodes and %d edges found", len(reply.Nodes), len(reply.Edges))
+	return reply, nil
+}
+
+func (c *controller) getTaskGraph(taskName string) (*pb.GetTaskGraphReply, error) {
+	tsk, err := c.getTask(taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &pb.GetTaskGraphReply{
+		ClusterId: tsk.GetClusterID(),
+		TaskName:  tsk.GetName(),
+	}
+
+	var visit func(t task.Task) error
+	visit = func(t task.Task) error {
+		reply.Nodes = append(reply.Nodes, &pb.TaskGraphNode{
+			Kind:   taskEventKindTask,
+			Name:   t.GetName(),
+			Type:   string(t.GetType()),
+			Parent: t.GetParent(),
+			Status: string(t.GetStatus()),
+		})
+
+		for _, act := range t.GetActions() {
+			node := &pb.TaskGraphNode{
+				Kind:   taskEventKindAction,
+				Name:   act.GetName(),
+				Type:   string(act.GetType()),
+				Parent: t.GetName(),
+				Status: string(act.GetStatus()),
+			}
+			if act.GetNode() != nil {
+				node.NodeName = act.GetNode().GetName()
+			}
+			reply.Nodes = append(reply.Nodes, node)
+		}
+
+		edges, err := task.GetSubTaskEdges(t)
+		if err != nil {
+			return fmt.Errorf("task %s: %v", t.GetName(), err)
+		}
+		for _, edge := range edges {
+			reply.Edges = append(reply.Edges, &pb.TaskGraphEdge{
+				From: edge[0],
+				To:   edge[1],
+			})
+		}
+
+		for _, subTask := range t.GetSubTasks() {
+			if err := visit(subTask); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(tsk); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+func TestGetTaskGraph(t *testing.T) {
+	store := task.NewCacheStore()
+	deployTask := newWatchedTask("cluster1")
+	deployTask.SubTasks = append([]task.Task{
+		&task.Base{
+			Name:     "init",
+			TaskType: task.TaskTypeNodeInit,
+			Status:   task.TaskSuccessful,
+			Parent:   deployTask.GetName(),
+		},
+	}, deployTask.SubTasks...)
+	deployTask.SubTasks[1].(*task.Base).Dependencies = []string{"init"}
+	assert.NoError(t, store.AddTask(deployTask))
+	c := &controller{store: store}
+
+	reply, err := c.GetTaskGraph(context.Background(), &pb.GetTaskGraphRequest{ClusterId: "cluster1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster1-deploy", reply.TaskName)
+	assert.Equal(t, []*pb.TaskGraphNode{
+		{
+			Kind:   taskEventKindTask,
+			Name:   "cluster1-deploy",
+			Type:   string(task.TaskTypeDeploy),
+			Status: string(task.TaskDoing),
+		},
+		{
+			Kind:   taskEventKindTask,
+			Name:   "init",
+			Type:   string(task.TaskTypeNodeInit),
+			Parent: "cluster1-deploy",
+			Status: string(task.TaskSuccessful),
+		},
+		{
+			Kind:   taskEventKindTask,
+			Name:   "deploy-etcd",
+			Type:   string(task.TaskTypeDeployEtcd),
+			Parent: "cluster1-deploy",
+			Status: string(task.TaskDoing),
+		},
+		{
+			Kind:     taskEventKindAction,
+			Name:     "etcd-action",
+			Type:     string(action.ActionTypeDeployEtcd),
+			Parent:   "deploy-etcd",
+			NodeName: "node1",
+			Status:   string(action.ActionDoing),
+		},
+	}, reply.Nodes)
+	assert.Equal(t, []*pb.TaskGraphEdge{{From: "init", To: "deploy-etcd"}}, reply.Edges)
+
+	// a dependency cycle
+	deployTask.SubTasks[0].(*task.Base).Dependencies = []string{"deploy-etcd"}
+	reply, err = c.GetTaskGraph(context.Background(), &pb.GetTaskGraphRequest{TaskName: "cluster1-deploy"})
+	assert.Error(t, err)
+	assert.Contains(t, reply.Err.Detail, "dependency cycle")
+
+	// unknown task
+	reply, err = c.GetTaskGraph(context.Background(), &pb.GetTaskGraphRequest{ClusterId: "cluster2"})
+	assert.Error(t, err)
+	assert.Equal(t, "cluster2-deploy", reply.TaskName)
+}
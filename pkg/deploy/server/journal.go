@@ -0,0 +1,80 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+func (c *controller) GetTaskEvents(ctx context.Context, req *pb.GetTaskEventsRequest) (*pb.GetTaskEventsReply, error) {
+	logrus.Infof("Begins GetTaskEvents request, cluster id: %q, task name: %q", req.GetClusterId(), req.GetTaskName())
+
+	taskName := req.GetTaskName()
+	if taskName == "" {
+		taskName = getDeployTaskName(req.GetClusterId())
+	}
+
+	tsk, err := c.getTask(taskName)
+	if err != nil {
+		logrus.Errorf("GetTaskEvents request failed: %s", err)
+		return &pb.GetTaskEventsReply{
+			ClusterId: req.GetClusterId(),
+			TaskName:  taskName,
+			Err: &pb.Error{
+				Reason: consts.MsgRequestFailed,
+				Detail: err.Error(),
+			},
+		}, err
+	}
+
+	reply := &pb.GetTaskEventsReply{
+		ClusterId: tsk.GetClusterID(),
+		TaskName:  tsk.GetName(),
+		TaskType:  string(tsk.GetType()),
+	}
+	// the journal is created once the task starts
+	if tsk.GetJournal() != nil {
+		for _, event := range tsk.GetJournal().Events() {
+			reply.Events = append(reply.Events, toJournalEvent(event))
+		}
+	}
+
+	logrus.Infof("Ends GetTaskEvents request, %d events found", len(reply.Events))
+	return reply, nil
+}
+
+func toJournalEvent(event *task.Event) *pb.JournalEvent {
+	return &pb.JournalEvent{
+		Seq:        event.Seq,
+		Kind:       event.Kind,
+		Name:       event.Name,
+		Parent:     event.Parent,
+		ActionType: event.ActionType,
+		NodeName:   event.NodeName,
+		FromStatus: event.From,
+		ToStatus:   event.To,
+		Err:        event.Err,
+		Timestamp:  event.Timestamp.UnixNano() / int64(time.Millisecond),
+		Duration:   int64(event.Duration / time.Millisecond),
+		CauseSeq:   event.CauseSeq,
+	}
+}
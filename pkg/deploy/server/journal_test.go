@@ -0,0 +1,66 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+func TestGetTaskEvents(t *testing.T) {
+	store := task.NewCacheStore()
+	deployTask := newWatchedTask("cluster1")
+	assert.NoError(t, store.AddTask(deployTask))
+	c := &controller{store: store}
+
+	// the task isn't started yet
+	reply, err := c.GetTaskEvents(context.Background(), &pb.GetTaskEventsRequest{ClusterId: "cluster1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster1-deploy", reply.TaskName)
+	assert.Equal(t, string(task.TaskTypeDeploy), reply.TaskType)
+	assert.Empty(t, reply.Events)
+
+	journal := task.NewJournal()
+	deployTask.SetJournal(journal)
+	etcdTask := deployTask.GetSubTasks()[0]
+	etcdTask.SetJournal(journal)
+	etcdTask.SetStatus(task.TaskFailed)
+	etcdTask.SetErr(&pb.Error{Reason: "etcd failed"})
+	deployTask.SetStatus(task.TaskFailed)
+
+	reply, err = c.GetTaskEvents(context.Background(), &pb.GetTaskEventsRequest{ClusterId: "cluster1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster1", reply.ClusterId)
+	if assert.Len(t, reply.Events, 2) {
+		assert.Equal(t, uint64(1), reply.Events[0].Seq)
+		assert.Equal(t, task.EventKindTask, reply.Events[0].Kind)
+		assert.Equal(t, "deploy-etcd", reply.Events[0].Name)
+		assert.Equal(t, "cluster1-deploy", reply.Events[0].Parent)
+		assert.Equal(t, string(task.TaskDoing), reply.Events[0].FromStatus)
+		assert.Equal(t, string(task.TaskFailed), reply.Events[0].ToStatus)
+		assert.Equal(t, "etcd failed", reply.Events[0].Err.Reason)
+		assert.True(t, reply.Events[0].Timestamp > 0)
+		assert.Equal(t, "cluster1-deploy", reply.Events[1].Name)
+		assert.Equal(t, uint64(1), reply.Events[1].CauseSeq)
+	}
+
+	_, err = c.GetTaskEvents(context.Background(), &pb.GetTaskEventsRequest{TaskName: "not-exist"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func (c *controller) ListKnownHosts(ctx context.Context, req *pb.ListKnownHostsRequest) (*pb.ListKnownHostsReply, error) {
+	logrus.Info("Begins ListKnownHosts request")
+
+	reply := &pb.ListKnownHostsReply{}
+	for _, knownHost := range mssh.GetKnownHostsStore().List() {
+		reply.KnownHosts = append(reply.KnownHosts, &pb.KnownHost{
+			Host:            knownHost.Host,
+			KeyType:         knownHost.Key.Type(),
+			Fingerprint:     knownHost.Fingerprint,
+			PinnedTimestamp: knownHost.PinnedAt.Unix(),
+		})
+	}
+
+	logrus.Infof("Ends ListKnownHosts request, %d known hosts found", len(reply.KnownHosts))
+	return reply, nil
+}
+
+func (c *controller) ForgetKnownHost(ctx context.Context, req *pb.ForgetKnownHostRequest) (*pb.ForgetKnownHostReply, error) {
+	logrus.Infof("Begins ForgetKnownHost request, host: %s", req.GetHost())
+
+	forgotten, err := mssh.GetKnownHostsStore().Forget(req.GetHost())
+	if err != nil {
+		logrus.Errorf("ForgetKnownHost request failed: %s", err)
+		return &pb.ForgetKnownHostReply{
+			Forgotten: false,
+			Err: &pb.Error{
+				Reason: consts.MsgRequestFailed,
+				Detail: err.Error(),
+			},
+		}, err
+	}
+
+	logrus.Infof("Ends ForgetKnownHost request, forgotten: %v", forgotten)
+	return &pb.ForgetKnownHostReply{
+		Forgotten: forgotten,
+	}, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func TestKnownHosts(t *testing.T) {
+	store := mssh.NewMemoryKnownHostsStore()
+	mssh.SetKnownHostsStore(store)
+	defer mssh.SetKnownHostsStore(mssh.NewMemoryKnownHostsStore())
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	key, err := ssh.NewPublicKey(publicKey)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Pin("192.168.0.1:22", key))
+
+	c := &controller{}
+	listReply, err := c.ListKnownHosts(context.Background(), &pb.ListKnownHostsRequest{})
+	assert.Nil(t, err)
+	assert.Len(t, listReply.KnownHosts, 1)
+	assert.Equal(t, "192.168.0.1", listReply.KnownHosts[0].Host)
+	assert.Equal(t, ssh.KeyAlgoED25519, listReply.KnownHosts[0].KeyType)
+	assert.Equal(t, mssh.Fingerprint(key), listReply.KnownHosts[0].Fingerprint)
+
+	forgetReply, err := c.ForgetKnownHost(context.Background(), &pb.ForgetKnownHostRequest{Host: "192.168.0.1"})
+	assert.Nil(t, err)
+	assert.True(t, forgetReply.Forgotten)
+
+	forgetReply, err = c.ForgetKnownHost(context.Background(), &pb.ForgetKnownHostRequest{Host: "192.168.0.1"})
+	assert.Nil(t, err)
+	assert.False(t, forgetReply.Forgotten)
+}
@@ -16,9 +16,12 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -28,6 +31,16 @@ import (
 	"github.com/kpaas-io/kpaas/pkg/deploy/task"
 )
 
+const (
+	// the interval to look for the new written logs when following a log
+	tailLogPeriod = 500 * time.Millisecond
+	// the max size of the data in a log chunk
+	logChunkSize = 32 * 1024
+)
+
+// the separator appended after each action log file
+var logFileSeparator = []byte("\n\n")
+
 func (c *controller) getCheckNodesLog(aTask task.Task, nodeName string) (*pb.GetCheckNodesLogReply, error) {
 	if aTask == nil {
 		return nil, fmt.Errorf("Task is nil")
@@ -38,13 +51,9 @@ func (c *controller) getCheckNodesLog(aTask task.Task, nodeName string) (*pb.Get
 	// several hundred MBs). If they are huge, we need to consider to send back the log via stream (gRPC support this).
 	var buf bytes.Buffer
 
-	// Get all actions of the check nodes task
-	actions := task.GetAllActions(aTask)
+	// Get the actions of the node in the check nodes task
+	actions := getSortedActions(aTask, checkNodesLogFilter(nodeName))
 	for _, act := range actions {
-		node := act.GetNode()
-		if node == nil || node.GetName() != nodeName {
-			continue
-		}
 		logFilePath := act.GetLogFilePath()
 		if logFilePath == "" {
 			continue
@@ -62,7 +71,7 @@ func (c *controller) getCheckNodesLog(aTask task.Task, nodeName string) (*pb.Get
 		}
 
 		// Append two blank lines between each log files
-		buf.WriteString("\n\n")
+		buf.Write(logFileSeparator)
 	}
 
 	result := &pb.GetCheckNodesLogReply{
@@ -84,17 +93,9 @@ func (c *controller) getDeployLog(aTask task.Task, role constant.MachineRole, no
 	// several hundred MBs). If they are huge, we need to consider to send back the log via stream (gRPC support this).
 	var buf bytes.Buffer
 
-	// Get all actions of the deploy task
-	actions := task.GetAllActions(aTask)
+	// Only collect the log of the actions that was created to the deploy role of the node.
+	actions := getSortedActions(aTask, deployLogFilter(role, nodeName))
 	for _, act := range actions {
-		// Only collect the log of the actions that was created to the deploy role.
-		if !actionBelongsToRole(act.GetType(), role) {
-			continue
-		}
-		node := act.GetNode()
-		if node == nil || node.GetName() != nodeName {
-			continue
-		}
 		logFilePath := act.GetLogFilePath()
 		if logFilePath == "" {
 			continue
@@ -112,7 +113,7 @@ func (c *controller) getDeployLog(aTask task.Task, role constant.MachineRole, no
 		}
 
 		// Append two blank lines between each log files
-		buf.WriteString("\n\n")
+		buf.Write(logFileSeparator)
 	}
 
 	result := &pb.GetDeployLogReply{
@@ -137,8 +138,9 @@ var roleActionTypeMap = map[constant.MachineRole]map[action.Type]struct{}{
 		action.ActionTypeJoinMaster: struct{}{},
 	},
 	constant.MachineRoleWorker: map[action.Type]struct{}{
-		action.ActionTypeNodeInit:     struct{}{},
-		action.ActionTypeDeployWorker: struct{}{},
+		action.ActionTypeNodeInit:      struct{}{},
+		action.ActionTypePrepareWorker: struct{}{},
+		action.ActionTypeDeployWorker:  struct{}{},
 	},
 	constant.MachineRoleIngress: map[action.Type]struct{}{
 		action.ActionTypeNodeInit: struct{}{},
@@ -158,3 +160,190 @@ func actionBelongsToRole(actionType action.Type, role constant.MachineRole) bool
 
 	return ok
 }
+
+// actionFilter selects the actions whose log files make up a log.
+type actionFilter func(act action.Action) bool
+
+func checkNodesLogFilter(nodeName string) actionFilter {
+	return func(act action.Action) bool {
+		node := act.GetNode()
+		return node != nil && node.GetName() == nodeName
+	}
+}
+
+func deployLogFilter(role constant.MachineRole, nodeName string) actionFilter {
+	return func(act action.Action) bool {
+		node := act.GetNode()
+		return actionBelongsToRole(act.GetType(), role) && node != nil && node.GetName() == nodeName
+	}
+}
+
+// tailLog follows the log files of the selected actions of the task and sends the new written logs,
+// until the task finishes or the context is cancelled. The log is the action log files ordered by
+// creation time, each file is followed by two blank lines. Offset is the position in the log to
+// start from.
+func tailLog(ctx context.Context, aTask task.Task, filter actionFilter, offset int64, send func(*pb.LogChunk) error) error {
+	if aTask == nil {
+		return fmt.Errorf("Task is nil")
+	}
+
+	ticker := time.NewTicker(tailLogPeriod)
+	defer ticker.Stop()
+
+	follower := &logFollower{skip: offset, send: send}
+	// the actions whose log files have been followed to the end
+	followed := make(map[string]bool)
+	for {
+		// Check the task status before reading the logs, so that the logs written
+		// before the task finished are all read.
+		taskFinished := isTaskFinished(aTask)
+
+		actions := getSortedActions(aTask, filter)
+		for _, act := range actions {
+			if followed[act.GetName()] {
+				continue
+			}
+			done, err := follower.follow(act, taskFinished)
+			if err != nil {
+				return err
+			}
+			// Follow the next action log only after the current one has been finished,
+			// so that the logs of different actions are not interleaved.
+			if !done {
+				break
+			}
+			followed[act.GetName()] = true
+		}
+
+		if taskFinished && len(followed) == len(actions) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func getSortedActions(aTask task.Task, filter actionFilter) []action.Action {
+	var actions []action.Action
+	for _, act := range task.GetAllActions(aTask) {
+		if filter(act) {
+			actions = append(actions, act)
+		}
+	}
+
+	// The actions created later are appended to the end, so the log is stable while it grows.
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].GetCreationTimestamp().Before(actions[j].GetCreationTimestamp())
+	})
+	return actions
+}
+
+func isActionFinished(act action.Action) bool {
+	switch act.GetStatus() {
+	case action.ActionDone, action.ActionFailed, action.ActionAborted:
+		return true
+	}
+	return false
+}
+
+// logFollower reads the action log files one by one and sends the data.
+type logFollower struct {
+	// the action being followed and the position in its log file
+	current  string
+	position int64
+	// the position in the log of the next data to send
+	offset int64
+	// the data to skip before sending
+	skip int64
+	send func(*pb.LogChunk) error
+}
+
+// follow sends the new written log of the action, returns true if the whole log of the action was sent.
+func (f *logFollower) follow(act action.Action, taskFinished bool) (bool, error) {
+	if f.current != act.GetName() {
+		f.current = act.GetName()
+		f.position = 0
+	}
+
+	// The action will not run any more once the task finished.
+	finished := taskFinished || isActionFinished(act)
+
+	logFilePath := act.GetLogFilePath()
+	if logFilePath == "" {
+		return finished, nil
+	}
+
+	exist, err := f.readFile(logFilePath)
+	if err != nil {
+		logrus.Errorf("Read log file %q failed: %v", logFilePath, err)
+		return false, err
+	}
+
+	if !finished {
+		return false, nil
+	}
+
+	if exist {
+		if err := f.emit(logFileSeparator); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// readFile sends the data after the current position of the file, returns false if the file not exist.
+func (f *logFollower) readFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	if _, err = file.Seek(f.position, io.SeekStart); err != nil {
+		return true, err
+	}
+
+	for {
+		data := make([]byte, logChunkSize)
+		n, err := file.Read(data)
+		if n > 0 {
+			f.position += int64(n)
+			if sendErr := f.emit(data[:n]); sendErr != nil {
+				return true, sendErr
+			}
+		}
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return true, err
+		}
+	}
+}
+
+func (f *logFollower) emit(data []byte) error {
+	if f.skip > 0 {
+		if int64(len(data)) <= f.skip {
+			f.skip -= int64(len(data))
+			f.offset += int64(len(data))
+			return nil
+		}
+		data = data[f.skip:]
+		f.offset += f.skip
+		f.skip = 0
+	}
+
+	chunk := &pb.LogChunk{
+		Data:   data,
+		Offset: f.offset,
+	}
+	f.offset += int64(len(data))
+	return f.send(chunk)
+}
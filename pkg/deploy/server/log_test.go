@@ -15,12 +15,20 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
 	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
 )
 
 func TestActionBelongsToRole(t *testing.T) {
@@ -64,6 +72,10 @@ func TestActionBelongsToRole(t *testing.T) {
 			input: inputS{action.ActionTypeDeployWorker, constant.MachineRoleWorker},
 			want:  true,
 		},
+		{
+			input: inputS{action.ActionTypePrepareWorker, constant.MachineRoleWorker},
+			want:  true,
+		},
 		{
 			input: inputS{action.ActionTypeDeployEtcd, constant.MachineRoleMaster},
 			want:  false,
@@ -91,3 +103,118 @@ func TestActionBelongsToRole(t *testing.T) {
 		assert.Equal(t, tt.want, result)
 	}
 }
+
+func newLogAction(t *testing.T, dir, name string, actionType action.Type, content string, created time.Time) action.Action {
+	logFilePath := filepath.Join(dir, name+".log")
+	assert.NoError(t, ioutil.WriteFile(logFilePath, []byte(content), 0644))
+	return &action.Base{
+		Name:              name,
+		ActionType:        actionType,
+		Status:            action.ActionDone,
+		LogFilePath:       logFilePath,
+		CreationTimestamp: created,
+		Node:              &pb.Node{Name: "node1"},
+	}
+}
+
+func newLogTask(t *testing.T, dir string) task.Task {
+	now := time.Now()
+	return &task.DeployTask{
+		Base: task.Base{
+			Name:     "cluster1-deploy",
+			TaskType: task.TaskTypeDeploy,
+			Status:   task.TaskSuccessful,
+			Actions: []action.Action{
+				newLogAction(t, dir, "init-master", action.ActionTypeInitMaster, "init master log", now.Add(time.Second)),
+				newLogAction(t, dir, "node-init", action.ActionTypeNodeInit, "node init log", now),
+				newLogAction(t, dir, "deploy-etcd", action.ActionTypeDeployEtcd, "deploy etcd log", now),
+			},
+		},
+	}
+}
+
+func tailLogToBuffer(aTask task.Task, filter actionFilter, offset int64) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	expectedOffset := offset
+	err := tailLog(context.Background(), aTask, filter, offset, func(chunk *pb.LogChunk) error {
+		if chunk.Offset != expectedOffset {
+			return assert.AnError
+		}
+		expectedOffset += int64(len(chunk.Data))
+		buf.Write(chunk.Data)
+		return nil
+	})
+	return &buf, err
+}
+
+func TestTailLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kpaas-tail-log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	aTask := newLogTask(t, dir)
+	c := &controller{}
+
+	// the followed log of a finished task is the same as the log got at once
+	buf, err := tailLogToBuffer(aTask, deployLogFilter(constant.MachineRoleMaster, "node1"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "node init log\n\ninit master log\n\n", buf.String())
+	reply, err := c.getDeployLog(aTask, constant.MachineRoleMaster, "node1")
+	assert.NoError(t, err)
+	assert.Equal(t, buf.String(), string(reply.Log))
+
+	// resume from an offset
+	buf, err = tailLogToBuffer(aTask, deployLogFilter(constant.MachineRoleMaster, "node1"), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "log\n\ninit master log\n\n", buf.String())
+	buf, err = tailLogToBuffer(aTask, deployLogFilter(constant.MachineRoleMaster, "node1"), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "", buf.String())
+
+	buf, err = tailLogToBuffer(aTask, checkNodesLogFilter("node2"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "", buf.String())
+}
+
+func TestLogFollower(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kpaas-tail-log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var buf bytes.Buffer
+	follower := &logFollower{send: func(chunk *pb.LogChunk) error {
+		buf.Write(chunk.Data)
+		return nil
+	}}
+
+	act := newLogAction(t, dir, "node-init", action.ActionTypeNodeInit, "line1\n", time.Now())
+	act.SetStatus(action.ActionDoing)
+
+	done, err := follower.follow(act, false)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "line1\n", buf.String())
+
+	// only the new written log is sent
+	file, err := os.OpenFile(act.GetLogFilePath(), os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = file.WriteString("line2\n")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+	act.SetStatus(action.ActionDone)
+
+	done, err = follower.follow(act, false)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "line1\nline2\n\n\n", buf.String())
+
+	// the log file of a pending action is not created, it's done once the task finished
+	pending := &action.Base{Name: "pending", Status: action.ActionPending, LogFilePath: filepath.Join(dir, "pending.log")}
+	done, err = follower.follow(pending, false)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	done, err = follower.follow(pending, true)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "line1\nline2\n\n\n", buf.String())
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+// PlanDeploy splits the deploy task and plans its actions, the task is neither stored nor executed.
+func (c *controller) PlanDeploy(ctx context.Context, req *pb.PlanDeployRequest) (*pb.PlanDeployReply, error) {
+	logrus.Infof("Begins PlanDeploy request, cluster id: %q", req.GetClusterId())
+
+	taskConfig := &task.DeployTaskConfig{
+		NodeConfigs:     req.NodeConfigs,
+		ClusterConfig:   req.ClusterConfig,
+		LogFileBasePath: c.logFileLoc,
+	}
+
+	deployTask, err := task.NewDeployTask(getDeployTaskName(req.GetClusterId()), taskConfig)
+	if err != nil {
+		logrus.Errorf("PlanDeploy request failed: %s", err)
+		return &pb.PlanDeployReply{
+			ClusterId: req.GetClusterId(),
+			Err: &pb.Error{
+				Reason: consts.MsgRequestFailed,
+				Detail: err.Error(),
+			},
+		}, err
+	}
+	deployTask.SetClusterID(req.GetClusterId())
+
+	plan := task.PlanTask(deployTask)
+
+	logrus.Infof("Ends PlanDeploy request, %d sub tasks planned", len(plan.SubTasks))
+	return &pb.PlanDeployReply{
+		ClusterId: req.GetClusterId(),
+		Plan:      plan,
+		Err:       plan.Err,
+	}, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+func TestPlanDeploy(t *testing.T) {
+	store := task.NewCacheStore()
+	c := &controller{store: store}
+
+	reply, err := c.PlanDeploy(context.Background(), &pb.PlanDeployRequest{
+		ClusterId: "cluster1",
+		NodeConfigs: []*pb.NodeDeployConfig{
+			{Node: &pb.Node{Name: "master1", Ip: "10.1.1.1"}, Roles: []string{"etcd", "master"}},
+		},
+		ClusterConfig: &pb.ClusterConfig{
+			KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "firstMasterIP"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, reply.Err)
+	assert.Equal(t, "cluster1-deploy", reply.Plan.Name)
+	assert.Len(t, reply.Plan.SubTasks, 3)
+
+	// the planned task is not stored
+	assert.Nil(t, store.GetTask("cluster1-deploy"))
+
+	// nothing to deploy
+	reply, err = c.PlanDeploy(context.Background(), &pb.PlanDeployRequest{ClusterId: "cluster1"})
+	assert.Error(t, err)
+	assert.NotNil(t, reply.Err)
+}
@@ -17,15 +17,23 @@ package server
 import (
 	"fmt"
 	"net"
+	"path/filepath"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
+	"k8s.io/apimachinery/pkg/util/wait"
 
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
 	"github.com/kpaas-io/kpaas/pkg/deploy/task"
 )
 
+// the interval to save the changed tasks into the persistent task store
+const taskStoreSyncPeriod = 5 * time.Second
+
 type Interface interface {
 	Run(stopCh <-chan struct{}) error
 }
@@ -33,25 +41,91 @@ type Interface interface {
 type ServerOptions struct {
 	Port       uint16
 	LogFileLoc string
+	// the max number of the concurrent ssh sessions to a node, the default value is used if it's 0
+	MaxSSHSessionsPerNode int
+
+	// the concurrency limits of the action execution, the default values are used if they're 0
+	MaxConcurrentActions            int
+	MaxConcurrentActionsPerTaskType map[string]int
+	WorkerBatchSize                 int
+	WorkerBatchFailureThreshold     int
 }
 
 type server struct {
-	port       uint16
-	logFileLoc string
+	port                  uint16
+	logFileLoc            string
+	maxSSHSessionsPerNode int
+	concurrencyOptions    task.ConcurrencyOptions
 }
 
 func New(options ServerOptions) Interface {
 	return &server{
-		port:       options.Port,
-		logFileLoc: options.LogFileLoc,
+		port:                  options.Port,
+		logFileLoc:            options.LogFileLoc,
+		maxSSHSessionsPerNode: options.MaxSSHSessionsPerNode,
+		concurrencyOptions:    newConcurrencyOptions(options),
+	}
+}
+
+func newConcurrencyOptions(options ServerOptions) task.ConcurrencyOptions {
+	perTaskType := make(map[task.Type]int, len(options.MaxConcurrentActionsPerTaskType))
+	for taskType, max := range options.MaxConcurrentActionsPerTaskType {
+		perTaskType[task.Type(taskType)] = max
+	}
+
+	return task.ConcurrencyOptions{
+		MaxConcurrentActions:            options.MaxConcurrentActions,
+		MaxConcurrentActionsPerTaskType: perTaskType,
+		WorkerBatchSize:                 options.WorkerBatchSize,
+		WorkerBatchFailureThreshold:     options.WorkerBatchFailureThreshold,
 	}
 }
 
 func (s *server) Run(stopCh <-chan struct{}) error {
-	gRpcSvr := grpc.NewServer()
+	// the requests are validated before they are handled
+	gRpcSvr := grpc.NewServer(
+		grpc.UnaryInterceptor(validateUnaryRequest),
+		grpc.StreamInterceptor(validateStreamRequest),
+	)
+
+	// use the persistent store under the log file location, so that the tasks can be reloaded
+	// after restart. Fall back to the map cache store if it can't be opened.
+	var store task.Store
+	persistentStore, err := task.NewPersistentStore(filepath.Join(s.logFileLoc, task.TaskStoreFileName))
+	if err != nil {
+		logrus.Warnf("Failed to open the persistent task store, use the cache store instead: %s", err)
+		store = task.GetGlobalCacheStore()
+	} else {
+		store = persistentStore
+		defer func() {
+			if err := persistentStore.Close(); err != nil {
+				logrus.Errorf("Failed to close the persistent task store: %s", err)
+			}
+		}()
+		go wait.Until(func() {
+			if err := persistentStore.Sync(); err != nil {
+				logrus.Errorf("Failed to sync the persistent task store: %s", err)
+			}
+		}, taskStoreSyncPeriod, stopCh)
+	}
+
+	// the pinned ssh host keys are saved under the log file location too, so that they are
+	// still verified after restart.
+	knownHostsStore, err := mssh.NewKnownHostsStore(filepath.Join(s.logFileLoc, mssh.KnownHostsFileName))
+	if err != nil {
+		return fmt.Errorf("failed to open the known hosts store: %s", err)
+	}
+	mssh.SetKnownHostsStore(knownHostsStore)
+
+	// the ssh connections to the nodes are shared by the actions, and closed when the server stops
+	machine.SetPoolOptions(machine.PoolOptions{
+		MaxSessionsPerNode: s.maxSSHSessionsPerNode,
+	})
+	defer machine.ClosePool()
+
+	// the actions are executed in the limits, so that a large cluster doesn't overload the controller and the mirrors
+	task.SetConcurrencyOptions(s.concurrencyOptions)
 
-	// use the map cache store
-	store := task.GetGlobalCacheStore()
 	protos.RegisterDeployContollerServer(gRpcSvr, &controller{
 		store:      store,
 		logFileLoc: s.logFileLoc,
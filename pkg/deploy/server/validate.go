@@ -0,0 +1,73 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"regexp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The cluster id is used in the task names, which are the keys of the task store and
+// the directory names of the task logs, so only the safe characters are allowed.
+var clusterIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+type clusterIDGetter interface {
+	GetClusterId() string
+}
+
+// validateRequest rejects the request with an invalid cluster id.
+func validateRequest(req interface{}) error {
+	r, ok := req.(clusterIDGetter)
+	if !ok {
+		return nil
+	}
+	if clusterID := r.GetClusterId(); !clusterIDPattern.MatchString(clusterID) {
+		return status.Errorf(codes.InvalidArgument,
+			"invalid cluster id %q: only letters, digits, '_' and '-' are allowed", clusterID)
+	}
+	return nil
+}
+
+// validateUnaryRequest is the unary interceptor which validates the requests before handling them.
+func validateUnaryRequest(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// validateStreamRequest is the stream interceptor which validates the requests received by the stream handlers.
+func validateStreamRequest(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	return handler(srv, &validatingServerStream{ServerStream: ss})
+}
+
+type validatingServerStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validateRequest(m)
+}
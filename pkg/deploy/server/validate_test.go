@@ -0,0 +1,75 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func TestValidateUnaryRequest(t *testing.T) {
+	handled := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled++
+		return &pb.GetDeployResultReply{}, nil
+	}
+
+	for _, clusterID := range []string{"", "cluster-1", "Cluster_2"} {
+		_, err := validateUnaryRequest(context.Background(), &pb.GetDeployResultRequest{ClusterId: clusterID}, nil, handler)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 3, handled)
+
+	for _, clusterID := range []string{"../../etc", "a/b", "a b", "cluster.1"} {
+		_, err := validateUnaryRequest(context.Background(), &pb.GetDeployResultRequest{ClusterId: clusterID}, nil, handler)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), clusterID)
+	}
+	assert.Equal(t, 3, handled)
+
+	// the requests without the cluster id are not validated
+	_, err := validateUnaryRequest(context.Background(), &pb.ListKnownHostsRequest{}, nil, handler)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, handled)
+}
+
+// fakeServerStream receives the request from the field.
+type fakeServerStream struct {
+	grpc.ServerStream
+	req *pb.WatchTaskRequest
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	*m.(*pb.WatchTaskRequest) = *s.req
+	return nil
+}
+
+func TestValidateStreamRequest(t *testing.T) {
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&pb.WatchTaskRequest{})
+	}
+
+	err := validateStreamRequest(nil, &fakeServerStream{req: &pb.WatchTaskRequest{ClusterId: "cluster1"}}, nil, handler)
+	assert.Nil(t, err)
+
+	err = validateStreamRequest(nil, &fakeServerStream{req: &pb.WatchTaskRequest{ClusterId: "../cluster1"}}, nil, handler)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
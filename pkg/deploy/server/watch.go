@@ -0,0 +1,278 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+// The status transitions of the watched tasks are streamed from their journals once they happen,
+// the store is rescanned in this interval only to find the tasks created or replaced.
+const watchTaskRescanPeriod = time.Second
+
+const (
+	taskEventKindTask   = task.EventKindTask
+	taskEventKindAction = task.EventKindAction
+)
+
+// WatchTask streams the status transitions of the named task, or of all the tasks of the cluster
+// if no task name specified. The current status of the tasks are sent at first.
+func (c *controller) WatchTask(req *pb.WatchTaskRequest, stream pb.DeployContoller_WatchTaskServer) error {
+	logrus.Infof("Begins WatchTask request, cluster id: %q, task name: %q", req.GetClusterId(), req.GetTaskName())
+
+	if c.store == nil {
+		err := fmt.Errorf("no task store")
+		logrus.Errorf("WatchTask request failed: %s", err)
+		return err
+	}
+
+	if req.GetTaskName() != "" {
+		if _, err := c.getTask(req.GetTaskName()); err != nil {
+			logrus.Errorf("WatchTask request failed: %s", err)
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(watchTaskRescanPeriod)
+	defer ticker.Stop()
+
+	// notified by the journals of the watched tasks
+	notify := make(chan struct{}, 1)
+	// key is the name of the top level task
+	watchers := make(map[string]*taskWatcher)
+	defer func() {
+		for _, watcher := range watchers {
+			watcher.stop()
+		}
+	}()
+
+	for {
+		tasks := c.getWatchedTasks(req)
+		watched := make(map[string]bool, len(tasks))
+		for _, tsk := range tasks {
+			watched[tsk.GetName()] = true
+			watcher, ok := watchers[tsk.GetName()]
+			// the task is watched again if it's replaced by a new one
+			if !ok || watcher.task != tsk {
+				if ok {
+					watcher.stop()
+				}
+				watcher = newTaskWatcher(tsk)
+				watchers[tsk.GetName()] = watcher
+			}
+
+			for _, event := range watcher.events(notify) {
+				if err := stream.Send(event); err != nil {
+					logrus.Infof("Ends WatchTask request: %s", err)
+					return err
+				}
+			}
+		}
+		for name, watcher := range watchers {
+			if !watched[name] {
+				watcher.stop()
+				delete(watchers, name)
+			}
+		}
+
+		// stop watching once the named task finishes
+		if req.GetTaskName() != "" && len(tasks) == 1 && isTaskFinished(tasks[0]) {
+			logrus.Infof("Ends WatchTask request, task %q finished", req.GetTaskName())
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			logrus.Infof("Ends WatchTask request: %s", stream.Context().Err())
+			return nil
+		case <-notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *controller) getWatchedTasks(req *pb.WatchTaskRequest) []task.Task {
+	if req.GetTaskName() != "" {
+		tsk := c.store.GetTask(req.GetTaskName())
+		if tsk == nil {
+			return nil
+		}
+		return []task.Task{tsk}
+	}
+
+	var tasks []task.Task
+	for _, tsk := range c.store.ListTasks() {
+		if tsk.GetClusterID() == req.GetClusterId() {
+			tasks = append(tasks, tsk)
+		}
+	}
+	return tasks
+}
+
+func isTaskFinished(tsk task.Task) bool {
+	return tsk.GetStatus() == task.TaskSuccessful || tsk.GetStatus() == task.TaskFailed
+}
+
+// taskWatcher finds out the status transitions of a top level task, its sub tasks and actions.
+// The current status is sent at first, then the transitions are taken from the journal of the task,
+// so none of them is missed however fast they happen.
+type taskWatcher struct {
+	task        task.Task
+	started     bool
+	journal     *task.Journal
+	unsubscribe func()
+	// the last seen status of the tasks and actions, only used before the journal is created. Key is
+	// the kind, parent and name of a task, or the kind, parent, action type and node of an action,
+	// value is status and error.
+	lastSeen map[string]string
+	// the journal events which were sent, key is the seq, value is status and error
+	sent map[uint64]string
+}
+
+func newTaskWatcher(root task.Task) *taskWatcher {
+	return &taskWatcher{
+		task:     root,
+		lastSeen: make(map[string]string),
+		sent:     make(map[uint64]string),
+	}
+}
+
+// events returns the events of the status transitions since the last call, notify is subscribed
+// to the journal of the task once the journal is created.
+func (w *taskWatcher) events(notify chan<- struct{}) []*pb.TaskEvent {
+	journal := w.task.GetJournal()
+	if journal == nil {
+		// the task is not started yet
+		w.started = true
+		return w.diff()
+	}
+
+	if journal != w.journal {
+		w.stop()
+		w.journal = journal
+		w.unsubscribe = journal.Subscribe(notify)
+		w.sent = make(map[uint64]string)
+		if !w.started {
+			// the recorded transitions are covered by the current status
+			w.started = true
+			for _, event := range journal.Events() {
+				w.sent[event.Seq] = getTaskEventState(event.To, event.Err)
+			}
+			return w.diff()
+		}
+	}
+
+	var events []*pb.TaskEvent
+	for _, event := range journal.Events() {
+		// the error of an event may be filled after it was sent
+		state := getTaskEventState(event.To, event.Err)
+		if w.sent[event.Seq] == state {
+			continue
+		}
+		w.sent[event.Seq] = state
+		events = append(events, &pb.TaskEvent{
+			ClusterId:  w.task.GetClusterID(),
+			TaskName:   w.task.GetName(),
+			TaskType:   string(w.task.GetType()),
+			Kind:       event.Kind,
+			Name:       event.Name,
+			Parent:     event.Parent,
+			ActionType: event.ActionType,
+			NodeName:   event.NodeName,
+			Status:     event.To,
+			Err:        event.Err,
+			Timestamp:  event.Timestamp.Unix(),
+		})
+	}
+	return events
+}
+
+// stop unsubscribes the journal of the task.
+func (w *taskWatcher) stop() {
+	if w.unsubscribe != nil {
+		w.unsubscribe()
+		w.unsubscribe = nil
+	}
+}
+
+// diff returns the events of the task, its sub tasks and actions whose status or error
+// changed since the last call.
+func (w *taskWatcher) diff() []*pb.TaskEvent {
+	root := w.task
+	var events []*pb.TaskEvent
+	now := time.Now().Unix()
+
+	newEvent := func(key, kind, name, parent string, status string, err *pb.Error) *pb.TaskEvent {
+		state := getTaskEventState(status, err)
+		if w.lastSeen[key] == state {
+			return nil
+		}
+		w.lastSeen[key] = state
+
+		event := &pb.TaskEvent{
+			ClusterId: root.GetClusterID(),
+			TaskName:  root.GetName(),
+			TaskType:  string(root.GetType()),
+			Kind:      kind,
+			Name:      name,
+			Parent:    parent,
+			Status:    status,
+			Err:       err,
+			Timestamp: now,
+		}
+		events = append(events, event)
+		return event
+	}
+
+	var visit func(tsk task.Task)
+	visit = func(tsk task.Task) {
+		key := fmt.Sprintf("%s/%s/%s", taskEventKindTask, tsk.GetParent(), tsk.GetName())
+		newEvent(key, taskEventKindTask, tsk.GetName(), tsk.GetParent(), string(tsk.GetStatus()), tsk.GetErr())
+
+		for _, act := range tsk.GetActions() {
+			// the actions of a task are identified by their types and nodes
+			key := fmt.Sprintf("%s/%s/%s/%s", taskEventKindAction, tsk.GetName(), act.GetType(), act.GetNode().GetName())
+			event := newEvent(key, taskEventKindAction, act.GetName(), tsk.GetName(), string(act.GetStatus()), act.GetErr())
+			if event != nil {
+				fillActionEvent(event, act)
+			}
+		}
+
+		for _, subTask := range tsk.GetSubTasks() {
+			visit(subTask)
+		}
+	}
+	visit(root)
+
+	return events
+}
+
+func getTaskEventState(status string, err *pb.Error) string {
+	return fmt.Sprintf("%s %v", status, err)
+}
+
+func fillActionEvent(event *pb.TaskEvent, act action.Action) {
+	event.ActionType = string(act.GetType())
+	if act.GetNode() != nil {
+		event.NodeName = act.GetNode().GetName()
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
+)
+
+type fakeWatchTaskServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*pb.TaskEvent
+}
+
+func (s *fakeWatchTaskServer) Send(event *pb.TaskEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *fakeWatchTaskServer) Context() context.Context {
+	return s.ctx
+}
+
+func newWatchedTask(clusterID string) *task.DeployTask {
+	subTask := &task.Base{
+		Name:     "deploy-etcd",
+		TaskType: task.TaskTypeDeployEtcd,
+		Status:   task.TaskDoing,
+		Parent:   getDeployTaskName(clusterID),
+		Actions: []action.Action{
+			&action.Base{
+				Name:       "etcd-action",
+				ActionType: action.ActionTypeDeployEtcd,
+				Status:     action.ActionDoing,
+				Node:       &pb.Node{Name: "node1"},
+			},
+		},
+	}
+	return &task.DeployTask{
+		Base: task.Base{
+			Name:      getDeployTaskName(clusterID),
+			TaskType:  task.TaskTypeDeploy,
+			Status:    task.TaskDoing,
+			ClusterID: clusterID,
+			SubTasks:  []task.Task{subTask},
+		},
+	}
+}
+
+func TestTaskWatcherDiff(t *testing.T) {
+	deployTask := newWatchedTask("cluster1")
+	watcher := newTaskWatcher(deployTask)
+
+	events := watcher.diff()
+	assert.Len(t, events, 3)
+	assert.Equal(t, taskEventKindTask, events[0].Kind)
+	assert.Equal(t, "cluster1-deploy", events[0].Name)
+	assert.Equal(t, taskEventKindTask, events[1].Kind)
+	assert.Equal(t, "deploy-etcd", events[1].Name)
+	assert.Equal(t, "cluster1-deploy", events[1].Parent)
+	assert.Equal(t, &pb.TaskEvent{
+		ClusterId:  "cluster1",
+		TaskName:   "cluster1-deploy",
+		TaskType:   string(task.TaskTypeDeploy),
+		Kind:       taskEventKindAction,
+		Name:       "etcd-action",
+		Parent:     "deploy-etcd",
+		ActionType: string(action.ActionTypeDeployEtcd),
+		NodeName:   "node1",
+		Status:     string(action.ActionDoing),
+		Timestamp:  events[2].Timestamp,
+	}, events[2])
+
+	// nothing changed
+	assert.Empty(t, watcher.diff())
+
+	act := deployTask.SubTasks[0].GetActions()[0]
+	act.SetStatus(action.ActionFailed)
+	events = watcher.diff()
+	assert.Len(t, events, 1)
+	assert.Equal(t, string(action.ActionFailed), events[0].Status)
+
+	// an error change is a transition too
+	act.SetErr(&pb.Error{Reason: "failed"})
+	events = watcher.diff()
+	assert.Len(t, events, 1)
+	assert.Equal(t, "failed", events[0].Err.Reason)
+
+	// the actions with the same name in different tasks are different
+	deployTask.SubTasks = append(deployTask.SubTasks, &task.Base{
+		Name:     "deploy-master",
+		TaskType: task.TaskTypeDeployMaster,
+		Status:   task.TaskDoing,
+		Parent:   "cluster1-deploy",
+		Actions: []action.Action{
+			&action.Base{
+				Name:       "etcd-action",
+				ActionType: action.ActionTypeDeployEtcd,
+				Status:     action.ActionFailed,
+				Err:        &pb.Error{Reason: "failed"},
+				Node:       &pb.Node{Name: "node1"},
+			},
+		},
+	})
+	events = watcher.diff()
+	assert.Len(t, events, 2)
+	assert.Equal(t, "deploy-master", events[1].Parent)
+}
+
+func TestTaskWatcherEvents(t *testing.T) {
+	deployTask := newWatchedTask("cluster1")
+	watcher := newTaskWatcher(deployTask)
+	defer watcher.stop()
+	notify := make(chan struct{}, 1)
+
+	// the current status is sent before the task is started
+	assert.Len(t, watcher.events(notify), 3)
+	assert.Empty(t, watcher.events(notify))
+
+	// all of the transitions are sent once the journal is created, however fast they happen
+	journal := task.NewJournal()
+	deployTask.SetJournal(journal)
+	etcdTask := deployTask.GetSubTasks()[0]
+	etcdTask.SetJournal(journal)
+	etcdTask.SetStatus(task.TaskFailed)
+	etcdTask.SetStatus(task.TaskDoing)
+	etcdTask.SetStatus(task.TaskSuccessful)
+	select {
+	case <-notify:
+		t.Fatal("notified before the journal is subscribed")
+	default:
+	}
+
+	events := watcher.events(notify)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, string(task.TaskFailed), events[0].Status)
+		assert.Equal(t, string(task.TaskDoing), events[1].Status)
+		assert.Equal(t, string(task.TaskSuccessful), events[2].Status)
+		assert.Equal(t, "deploy-etcd", events[2].Name)
+		assert.Equal(t, "cluster1-deploy", events[2].Parent)
+	}
+
+	// the transitions are notified by the journal
+	deployTask.SetStatus(task.TaskFailed)
+	<-notify
+	events = watcher.events(notify)
+	assert.Len(t, events, 1)
+	assert.Equal(t, string(task.TaskFailed), events[0].Status)
+
+	// the error filled after the status is sent again
+	deployTask.SetErr(&pb.Error{Reason: "failed"})
+	<-notify
+	events = watcher.events(notify)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "failed", events[0].Err.Reason)
+
+	// the recorded transitions are covered by the current status for a new watcher
+	watcher = newTaskWatcher(deployTask)
+	defer watcher.stop()
+	events = watcher.events(notify)
+	assert.Len(t, events, 3)
+	assert.Equal(t, string(task.TaskFailed), events[0].Status)
+	assert.Empty(t, watcher.events(notify))
+}
+
+func TestWatchTask(t *testing.T) {
+	store := task.NewCacheStore()
+	deployTask := newWatchedTask("cluster1")
+	deployTask.Status = task.TaskSuccessful
+	assert.NoError(t, store.AddTask(deployTask))
+	assert.NoError(t, store.AddTask(newWatchedTask("cluster2")))
+	c := &controller{store: store}
+
+	// the stream ends once the named task finished
+	stream := &fakeWatchTaskServer{ctx: context.Background()}
+	err := c.WatchTask(&pb.WatchTaskRequest{ClusterId: "cluster1", TaskName: "cluster1-deploy"}, stream)
+	assert.NoError(t, err)
+	assert.Len(t, stream.events, 3)
+	assert.Equal(t, string(task.TaskSuccessful), stream.events[0].Status)
+
+	// the stream of a cluster ends when the client cancels
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream = &fakeWatchTaskServer{ctx: ctx}
+	err = c.WatchTask(&pb.WatchTaskRequest{ClusterId: "cluster2"}, stream)
+	assert.NoError(t, err)
+	assert.Len(t, stream.events, 3)
+	for _, event := range stream.events {
+		assert.Equal(t, "cluster2", event.ClusterId)
+	}
+
+	err = c.WatchTask(&pb.WatchTaskRequest{ClusterId: "cluster1", TaskName: "not-exist"}, stream)
+	assert.Error(t, err)
+}
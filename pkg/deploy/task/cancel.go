@@ -0,0 +1,116 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+type runningTask struct {
+	cancel context.CancelFunc
+}
+
+// The tasks launched in background, keyed by task name.
+var _runningTasks = struct {
+	sync.Mutex
+	m map[string]*runningTask
+}{
+	m: make(map[string]*runningTask),
+}
+
+// newRunningContext reserves the task name for a task launched in background and returns a cancellable
+// context for it, the task can be cancelled by its name until the returned release function is called.
+// An error is returned if a task with the same name is still running.
+func newRunningContext(t Task) (context.Context, func(), error) {
+	_runningTasks.Lock()
+	defer _runningTasks.Unlock()
+	if _, ok := _runningTasks.m[t.GetName()]; ok {
+		return nil, nil, fmt.Errorf("%s: %s", consts.MsgTaskAlreadyRunning, t.GetName())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := &runningTask{cancel: cancel}
+	_runningTasks.m[t.GetName()] = running
+
+	release := func() {
+		_runningTasks.Lock()
+		defer _runningTasks.Unlock()
+		if _runningTasks.m[t.GetName()] == running {
+			delete(_runningTasks.m, t.GetName())
+		}
+		cancel()
+	}
+	return ctx, release, nil
+}
+
+// IsTaskRunning returns whether a task with the name was launched by StartTask or StartResumeTask
+// and is not finished yet.
+func IsTaskRunning(name string) bool {
+	_runningTasks.Lock()
+	defer _runningTasks.Unlock()
+	_, ok := _runningTasks.m[name]
+	return ok
+}
+
+// CancelTask cancels a running task which was launched by StartTask or StartResumeTask. The running
+// actions will be stopped and the remaining actions will be marked as aborted.
+func CancelTask(name string) error {
+	_runningTasks.Lock()
+	running, ok := _runningTasks.m[name]
+	_runningTasks.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s: %s", consts.MsgTaskNotRunning, name)
+	}
+
+	logrus.WithField(consts.LogFieldTask, name).Info("Cancel task")
+	running.cancel()
+	return nil
+}
+
+// abortTask marks the unfinished part of a cancelled task as aborted: the pending actions
+// are set to aborted and the unfinished tasks are set to failed. The started sub tasks were
+// aborted by their own execution, only the pending ones are handled here.
+func abortTask(t Task, cause error) {
+	for _, subTask := range t.GetSubTasks() {
+		if subTask.GetStatus() == TaskPending {
+			abortTask(subTask, cause)
+		}
+	}
+
+	for _, act := range t.GetActions() {
+		if act.GetStatus() == action.ActionPending {
+			action.AbortAction(act, cause)
+		}
+	}
+
+	if t.GetStatus() == TaskSuccessful {
+		return
+	}
+	t.SetStatus(TaskFailed)
+	t.SetErr(&pb.Error{
+		Reason:     consts.MsgTaskCancelled,
+		Detail:     cause.Error(),
+		FixMethods: "execute the task again if needed",
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// Mockup an action and excutor which blocks until it's cancelled
+const ActionTypeTestCancelMockup action.Type = "ActionTypeMockupForCancelTest"
+
+type actionMockupForCancelTest struct {
+	action.Base
+}
+type executorMockupForCancelTest struct {
+	started chan struct{}
+}
+
+func (e *executorMockupForCancelTest) Execute(ctx context.Context, act action.Action) *pb.Error {
+	e.started <- struct{}{}
+	<-ctx.Done()
+	return &pb.Error{Reason: ctx.Err().Error()}
+}
+
+// Mockup a parent task type and a leaf task type
+const TaskTypeTestCancelMockupParent Type = "TaskTypeMockupForCancelTestParent"
+const TaskTypeTestCancelMockupLeaf Type = "TaskTypeMockupForCancelTestLeaf"
+
+type taskMockupForCancelTest struct {
+	Base
+}
+
+type processorMockupForCancelTestParent struct{}
+type processorMockupForCancelTestLeaf struct{}
+
+func (p *processorMockupForCancelTestParent) SplitTask(t Task) error {
+	tsk, ok := t.(*taskMockupForCancelTest)
+	if !ok {
+		return fmt.Errorf("mismatched task type")
+	}
+
+	// Split it into two leaf tasks with different priorities
+	for i := 1; i <= 2; i++ {
+		tsk.SubTasks = append(tsk.SubTasks, &taskMockupForCancelTest{
+			Base: Base{
+				Name:     fmt.Sprintf("leaf%d", i),
+				TaskType: TaskTypeTestCancelMockupLeaf,
+				Status:   TaskPending,
+				Priority: i,
+				Parent:   t.GetName(),
+			},
+		})
+	}
+	return nil
+}
+
+func (p *processorMockupForCancelTestLeaf) SplitTask(t Task) error {
+	tsk, ok := t.(*taskMockupForCancelTest)
+	if !ok {
+		return fmt.Errorf("mismatched task type")
+	}
+
+	tsk.Actions = []action.Action{
+		&actionMockupForCancelTest{
+			Base: action.Base{
+				Name:       fmt.Sprintf("%s-action", t.GetName()),
+				ActionType: ActionTypeTestCancelMockup,
+				Status:     action.ActionPending,
+			},
+		},
+	}
+	return nil
+}
+
+func TestCancelTask(t *testing.T) {
+	executor := &executorMockupForCancelTest{started: make(chan struct{}, 1)}
+	err := action.RegisterExecutor(ActionTypeTestCancelMockup, func() action.Executor { return executor })
+	assert.NoError(t, err)
+
+	err = RegisterProcessor(TaskTypeTestCancelMockupParent, new(processorMockupForCancelTestParent))
+	assert.NoError(t, err)
+	err = RegisterProcessor(TaskTypeTestCancelMockupLeaf, new(processorMockupForCancelTestLeaf))
+	assert.NoError(t, err)
+
+	parent := &taskMockupForCancelTest{
+		Base: Base{
+			Name:     "cancel-parent",
+			TaskType: TaskTypeTestCancelMockupParent,
+			Status:   TaskPending,
+		},
+	}
+
+	assert.Error(t, CancelTask(parent.GetName()))
+
+	err = StartTask(parent)
+	assert.NoError(t, err)
+	// the task can't be launched again while it's running
+	assert.Error(t, StartTask(parent))
+
+	// wait for the action of the first leaf task to start
+	select {
+	case <-executor.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the action is not started")
+	}
+
+	assert.NoError(t, CancelTask(parent.GetName()))
+
+	// wait for the task to finish
+	for i := 0; i < 500 && IsTaskRunning(parent.GetName()); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, TaskFailed, parent.GetStatus())
+	assert.NotNil(t, parent.GetErr())
+
+	subTasks := parent.GetSubTasks()
+	assert.Len(t, subTasks, 2)
+	// the running action is aborted
+	assert.Equal(t, TaskFailed, subTasks[0].GetStatus())
+	assert.Equal(t, action.ActionAborted, subTasks[0].GetActions()[0].GetStatus())
+	// the pending task is never started
+	assert.Equal(t, TaskFailed, subTasks[1].GetStatus())
+	assert.Len(t, subTasks[1].GetActions(), 0)
+
+	// cleanup
+	_processRegistry = nil
+}
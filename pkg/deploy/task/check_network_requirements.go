@@ -91,7 +91,7 @@ func (p *checkNetworkRequirementsProcessor) SplitTask(task Task) error {
 			}
 		}
 		actions, err = p.splitActionsCalico(checkNetworkRequirementsTask)
-		checkNetworkRequirementsTask.Actions = actions
+		checkNetworkRequirementsTask.SetActions(actions)
 		if err != nil {
 			return fmt.Errorf("failed to split actions, error %v", err)
 		}
@@ -0,0 +1,203 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+)
+
+const (
+	DefaultMaxConcurrentActions        = 50
+	DefaultWorkerBatchSize             = 20
+	DefaultWorkerBatchFailureThreshold = 5
+)
+
+// ConcurrencyOptions limits the actions executed at the same time, so that a large cluster doesn't
+// open too many ssh sessions and package downloads from the controller and the mirrors.
+type ConcurrencyOptions struct {
+	// MaxConcurrentActions is the max number of the actions executed at the same time by all the tasks.
+	MaxConcurrentActions int
+	// MaxConcurrentActionsPerTaskType is the max number of the actions executed at the same time by the
+	// tasks of a type, there is no limit other than MaxConcurrentActions for the types not in it.
+	MaxConcurrentActionsPerTaskType map[Type]int
+	// WorkerBatchSize is the number of the workers joined in a rolling batch, the next batch is started
+	// after the current one finishes.
+	WorkerBatchSize int
+	// WorkerBatchFailureThreshold is the number of the failed workers which halts the following batches,
+	// the workers in the halted batches are aborted.
+	WorkerBatchFailureThreshold int
+}
+
+// actionLimiter limits the concurrency of the actions by the options.
+type actionLimiter struct {
+	options ConcurrencyOptions
+	global  chan struct{}
+	perType map[Type]chan struct{}
+}
+
+var (
+	limiterLock   sync.RWMutex
+	globalLimiter = newActionLimiter(ConcurrencyOptions{})
+)
+
+// SetConcurrencyOptions sets the concurrency options of the action execution, the zero fields are set to the
+// default values. The actions being executed are not counted by the new limits.
+func SetConcurrencyOptions(options ConcurrencyOptions) {
+	limiter := newActionLimiter(options)
+
+	limiterLock.Lock()
+	defer limiterLock.Unlock()
+
+	globalLimiter = limiter
+}
+
+// GetConcurrencyOptions returns the concurrency options of the action execution.
+func GetConcurrencyOptions() ConcurrencyOptions {
+	return getActionLimiter().options
+}
+
+func getActionLimiter() *actionLimiter {
+	limiterLock.RLock()
+	defer limiterLock.RUnlock()
+
+	return globalLimiter
+}
+
+func newActionLimiter(options ConcurrencyOptions) *actionLimiter {
+	if options.MaxConcurrentActions <= 0 {
+		options.MaxConcurrentActions = DefaultMaxConcurrentActions
+	}
+	if options.WorkerBatchSize <= 0 {
+		options.WorkerBatchSize = DefaultWorkerBatchSize
+	}
+	if options.WorkerBatchFailureThreshold <= 0 {
+		options.WorkerBatchFailureThreshold = DefaultWorkerBatchFailureThreshold
+	}
+
+	limiter := &actionLimiter{
+		options: options,
+		global:  make(chan struct{}, options.MaxConcurrentActions),
+		perType: make(map[Type]chan struct{}),
+	}
+	for taskType, max := range options.MaxConcurrentActionsPerTaskType {
+		if max > 0 {
+			limiter.perType[taskType] = make(chan struct{}, max)
+		}
+	}
+	return limiter
+}
+
+// acquire waits for the slots of the task type and the global one, it returns the function to release the
+// slots, or the error of the context if the context is done before.
+func (l *actionLimiter) acquire(ctx context.Context, taskType Type) (release func(), err error) {
+	// acquire the slot of the type first, so that the global slot is not held while waiting for the type
+	var slots []chan struct{}
+	if typeSlots, ok := l.perType[taskType]; ok {
+		slots = append(slots, typeSlots)
+	}
+	slots = append(slots, l.global)
+
+	release = func() {
+		for i := len(slots) - 1; i >= 0; i-- {
+			<-slots[i]
+		}
+	}
+
+	for i, sem := range slots {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			slots = slots[:i]
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// splitActionBatches splits the actions into the rolling batches executed in turn, only the worker joins are
+// batched, the actions of the other tasks are in one batch.
+func splitActionBatches(t Task, actions []action.Action, options ConcurrencyOptions) [][]action.Action {
+	if t.GetType() != TaskTypeDeployWorker || len(actions) <= options.WorkerBatchSize {
+		return [][]action.Action{actions}
+	}
+
+	var batches [][]action.Action
+	for start := 0; start < len(actions); start += options.WorkerBatchSize {
+		end := start + options.WorkerBatchSize
+		if end > len(actions) {
+			end = len(actions)
+		}
+		batches = append(batches, actions[start:end])
+	}
+	return batches
+}
+
+// runActions executes the actions of the task in the rolling batches parallelly, limited by the concurrency
+// options. The following batches are aborted once the failed actions reach the failure threshold.
+func runActions(ctx context.Context, t Task, actions []action.Action) {
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldTask: t.GetName(),
+	})
+
+	limiter := getActionLimiter()
+	batches := splitActionBatches(t, actions, limiter.options)
+
+	failed := 0
+	for i, batch := range batches {
+		if len(batches) > 1 {
+			logger.Debugf("Start to execute batch %d/%d: %d actions", i+1, len(batches), len(batch))
+		}
+
+		var wg sync.WaitGroup
+		for _, act := range batch {
+			wg.Add(1)
+			go func(act action.Action) {
+				release, err := limiter.acquire(ctx, t.GetType())
+				if err == nil {
+					defer release()
+				}
+				// the action is aborted by ExecuteAction if the context is done
+				action.ExecuteAction(ctx, act, &wg)
+			}(act)
+		}
+		wg.Wait()
+
+		for _, act := range batch {
+			if status := act.GetStatus(); status == action.ActionFailed || status == action.ActionAborted {
+				failed++
+			}
+		}
+
+		if i < len(batches)-1 && failed >= limiter.options.WorkerBatchFailureThreshold {
+			cause := fmt.Errorf("%d action(s) failed in the previous batches, reaching the failure threshold %d",
+				failed, limiter.options.WorkerBatchFailureThreshold)
+			logger.Warnf("Halt the following batches: %v", cause)
+			for _, halted := range batches[i+1:] {
+				for _, act := range halted {
+					action.AbortAction(act, cause)
+				}
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// Mockup an action and executor which records the max number of the concurrent executions
+const ActionTypeTestConcurrencyMockup action.Type = "ActionTypeMockupForConcurrencyTest"
+
+type actionMockupForConcurrencyTest struct {
+	action.Base
+	fail bool
+}
+
+type executorMockupForConcurrencyTest struct {
+	lock          sync.Mutex
+	running       int
+	maxRunning    int
+	executedNames []string
+}
+
+func (e *executorMockupForConcurrencyTest) Execute(ctx context.Context, act action.Action) *pb.Error {
+	e.lock.Lock()
+	e.running++
+	if e.running > e.maxRunning {
+		e.maxRunning = e.running
+	}
+	e.executedNames = append(e.executedNames, act.GetName())
+	e.lock.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	e.lock.Lock()
+	e.running--
+	e.lock.Unlock()
+
+	if act.(*actionMockupForConcurrencyTest).fail {
+		return &pb.Error{Reason: "failed to join the cluster"}
+	}
+	return nil
+}
+
+var concurrencyMockupExecutor = new(executorMockupForConcurrencyTest)
+
+func init() {
+	action.RegisterExecutor(ActionTypeTestConcurrencyMockup, func() action.Executor { return concurrencyMockupExecutor })
+}
+
+func newConcurrencyMockupActions(count int, failed ...int) []action.Action {
+	actions := make([]action.Action, 0, count)
+	for i := 0; i < count; i++ {
+		act := &actionMockupForConcurrencyTest{
+			Base: action.Base{
+				Name:       fmt.Sprintf("action%d", i),
+				ActionType: ActionTypeTestConcurrencyMockup,
+				Status:     action.ActionPending,
+			},
+		}
+		for _, index := range failed {
+			if index == i {
+				act.fail = true
+			}
+		}
+		actions = append(actions, act)
+	}
+	return actions
+}
+
+func resetConcurrencyMockupExecutor() *executorMockupForConcurrencyTest {
+	concurrencyMockupExecutor.lock.Lock()
+	defer concurrencyMockupExecutor.lock.Unlock()
+
+	concurrencyMockupExecutor.maxRunning = 0
+	concurrencyMockupExecutor.executedNames = nil
+	return concurrencyMockupExecutor
+}
+
+func TestRunActionsWithConcurrencyLimits(t *testing.T) {
+	defer SetConcurrencyOptions(ConcurrencyOptions{})
+
+	// the global limit
+	SetConcurrencyOptions(ConcurrencyOptions{MaxConcurrentActions: 3})
+	executor := resetConcurrencyMockupExecutor()
+	tsk := &taskMockupForCancelTest{Base: Base{Name: "node-check", TaskType: TaskTypeNodeCheck}}
+	actions := newConcurrencyMockupActions(10)
+	runActions(context.Background(), tsk, actions)
+	assert.Equal(t, 3, executor.maxRunning)
+	assert.Len(t, executor.executedNames, 10)
+	for _, act := range actions {
+		assert.Equal(t, action.ActionDone, act.GetStatus())
+	}
+
+	// the limit of the task type is lower than the global one
+	SetConcurrencyOptions(ConcurrencyOptions{
+		MaxConcurrentActions:            5,
+		MaxConcurrentActionsPerTaskType: map[Type]int{TaskTypeNodeInit: 2},
+	})
+	executor = resetConcurrencyMockupExecutor()
+	tsk = &taskMockupForCancelTest{Base: Base{Name: "node-init", TaskType: TaskTypeNodeInit}}
+	runActions(context.Background(), tsk, newConcurrencyMockupActions(10))
+	assert.Equal(t, 2, executor.maxRunning)
+	assert.Len(t, executor.executedNames, 10)
+}
+
+func TestRunActionsInWorkerBatches(t *testing.T) {
+	defer SetConcurrencyOptions(ConcurrencyOptions{})
+
+	SetConcurrencyOptions(ConcurrencyOptions{WorkerBatchSize: 4, WorkerBatchFailureThreshold: 2})
+	options := GetConcurrencyOptions()
+	assert.Equal(t, DefaultMaxConcurrentActions, options.MaxConcurrentActions)
+
+	// the failures don't reach the threshold
+	executor := resetConcurrencyMockupExecutor()
+	tsk := &taskMockupForCancelTest{Base: Base{Name: "deploy-worker", TaskType: TaskTypeDeployWorker}}
+	actions := newConcurrencyMockupActions(10, 1)
+	runActions(context.Background(), tsk, actions)
+	assert.Equal(t, 4, executor.maxRunning)
+	assert.Len(t, executor.executedNames, 10)
+
+	// the batches after the one reaching the threshold are halted
+	executor = resetConcurrencyMockupExecutor()
+	actions = newConcurrencyMockupActions(10, 1, 5)
+	runActions(context.Background(), tsk, actions)
+	assert.Len(t, executor.executedNames, 8)
+	for _, act := range actions[:8] {
+		assert.NotEqual(t, action.ActionAborted, act.GetStatus())
+	}
+	for _, act := range actions[8:] {
+		assert.Equal(t, action.ActionAborted, act.GetStatus())
+		assert.Equal(t, consts.MsgActionAborted, act.GetErr().Reason)
+		assert.Contains(t, act.GetErr().Detail, "reaching the failure threshold 2")
+	}
+
+	// the other tasks are not batched
+	batches := splitActionBatches(&taskMockupForCancelTest{Base: Base{TaskType: TaskTypeNodeInit}},
+		newConcurrencyMockupActions(10), GetConcurrencyOptions())
+	assert.Len(t, batches, 1)
+}
+
+func TestRunActionsCancelledWhileWaiting(t *testing.T) {
+	defer SetConcurrencyOptions(ConcurrencyOptions{})
+
+	SetConcurrencyOptions(ConcurrencyOptions{MaxConcurrentActions: 1})
+	limiter := getActionLimiter()
+	release, err := limiter.acquire(context.Background(), TaskTypeNodeCheck)
+	assert.NoError(t, err)
+
+	// the actions wait for the slot held above until the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	executor := resetConcurrencyMockupExecutor()
+	tsk := &taskMockupForCancelTest{Base: Base{Name: "node-check", TaskType: TaskTypeNodeCheck}}
+	actions := newConcurrencyMockupActions(3)
+	runActions(ctx, tsk, actions)
+	release()
+
+	assert.Empty(t, executor.executedNames)
+	for _, act := range actions {
+		assert.Equal(t, action.ActionAborted, act.GetStatus())
+	}
+
+	// the slot is released
+	release, err = limiter.acquire(context.Background(), TaskTypeNodeCheck)
+	assert.NoError(t, err)
+	release()
+}
@@ -15,6 +15,8 @@
 package task
 
 import (
+	"crypto"
+	"crypto/x509"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -47,9 +49,8 @@ func (p *deployEtcdProcessor) SplitTask(t Task) error {
 
 	etcdTask := t.(*DeployEtcdTask)
 
-	// generate etcd ca cert and key and put it into every action
-	caCertConfig := etcd.GetCaCrtConfig()
-	caCrt, cakey, err := etcd.CreateAsCA(caCertConfig)
+	// get etcd ca cert and key and put it into every action
+	caCrt, cakey, err := p.getCA(etcdTask)
 	if err != nil {
 		return fmt.Errorf("failed to get etcd-ca key and cert, error: %v", err)
 	}
@@ -71,13 +72,33 @@ func (p *deployEtcdProcessor) SplitTask(t Task) error {
 		}
 		actions = append(actions, act)
 	}
-	etcdTask.Actions = actions
+	etcdTask.SetActions(actions)
 
 	logger.Debugf("Finish to split deploy etcd task: %d actions", len(actions))
 
 	return nil
 }
 
+// getCA returns the etcd ca cert and key. If the task is split again to be resumed, the ca of the
+// previous actions must be reused, otherwise a new ca is generated.
+func (p *deployEtcdProcessor) getCA(etcdTask *DeployEtcdTask) (*x509.Certificate, crypto.Signer, error) {
+	for _, act := range etcdTask.Actions {
+		etcdAction, ok := act.(*action.DeployEtcdAction)
+		if ok && etcdAction.CACrt != nil && etcdAction.CAKey != nil {
+			return etcdAction.CACrt, etcdAction.CAKey, nil
+		}
+	}
+
+	// The ca is not saved in the actions reloaded from an older task store, fetch it from a deployed etcd node.
+	for _, act := range etcdTask.Actions {
+		if act.GetStatus() == action.ActionDone {
+			return etcd.FetchEtcdCertAndKey(act.GetNode(), "ca")
+		}
+	}
+
+	return etcd.CreateAsCA(etcd.GetCaCrtConfig())
+}
+
 // Verify if the task is valid.
 func (p *deployEtcdProcessor) verifyTask(t Task) error {
 	if t == nil {
@@ -29,7 +29,7 @@ const TaskTypeDeployEtcd Type = "DeployEtcd"
 type DeployEtcdTaskConfig struct {
 	Nodes           []*pb.Node
 	LogFileBasePath string
-	Priority        int
+	Dependencies    []string
 	Parent          string
 }
 
@@ -62,7 +62,7 @@ func NewDeployEtcdTask(taskName string, taskConfig *DeployEtcdTaskConfig) (Task,
 			Status:            TaskPending,
 			LogFileDir:        GenTaskLogFileDir(taskConfig.LogFileBasePath, taskName),
 			CreationTimestamp: time.Now(),
-			Priority:          taskConfig.Priority,
+			Dependencies:      taskConfig.Dependencies,
 			Parent:            taskConfig.Parent,
 		},
 		Nodes: taskConfig.Nodes,
@@ -57,7 +57,7 @@ func (p *deployMasterProcessor) SplitTask(t Task) error {
 		subTasks = append(subTasks, task)
 	}
 
-	deployMasterTask.SubTasks = subTasks
+	deployMasterTask.SetSubTasks(subTasks)
 	logger.Debugf("Finish to split deploy master task: %d sub tasks", len(subTasks))
 
 	return nil
@@ -105,12 +105,10 @@ func (p *deployMasterProcessor) createDeployMasterSubTask(index int, parent *dep
 			MasterNodes:     parent.Nodes,
 			clusterConfig:   parent.ClusterConfig,
 			logFileBasePath: parent.GetLogFileDir(),
-			Priority:        int(InitMasterPriority),
 			parent:          parent.GetName(),
 		}
 
-		taskName := "initMaster"
-		return NewInitMasterTask(taskName, config)
+		return NewInitMasterTask(initMasterTaskName, config)
 	default:
 		config := &JoinMasterTaskConfig{
 			certKey:         parent.CertKey,
@@ -119,7 +117,7 @@ func (p *deployMasterProcessor) createDeployMasterSubTask(index int, parent *dep
 			masterNodes:     parent.Nodes,
 			clusterConfig:   parent.ClusterConfig,
 			logFileBasePath: parent.GetLogFileDir(),
-			priority:        int(JoinMasterPriority),
+			dependencies:    []string{initMasterTaskName},
 			parent:          parent.GetName(),
 		}
 		// Use the role name as the task name for now.
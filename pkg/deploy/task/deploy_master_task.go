@@ -33,7 +33,7 @@ type DeployMasterTaskConfig struct {
 	NodeConfigs     []*pb.NodeDeployConfig
 	ClusterConfig   *pb.ClusterConfig
 	LogFileBasePath string
-	Priority        int
+	Dependencies    []string
 	Parent          string
 }
 
@@ -72,7 +72,7 @@ func NewDeployMasterTask(taskName string, taskConfig *DeployMasterTaskConfig) (T
 			Status:            TaskPending,
 			LogFileDir:        GenTaskLogFileDir(taskConfig.LogFileBasePath, taskName),
 			CreationTimestamp: time.Now(),
-			Priority:          taskConfig.Priority,
+			Dependencies:      taskConfig.Dependencies,
 			Parent:            taskConfig.Parent,
 		},
 		CertKey:       taskConfig.CertKey,
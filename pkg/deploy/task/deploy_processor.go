@@ -47,22 +47,37 @@ func (p *deployProcessor) SplitTask(t Task) error {
 
 	logger.Debug("Start to split deploy task")
 
-	// split task into subtask: init, deploy etcd, deploy master, deploy worker, deploy ingress
+	// split task into subtask: init, prepare worker, deploy etcd, deploy master, deploy worker, deploy ingress
 	var subTasks []Task
 
 	// first collect all roles and their related nodes
 	roles := p.groupByRole(deployTask.NodeConfigs)
 
-	// create the init sub tasks with priority = 10
-	initTask, err := p.createInitSubTask(deployTask, roles)
-	if err != nil {
-		err = fmt.Errorf("failed to create common init sub tasks: %s", err)
-		logger.Error(err)
-		return err
+	// create the init sub tasks, one for each node, so that the sub tasks of a node only wait for
+	// the initialization of the nodes they deploy
+	for _, nodeCfg := range deployTask.NodeConfigs {
+		initTask, err := p.createInitSubTask(deployTask, nodeCfg)
+		if err != nil {
+			err = fmt.Errorf("failed to create common init sub tasks: %s", err)
+			logger.Error(err)
+			return err
+		}
+		subTasks = append(subTasks, initTask)
+	}
+
+	// create the prepare worker sub tasks, one for each worker node, which only depend on the
+	// initialization of the node, so that the workers are prepared while the masters are deployed
+	for _, nodeCfg := range roles[constant.MachineRoleWorker] {
+		prepareTask, err := p.createPrepareWorkerSubTask(deployTask, nodeCfg)
+		if err != nil {
+			err = fmt.Errorf("failed to create prepare worker sub tasks: %s", err)
+			logger.Error(err)
+			return err
+		}
+		subTasks = append(subTasks, prepareTask)
 	}
-	subTasks = append(subTasks, initTask)
 
-	// create the deploy etcd sub tasks with priority = 20
+	// create the deploy etcd sub tasks
 	if _, ok := roles[constant.MachineRoleEtcd]; ok {
 		etcdTask, err := p.createDeploySubTask(constant.MachineRoleEtcd, deployTask, roles)
 		if err != nil {
@@ -73,7 +88,7 @@ func (p *deployProcessor) SplitTask(t Task) error {
 		subTasks = append(subTasks, etcdTask)
 	}
 
-	// create the deploy master sub tasks with priority = 30
+	// create the deploy master sub tasks
 	if _, ok := roles[constant.MachineRoleMaster]; ok {
 		masterTask, err := p.createDeploySubTask(constant.MachineRoleMaster, deployTask, roles)
 		if err != nil {
@@ -84,7 +99,7 @@ func (p *deployProcessor) SplitTask(t Task) error {
 		subTasks = append(subTasks, masterTask)
 	}
 
-	// create the deploy worker sub tasks with priority = 40
+	// create the deploy worker sub tasks
 	if _, ok := roles[constant.MachineRoleWorker]; ok {
 		workerTask, err := p.createDeploySubTask(constant.MachineRoleWorker, deployTask, roles)
 		if err != nil {
@@ -95,7 +110,7 @@ func (p *deployProcessor) SplitTask(t Task) error {
 		subTasks = append(subTasks, workerTask)
 	}
 
-	// create the deploy ingress sub tasks with priority = 50
+	// create the deploy ingress sub tasks
 	if _, ok := roles[constant.MachineRoleIngress]; ok {
 		ingressTask, err := p.createDeploySubTask(constant.MachineRoleIngress, deployTask, roles)
 		if err != nil {
@@ -106,7 +121,7 @@ func (p *deployProcessor) SplitTask(t Task) error {
 		subTasks = append(subTasks, ingressTask)
 	}
 
-	deployTask.SubTasks = subTasks
+	deployTask.SetSubTasks(subTasks)
 	logger.Debugf("Finish to split deploy task: %d sub tasks", len(subTasks))
 
 	return nil
@@ -142,16 +157,29 @@ func (p *deployProcessor) groupByRole(cfgs []*pb.NodeDeployConfig) map[constant.
 	return roles
 }
 
-func (p *deployProcessor) createInitSubTask(parent *DeployTask, rn map[constant.MachineRole][]*pb.NodeDeployConfig) (task Task, err error) {
+func (p *deployProcessor) createInitSubTask(parent *DeployTask, nodeCfg *pb.NodeDeployConfig) (task Task, err error) {
 
 	config := &NodeInitTaskConfig{
-		NodeConfigs:     parent.NodeConfigs,
+		NodeConfigs:        []*pb.NodeDeployConfig{nodeCfg},
+		ClusterNodeConfigs: parent.NodeConfigs,
+		LogFileBasePath:    parent.GetLogFileDir(),
+		Parent:             parent.GetName(),
+		ClusterConfig:      parent.ClusterConfig,
+	}
+	task, err = NewNodeInitTask(fmt.Sprintf(initSubTaskNameTmpl, nodeCfg.GetNode().GetName()), config)
+	return
+}
+
+func (p *deployProcessor) createPrepareWorkerSubTask(parent *DeployTask, nodeCfg *pb.NodeDeployConfig) (task Task, err error) {
+
+	nodeName := nodeCfg.GetNode().GetName()
+	config := &PrepareWorkerTaskConfig{
+		Nodes:           []*pb.NodeDeployConfig{nodeCfg},
 		LogFileBasePath: parent.GetLogFileDir(),
-		Priority:        int(initPriority),
+		Dependencies:    []string{fmt.Sprintf(initSubTaskNameTmpl, nodeName)},
 		Parent:          parent.GetName(),
-		ClusterConfig:   parent.ClusterConfig,
 	}
-	task, err = NewNodeInitTask(fmt.Sprintf("init"), config)
+	task, err = NewPrepareWorkerTask(fmt.Sprintf(prepareWorkerSubTaskNameTmpl, nodeName), config)
 	return
 }
 
@@ -162,11 +190,11 @@ func (p *deployProcessor) createDeploySubTask(role constant.MachineRole, parent
 		config := &DeployEtcdTaskConfig{
 			Nodes:           p.unwrapNodes(rn[role]),
 			LogFileBasePath: parent.GetLogFileDir(),
-			Priority:        int(Priorities[role]),
+			Dependencies:    p.dependencies(role, rn),
 			Parent:          parent.GetName(),
 		}
 		// Use the role name as the task name for now.
-		task, err = NewDeployEtcdTask(fmt.Sprintf("deploy-%s", role), config)
+		task, err = NewDeployEtcdTask(fmt.Sprintf(deploySubTaskNameTmpl, role), config)
 
 	case constant.MachineRoleMaster:
 		certificateKey, err := copycerts.CreateCertificateKey()
@@ -181,11 +209,11 @@ func (p *deployProcessor) createDeploySubTask(role constant.MachineRole, parent
 			Nodes:           p.unwrapNodes(rn[role]),
 			ClusterConfig:   parent.ClusterConfig,
 			LogFileBasePath: parent.GetLogFileDir(),
-			Priority:        int(Priorities[role]),
+			Dependencies:    p.dependencies(role, rn),
 			Parent:          parent.GetName(),
 		}
 		// Use the role name as the task name for now.
-		task, err = NewDeployMasterTask(fmt.Sprintf("deploy-%s", role), config)
+		task, err = NewDeployMasterTask(fmt.Sprintf(deploySubTaskNameTmpl, role), config)
 
 	case constant.MachineRoleWorker:
 
@@ -193,13 +221,13 @@ func (p *deployProcessor) createDeploySubTask(role constant.MachineRole, parent
 			Nodes:           rn[constant.MachineRoleWorker],
 			ClusterConfig:   parent.ClusterConfig,
 			LogFileBasePath: parent.GetLogFileDir(), // /app/deploy/logs/unknown
-			Priority:        int(Priorities[role]),
+			Dependencies:    p.dependencies(role, rn),
 			Parent:          parent.GetName(),
 			MasterNodes:     p.unwrapNodes(rn[constant.MachineRoleMaster]),
 		}
 
 		// Use the role name as the task name for now.
-		return NewDeployWorkerTask(fmt.Sprintf("deploy-%s", role), config)
+		return NewDeployWorkerTask(fmt.Sprintf(deploySubTaskNameTmpl, role), config)
 	default:
 		err = fmt.Errorf("unrecognized role:%v", role)
 	}
@@ -207,6 +235,28 @@ func (p *deployProcessor) createDeploySubTask(role constant.MachineRole, parent
 	return
 }
 
+// dependencies returns the names of the sub tasks which the deployment of the role depends on: the
+// initialization of the nodes of the role, or the preparation of the worker nodes, and the deployment
+// of the roles it depends on.
+func (p *deployProcessor) dependencies(role constant.MachineRole, rn map[constant.MachineRole][]*pb.NodeDeployConfig) []string {
+	nodeTaskNameTmpl := initSubTaskNameTmpl
+	if role == constant.MachineRoleWorker {
+		nodeTaskNameTmpl = prepareWorkerSubTaskNameTmpl
+	}
+
+	var dependencies []string
+	for _, nodeCfg := range rn[role] {
+		dependencies = append(dependencies, fmt.Sprintf(nodeTaskNameTmpl, nodeCfg.GetNode().GetName()))
+	}
+	for _, dependency := range RoleDependencies[role] {
+		// skip the roles without any node
+		if _, ok := rn[dependency]; ok {
+			dependencies = append(dependencies, fmt.Sprintf(deploySubTaskNameTmpl, dependency))
+		}
+	}
+	return dependencies
+}
+
 func (p deployProcessor) unwrapNode(config *pb.NodeDeployConfig) *pb.Node {
 	return config.GetNode()
 }
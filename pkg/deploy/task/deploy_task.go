@@ -26,27 +26,30 @@ import (
 )
 
 type Operation string
-type Priority int
 
 const TaskTypeDeploy Type = "Deploy"
 
 const (
 	initOperation   Operation = "initialization"
 	deployOperation Operation = "deployment"
+)
 
-	initPriority          Priority = 10
-	DeployEtcdPriority    Priority = 20
-	DeployMasterPriority  Priority = 30
-	DeployWorkerPriority  Priority = 40
-	DeployIngressPriority Priority = 50
+const (
+	initSubTaskNameTmpl          = "init-%s"
+	prepareWorkerSubTaskNameTmpl = "prepare-worker-%s"
+	initMasterTaskName           = "initMaster"
+	deploySubTaskNameTmpl        = "deploy-%s"
 )
 
 var (
-	Priorities = map[constant.MachineRole]Priority{
-		constant.MachineRoleEtcd:    DeployEtcdPriority,
-		constant.MachineRoleMaster:  DeployMasterPriority,
-		constant.MachineRoleWorker:  DeployWorkerPriority,
-		constant.MachineRoleIngress: DeployIngressPriority,
+	// RoleDependencies are the roles whose deployment the deployment of a role depends on,
+	// the deployment of a role also depends on the initialization of its own nodes, and the
+	// deployment of the workers depends on the preparation of the worker nodes instead.
+	RoleDependencies = map[constant.MachineRole][]constant.MachineRole{
+		constant.MachineRoleEtcd:    nil,
+		constant.MachineRoleMaster:  {constant.MachineRoleEtcd},
+		constant.MachineRoleWorker:  {constant.MachineRoleMaster},
+		constant.MachineRoleIngress: {constant.MachineRoleMaster},
 	}
 )
 
@@ -62,7 +62,7 @@ func (processor *DeployWorkerProcessor) SplitTask(task Task) error {
 		}
 		actions = append(actions, act)
 	}
-	deployTask.Actions = actions
+	deployTask.SetActions(actions)
 
 	logger.Debugf("Finish to split deploy worker task: %d actions", len(actions))
 
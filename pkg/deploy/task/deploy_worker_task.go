@@ -28,7 +28,7 @@ type DeployWorkerTaskConfig struct {
 	Nodes           []*protos.NodeDeployConfig
 	ClusterConfig   *protos.ClusterConfig
 	LogFileBasePath string
-	Priority        int
+	Dependencies    []string
 	Parent          string
 }
 
@@ -60,7 +60,7 @@ func NewDeployWorkerTask(taskName string, taskConfig *DeployWorkerTaskConfig) (T
 			Status:              TaskPending,
 			LogFileDir:          GenTaskLogFileDir(taskConfig.LogFileBasePath, taskName), // /app/deploy/logs/unknown/deploy-worker
 			CreationTimestamp:   time.Now(),
-			Priority:            taskConfig.Priority,
+			Dependencies:        taskConfig.Dependencies,
 			Parent:              taskConfig.Parent,
 			FailureCanBeIgnored: true,
 		},
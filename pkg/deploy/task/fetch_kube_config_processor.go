@@ -54,7 +54,7 @@ func (p *fetchKubeConfigProcessor) SplitTask(t Task) error {
 	if err != nil {
 		return err
 	}
-	kubeCfgTask.Actions = []action.Action{act}
+	kubeCfgTask.SetActions([]action.Action{act})
 
 	logrus.Debugf("Finish to split task")
 	return nil
@@ -81,7 +81,9 @@ func (p *fetchKubeConfigProcessor) ProcessExtraResult(t Task) error {
 		return fmt.Errorf("%s: %T", consts.MsgActionTypeMismatched, kubeCfgTask.Actions[0])
 	}
 
-	kubeCfgTask.KubeConfig = kubeCfgAction.KubeConfig
+	kubeCfgTask.Update(func() {
+		kubeCfgTask.KubeConfig = kubeCfgAction.KubeConfig
+	})
 	logger.Debugf("KubeConfig: %v", kubeCfgTask.KubeConfig)
 	return nil
 }
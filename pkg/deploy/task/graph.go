@@ -0,0 +1,228 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+)
+
+// taskGraph is the dependency graph of the sibling sub tasks, a task starts after all the tasks
+// it depends on are successful.
+type taskGraph struct {
+	tasks        []Task
+	dependencies map[string][]Task
+}
+
+// newTaskGraph builds the dependency graph of the sibling tasks. If none of the tasks declares its
+// dependencies, a task depends on the tasks of the nearest higher priority instead.
+// An error is returned if a dependency is unknown or there is a cycle.
+func newTaskGraph(tasks []Task) (*taskGraph, error) {
+	graph := &taskGraph{
+		tasks:        tasks,
+		dependencies: make(map[string][]Task, len(tasks)),
+	}
+
+	byName := make(map[string]Task, len(tasks))
+	explicit := false
+	for _, t := range tasks {
+		if _, ok := byName[t.GetName()]; ok {
+			return nil, fmt.Errorf("duplicated sub task name: %s", t.GetName())
+		}
+		byName[t.GetName()] = t
+		explicit = explicit || len(t.GetDependencies()) > 0
+	}
+
+	if !explicit {
+		graph.dependOnPriorities()
+		return graph, nil
+	}
+
+	for _, t := range tasks {
+		for _, name := range t.GetDependencies() {
+			dependency, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("sub task %s depends on an unknown task: %s", t.GetName(), name)
+			}
+			graph.dependencies[t.GetName()] = append(graph.dependencies[t.GetName()], dependency)
+		}
+	}
+
+	if cycle := graph.findCycle(); len(cycle) > 0 {
+		return nil, fmt.Errorf("dependency cycle found in the sub tasks: %s", strings.Join(cycle, " -> "))
+	}
+	return graph, nil
+}
+
+// dependOnPriorities makes each task depend on the tasks of the nearest higher priority.
+func (g *taskGraph) dependOnPriorities() {
+	groups := prioritizeTasks(g.tasks)
+	for i := 1; i < len(groups); i++ {
+		for _, t := range groups[i] {
+			g.dependencies[t.GetName()] = append([]Task(nil), groups[i-1]...)
+		}
+	}
+}
+
+// findCycle returns the names of the tasks in a dependency cycle, or nil if there is no cycle.
+func (g *taskGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make(map[string]int, len(g.tasks))
+	var path []string
+	var visit func(t Task) []string
+	visit = func(t Task) []string {
+		states[t.GetName()] = visiting
+		path = append(path, t.GetName())
+		for _, dependency := range g.dependencies[t.GetName()] {
+			switch states[dependency.GetName()] {
+			case visiting:
+				// the cycle starts from the first occurrence of the dependency in the path
+				for i, name := range path {
+					if name == dependency.GetName() {
+						return append(append([]string(nil), path[i:]...), name)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		states[t.GetName()] = visited
+		return nil
+	}
+
+	for _, t := range g.tasks {
+		if states[t.GetName()] == unvisited {
+			if cycle := visit(t); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Dependencies returns the tasks which the task depends on.
+func (g *taskGraph) Dependencies(t Task) []Task {
+	return g.dependencies[t.GetName()]
+}
+
+// Edges returns the dependency edges as [dependency, dependent] pairs, ordered by the dependents.
+func (g *taskGraph) Edges() [][2]string {
+	var edges [][2]string
+	for _, t := range g.tasks {
+		dependencies := make([]string, 0, len(g.dependencies[t.GetName()]))
+		for _, dependency := range g.dependencies[t.GetName()] {
+			dependencies = append(dependencies, dependency.GetName())
+		}
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			edges = append(edges, [2]string{dependency, t.GetName()})
+		}
+	}
+	return edges
+}
+
+// GetSubTaskEdges returns the dependency edges between the sub tasks of the task as [dependency, dependent] pairs.
+func GetSubTaskEdges(t Task) ([][2]string, error) {
+	graph, err := newTaskGraph(t.GetSubTasks())
+	if err != nil {
+		return nil, err
+	}
+	return graph.Edges(), nil
+}
+
+// isDependencySatisfied returns true if the tasks depending on the task can be started.
+func isDependencySatisfied(t Task) bool {
+	return t.GetStatus() == TaskSuccessful || t.GetFailureCanBeIgnored()
+}
+
+// runSubTasks runs the sub tasks of the task by the dependency graph: a sub task starts as soon as all its
+// dependencies are successful, and is left unstarted if any of them failed or the task is cancelled.
+// The skip function tells the sub tasks which don't need to run again, like the successful ones on resume.
+func runSubTasks(ctx context.Context, t Task, run func(ctx context.Context, subTask Task), skip func(subTask Task) bool) error {
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldTask: t.GetName(),
+	})
+
+	graph, err := newTaskGraph(t.GetSubTasks())
+	if err != nil {
+		return err
+	}
+
+	done := make(map[string]chan struct{}, len(graph.tasks))
+	for _, subTask := range graph.tasks {
+		done[subTask.GetName()] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for _, subTask := range graph.tasks {
+		wg.Add(1)
+		go func(subTask Task) {
+			defer wg.Done()
+			defer close(done[subTask.GetName()])
+
+			for _, dependency := range graph.Dependencies(subTask) {
+				<-done[dependency.GetName()]
+				if !isDependencySatisfied(dependency) {
+					logger.Debugf("Not start the sub task %s: the sub task %s it depends on was failed",
+						subTask.GetName(), dependency.GetName())
+					return
+				}
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+			if skip != nil && skip(subTask) {
+				logger.Debugf("Skip the sub task: %s", subTask.GetName())
+				return
+			}
+			run(ctx, subTask)
+		}(subTask)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %s", consts.MsgTaskCancelled, err)
+	}
+
+	// If any sub task was failed and its failure can't be ignored, or any sub task was not started
+	// because of that, the task fails.
+	for _, subTask := range graph.tasks {
+		if subTask.GetStatus() == TaskFailed && !subTask.GetFailureCanBeIgnored() {
+			return fmt.Errorf("[%s] sub task was failed", subTask.GetName())
+		}
+	}
+	for _, subTask := range graph.tasks {
+		if !isDependencySatisfied(subTask) {
+			return fmt.Errorf("[%s] sub task was not successful", subTask.GetName())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/constant"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func newGraphMockupTask(name string, priority int, dependencies ...string) *taskMockupForCancelTest {
+	return &taskMockupForCancelTest{
+		Base: Base{
+			Name:         name,
+			Status:       TaskPending,
+			Priority:     priority,
+			Dependencies: dependencies,
+		},
+	}
+}
+
+func TestNewTaskGraph(t *testing.T) {
+	// the explicit dependencies
+	graph, err := newTaskGraph([]Task{
+		newGraphMockupTask("init", 0),
+		newGraphMockupTask("deploy-etcd", 0, "init"),
+		newGraphMockupTask("deploy-master", 0, "init", "deploy-etcd"),
+		newGraphMockupTask("deploy-worker", 0, "init", "deploy-master"),
+		newGraphMockupTask("deploy-ingress", 0, "init", "deploy-master"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"init", "deploy-etcd"},
+		{"deploy-etcd", "deploy-master"},
+		{"init", "deploy-master"},
+		{"deploy-master", "deploy-worker"},
+		{"init", "deploy-worker"},
+		{"deploy-master", "deploy-ingress"},
+		{"init", "deploy-ingress"},
+	}, graph.Edges())
+
+	// fall back to the priorities if no dependency is declared
+	graph, err = newTaskGraph([]Task{
+		newGraphMockupTask("a", 1),
+		newGraphMockupTask("b", 2),
+		newGraphMockupTask("c", 2),
+		newGraphMockupTask("d", 3),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"a", "b"},
+		{"a", "c"},
+		{"b", "d"},
+		{"c", "d"},
+	}, graph.Edges())
+
+	// unknown dependency
+	_, err = newTaskGraph([]Task{
+		newGraphMockupTask("a", 0, "b"),
+	})
+	assert.EqualError(t, err, "sub task a depends on an unknown task: b")
+
+	// duplicated names
+	_, err = newTaskGraph([]Task{
+		newGraphMockupTask("a", 0),
+		newGraphMockupTask("a", 0),
+	})
+	assert.Error(t, err)
+
+	// cycle
+	_, err = newTaskGraph([]Task{
+		newGraphMockupTask("a", 0),
+		newGraphMockupTask("b", 0, "a", "d"),
+		newGraphMockupTask("c", 0, "b"),
+		newGraphMockupTask("d", 0, "c"),
+	})
+	assert.EqualError(t, err, "dependency cycle found in the sub tasks: b -> d -> c -> b")
+}
+
+func TestRunSubTasks(t *testing.T) {
+	parent := &taskMockupForCancelTest{
+		Base: Base{
+			Name: "parent",
+			SubTasks: []Task{
+				newGraphMockupTask("a", 0),
+				newGraphMockupTask("b", 0),
+				newGraphMockupTask("c", 0, "a", "b"),
+				newGraphMockupTask("failed", 0, "a"),
+				newGraphMockupTask("d", 0, "failed"),
+			},
+		},
+	}
+
+	// a and b don't depend on each other, so each of them waits for the other one to start
+	var lock sync.Mutex
+	var ran []string
+	started := map[string]chan struct{}{
+		"a": make(chan struct{}),
+		"b": make(chan struct{}),
+	}
+	parallel := map[string]bool{}
+	run := func(ctx context.Context, subTask Task) {
+		name := subTask.GetName()
+		if ch, ok := started[name]; ok {
+			close(ch)
+			other := "a"
+			if name == "a" {
+				other = "b"
+			}
+			select {
+			case <-started[other]:
+				lock.Lock()
+				parallel[name] = true
+				lock.Unlock()
+			case <-time.After(time.Second):
+			}
+		}
+
+		lock.Lock()
+		ran = append(ran, name)
+		lock.Unlock()
+
+		if name == "failed" {
+			subTask.SetStatus(TaskFailed)
+			return
+		}
+		subTask.SetStatus(TaskSuccessful)
+	}
+
+	err := runSubTasks(context.Background(), parent, run, nil)
+	assert.EqualError(t, err, "[failed] sub task was failed")
+	assert.True(t, parallel["a"])
+	assert.True(t, parallel["b"])
+	assert.ElementsMatch(t, []string{"a", "b", "c", "failed"}, ran)
+	// c starts after both a and b, d is left unstarted since the task it depends on was failed
+	assert.NotEqual(t, "c", ran[0])
+	assert.NotEqual(t, "c", ran[1])
+	assert.Equal(t, TaskPending, parent.SubTasks[4].GetStatus())
+
+	// the skipped sub tasks are not run again, but the dependents still run when they are successful
+	ran = nil
+	skip := func(subTask Task) bool {
+		return subTask.GetStatus() == TaskSuccessful
+	}
+	parent.SubTasks[3].SetStatus(TaskPending)
+	parent.SubTasks[3].(*taskMockupForCancelTest).Name = "retried"
+	parent.SubTasks[4].(*taskMockupForCancelTest).Dependencies = []string{"retried"}
+	err = runSubTasks(context.Background(), parent, run, skip)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"retried", "d"}, ran)
+
+	// nothing is started after cancelled
+	ran = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, subTask := range parent.SubTasks {
+		subTask.SetStatus(TaskPending)
+	}
+	err = runSubTasks(ctx, parent, run, nil)
+	assert.Error(t, err)
+	assert.Empty(t, ran)
+}
+
+func TestDeployProcessorDependencies(t *testing.T) {
+	p := new(deployProcessor)
+	roleNodes := map[constant.MachineRole][]*pb.NodeDeployConfig{
+		constant.MachineRoleEtcd:   {{Node: &pb.Node{Name: "node1"}}},
+		constant.MachineRoleMaster: {{Node: &pb.Node{Name: "node1"}}},
+		constant.MachineRoleWorker: {{Node: &pb.Node{Name: "node2"}}},
+	}
+
+	assert.Equal(t, []string{"init-node1"}, p.dependencies(constant.MachineRoleEtcd, roleNodes))
+	assert.Equal(t, []string{"init-node1", "deploy-etcd"}, p.dependencies(constant.MachineRoleMaster, roleNodes))
+	assert.Equal(t, []string{"prepare-worker-node2", "deploy-master"}, p.dependencies(constant.MachineRoleWorker, roleNodes))
+
+	// the roles without any node are not depended on
+	delete(roleNodes, constant.MachineRoleEtcd)
+	assert.Equal(t, []string{"init-node1"}, p.dependencies(constant.MachineRoleMaster, roleNodes))
+}
+
+func TestDeployProcessorSplitTask(t *testing.T) {
+	nodeConfigs := []*pb.NodeDeployConfig{
+		{Node: &pb.Node{Name: "master1"}, Roles: []string{"etcd", "master"}},
+		{Node: &pb.Node{Name: "worker1"}, Roles: []string{"worker"}},
+		{Node: &pb.Node{Name: "worker2"}, Roles: []string{"worker"}},
+	}
+	deployTask, err := NewDeployTask("cluster1-deploy", &DeployTaskConfig{
+		NodeConfigs:   nodeConfigs,
+		ClusterConfig: &pb.ClusterConfig{},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, new(deployProcessor).SplitTask(deployTask))
+
+	edges, err := GetSubTaskEdges(deployTask)
+	assert.NoError(t, err)
+	// the workers are prepared once their own nodes are initialized
+	assert.Equal(t, [][2]string{
+		{"init-worker1", "prepare-worker-worker1"},
+		{"init-worker2", "prepare-worker-worker2"},
+		{"init-master1", "deploy-etcd"},
+		{"deploy-etcd", "deploy-master"},
+		{"init-master1", "deploy-master"},
+		{"deploy-master", "deploy-worker"},
+		{"prepare-worker-worker1", "deploy-worker"},
+		{"prepare-worker-worker2", "deploy-worker"},
+	}, edges)
+
+	// each init sub task initializes its own node with the configs of all the nodes
+	initTask := deployTask.GetSubTasks()[1].(*NodeInitTask)
+	assert.Equal(t, "init-worker1", initTask.GetName())
+	assert.Equal(t, []*pb.NodeDeployConfig{nodeConfigs[1]}, initTask.NodeConfigs)
+	assert.Equal(t, nodeConfigs, initTask.getClusterNodeConfigs())
+}
@@ -58,7 +58,7 @@ func (p *initMasterProcessor) SplitTask(t Task) error {
 		return err
 	}
 	actions = append(actions, act)
-	task.Actions = actions
+	task.SetActions(actions)
 
 	logger.Debugf("Finish to split init master task: %d actions", len(actions))
 
@@ -27,7 +27,6 @@ const TaskTypeInitMaster Type = "InitMaster"
 
 const (
 	InitMasterOperation Operation = "init"
-	InitMasterPriority  Priority  = 10
 )
 
 type InitMasterTaskConfig struct {
@@ -39,7 +38,6 @@ type InitMasterTaskConfig struct {
 	roles           []string
 	clusterConfig   *pb.ClusterConfig
 	logFileBasePath string
-	Priority        int
 	parent          string
 }
 
@@ -78,7 +76,6 @@ func NewInitMasterTask(taskName string, taskConfig *InitMasterTaskConfig) (Task,
 			Status:            TaskPending,
 			LogFileDir:        GenTaskLogFileDir(taskConfig.logFileBasePath, taskName),
 			CreationTimestamp: time.Now(),
-			Priority:          taskConfig.Priority,
 			Parent:            taskConfig.parent,
 		},
 		CertKey:       taskConfig.certKey,
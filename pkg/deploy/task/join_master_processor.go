@@ -56,7 +56,7 @@ func (p *joinMasterProcessor) SplitTask(t Task) error {
 		return err
 	}
 	actions = append(actions, act)
-	task.Actions = actions
+	task.SetActions(actions)
 
 	logger.Debugf("Finish to split join master task: %d actions", len(actions))
 
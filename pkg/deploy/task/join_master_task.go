@@ -27,7 +27,6 @@ const TaskTypeJoinMaster Type = "JoinMaster"
 
 const (
 	JointMasterOperation Operation = "join"
-	JoinMasterPriority   Priority  = 20
 )
 
 type JoinMasterTaskConfig struct {
@@ -38,7 +37,7 @@ type JoinMasterTaskConfig struct {
 	masterNodes     []*pb.Node
 	clusterConfig   *pb.ClusterConfig
 	logFileBasePath string
-	priority        int
+	dependencies    []string
 	parent          string
 }
 
@@ -72,7 +71,7 @@ func NewJoinMasterTask(taskName string, taskConfig *JoinMasterTaskConfig) (Task,
 			Status:            TaskPending,
 			LogFileDir:        GenTaskLogFileDir(taskConfig.logFileBasePath, taskName),
 			CreationTimestamp: time.Now(),
-			Priority:          taskConfig.priority,
+			Dependencies:      taskConfig.dependencies,
 			Parent:            taskConfig.parent,
 		},
 		CertKey:       taskConfig.certKey,
@@ -0,0 +1,221 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const (
+	EventKindTask   = "task"
+	EventKindAction = "action"
+)
+
+// Event is a status transition of a task or an action recorded in the journal.
+type Event struct {
+	// Seq is the sequence number of the event in the journal, starting from 1.
+	Seq        uint64    `json:"seq"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Parent     string    `json:"parent,omitempty"`
+	ActionType string    `json:"actionType,omitempty"`
+	NodeName   string    `json:"nodeName,omitempty"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Err        *pb.Error `json:"err,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	// Duration is how long the task or action stayed in the From status.
+	Duration time.Duration `json:"duration"`
+	// CauseSeq is set only for a failed task, it's the Seq of the first failure of its sub tasks
+	// or actions since the task started, so the failure can be traced down to an action.
+	CauseSeq uint64 `json:"causeSeq,omitempty"`
+}
+
+// Journal is the append-only record of the status transitions of a top level task, its sub tasks
+// and actions. The journal is shared by the whole task tree, and saved along with the top level
+// task by the PersistentStore.
+type Journal struct {
+	lock   sync.Mutex
+	events []*Event
+	// key is the kind, parent and name, value is the index of the last event
+	last map[string]int
+	// the channels notified once an event is recorded or updated
+	subscribers map[chan<- struct{}]bool
+}
+
+// NewJournal returns an empty journal.
+func NewJournal() *Journal {
+	return newJournal(nil)
+}
+
+func newJournal(events []*Event) *Journal {
+	j := &Journal{
+		events:      events,
+		last:        make(map[string]int),
+		subscribers: make(map[chan<- struct{}]bool),
+	}
+	for i, event := range events {
+		j.last[eventKey(event.Kind, event.Parent, event.Name)] = i
+	}
+	return j
+}
+
+// Events returns a copy of all the events in order.
+func (j *Journal) Events() []*Event {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	events := make([]*Event, 0, len(j.events))
+	for _, event := range j.events {
+		copied := *event
+		events = append(events, &copied)
+	}
+	return events
+}
+
+// Subscribe registers a channel which is notified once an event is recorded, or the error of an
+// event is filled. The notification never blocks the recording, so the channel should be buffered,
+// and the subscriber should look for the changes by Events. The returned function unsubscribes it.
+func (j *Journal) Subscribe(ch chan<- struct{}) func() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.subscribers[ch] = true
+
+	return func() {
+		j.lock.Lock()
+		defer j.lock.Unlock()
+		delete(j.subscribers, ch)
+	}
+}
+
+func (j *Journal) notifyLocked() {
+	for ch := range j.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// record appends an event if the status was changed. Otherwise the error, which is usually
+// set right after the status, is filled into the last event of the task or action.
+func (j *Journal) record(event *Event, since time.Time) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	key := eventKey(event.Kind, event.Parent, event.Name)
+	last, found := j.last[key]
+	if event.From == event.To {
+		if found && j.events[last].To == event.To && event.Err != nil {
+			j.events[last].Err = event.Err
+			j.notifyLocked()
+		}
+		return
+	}
+
+	event.Seq = uint64(len(j.events) + 1)
+	event.Timestamp = time.Now()
+	if found {
+		since = j.events[last].Timestamp
+	}
+	if !since.IsZero() {
+		event.Duration = event.Timestamp.Sub(since)
+	}
+	if event.Kind == EventKindTask && event.To == string(TaskFailed) {
+		if !found {
+			last = -1
+		}
+		event.CauseSeq = j.findCause(event.Name, last)
+	}
+
+	j.events = append(j.events, event)
+	j.last[key] = len(j.events) - 1
+	j.notifyLocked()
+}
+
+// findCause returns the Seq of the first failed sub task or action of the task after the
+// event at index from, or 0 if not found. All the events are searched if from is -1.
+func (j *Journal) findCause(taskName string, from int) uint64 {
+	for _, event := range j.events[from+1:] {
+		if event.Parent != taskName {
+			continue
+		}
+		switch event.To {
+		case string(TaskFailed), string(action.ActionAborted):
+			return event.Seq
+		}
+	}
+	return 0
+}
+
+func (j *Journal) recordTask(b *Base, from Status) {
+	j.record(&Event{
+		Kind:   EventKindTask,
+		Name:   b.Name,
+		Parent: b.Parent,
+		From:   string(from),
+		To:     string(b.Status),
+		Err:    b.Err,
+	}, b.CreationTimestamp)
+}
+
+// watchAction records the status transitions of an action of the task.
+func (j *Journal) watchAction(taskName string, act action.Action) {
+	act.SetStatusObserver(func(from action.Status) {
+		event := &Event{
+			Kind:       EventKindAction,
+			Name:       act.GetName(),
+			Parent:     taskName,
+			ActionType: string(act.GetType()),
+			From:       string(from),
+			To:         string(act.GetStatus()),
+			Err:        act.GetErr(),
+		}
+		if act.GetNode() != nil {
+			event.NodeName = act.GetNode().GetName()
+		}
+		j.record(event, act.GetCreationTimestamp())
+	})
+}
+
+func eventKey(kind, parent, name string) string {
+	return kind + "/" + parent + "/" + name
+}
+
+// attachJournal shares the journal of the task with its sub tasks and actions recursively, a
+// journal is created for the top level task if it has none. It should be called again once
+// new sub tasks or actions are created.
+func attachJournal(t Task) {
+	j := t.GetJournal()
+	if j == nil {
+		if t.GetParent() != "" {
+			return
+		}
+		j = NewJournal()
+		t.SetJournal(j)
+	}
+
+	for _, subTask := range t.GetSubTasks() {
+		subTask.SetJournal(j)
+		attachJournal(subTask)
+	}
+	for _, act := range t.GetActions() {
+		j.watchAction(t.GetName(), act)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func TestJournal(t *testing.T) {
+	deployTask := newPersistentStoreTestTask(TaskPending, action.ActionPending)
+	attachJournal(deployTask)
+	assert.NotNil(t, deployTask.GetJournal())
+	initTask := deployTask.GetSubTasks()[0]
+	assert.Equal(t, deployTask.GetJournal(), initTask.GetJournal())
+	initAction := initTask.GetActions()[0]
+
+	actionErr := &pb.Error{Reason: "failed"}
+	deployTask.SetStatus(TaskDoing)
+	initTask.SetStatus(TaskDoing)
+	initAction.SetStatus(action.ActionDoing)
+	initAction.SetStatus(action.ActionFailed)
+	// the error set after the status is filled into the event
+	initAction.SetErr(actionErr)
+	initTask.SetStatus(TaskFailed)
+	// the status is not changed, no event
+	initTask.SetStatus(TaskFailed)
+	deployTask.SetStatus(TaskFailed)
+
+	events := deployTask.GetJournal().Events()
+	if !assert.Len(t, events, 6) {
+		return
+	}
+	for i, event := range events {
+		assert.Equal(t, uint64(i+1), event.Seq)
+		assert.False(t, event.Timestamp.IsZero())
+		assert.True(t, event.Duration >= 0)
+	}
+
+	assert.Equal(t, EventKindTask, events[0].Kind)
+	assert.Equal(t, "deploy", events[0].Name)
+	assert.Equal(t, string(TaskPending), events[0].From)
+	assert.Equal(t, string(TaskDoing), events[0].To)
+
+	assert.Equal(t, EventKindAction, events[3].Kind)
+	assert.Equal(t, "node-init-node1", events[3].Name)
+	assert.Equal(t, "deploy-init", events[3].Parent)
+	assert.Equal(t, string(action.ActionTypeNodeInit), events[3].ActionType)
+	assert.Equal(t, "node1", events[3].NodeName)
+	assert.Equal(t, string(action.ActionDoing), events[3].From)
+	assert.Equal(t, string(action.ActionFailed), events[3].To)
+	assert.Equal(t, actionErr, events[3].Err)
+
+	// the failure is traced down to the action
+	assert.Equal(t, "deploy-init", events[4].Name)
+	assert.Equal(t, events[3].Seq, events[4].CauseSeq)
+	assert.Equal(t, "deploy", events[5].Name)
+	assert.Equal(t, events[4].Seq, events[5].CauseSeq)
+
+	// the events returned are copies
+	events[0].To = "changed"
+	assert.Equal(t, string(TaskDoing), deployTask.GetJournal().Events()[0].To)
+}
+
+func TestAttachJournalToSubTask(t *testing.T) {
+	// the sub task executed alone has no journal
+	initTask := newPersistentStoreTestTask(TaskPending, action.ActionPending).GetSubTasks()[0]
+	attachJournal(initTask)
+	assert.Nil(t, initTask.GetJournal())
+	initTask.SetStatus(TaskDoing)
+}
+
+func TestJournalSubscribe(t *testing.T) {
+	deployTask := newPersistentStoreTestTask(TaskPending, action.ActionPending)
+	attachJournal(deployTask)
+	journal := deployTask.GetJournal()
+
+	notify := make(chan struct{}, 1)
+	unsubscribe := journal.Subscribe(notify)
+
+	// the recording is never blocked by the subscriber
+	deployTask.SetStatus(TaskDoing)
+	deployTask.SetStatus(TaskFailed)
+	<-notify
+	assert.Len(t, journal.Events(), 2)
+
+	// the error filled is notified too
+	deployTask.SetErr(&pb.Error{Reason: "failed"})
+	<-notify
+
+	unsubscribe()
+	deployTask.SetStatus(TaskDoing)
+	select {
+	case <-notify:
+		t.Fatal("notified after unsubscribed")
+	default:
+	}
+}
@@ -89,7 +89,7 @@ func (p *nodeCheckProcessor) SplitTask(t Task) error {
 		}
 	}
 
-	checkTask.Actions = actions
+	checkTask.SetActions(actions)
 	logrus.Debugf("Finish to split node check task: %d actions", len(actions))
 	return nil
 }
@@ -53,7 +53,7 @@ func (p *nodeInitProcessor) SplitTask(t Task) error {
 			NodeInitConfig:  node,
 			LogFileBasePath: initTask.LogFileDir,
 			ClusterConfig:   initTask.ClusterConfig,
-			NodesConfig:     initTask.NodeConfigs,
+			NodesConfig:     initTask.getClusterNodeConfigs(),
 		}
 		act, err := action.NewNodeInitAction(actionCfg)
 		if err != nil {
@@ -61,7 +61,7 @@ func (p *nodeInitProcessor) SplitTask(t Task) error {
 		}
 		actions = append(actions, act)
 	}
-	initTask.Actions = actions
+	initTask.SetActions(actions)
 
 	logrus.Debugf("Finish to split node init task: %d actions", len(actions))
 	return nil
@@ -27,17 +27,21 @@ const TaskTypeNodeInit Type = "NodeInit"
 
 // NodeInitTaskConfig represents the config for a node init task
 type NodeInitTaskConfig struct {
-	NodeConfigs     []*pb.NodeDeployConfig
-	ClusterConfig   *pb.ClusterConfig
-	LogFileBasePath string
-	Priority        int
-	Parent          string
+	NodeConfigs []*pb.NodeDeployConfig
+	// ClusterNodeConfigs are the configs of all the nodes in the cluster if only some of them
+	// are initialized by the task, NodeConfigs are used if it's empty.
+	ClusterNodeConfigs []*pb.NodeDeployConfig
+	ClusterConfig      *pb.ClusterConfig
+	LogFileBasePath    string
+	Priority           int
+	Parent             string
 }
 
 type NodeInitTask struct {
 	Base
-	NodeConfigs   []*pb.NodeDeployConfig
-	ClusterConfig *pb.ClusterConfig
+	NodeConfigs        []*pb.NodeDeployConfig
+	ClusterNodeConfigs []*pb.NodeDeployConfig
+	ClusterConfig      *pb.ClusterConfig
 }
 
 // NewNodeInitTask returns a common node init task based on the config.
@@ -65,9 +69,18 @@ func NewNodeInitTask(taskName string, taskConfig *NodeInitTaskConfig) (Task, err
 			Priority:          taskConfig.Priority,
 			Parent:            taskConfig.Parent,
 		},
-		NodeConfigs:   taskConfig.NodeConfigs,
-		ClusterConfig: taskConfig.ClusterConfig,
+		NodeConfigs:        taskConfig.NodeConfigs,
+		ClusterNodeConfigs: taskConfig.ClusterNodeConfigs,
+		ClusterConfig:      taskConfig.ClusterConfig,
 	}
 
 	return task, nil
 }
+
+// getClusterNodeConfigs returns the configs of all the nodes in the cluster.
+func (t *NodeInitTask) getClusterNodeConfigs() []*pb.NodeDeployConfig {
+	if len(t.ClusterNodeConfigs) == 0 {
+		return t.NodeConfigs
+	}
+	return t.ClusterNodeConfigs
+}
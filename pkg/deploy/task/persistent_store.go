@@ -0,0 +1,487 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const (
+	// TaskStoreFileName is the file name of the persistent task store in the log file location.
+	TaskStoreFileName = "tasks.db"
+
+	taskBucket         = "tasks"
+	taskStoreOpenLimit = 5 * time.Second
+
+	// A test connection task is created with a unique name for each request, only the latest
+	// finished ones are kept and the older ones are pruned with their logs.
+	maxTestConnectionTasks = 50
+)
+
+// PersistentStore is a Store which also saves the tasks into a local file, so that the tasks
+// can be reloaded after the deploy controller is restarted.
+type PersistentStore interface {
+	Store
+	// Sync saves all changed tasks into the file and prunes the outdated tasks. Tasks are
+	// updated in place during their execution, so Sync should be called periodically.
+	Sync() error
+	// Close saves all tasks and closes the file.
+	Close() error
+}
+
+// A PersistentStore implementation via an embedded bolt database, all tasks are kept in
+// the map cache as well.
+type boltStore struct {
+	cache
+	db *bolt.DB
+
+	syncLock sync.Mutex
+	// the last saved content of each task, used to skip the unchanged tasks.
+	saved map[string][]byte
+}
+
+// taskRecord is the serialized form of a task. The Actions and SubTasks fields of Base
+// are interfaces, so they are saved with their types in separate fields. The journal is
+// saved only with the top level task.
+type taskRecord struct {
+	Type     Type            `json:"type"`
+	Task     json.RawMessage `json:"task"`
+	SubTasks []*taskRecord   `json:"subTasks,omitempty"`
+	Actions  []*actionRecord `json:"actions,omitempty"`
+	Events   []*Event        `json:"events,omitempty"`
+}
+
+type actionRecord struct {
+	Type   action.Type     `json:"type"`
+	Action json.RawMessage `json:"action"`
+}
+
+// NewPersistentStore opens (or creates) the task store file and loads all saved tasks.
+func NewPersistentStore(path string) (PersistentStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return nil, fmt.Errorf("failed to create the dir of task store: %s", err)
+	}
+
+	db, err := bolt.Open(path, os.FileMode(0600), &bolt.Options{Timeout: taskStoreOpenLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task store %q: %s", path, err)
+	}
+
+	s := &boltStore{
+		cache: cache{
+			m: make(map[string]Task),
+		},
+		db:    db,
+		saved: make(map[string][]byte),
+	}
+
+	if err = s.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *boltStore) AddTask(task Task) error {
+	if err := s.cache.AddTask(task); err != nil {
+		return err
+	}
+	return s.save(task)
+}
+
+func (s *boltStore) UpdateTask(task Task) error {
+	if err := s.cache.UpdateTask(task); err != nil {
+		return err
+	}
+	return s.save(task)
+}
+
+func (s *boltStore) UpdateOrAddTask(task Task) error {
+	if err := s.cache.UpdateOrAddTask(task); err != nil {
+		return err
+	}
+	return s.save(task)
+}
+
+func (s *boltStore) Sync() error {
+	if err := s.prune(); err != nil {
+		logrus.Warnf("Failed to prune the task store: %s", err)
+	}
+
+	s.cache.RLock()
+	tasks := make([]Task, 0, len(s.cache.m))
+	for _, t := range s.cache.m {
+		tasks = append(tasks, t)
+	}
+	s.cache.RUnlock()
+
+	var errs []string
+	for _, t := range tasks {
+		if err := s.save(t); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to sync tasks: %v", errs)
+	}
+	return nil
+}
+
+func (s *boltStore) Close() error {
+	syncErr := s.Sync()
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+// save writes a task into the bolt database if it was changed since the last save.
+func (s *boltStore) save(t Task) error {
+	record, err := newTaskRecord(t)
+	if err != nil {
+		return err
+	}
+	if t.GetJournal() != nil {
+		record.Events = t.GetJournal().Events()
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task %q: %s", t.GetName(), err)
+	}
+
+	s.syncLock.Lock()
+	defer s.syncLock.Unlock()
+
+	if bytes.Equal(s.saved[t.GetName()], value) {
+		return nil
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(taskBucket))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(t.GetName()), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save task %q: %s", t.GetName(), err)
+	}
+
+	s.saved[t.GetName()] = value
+	return nil
+}
+
+// prune deletes the finished test connection tasks beyond maxTestConnectionTasks, the older ones
+// are deleted first.
+func (s *boltStore) prune() error {
+	s.cache.Lock()
+	var finished []Task
+	for _, t := range s.cache.m {
+		if t.GetType() != TaskTypeTestConnection {
+			continue
+		}
+		switch t.GetStatus() {
+		case TaskSuccessful, TaskFailed:
+			finished = append(finished, t)
+		}
+	}
+	if len(finished) <= maxTestConnectionTasks {
+		s.cache.Unlock()
+		return nil
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].GetCreationTimestamp().After(finished[j].GetCreationTimestamp())
+	})
+	pruned := finished[maxTestConnectionTasks:]
+	for _, t := range pruned {
+		delete(s.cache.m, t.GetName())
+	}
+	s.cache.Unlock()
+
+	s.syncLock.Lock()
+	defer s.syncLock.Unlock()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(taskBucket))
+		if bucket == nil {
+			return nil
+		}
+		for _, t := range pruned {
+			if err := bucket.Delete([]byte(t.GetName())); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete the pruned tasks: %s", err)
+	}
+
+	for _, t := range pruned {
+		delete(s.saved, t.GetName())
+		if t.GetLogFileDir() != "" {
+			if err := os.RemoveAll(t.GetLogFileDir()); err != nil {
+				logrus.Warnf("Failed to remove the log dir of the pruned task %q: %s", t.GetName(), err)
+			}
+		}
+	}
+	logrus.Debugf("%d test connection tasks are pruned", len(pruned))
+	return nil
+}
+
+// load reads all tasks from the bolt database into the map cache.
+func (s *boltStore) load() error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(taskBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(name, value []byte) error {
+			record := new(taskRecord)
+			if err := json.Unmarshal(value, record); err != nil {
+				logrus.Warnf("Failed to unmarshal task %q, skip it: %s", name, err)
+				return nil
+			}
+			t, err := record.toTask()
+			if err != nil {
+				logrus.Warnf("Failed to load task %q, skip it: %s", name, err)
+				return nil
+			}
+
+			// the interruptions are recorded in the journal as well
+			if len(record.Events) > 0 {
+				t.SetJournal(newJournal(record.Events))
+			}
+			attachJournal(t)
+			markInterrupted(t)
+			s.cache.m[t.GetName()] = t
+			s.saved[t.GetName()] = append([]byte(nil), value...)
+			return nil
+		})
+	})
+}
+
+// markInterrupted marks the unfinished tasks and the doing actions in a reloaded task as
+// failed, since their execution was stopped by the restart of the deploy controller.
+func markInterrupted(t Task) {
+	for _, subTask := range t.GetSubTasks() {
+		markInterrupted(subTask)
+	}
+
+	for _, act := range t.GetActions() {
+		if act.GetStatus() == action.ActionDoing {
+			act.SetStatus(action.ActionFailed)
+			act.SetErr(&pb.Error{
+				Reason:     "the action was interrupted",
+				Detail:     "the deploy controller was restarted while the action was executing",
+				FixMethods: "please execute the action again",
+			})
+		}
+	}
+
+	switch t.GetStatus() {
+	case TaskSuccessful, TaskFailed:
+		return
+	}
+
+	t.SetStatus(TaskFailed)
+	t.SetErr(&pb.Error{
+		Reason:     "the task was interrupted",
+		Detail:     "the deploy controller was restarted while the task was executing",
+		FixMethods: "please execute the task again",
+	})
+}
+
+// newTaskRecord takes a snapshot of the task, which may be changed by the executing goroutines
+// meanwhile, so each task and action is marshaled under its lock.
+func newTaskRecord(t Task) (*taskRecord, error) {
+	var content []byte
+	var err error
+	t.View(func() {
+		content, err = json.Marshal(t)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task %q: %s", t.GetName(), err)
+	}
+
+	record := &taskRecord{
+		Type: t.GetType(),
+		Task: content,
+	}
+
+	for _, subTask := range t.GetSubTasks() {
+		subRecord, err := newTaskRecord(subTask)
+		if err != nil {
+			return nil, err
+		}
+		record.SubTasks = append(record.SubTasks, subRecord)
+	}
+
+	for _, act := range t.GetActions() {
+		var content []byte
+		var err error
+		act.View(func() {
+			content, err = json.Marshal(act)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal action %q: %s", act.GetName(), err)
+		}
+		record.Actions = append(record.Actions, &actionRecord{
+			Type:   act.GetType(),
+			Action: content,
+		})
+	}
+
+	return record, nil
+}
+
+func (r *taskRecord) toTask() (Task, error) {
+	t, err := newEmptyTask(r.Type)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(r.Task, t); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task: %s", err)
+	}
+
+	base := getBase(t)
+	for _, subRecord := range r.SubTasks {
+		subTask, err := subRecord.toTask()
+		if err != nil {
+			return nil, err
+		}
+		base.SubTasks = append(base.SubTasks, subTask)
+	}
+
+	for _, actRecord := range r.Actions {
+		act, err := newEmptyAction(actRecord.Type)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(actRecord.Action, act); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal action: %s", err)
+		}
+		base.Actions = append(base.Actions, act)
+	}
+
+	return t, nil
+}
+
+// newEmptyTask returns an empty task of the type to unmarshal a saved task into.
+func newEmptyTask(taskType Type) (Task, error) {
+	switch taskType {
+	case TaskTypeDeploy:
+		return new(DeployTask), nil
+	case TaskTypeNodeInit:
+		return new(NodeInitTask), nil
+	case TaskTypeDeployEtcd:
+		return new(DeployEtcdTask), nil
+	case TaskTypeDeployMaster:
+		return new(deployMasterTask), nil
+	case TaskTypeInitMaster:
+		return new(InitMasterTask), nil
+	case TaskTypeJoinMaster:
+		return new(JoinMasterTask), nil
+	case TaskTypePrepareWorker:
+		return new(prepareWorkerTask), nil
+	case TaskTypeDeployWorker:
+		return new(deployWorkerTask), nil
+	case TaskTypeNodeCheck:
+		return new(NodeCheckTask), nil
+	case TaskTypeCheckNetworkRequirements:
+		return new(CheckNetworkRequirementsTask), nil
+	case TaskTypeTestConnection:
+		return new(TestConnectionTask), nil
+	case TaskTypeFetchKubeConfig:
+		return new(FetchKubeConfigTask), nil
+	default:
+		return nil, fmt.Errorf("%s: %s", "unsupported task type", taskType)
+	}
+}
+
+// newEmptyAction returns an empty action of the type to unmarshal a saved action into.
+func newEmptyAction(actionType action.Type) (action.Action, error) {
+	switch actionType {
+	case action.ActionTypeNodeInit:
+		return new(action.NodeInitAction), nil
+	case action.ActionTypeDeployEtcd:
+		return new(action.DeployEtcdAction), nil
+	case action.ActionTypeInitMaster:
+		return new(action.InitMasterAction), nil
+	case action.ActionTypeJoinMaster:
+		return new(action.JoinMasterAction), nil
+	case action.ActionTypePrepareWorker:
+		return new(action.PrepareWorkerAction), nil
+	case action.ActionTypeDeployWorker:
+		return new(action.DeployWorkerAction), nil
+	case action.ActionTypeNodeCheck:
+		return new(action.NodeCheckAction), nil
+	case action.ActionTypeConnectivityCheck:
+		return new(action.ConnectivityCheckAction), nil
+	case action.ActionTypeTestConnection:
+		return new(action.TestConnectionAction), nil
+	case action.ActionTypeFetchKubeConfig:
+		return new(action.FetchKubeConfigAction), nil
+	default:
+		return nil, fmt.Errorf("%s: %s", "unsupported action type", actionType)
+	}
+}
+
+// getBase returns the embedded Base of a task created by newEmptyTask.
+func getBase(t Task) *Base {
+	switch concrete := t.(type) {
+	case *DeployTask:
+		return &concrete.Base
+	case *NodeInitTask:
+		return &concrete.Base
+	case *DeployEtcdTask:
+		return &concrete.Base
+	case *deployMasterTask:
+		return &concrete.Base
+	case *InitMasterTask:
+		return &concrete.Base
+	case *JoinMasterTask:
+		return &concrete.Base
+	case *prepareWorkerTask:
+		return &concrete.Base
+	case *deployWorkerTask:
+		return &concrete.Base
+	case *NodeCheckTask:
+		return &concrete.Base
+	case *CheckNetworkRequirementsTask:
+		return &concrete.Base
+	case *TestConnectionTask:
+		return &concrete.Base
+	case *FetchKubeConfigTask:
+		return &concrete.Base
+	}
+	return nil
+}
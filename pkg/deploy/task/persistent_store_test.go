@@ -0,0 +1,305 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/operation/etcd"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+func newPersistentStoreTestTask(taskStatus Status, actionStatus action.Status) *DeployTask {
+	node := &pb.Node{Name: "node1", Ip: "192.168.1.1"}
+	nodeConfig := &pb.NodeDeployConfig{Node: node, Roles: []string{"master"}}
+	clusterConfig := &pb.ClusterConfig{ClusterName: "cluster1"}
+
+	initAction := &action.NodeInitAction{
+		Base: action.Base{
+			Name:       "node-init-node1",
+			ActionType: action.ActionTypeNodeInit,
+			Status:     actionStatus,
+			Node:       node,
+		},
+		NodeInitConfig: nodeConfig,
+		ClusterConfig:  clusterConfig,
+		InitItems: []*action.NodeInitItem{
+			{Name: "swap", Status: action.ItemDone},
+		},
+	}
+	initTask := &NodeInitTask{
+		Base: Base{
+			Name:     "deploy-init",
+			TaskType: TaskTypeNodeInit,
+			Status:   taskStatus,
+			Actions:  []action.Action{initAction},
+			Parent:   "deploy",
+		},
+		NodeConfigs:   []*pb.NodeDeployConfig{nodeConfig},
+		ClusterConfig: clusterConfig,
+	}
+
+	return &DeployTask{
+		Base: Base{
+			Name:     "deploy",
+			TaskType: TaskTypeDeploy,
+			Status:   taskStatus,
+			SubTasks: []Task{initTask},
+		},
+		NodeConfigs:   []*pb.NodeDeployConfig{nodeConfig},
+		ClusterConfig: clusterConfig,
+	}
+}
+
+func TestPersistentStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, TaskStoreFileName)
+
+	store, err := NewPersistentStore(path)
+	assert.NoError(t, err)
+	deployTask := newPersistentStoreTestTask(TaskSuccessful, action.ActionDone)
+	assert.NoError(t, store.AddTask(deployTask))
+	assert.Error(t, store.AddTask(deployTask))
+	assert.NoError(t, store.Close())
+
+	store, err = NewPersistentStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	loaded, ok := store.GetTask("deploy").(*DeployTask)
+	assert.True(t, ok)
+	assert.Equal(t, TaskSuccessful, loaded.GetStatus())
+	assert.Equal(t, deployTask.ClusterConfig, loaded.ClusterConfig)
+	assert.Nil(t, loaded.GetErr())
+
+	assert.Len(t, loaded.GetSubTasks(), 1)
+	subTask, ok := loaded.GetSubTasks()[0].(*NodeInitTask)
+	assert.True(t, ok)
+	assert.Equal(t, "deploy", subTask.GetParent())
+
+	actions := GetAllActions(loaded)
+	assert.Len(t, actions, 1)
+	initAction, ok := actions[0].(*action.NodeInitAction)
+	assert.True(t, ok)
+	assert.Equal(t, action.ActionDone, initAction.GetStatus())
+	assert.Equal(t, "node1", initAction.GetNode().GetName())
+	assert.Equal(t, action.ItemDone, initAction.InitItems[0].Status)
+}
+
+func TestPersistentStoreSync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, TaskStoreFileName)
+
+	store, err := NewPersistentStore(path)
+	assert.NoError(t, err)
+	deployTask := newPersistentStoreTestTask(TaskPending, action.ActionPending)
+	assert.NoError(t, store.UpdateOrAddTask(deployTask))
+
+	// tasks are changed in place during execution
+	deployTask.SetStatus(TaskDoing)
+	deployTask.GetSubTasks()[0].SetStatus(TaskDoing)
+	deployTask.GetSubTasks()[0].GetActions()[0].SetStatus(action.ActionDoing)
+	assert.NoError(t, store.Sync())
+	assert.NoError(t, store.Close())
+
+	// unfinished tasks and actions should be marked as failed after reloading
+	store, err = NewPersistentStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	loaded := store.GetTask("deploy")
+	assert.NotNil(t, loaded)
+	assert.Equal(t, TaskFailed, loaded.GetStatus())
+	assert.NotNil(t, loaded.GetErr())
+	assert.Equal(t, TaskFailed, loaded.GetSubTasks()[0].GetStatus())
+	act := loaded.GetSubTasks()[0].GetActions()[0]
+	assert.Equal(t, action.ActionFailed, act.GetStatus())
+	assert.NotNil(t, act.GetErr())
+}
+
+func TestPersistentStoreJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, TaskStoreFileName)
+
+	store, err := NewPersistentStore(path)
+	assert.NoError(t, err)
+	deployTask := newPersistentStoreTestTask(TaskPending, action.ActionPending)
+	attachJournal(deployTask)
+	assert.NoError(t, store.AddTask(deployTask))
+	deployTask.SetStatus(TaskDoing)
+	deployTask.GetSubTasks()[0].GetActions()[0].SetStatus(action.ActionDoing)
+	assert.NoError(t, store.Close())
+
+	// the journal is reloaded with the task, and the interruptions are appended
+	store, err = NewPersistentStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	loaded := store.GetTask("deploy")
+	if !assert.NotNil(t, loaded.GetJournal()) {
+		return
+	}
+	events := loaded.GetJournal().Events()
+	if !assert.Len(t, events, 5) {
+		return
+	}
+	assert.Equal(t, "deploy", events[0].Name)
+	assert.Equal(t, string(TaskDoing), events[0].To)
+	assert.Equal(t, "node-init-node1", events[1].Name)
+	assert.Equal(t, string(action.ActionDoing), events[1].To)
+	assert.Equal(t, "node-init-node1", events[2].Name)
+	assert.Equal(t, string(action.ActionFailed), events[2].To)
+	assert.Equal(t, "the action was interrupted", events[2].Err.Reason)
+	assert.Equal(t, "deploy-init", events[3].Name)
+	assert.Equal(t, string(TaskFailed), events[3].To)
+	assert.Equal(t, "deploy", events[4].Name)
+	assert.Equal(t, string(TaskFailed), events[4].To)
+	// the durations are continued after reloading
+	assert.True(t, events[0].Timestamp.Equal(events[4].Timestamp.Add(-events[4].Duration)))
+}
+
+func TestPersistentStoreSyncWhileExecuting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewPersistentStore(filepath.Join(dir, TaskStoreFileName))
+	assert.NoError(t, err)
+	defer store.Close()
+	deployTask := newPersistentStoreTestTask(TaskPending, action.ActionPending)
+	assert.NoError(t, store.AddTask(deployTask))
+
+	// the task is changed by the executing goroutine while it's being synced
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		initAction := deployTask.GetSubTasks()[0].GetActions()[0].(*action.NodeInitAction)
+		for i := 0; i < 100; i++ {
+			deployTask.SetStatus(TaskDoing)
+			initAction.SetStatus(action.ActionDoing)
+			initAction.Update(func() {
+				initAction.InitItems = append(initAction.InitItems, &action.NodeInitItem{Status: action.ItemDone})
+			})
+			deployTask.SetStatus(TaskPending)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, store.Sync())
+	}
+	<-done
+}
+
+func TestPersistentStoreEtcdCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, TaskStoreFileName)
+
+	caCrt, caKey, err := etcd.CreateAsCA(etcd.GetCaCrtConfig())
+	assert.NoError(t, err)
+	node := &pb.Node{Name: "node1", Ip: "192.168.1.1"}
+	act, err := action.NewDeployEtcdAction(&action.DeployEtcdActionConfig{
+		CaCrt:        caCrt,
+		CaKey:        caKey,
+		Node:         node,
+		ClusterNodes: []*pb.Node{node},
+	})
+	assert.NoError(t, err)
+	etcdTask := &DeployEtcdTask{
+		Base: Base{
+			Name:     "deploy-etcd",
+			TaskType: TaskTypeDeployEtcd,
+			Status:   TaskFailed,
+			Actions:  []action.Action{act},
+		},
+		Nodes: []*pb.Node{node},
+	}
+
+	store, err := NewPersistentStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.AddTask(etcdTask))
+	assert.NoError(t, store.Close())
+
+	// the ca is reloaded with the action, so that the task can be resumed with the same ca
+	store, err = NewPersistentStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	loaded := store.GetTask("deploy-etcd")
+	if !assert.NotNil(t, loaded) || !assert.Len(t, loaded.GetActions(), 1) {
+		return
+	}
+	etcdAction, ok := loaded.GetActions()[0].(*action.DeployEtcdAction)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.True(t, caCrt.Equal(etcdAction.CACrt))
+	if assert.NotNil(t, etcdAction.CAKey) {
+		assert.Equal(t, caKey.Public(), etcdAction.CAKey.Public())
+	}
+	assert.Equal(t, "node1", etcdAction.ClusterNodes[0].GetName())
+}
+
+func TestPersistentStorePrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, TaskStoreFileName)
+
+	store, err := NewPersistentStore(path)
+	assert.NoError(t, err)
+	node := &pb.Node{Name: "node1"}
+	start := time.Now()
+	for i := 0; i < maxTestConnectionTasks+2; i++ {
+		testConnTask, err := NewTestConnectionTask(fmt.Sprintf("test-connection-%d", i),
+			&TestConnectionTaskConfig{Node: node, LogFileBasePath: dir})
+		assert.NoError(t, err)
+		getBase(testConnTask).CreationTimestamp = start.Add(time.Duration(i) * time.Second)
+		testConnTask.SetStatus(TaskSuccessful)
+		assert.NoError(t, store.AddTask(testConnTask))
+	}
+	// the running ones are not pruned
+	runningTask, err := NewTestConnectionTask("test-connection-running", &TestConnectionTaskConfig{Node: node})
+	assert.NoError(t, err)
+	getBase(runningTask).CreationTimestamp = start.Add(-time.Hour)
+	runningTask.SetStatus(TaskDoing)
+	assert.NoError(t, store.AddTask(runningTask))
+	assert.NoError(t, store.Sync())
+	assert.NoError(t, store.Close())
+
+	store, err = NewPersistentStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	assert.Len(t, store.ListTasks(), maxTestConnectionTasks+1)
+	assert.Nil(t, store.GetTask("test-connection-0"))
+	assert.Nil(t, store.GetTask("test-connection-1"))
+	assert.NotNil(t, store.GetTask("test-connection-2"))
+	assert.NotNil(t, store.GetTask("test-connection-running"))
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// PlanTask splits the task and its sub tasks recursively, and plans the actions without executing
+// them. Nothing is done on the nodes, the task is left split but not executed.
+func PlanTask(t Task) *pb.TaskPlan {
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldTask: t.GetName(),
+	})
+
+	taskPlan := &pb.TaskPlan{
+		Name:         t.GetName(),
+		Type:         string(t.GetType()),
+		Parent:       t.GetParent(),
+		Dependencies: t.GetDependencies(),
+	}
+
+	if err := splitTask(t); err != nil {
+		logger.Errorf("Failed to plan task: %v", err)
+		taskPlan.Err = t.GetErr()
+		return taskPlan
+	}
+
+	graph, err := newTaskGraph(t.GetSubTasks())
+	if err != nil {
+		logger.Errorf("Failed to plan task: %v", err)
+		taskPlan.Err = &pb.Error{
+			Reason: consts.MsgTaskPlanFailed,
+			Detail: err.Error(),
+		}
+		return taskPlan
+	}
+
+	for _, subTask := range t.GetSubTasks() {
+		subTaskPlan := PlanTask(subTask)
+		// the dependencies may come from the priorities
+		subTaskPlan.Dependencies = nil
+		for _, dependency := range graph.Dependencies(subTask) {
+			subTaskPlan.Dependencies = append(subTaskPlan.Dependencies, dependency.GetName())
+		}
+		taskPlan.SubTasks = append(taskPlan.SubTasks, subTaskPlan)
+	}
+
+	for _, act := range t.GetActions() {
+		taskPlan.Actions = append(taskPlan.Actions, action.PlanAction(act))
+	}
+
+	logger.Debugf("Finish to plan task: %d sub tasks and %d actions", len(taskPlan.SubTasks), len(taskPlan.Actions))
+	return taskPlan
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// registerDeployProcessors registers the processors of the deploy task again, since some tests
+// clean up all the processors registered.
+func registerDeployProcessors() {
+	processors := map[Type]Processor{
+		TaskTypeDeploy:        new(deployProcessor),
+		TaskTypeNodeInit:      new(nodeInitProcessor),
+		TaskTypeDeployEtcd:    new(deployEtcdProcessor),
+		TaskTypeDeployMaster:  new(deployMasterProcessor),
+		TaskTypeInitMaster:    new(initMasterProcessor),
+		TaskTypeJoinMaster:    new(joinMasterProcessor),
+		TaskTypePrepareWorker: new(prepareWorkerProcessor),
+		TaskTypeDeployWorker:  new(DeployWorkerProcessor),
+	}
+	for taskType, proc := range processors {
+		if _, ok := _processRegistry[taskType]; !ok {
+			RegisterProcessor(taskType, proc)
+		}
+	}
+}
+
+func TestPlanDeployTask(t *testing.T) {
+	registerDeployProcessors()
+	master := &pb.Node{Name: "master1", Ip: "10.1.1.1"}
+	worker := &pb.Node{Name: "worker1", Ip: "10.1.1.2"}
+	deployTask, err := NewDeployTask("cluster1-deploy", &DeployTaskConfig{
+		NodeConfigs: []*pb.NodeDeployConfig{
+			{Node: master, Roles: []string{"etcd", "master"}},
+			{Node: worker, Roles: []string{"worker"}, Labels: map[string]string{"env": "test"}},
+		},
+		ClusterConfig: &pb.ClusterConfig{
+			KubeAPIServerConnect: &pb.KubeAPIServerConnect{Type: "firstMasterIP"},
+		},
+	})
+	assert.NoError(t, err)
+
+	plan := PlanTask(deployTask)
+	assert.Nil(t, plan.Err)
+	assert.Equal(t, "cluster1-deploy", plan.Name)
+	if !assert.Len(t, plan.SubTasks, 6) {
+		return
+	}
+
+	masterInitPlan, workerInitPlan, preparePlan := plan.SubTasks[0], plan.SubTasks[1], plan.SubTasks[2]
+	etcdPlan, masterPlan, workerPlan := plan.SubTasks[3], plan.SubTasks[4], plan.SubTasks[5]
+	assert.Equal(t, "init-master1", masterInitPlan.Name)
+	assert.Equal(t, "init-worker1", workerInitPlan.Name)
+	assert.Empty(t, masterInitPlan.Dependencies)
+	assert.Empty(t, workerInitPlan.Dependencies)
+	// the worker is prepared once the node is initialized, without waiting for the masters
+	assert.Equal(t, "prepare-worker-worker1", preparePlan.Name)
+	assert.Equal(t, []string{"init-worker1"}, preparePlan.Dependencies)
+	assert.Equal(t, []string{"init-master1"}, etcdPlan.Dependencies)
+	assert.Equal(t, []string{"init-master1", "deploy-etcd"}, masterPlan.Dependencies)
+	assert.Equal(t, []string{"prepare-worker-worker1", "deploy-master"}, workerPlan.Dependencies)
+
+	// an init action for each node, the kube tools are installed on all of them
+	for _, initPlan := range []*pb.TaskPlan{masterInitPlan, workerInitPlan} {
+		if !assert.Len(t, initPlan.Actions, 1) {
+			continue
+		}
+		actionPlan := initPlan.Actions[0]
+		assert.Nil(t, actionPlan.Err)
+		var installed bool
+		for _, step := range actionPlan.Steps {
+			assert.Equal(t, actionPlan.NodeName, step.NodeName)
+			if step.Action == machine.StepRun && strings.Contains(step.Command, "init_deploy_kubetool.sh setup kubelet") {
+				installed = true
+			}
+			// the scripts are not shown
+			if step.Action == machine.StepPutFile {
+				assert.True(t, step.ContentHidden)
+			}
+		}
+		assert.True(t, installed)
+	}
+
+	// the kubelet of the worker is started by the preparation
+	if assert.Len(t, preparePlan.Actions, 1) {
+		assert.Nil(t, preparePlan.Actions[0].Err)
+		assert.Equal(t, "systemctl restart kubelet", preparePlan.Actions[0].Steps[0].Command)
+	}
+
+	// the master task is split into the sub task to init the first master
+	if assert.Len(t, masterPlan.SubTasks, 1) && assert.Len(t, masterPlan.SubTasks[0].Actions, 1) {
+		assert.Equal(t, "initMaster", masterPlan.SubTasks[0].Name)
+		assert.Nil(t, masterPlan.SubTasks[0].Actions[0].Err)
+		assert.NotEmpty(t, masterPlan.SubTasks[0].Actions[0].Steps)
+	}
+
+	// the worker joins the cluster, and the labels are set from the master
+	if assert.Len(t, workerPlan.Actions, 1) {
+		workerAction := workerPlan.Actions[0]
+		assert.Nil(t, workerAction.Err)
+		assert.Equal(t, "worker1", workerAction.Steps[0].NodeName)
+		assert.Equal(t, "master1", workerAction.Steps[len(workerAction.Steps)-1].NodeName)
+	}
+
+	// nothing is executed
+	assert.Equal(t, TaskPending, deployTask.GetStatus())
+	for _, act := range deployTask.GetSubTasks()[0].GetActions() {
+		assert.Equal(t, action.ActionPending, act.GetStatus())
+	}
+}
+
+func TestPlanTaskFailedToSplit(t *testing.T) {
+	deployTask := &DeployTask{Base: Base{Name: "cluster1-deploy", TaskType: TaskTypeDeploy}}
+
+	plan := PlanTask(deployTask)
+	assert.NotNil(t, plan.Err)
+	assert.Empty(t, plan.SubTasks)
+}
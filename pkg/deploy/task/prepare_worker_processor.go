@@ -0,0 +1,86 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+)
+
+func init() {
+	RegisterProcessor(TaskTypePrepareWorker, new(prepareWorkerProcessor))
+}
+
+// prepareWorkerProcessor implements the specific logic for the prepare worker task.
+type prepareWorkerProcessor struct {
+}
+
+// Spilt the task into one or more node prepare worker actions
+func (processor *prepareWorkerProcessor) SplitTask(task Task) error {
+	if err := processor.verifyTask(task); err != nil {
+		logrus.Errorf("Invalid task: %s", err)
+		return err
+	}
+
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldTask: task.GetName(),
+	})
+
+	logger.Debug("Start to split prepare worker task")
+
+	prepareTask := task.(*prepareWorkerTask)
+
+	// split task into actions: will create a action for every node, the action type
+	// is ActionTypePrepareWorker
+	actions := make([]action.Action, 0, len(prepareTask.Nodes))
+	for _, node := range prepareTask.Nodes {
+		actionCfg := &action.PrepareWorkerActionConfig{
+			NodeCfg:         node,
+			LogFileBasePath: prepareTask.LogFileDir,
+		}
+		act, err := action.NewPrepareWorkerAction(actionCfg)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, act)
+	}
+	prepareTask.SetActions(actions)
+
+	logger.Debugf("Finish to split prepare worker task: %d actions", len(actions))
+
+	return nil
+}
+
+// Verify if the task is valid.
+func (processor *prepareWorkerProcessor) verifyTask(task Task) error {
+	if task == nil {
+		return consts.ErrEmptyTask
+	}
+
+	prepareTask, ok := task.(*prepareWorkerTask)
+	if !ok {
+		return fmt.Errorf("%s: %T", consts.MsgTaskTypeMismatched, task)
+	}
+
+	if len(prepareTask.Nodes) == 0 {
+		return fmt.Errorf("nodes is empty")
+	}
+
+	return nil
+}
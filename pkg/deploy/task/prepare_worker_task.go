@@ -0,0 +1,69 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+const TaskTypePrepareWorker Type = "PrepareWorker"
+
+// PrepareWorkerTaskConfig represents the config for a prepare worker task, which prepares the worker
+// nodes to join the cluster. It doesn't depend on the masters.
+type PrepareWorkerTaskConfig struct {
+	Nodes           []*protos.NodeDeployConfig
+	LogFileBasePath string
+	Dependencies    []string
+	Parent          string
+}
+
+type prepareWorkerTask struct {
+	Base
+	Nodes []*protos.NodeDeployConfig
+}
+
+// NewPrepareWorkerTask returns a prepare worker task based on the config.
+// User should use this function to create a prepare worker task.
+func NewPrepareWorkerTask(taskName string, taskConfig *PrepareWorkerTaskConfig) (Task, error) {
+
+	if taskConfig == nil {
+
+		return nil, fmt.Errorf("invalid task config: nil")
+	}
+
+	if len(taskConfig.Nodes) == 0 {
+
+		return nil, fmt.Errorf("invalid task config: nodes is empty")
+	}
+
+	task := &prepareWorkerTask{
+		Base: Base{
+			Name:                taskName,
+			TaskType:            TaskTypePrepareWorker,
+			Status:              TaskPending,
+			LogFileDir:          GenTaskLogFileDir(taskConfig.LogFileBasePath, taskName),
+			CreationTimestamp:   time.Now(),
+			Dependencies:        taskConfig.Dependencies,
+			Parent:              taskConfig.Parent,
+			FailureCanBeIgnored: true,
+		},
+		Nodes: taskConfig.Nodes,
+	}
+
+	return task, nil
+}
@@ -15,10 +15,10 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
-	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -79,14 +79,24 @@ func NewProcessor(taskType Type) (Processor, error) {
 }
 
 // StartTask does a basic verification on the task,
-// then starts the task's execution and return immediately
+// then starts the task's execution and return immediately.
+// The task can be cancelled by CancelTask before it finishes.
 func StartTask(t Task) error {
 	if err := verifyTask(t); err != nil {
 		logrus.Error(err)
 		return err
 	}
 
-	go ExecuteTask(t)
+	ctx, release, err := newRunningContext(t)
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+
+	go func() {
+		defer release()
+		ExecuteTask(ctx, t)
+	}()
 	return nil
 }
 
@@ -103,7 +113,8 @@ func verifyTask(t Task) error {
 }
 
 // ExecuteTask starts the task's execution and wait it to finish.
-func ExecuteTask(t Task) error {
+// Once the context is cancelled, the execution stops and the unfinished part of the task is aborted.
+func ExecuteTask(ctx context.Context, t Task) error {
 	if t == nil {
 		return consts.ErrEmptyTask
 	}
@@ -121,6 +132,10 @@ func ExecuteTask(t Task) error {
 		if errState := statTask(t); errState != nil {
 			logger.Errorf("Failed in the Last Step: %v", errState)
 		}
+		if ctx.Err() != nil {
+			logger.Info("Task is cancelled")
+			abortTask(t, ctx.Err())
+		}
 		// If there was an error during task execution, we need to set
 		// the task's status to failed.
 		if err != nil && t.GetStatus() != TaskFailed {
@@ -135,6 +150,7 @@ func ExecuteTask(t Task) error {
 		}
 	}()
 
+	attachJournal(t)
 	t.SetStatus(TaskInitializing)
 	logger.Debug("Step 1: Setup")
 	if err = setup(t); err != nil {
@@ -142,6 +158,10 @@ func ExecuteTask(t Task) error {
 		return err
 	}
 
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
 	t.SetStatus(TaskSplitting)
 	logger.Debug("Step 2: Split Task")
 
@@ -149,16 +169,17 @@ func ExecuteTask(t Task) error {
 		logger.Errorf("Failed in Step 2: %v", err)
 		return err
 	}
+	attachJournal(t)
 
 	t.SetStatus(TaskDoing)
 	logger.Debug("Step 3: Execute Sub Tasks")
-	if err = executeSubTasks(t); err != nil {
+	if err = executeSubTasks(ctx, t); err != nil {
 		logger.Errorf("Failed in Step 3: %v", err)
 		return err
 	}
 
 	logger.Debug("Step 4: Execute Actions")
-	if err = executeActions(t); err != nil {
+	if err = executeActions(ctx, t); err != nil {
 		logger.Errorf("Failed in Step 4: %v", err)
 		return err
 	}
@@ -173,12 +194,6 @@ func ExecuteTask(t Task) error {
 	return nil
 }
 
-func executeTaskWithWG(t Task, wg *sync.WaitGroup) error {
-	defer wg.Done()
-
-	return ExecuteTask(t)
-}
-
 // Create the corresponding processor to split the task.
 func splitTask(t Task) error {
 	if t == nil {
@@ -218,7 +233,7 @@ func splitTask(t Task) error {
 }
 
 // Execute the sub tasks of a task
-func executeSubTasks(t Task) error {
+func executeSubTasks(ctx context.Context, t Task) error {
 	if t == nil {
 		return consts.ErrEmptyTask
 	}
@@ -234,25 +249,11 @@ func executeSubTasks(t Task) error {
 
 	logger.Debug("Start to execute sub tasks")
 
-	// Group the sub tasks by priority firstly.
-	priTasks := prioritizeTasks(t.GetSubTasks())
-	// Execute the task group sequentially.
-	for _, taskGp := range priTasks {
-		var wg sync.WaitGroup
-		// Execute the tasks in the same group parallelly.
-		for _, aSubTask := range taskGp {
-			wg.Add(1)
-			go executeTaskWithWG(aSubTask, &wg)
-		}
-		wg.Wait()
-
-		// If any sub task in the current task group was failed and its failure can't be ignored,
-		// stop to execut other task groups and return.
-		for _, aSubTask := range taskGp {
-			if aSubTask.GetStatus() != TaskSuccessful && !aSubTask.GetFailureCanBeIgnored() {
-				return fmt.Errorf("[%s] sub task was failed", aSubTask.GetName())
-			}
-		}
+	// Execute the sub tasks by their dependencies, the independent ones are executed parallelly.
+	if err := runSubTasks(ctx, t, func(ctx context.Context, subTask Task) {
+		ExecuteTask(ctx, subTask)
+	}, nil); err != nil {
+		return err
 	}
 
 	logger.Debug("Finish executing sub tasks")
@@ -260,7 +261,7 @@ func executeSubTasks(t Task) error {
 }
 
 // Execute the actions of a task
-func executeActions(t Task) error {
+func executeActions(ctx context.Context, t Task) error {
 	if t == nil {
 		return consts.ErrEmptyTask
 	}
@@ -276,13 +277,12 @@ func executeActions(t Task) error {
 
 	logger.Debug("Start to execute actions")
 
-	var wg sync.WaitGroup
-	// execute the actions parallelly
-	for _, act := range t.GetActions() {
-		wg.Add(1)
-		go action.ExecuteAction(act, &wg)
+	// execute the actions parallelly, limited by the concurrency options
+	runActions(ctx, t, t.GetActions())
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %s", consts.MsgTaskCancelled, err)
 	}
-	wg.Wait()
 
 	logger.Debug("Finish to execute actions")
 	return nil
@@ -351,7 +351,7 @@ func statTask(t Task) error {
 
 	for _, act := range t.GetActions() {
 		switch act.GetStatus() {
-		case action.ActionFailed:
+		case action.ActionFailed, action.ActionAborted:
 			failed++
 			errMsgs = append(errMsgs, fmt.Sprintf("%v", act.GetErr()))
 		case action.ActionDone:
@@ -15,6 +15,7 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -32,7 +33,7 @@ type actionMockupForProcessorTest struct {
 }
 type executorMockupForProcessorTest struct{}
 
-func (e *executorMockupForProcessorTest) Execute(act action.Action) *pb.Error {
+func (e *executorMockupForProcessorTest) Execute(ctx context.Context, act action.Action) *pb.Error {
 	_, ok := act.(*actionMockupForProcessorTest)
 	if !ok {
 		return new(pb.Error)
@@ -141,7 +142,7 @@ func TestRegisterProcessor(t *testing.T) {
 }
 
 func TestExecuteTask(t *testing.T) {
-	err := action.RegisterExecutor(ActionTypeTestProcessorMockup, new(executorMockupForProcessorTest))
+	err := action.RegisterExecutor(ActionTypeTestProcessorMockup, func() action.Executor { return new(executorMockupForProcessorTest) })
 	assert.NoError(t, err)
 
 	err = RegisterProcessor(TaskTypeTestProcessorMockup1, new(processorMockupForProcessorTest1))
@@ -159,7 +160,7 @@ func TestExecuteTask(t *testing.T) {
 		},
 	}
 
-	err = ExecuteTask(task1)
+	err = ExecuteTask(context.Background(), task1)
 	assert.NoError(t, err)
 	assert.Equal(t, TaskSuccessful, task1.GetStatus())
 	assert.Nil(t, task1.GetErr())
@@ -176,6 +177,25 @@ func TestExecuteTask(t *testing.T) {
 		}
 	}
 
+	// the status transitions of the whole task tree are recorded in the journal
+	if assert.NotNil(t, task1.GetJournal()) {
+		events := task1.GetJournal().Events()
+		assert.Equal(t, EventKindTask, events[0].Kind)
+		assert.Equal(t, "task1", events[0].Name)
+		assert.Equal(t, string(TaskInitializing), events[0].To)
+		last := events[len(events)-1]
+		assert.Equal(t, "task1", last.Name)
+		assert.Equal(t, string(TaskSuccessful), last.To)
+
+		var actionsDone int
+		for _, event := range events {
+			if event.Kind == EventKindAction && event.To == string(action.ActionDone) {
+				actionsDone++
+			}
+		}
+		assert.Equal(t, 2, actionsDone)
+	}
+
 	// cleanup
 	_processRegistry = nil
 }
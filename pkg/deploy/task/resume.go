@@ -0,0 +1,237 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// StartResumeTask does a basic verification on a previously executed task,
+// then starts to resume the task's execution and return immediately.
+// The task can be cancelled by CancelTask before it finishes.
+func StartResumeTask(t Task) error {
+	if err := verifyTask(t); err != nil {
+		logrus.Error(err)
+		return err
+	}
+
+	// Reserve the task name before checking the status, so the task can't be launched
+	// by others between the check and the launch.
+	ctx, release, err := newRunningContext(t)
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+
+	switch t.GetStatus() {
+	case TaskFailed, TaskPending:
+	default:
+		release()
+		err := fmt.Errorf("task %q can't be resumed in %q status", t.GetName(), t.GetStatus())
+		logrus.Error(err)
+		return err
+	}
+
+	go func() {
+		defer release()
+		ResumeTask(ctx, t)
+	}()
+	return nil
+}
+
+// ResumeTask resumes a previously executed task and wait it to finish. The successful sub tasks
+// and done actions are skipped, only the failed or pending ones are executed again by the dependency graph.
+func ResumeTask(ctx context.Context, t Task) error {
+	if t == nil {
+		return consts.ErrEmptyTask
+	}
+
+	if t.GetStatus() == TaskSuccessful {
+		return nil
+	}
+
+	// The task was not split in the previous execution, execute it from scratch.
+	if len(t.GetSubTasks()) == 0 && len(t.GetActions()) == 0 {
+		t.SetErr(nil)
+		return ExecuteTask(ctx, t)
+	}
+
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldTask: t.GetName(),
+	})
+
+	logger.Debug("Start to resume Task")
+
+	var err error
+	defer func() {
+		// No matter what happened, we have to summary the task status.
+		logger.Debug("Last Step: Stat Task")
+		if errState := statTask(t); errState != nil {
+			logger.Errorf("Failed in the Last Step: %v", errState)
+		}
+		if ctx.Err() != nil {
+			logger.Info("Task is cancelled")
+			abortTask(t, ctx.Err())
+		}
+		if err != nil && t.GetStatus() != TaskFailed {
+			t.SetStatus(TaskFailed)
+			if t.GetErr() == nil {
+				t.SetErr(&pb.Error{
+					Reason: "failed to resume the task",
+					Detail: err.Error(),
+				})
+			}
+		}
+	}()
+
+	attachJournal(t)
+	t.SetErr(nil)
+	t.SetStatus(TaskInitializing)
+	logger.Debug("Step 1: Setup")
+	if err = setup(t); err != nil {
+		logger.Errorf("Failed in Step 1: %v", err)
+		return err
+	}
+
+	// Only the leaf task is split again, the actions which are not done in the previous
+	// execution will be recreated by the processor.
+	if len(t.GetSubTasks()) == 0 {
+		t.SetStatus(TaskSplitting)
+		logger.Debug("Step 2: Split Task Again")
+		if err = resplitTask(t); err != nil {
+			logger.Errorf("Failed in Step 2: %v", err)
+			return err
+		}
+		attachJournal(t)
+	}
+
+	t.SetStatus(TaskDoing)
+	logger.Debug("Step 3: Resume Sub Tasks")
+	if err = resumeSubTasks(ctx, t); err != nil {
+		logger.Errorf("Failed in Step 3: %v", err)
+		return err
+	}
+
+	logger.Debug("Step 4: Resume Actions")
+	if err = resumeActions(ctx, t); err != nil {
+		logger.Errorf("Failed in Step 4: %v", err)
+		return err
+	}
+
+	logger.Debug("Step 5: Process Extra Result")
+	if err = processExtraResult(t); err != nil {
+		logger.Errorf("Failed in Step 5: %v", err)
+		return err
+	}
+
+	logger.Debug("Finish to resume task")
+	return nil
+}
+
+// Split the task again and keep the actions which were done in the previous execution.
+func resplitTask(t Task) error {
+	doneActions := make(map[string]action.Action)
+	for _, act := range t.GetActions() {
+		if act.GetStatus() == action.ActionDone {
+			doneActions[getActionKey(act)] = act
+		}
+	}
+
+	if err := splitTask(t); err != nil {
+		return err
+	}
+
+	// Replace the new actions with the done ones in place.
+	actions := t.GetActions()
+	t.Update(func() {
+		for i, act := range actions {
+			if doneAct, ok := doneActions[getActionKey(act)]; ok {
+				actions[i] = doneAct
+			}
+		}
+	})
+
+	return nil
+}
+
+// An action is identified by its type and node in a task.
+func getActionKey(act action.Action) string {
+	return fmt.Sprintf("%s/%s", act.GetType(), act.GetNode().GetName())
+}
+
+// Resume the sub tasks of a task, the successful sub tasks will be skipped.
+func resumeSubTasks(ctx context.Context, t Task) error {
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldTask: t.GetName(),
+	})
+
+	if len(t.GetSubTasks()) == 0 {
+		logger.Debug("No sub task")
+		return nil
+	}
+
+	logger.Debug("Start to resume sub tasks")
+
+	// Resume the sub tasks by their dependencies, the successful ones are skipped.
+	if err := runSubTasks(ctx, t, func(ctx context.Context, subTask Task) {
+		ResumeTask(ctx, subTask)
+	}, func(subTask Task) bool {
+		return subTask.GetStatus() == TaskSuccessful
+	}); err != nil {
+		return err
+	}
+
+	logger.Debug("Finish resuming sub tasks")
+	return nil
+}
+
+// Resume the actions of a task, the done actions will be skipped.
+func resumeActions(ctx context.Context, t Task) error {
+	logger := logrus.WithFields(logrus.Fields{
+		consts.LogFieldTask: t.GetName(),
+	})
+
+	if len(t.GetActions()) == 0 {
+		logger.Debug("No action")
+		return nil
+	}
+
+	logger.Debug("Start to resume actions")
+
+	var actions []action.Action
+	for _, act := range t.GetActions() {
+		if act.GetStatus() == action.ActionDone {
+			continue
+		}
+		act.SetStatus(action.ActionPending)
+		act.SetErr(nil)
+		actions = append(actions, act)
+	}
+	runActions(ctx, t, actions)
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %s", consts.MsgTaskCancelled, err)
+	}
+
+	logger.Debug("Finish to resume actions")
+	return nil
+}
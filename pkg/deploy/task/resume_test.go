@@ -0,0 +1,184 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/action"
+	pb "github.com/kpaas-io/kpaas/pkg/deploy/protos"
+)
+
+// Mockup an action and excutor which records the executed actions
+const ActionTypeTestResumeMockup action.Type = "ActionTypeMockupForResumeTest"
+
+type actionMockupForResumeTest struct {
+	action.Base
+}
+type executorMockupForResumeTest struct {
+	sync.Mutex
+	executed []string
+}
+
+func (e *executorMockupForResumeTest) Execute(ctx context.Context, act action.Action) *pb.Error {
+	e.Lock()
+	defer e.Unlock()
+	e.executed = append(e.executed, act.GetNode().GetName())
+	return nil
+}
+
+// Mockup a parent task type and a leaf task type
+const TaskTypeTestResumeMockupParent Type = "TaskTypeMockupForResumeTestParent"
+const TaskTypeTestResumeMockupLeaf Type = "TaskTypeMockupForResumeTestLeaf"
+
+type taskMockupForResumeTest struct {
+	Base
+}
+
+type processorMockupForResumeTestParent struct{}
+type processorMockupForResumeTestLeaf struct{}
+
+func (p *processorMockupForResumeTestParent) SplitTask(t Task) error {
+	return fmt.Errorf("the parent task should not be split again")
+}
+
+func (p *processorMockupForResumeTestLeaf) SplitTask(t Task) error {
+	tsk, ok := t.(*taskMockupForResumeTest)
+	if !ok {
+		return fmt.Errorf("mismatched task type")
+	}
+
+	// Split it into one mockup action for each node
+	tsk.Actions = []action.Action{
+		newActionMockupForResumeTest("node1", action.ActionPending),
+		newActionMockupForResumeTest("node2", action.ActionPending),
+	}
+	return nil
+}
+
+func newActionMockupForResumeTest(nodeName string, status action.Status) action.Action {
+	return &actionMockupForResumeTest{
+		Base: action.Base{
+			Name:       fmt.Sprintf("action-%s", nodeName),
+			ActionType: ActionTypeTestResumeMockup,
+			Status:     status,
+			Node:       &pb.Node{Name: nodeName},
+		},
+	}
+}
+
+func TestResumeTask(t *testing.T) {
+	executor := new(executorMockupForResumeTest)
+	err := action.RegisterExecutor(ActionTypeTestResumeMockup, func() action.Executor { return executor })
+	assert.NoError(t, err)
+
+	err = RegisterProcessor(TaskTypeTestResumeMockupParent, new(processorMockupForResumeTestParent))
+	assert.NoError(t, err)
+	err = RegisterProcessor(TaskTypeTestResumeMockupLeaf, new(processorMockupForResumeTestLeaf))
+	assert.NoError(t, err)
+
+	// The first sub task was successful, the second one was failed on node2.
+	leaf1 := &taskMockupForResumeTest{
+		Base: Base{
+			Name:     "leaf1",
+			TaskType: TaskTypeTestResumeMockupLeaf,
+			Status:   TaskSuccessful,
+			Priority: 1,
+			Actions: []action.Action{
+				newActionMockupForResumeTest("node1", action.ActionDone),
+				newActionMockupForResumeTest("node2", action.ActionDone),
+			},
+		},
+	}
+	doneAction := newActionMockupForResumeTest("node1", action.ActionDone)
+	leaf2 := &taskMockupForResumeTest{
+		Base: Base{
+			Name:     "leaf2",
+			TaskType: TaskTypeTestResumeMockupLeaf,
+			Status:   TaskFailed,
+			Priority: 2,
+			Actions: []action.Action{
+				doneAction,
+				newActionMockupForResumeTest("node2", action.ActionFailed),
+			},
+		},
+	}
+	parent := &taskMockupForResumeTest{
+		Base: Base{
+			Name:     "parent",
+			TaskType: TaskTypeTestResumeMockupParent,
+			Status:   TaskFailed,
+			Err:      new(pb.Error),
+			SubTasks: []Task{leaf1, leaf2},
+		},
+	}
+
+	err = ResumeTask(context.Background(), parent)
+	assert.NoError(t, err)
+	assert.Equal(t, TaskSuccessful, parent.GetStatus())
+	assert.Nil(t, parent.GetErr())
+	assert.Equal(t, TaskSuccessful, leaf1.GetStatus())
+	assert.Equal(t, TaskSuccessful, leaf2.GetStatus())
+	assert.Equal(t, doneAction, leaf2.GetActions()[0])
+	for _, act := range GetAllActions(parent) {
+		assert.Equal(t, action.ActionDone, act.GetStatus())
+	}
+	// only the failed action is executed again
+	assert.Equal(t, []string{"node2"}, executor.executed)
+
+	// A successful task won't be executed again
+	err = ResumeTask(context.Background(), parent)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node2"}, executor.executed)
+
+	// cleanup
+	_processRegistry = nil
+}
+
+func TestStartResumeTask(t *testing.T) {
+	err := RegisterProcessor(TaskTypeTestResumeMockupParent, new(processorMockupForResumeTestParent))
+	assert.NoError(t, err)
+
+	tsk := &taskMockupForResumeTest{
+		Base: Base{
+			Name:     "parent",
+			TaskType: TaskTypeTestResumeMockupParent,
+			Status:   TaskDoing,
+		},
+	}
+	assert.Error(t, StartResumeTask(tsk))
+
+	tsk.SetStatus(TaskSuccessful)
+	assert.Error(t, StartResumeTask(tsk))
+	// the task name is not kept reserved by the rejected launches
+	assert.False(t, IsTaskRunning(tsk.GetName()))
+
+	// A running task can't be resumed again
+	tsk.SetStatus(TaskFailed)
+	_, release, err := newRunningContext(tsk)
+	assert.NoError(t, err)
+	assert.Error(t, StartResumeTask(tsk))
+	assert.Equal(t, TaskFailed, tsk.GetStatus())
+	release()
+	assert.False(t, IsTaskRunning(tsk.GetName()))
+
+	// cleanup
+	_processRegistry = nil
+}
@@ -16,6 +16,7 @@ package task
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -27,6 +28,8 @@ type Store interface {
 	UpdateTask(task Task) error
 	// Update a task, if the task doesn't exist (task name is same), add it.
 	UpdateOrAddTask(task Task) error
+	// List all the tasks, sorted by task name.
+	ListTasks() []Task
 }
 
 // A Store implementation via map
@@ -94,15 +97,39 @@ func (c *cache) UpdateOrAddTask(task Task) error {
 	return nil
 }
 
+func (c *cache) ListTasks() []Task {
+	c.RLock()
+	defer c.RUnlock()
+
+	tasks := make([]Task, 0, len(c.m))
+	for _, task := range c.m {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].GetName() < tasks[j].GetName()
+	})
+
+	return tasks
+}
+
 // global cache store
 var cacheStore *cache
 
 func init() {
-	cacheStore = &cache{
+	cacheStore = newCache()
+}
+
+func newCache() *cache {
+	return &cache{
 		m: make(map[string]Task),
 	}
 }
 
+// NewCacheStore returns a new Store implementation via map.
+func NewCacheStore() Store {
+	return newCache()
+}
+
 func GetGlobalCacheStore() Store {
 	return cacheStore
 }
@@ -0,0 +1,36 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheListTasks(t *testing.T) {
+	store := newCache()
+	assert.Len(t, store.ListTasks(), 0)
+
+	for _, name := range []string{"cluster2-deploy", "cluster1-deploy", "cluster1-node-check"} {
+		assert.NoError(t, store.AddTask(&DeployTask{Base: Base{Name: name}}))
+	}
+
+	tasks := store.ListTasks()
+	assert.Len(t, tasks, 3)
+	assert.Equal(t, "cluster1-deploy", tasks[0].GetName())
+	assert.Equal(t, "cluster1-node-check", tasks[1].GetName())
+	assert.Equal(t, "cluster2-deploy", tasks[2].GetName())
+}
@@ -16,6 +16,7 @@ package task
 
 import (
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy/action"
@@ -37,14 +38,29 @@ type Task interface {
 	// Sub tasks are Task too.
 	GetSubTasks() []Task
 	// GetPriority returns the priority of the task: smaller value means higher prioirty.
-	// A task should wait until all higher priority tasks are done
+	// A task should wait until all higher priority tasks are done. The priorities are used
+	// only if none of the sibling tasks declares its dependencies.
 	GetPriority() int
+	// GetDependencies returns the names of the sibling tasks which the task depends on,
+	// the task starts after all of them are successful.
+	GetDependencies() []string
 	// If a task is not a sub task, this will return ""
 	GetParent() string
 	// FailureCanBeIgnored indicate whether the failure of the task can be ingnored, if yes,
 	// the task's failure will not affect other task's execution.
 	GetFailureCanBeIgnored() bool
 	SetFailureCanBeIgnored(bool)
+	// The cluster which the task belongs to, tasks of different clusters can run side by side.
+	GetClusterID() string
+	SetClusterID(string)
+	// The journal of the status transitions, which is shared by the top level task with all
+	// its sub tasks.
+	GetJournal() *Journal
+	SetJournal(*Journal)
+	// Update changes the state of the task under its lock, View reads it under the lock, so that
+	// the task can be read by other goroutines, e.g. to be saved, while it's being executed.
+	Update(change func())
+	View(read func())
 }
 
 // Type represents the type of a task
@@ -65,15 +81,21 @@ const (
 type Base struct {
 	Name                string
 	TaskType            Type
-	Actions             []action.Action
+	Actions             []action.Action `json:"-"`
 	Status              Status
 	Err                 *pb.Error
 	LogFileDir          string
 	CreationTimestamp   time.Time
-	SubTasks            []Task
+	SubTasks            []Task `json:"-"`
 	Priority            int
+	Dependencies        []string
 	Parent              string
 	FailureCanBeIgnored bool
+	ClusterID           string
+
+	journal *Journal
+	// lock guards the state changed during the execution against the readers in other goroutines
+	lock sync.RWMutex
 }
 
 func (b *Base) GetName() string {
@@ -85,19 +107,34 @@ func (b *Base) GetType() Type {
 }
 
 func (b *Base) GetStatus() Status {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
 	return b.Status
 }
 
 func (b *Base) SetStatus(status Status) {
+	b.lock.Lock()
+	from := b.Status
 	b.Status = status
+	b.lock.Unlock()
+	if b.journal != nil && status != from {
+		b.journal.recordTask(b, from)
+	}
 }
 
 func (b *Base) GetErr() *pb.Error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
 	return b.Err
 }
 
 func (b *Base) SetErr(err *pb.Error) {
+	b.lock.Lock()
 	b.Err = err
+	b.lock.Unlock()
+	if b.journal != nil {
+		b.journal.recordTask(b, b.Status)
+	}
 }
 
 func (b *Base) GetLogFileDir() string {
@@ -109,21 +146,43 @@ func (b *Base) SetLogFileDir(path string) {
 }
 
 func (b *Base) GetActions() []action.Action {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
 	return b.Actions
 }
 
+// SetActions is called by the processor when the task is split.
+func (b *Base) SetActions(actions []action.Action) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.Actions = actions
+}
+
 func (b *Base) GetCreationTimestamp() time.Time {
 	return b.CreationTimestamp
 }
 
 func (b *Base) GetSubTasks() []Task {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
 	return b.SubTasks
 }
 
+// SetSubTasks is called by the processor when the task is split.
+func (b *Base) SetSubTasks(subTasks []Task) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.SubTasks = subTasks
+}
+
 func (b *Base) GetPriority() int {
 	return b.Priority
 }
 
+func (b *Base) GetDependencies() []string {
+	return b.Dependencies
+}
+
 func (b *Base) GetParent() string {
 	return b.Parent
 }
@@ -136,6 +195,38 @@ func (b *Base) SetFailureCanBeIgnored(val bool) {
 	b.FailureCanBeIgnored = val
 }
 
+func (b *Base) GetClusterID() string {
+	return b.ClusterID
+}
+
+func (b *Base) SetClusterID(id string) {
+	b.ClusterID = id
+}
+
+func (b *Base) GetJournal() *Journal {
+	return b.journal
+}
+
+func (b *Base) SetJournal(j *Journal) {
+	b.journal = j
+}
+
+// Update calls change under the lock of the task, change must not call the methods of the task
+// which take the lock as well.
+func (b *Base) Update(change func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	change()
+}
+
+// View calls read under the read lock of the task, read must not call the methods of the task
+// which take the lock as well.
+func (b *Base) View(read func()) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	read()
+}
+
 // GenTaskLogFileDir is a helper to return the log file dir based on base path and task name
 func GenTaskLogFileDir(basePath, taskName string) string {
 	if basePath == "" || taskName == "" {
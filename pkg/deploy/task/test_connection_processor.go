@@ -53,9 +53,9 @@ func (p *testConnectionProcessor) SplitTask(t Task) error {
 	if err != nil {
 		return err
 	}
-	testConnTask.Actions = append(testConnTask.Actions, act)
+	testConnTask.SetActions(append(testConnTask.GetActions(), act))
 
-	logrus.Debugf("Finish to split node check task: %d actions", len(testConnTask.Actions))
+	logrus.Debugf("Finish to split node check task: %d actions", len(testConnTask.GetActions()))
 	return nil
 }
 
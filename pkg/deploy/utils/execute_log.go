@@ -18,11 +18,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/kpaas-io/kpaas/pkg/deploy/consts"
 )
 
+const executeLogTimeFormat = "2006-01-02 15:04:05"
+
 // ExecuteLogItem a log item about executing a command.
 type ExecuteLogItem struct {
 	StartTime   time.Time
@@ -32,6 +35,10 @@ type ExecuteLogItem struct {
 	Stderr      []byte
 	Err         error // other error messages
 	Description string
+	TimedOut    bool          // whether the command is killed because of timeout
+	Timeout     time.Duration // the timeout of the command
+	Attempt     int           // the attempt of the command, which counts from 1
+	MaxAttempts int           // the attempt is written only if the command can be retried
 }
 
 // WriteExecuteLog write an item into writer.
@@ -40,6 +47,43 @@ func WriteExecuteLog(w io.Writer, item *ExecuteLogItem) {
 		return
 	}
 	buf := &bytes.Buffer{}
+	writeExecuteLogHeader(buf, item)
+	// write stderr
+	buf.Write([]byte("[stderr]\n"))
+	buf.Write(item.Stderr)
+	buf.Write([]byte("\n"))
+	// write stdout
+	buf.Write([]byte("[stdout]\n"))
+	buf.Write(item.Stdout)
+	buf.Write([]byte("\n"))
+	writeExecuteLogFooter(buf, item)
+	w.Write(buf.Bytes())
+}
+
+// WriteExecuteLogHeader writes the description, start time and command of the item into writer,
+// it's written before the command starts if the output is streamed.
+func WriteExecuteLogHeader(w io.Writer, item *ExecuteLogItem) {
+	if w == nil || item == nil {
+		return
+	}
+	buf := &bytes.Buffer{}
+	writeExecuteLogHeader(buf, item)
+	buf.Write([]byte("[output]\n"))
+	w.Write(buf.Bytes())
+}
+
+// WriteExecuteLogFooter writes the error and end time of the item into writer,
+// it's written after the command finishes if the output is streamed.
+func WriteExecuteLogFooter(w io.Writer, item *ExecuteLogItem) {
+	if w == nil || item == nil {
+		return
+	}
+	buf := &bytes.Buffer{}
+	writeExecuteLogFooter(buf, item)
+	w.Write(buf.Bytes())
+}
+
+func writeExecuteLogHeader(buf *bytes.Buffer, item *ExecuteLogItem) {
 	buf.Write([]byte(consts.DashLine + "\n"))
 	// write description
 	if item.Description != "" {
@@ -47,30 +91,86 @@ func WriteExecuteLog(w io.Writer, item *ExecuteLogItem) {
 		buf.Write([]byte(item.Description + "\n"))
 	}
 	// write start time
-	startTimeMsg := fmt.Sprintf("[start time] %s\n", item.StartTime.Format(
-		"2006-01-02 15:04:05"))
+	startTimeMsg := fmt.Sprintf("[start time] %s\n", item.StartTime.Format(executeLogTimeFormat))
 	buf.Write([]byte(startTimeMsg))
 	// write command
 	buf.Write([]byte(fmt.Sprintf("[command] %s\n", item.Command)))
-	// write stderr
-	buf.Write([]byte("[stderr]\n"))
-	buf.Write(item.Stderr)
-	buf.Write([]byte("\n"))
-	// write stdout
-	buf.Write([]byte("[stdout]\n"))
-	buf.Write(item.Stdout)
-	buf.Write([]byte("\n"))
+	// write attempt
+	if item.MaxAttempts > 1 {
+		buf.Write([]byte(fmt.Sprintf("[attempt] %d/%d\n", item.Attempt, item.MaxAttempts)))
+	}
+}
+
+func writeExecuteLogFooter(buf *bytes.Buffer, item *ExecuteLogItem) {
 	// write error message
 	if item.Err != nil {
 		buf.Write([]byte("[error]\n"))
 		buf.Write([]byte(item.Err.Error()))
 		buf.Write([]byte("\n"))
 	}
+	// write timeout
+	if item.TimedOut {
+		buf.Write([]byte(fmt.Sprintf("[timeout] killed after %v\n", item.Timeout)))
+	}
 	// write end time
-	endTimeMsg := fmt.Sprintf("[end time] %s\n", item.EndTime.Format(
-		"2006-01-02 15:04:05"))
+	endTimeMsg := fmt.Sprintf("[end time] %s\n", item.EndTime.Format(executeLogTimeFormat))
 	buf.Write([]byte(endTimeMsg))
 	// write an extra empty line
 	buf.Write([]byte("\n"))
-	w.Write(buf.Bytes())
+}
+
+// NewStreamLogWriters returns the writers for the stdout and stderr of a running command, which write
+// the output into w line by line as they arrive, each line is prefixed with the time and the stream
+// name, like "2019-12-19 19:00:00 [stdout] a b c". The writers are safe to be used concurrently,
+// and should be flushed after the command finishes to write the last line without a line break.
+func NewStreamLogWriters(w io.Writer) (stdout, stderr *StreamLogWriter) {
+	lock := new(sync.Mutex)
+	return &StreamLogWriter{w: w, lock: lock, stream: "stdout", now: time.Now},
+		&StreamLogWriter{w: w, lock: lock, stream: "stderr", now: time.Now}
+}
+
+// StreamLogWriter writes the output of a stream into the execute log line by line.
+type StreamLogWriter struct {
+	w      io.Writer
+	lock   *sync.Mutex // shared by the writers of the same execute log
+	stream string
+	now    func() time.Time
+
+	partial []byte // the incomplete last line
+}
+
+func (sw *StreamLogWriter) Write(p []byte) (int, error) {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+
+	data := append(sw.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		sw.writeLine(data[:i])
+		data = data[i+1:]
+	}
+	sw.partial = append([]byte(nil), data...)
+
+	return len(p), nil
+}
+
+// Flush writes the incomplete last line.
+func (sw *StreamLogWriter) Flush() {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+
+	if len(sw.partial) > 0 {
+		sw.writeLine(sw.partial)
+		sw.partial = nil
+	}
+}
+
+func (sw *StreamLogWriter) writeLine(line []byte) {
+	if sw.w == nil {
+		return
+	}
+	sw.w.Write([]byte(fmt.Sprintf("%s [%s] %s\n", sw.now().Format(executeLogTimeFormat), sw.stream, line)))
 }
@@ -17,6 +17,8 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -73,6 +75,31 @@ mkdir: cannot create directory ‘/etc/l78z/config’: No such file or directory
 exit code 1
 [end time] 2019-12-19 19:00:00
 
+`,
+		},
+		{
+			input: &ExecuteLogItem{
+				StartTime: time.Date(2019, 12, 19, 19, 0, 0, 0, time.Local),
+				EndTime:   time.Date(2019, 12, 19, 19, 30, 0, 0, time.Local),
+				Command:   "yum install -y kubelet",
+				Stdout:    []byte{},
+				Stderr:    []byte{},
+				Err:       fmt.Errorf("timed out"),
+				TimedOut:  true,
+				Timeout:   30 * time.Minute,
+			},
+			want: consts.DashLine + `
+[start time] 2019-12-19 19:00:00
+[command] yum install -y kubelet
+[stderr]
+
+[stdout]
+
+[error]
+timed out
+[timeout] killed after 30m0s
+[end time] 2019-12-19 19:30:00
+
 `,
 		},
 	}
@@ -82,3 +109,46 @@ exit code 1
 		assert.Equal(t, testCase.want, buf.String())
 	}
 }
+
+func TestStreamLogWriters(t *testing.T) {
+	buf := &bytes.Buffer{}
+	stdout, stderr := NewStreamLogWriters(buf)
+	now := func() time.Time { return time.Date(2019, 12, 19, 19, 0, 0, 0, time.Local) }
+	stdout.now, stderr.now = now, now
+
+	stdout.Write([]byte("a b"))
+	stdout.Write([]byte(" c\nd e"))
+	stderr.Write([]byte("warning\n"))
+	stdout.Write([]byte(" f\ng"))
+	assert.Equal(t, `2019-12-19 19:00:00 [stdout] a b c
+2019-12-19 19:00:00 [stderr] warning
+2019-12-19 19:00:00 [stdout] d e f
+`, buf.String())
+
+	stdout.Flush()
+	stderr.Flush()
+	assert.True(t, strings.HasSuffix(buf.String(), "2019-12-19 19:00:00 [stdout] g\n"))
+}
+
+func TestStreamLogWritersConcurrently(t *testing.T) {
+	buf := &bytes.Buffer{}
+	stdout, stderr := NewStreamLogWriters(buf)
+
+	wg := sync.WaitGroup{}
+	for _, w := range []*StreamLogWriter{stdout, stderr} {
+		wg.Add(1)
+		go func(w *StreamLogWriter) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				w.Write([]byte("line\n"))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 200)
+	for _, line := range lines {
+		assert.Regexp(t, `^\S+ \S+ \[(stdout|stderr)\] line$`, line)
+	}
+}
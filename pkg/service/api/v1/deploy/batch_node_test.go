@@ -315,15 +315,15 @@ k8s-master1   root	    master,etcd          192.168.3.223    9999999999999999999
 
 	for _, test := range tests {
 
-		wizard.ClearCurrentWizardData()
-		wizardData := wizard.GetCurrentWizard()
+		wizardData := wizard.NewCluster()
 		wizardData.Nodes = test.BaseNodeList
 		var err error
 		resp := httptest.NewRecorder()
 		gin.SetMode(gin.TestMode)
 		ctx, _ := gin.CreateTestContext(resp)
+		setCluster(ctx, wizardData)
 		bodyReader := strings.NewReader(test.Input)
-		ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/batchnodes", bodyReader)
+		ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/batchnodes", bodyReader)
 
 		UploadBatchNodes(ctx)
 
@@ -370,6 +370,7 @@ k8s-master1   root	    master,etcd          192.168.3.223    22          1111111
 					"111111111111",
 					"-",
 					"/var/lib/docker",
+					"",
 				},
 			},
 			WantGroupNames: []string{
@@ -382,6 +383,39 @@ k8s-master1   root	    master,etcd          192.168.3.223    22          1111111
 				"password",
 				"privateKeyName",
 				"dockerPath",
+				"jumpHosts",
+			},
+		},
+		{
+			Input: []byte(`
+#<hostname> <user>  <role,role,role>     <IP>          <ssh port>  <password>  <login key name>  <docker path>      <jump hosts>
+k8s-worker1   root	    worker          10.0.3.231    22          -	      worker_key		                  /var/lib/docker    ops@192.168.3.1:2222/bastion_key,root@10.0.0.1
+`),
+			WantMatches: [][]string{
+				{
+					"k8s-worker1   root	    worker          10.0.3.231    22          -	      worker_key		                  /var/lib/docker    ops@192.168.3.1:2222/bastion_key,root@10.0.0.1",
+					"k8s-worker1",
+					"root",
+					"worker",
+					"10.0.3.231",
+					"22",
+					"-",
+					"worker_key",
+					"/var/lib/docker",
+					"ops@192.168.3.1:2222/bastion_key,root@10.0.0.1",
+				},
+			},
+			WantGroupNames: []string{
+				"",
+				"nodeName",
+				"username",
+				"roles",
+				"ip",
+				"port",
+				"password",
+				"privateKeyName",
+				"dockerPath",
+				"jumpHosts",
 			},
 		},
 	}
@@ -394,6 +428,45 @@ k8s-master1   root	    master,etcd          192.168.3.223    22          1111111
 	}
 }
 
+func TestParseInputJumpHosts(t *testing.T) {
+
+	nodeLoginData := api.SSHLoginData{
+		Username:           "root",
+		AuthenticationType: api.AuthenticationTypePassword,
+		Password:           "123456",
+	}
+
+	jumpHosts, err := parseInputJumpHosts("-", nodeLoginData)
+	assert.Nil(t, err)
+	assert.Nil(t, jumpHosts)
+
+	jumpHosts, err = parseInputJumpHosts("ops@192.168.3.1:2222/bastion_key,admin@10.0.0.1", nodeLoginData)
+	assert.Nil(t, err)
+	assert.Equal(t, []api.JumpHost{
+		{
+			SSHLoginData: api.SSHLoginData{
+				Username:           "ops",
+				AuthenticationType: api.AuthenticationTypePrivateKey,
+				PrivateKeyName:     "bastion_key",
+			},
+			IP:   "192.168.3.1",
+			Port: 2222,
+		},
+		{
+			SSHLoginData: api.SSHLoginData{
+				Username:           "admin",
+				AuthenticationType: api.AuthenticationTypePassword,
+				Password:           "123456",
+			},
+			IP:   "10.0.0.1",
+			Port: 22,
+		},
+	}, jumpHosts)
+
+	_, err = parseInputJumpHosts("192.168.3.1:2222", nodeLoginData)
+	assert.NotNil(t, err)
+}
+
 func TestSplitInputRoles(t *testing.T) {
 
 	tests := []struct {
@@ -36,9 +36,10 @@ import (
 // @Tags nodes
 // @Accept text/plain
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Param nodes body string true "node list"
 // @Success 201 {object} api.GetNodeListResponse
-// @Router /api/v1/deploy/wizard/batchnodes [post]
+// @Router /api/v1/deploy/wizard/clusters/{id}/batchnodes [post]
 func UploadBatchNodes(c *gin.Context) {
 
 	requestData, err := getUploadBatchNodesRequestData(c)
@@ -66,11 +67,12 @@ func UploadBatchNodes(c *gin.Context) {
 		case api.AuthenticationTypePrivateKey:
 			node.PrivateKeyName = data.PrivateKeyName
 		}
+		node.JumpHosts = convertAPIJumpHostsToModelJumpHosts(data.JumpHosts)
 
 		nodeList = append(nodeList, node)
 	}
 
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := getCluster(c)
 	err = wizardData.AddNodeList(nodeList)
 	if err != nil {
 
@@ -78,7 +80,7 @@ func UploadBatchNodes(c *gin.Context) {
 		return
 	}
 
-	responseNodeList := getWizardNodes()
+	responseNodeList := getWizardNodes(wizardData)
 	h.R(c, api.GetNodeListResponse{
 		Nodes: *responseNodeList,
 	})
@@ -101,6 +103,12 @@ func getUploadBatchNodesRequestData(c *gin.Context) (nodeList []*api.NodeData, e
 	k8s-worker1   root	    worker,etcd          192.168.3.226    22          -	                  worker_key 	          /var/lib/docker
 	k8s-worker2   root	    worker,etcd          192.168.3.229    22          -	                  worker_key 	          /var/lib/docker
 	k8s-worker3   root	    worker               192.168.3.230    22          -	                  worker_key 	          /var/lib/docker
+
+	The optional last column is the jump hosts, which are separated by comma in connecting order,
+	each of them is in the form of <user>@<IP>[:<ssh port>][/<login key name>], the node's password
+	or login key is used if the login key name is omitted.
+	#<hostname> <user>  <role,role,role>         <IP>             <ssh port>  <password>          <login key name>        <docker path>      <jump hosts>
+	k8s-worker4   root	    worker               10.0.3.231       22          -	                  worker_key 	          /var/lib/docker    ops@192.168.3.1:2222/bastion_key
 	*/
 	matches, groupNames := tryToMatchBatchNodes(data)
 
@@ -150,6 +158,12 @@ func getUploadBatchNodesRequestData(c *gin.Context) (nodeList []*api.NodeData, e
 			return
 		}
 
+		var jumpHosts []api.JumpHost
+		jumpHosts, err = parseInputJumpHosts(matchMap["jumpHosts"], loginData)
+		if err != nil {
+			return
+		}
+
 		node := &api.NodeData{
 			NodeBaseData: api.NodeBaseData{
 				Name:                matchMap["nodeName"],
@@ -160,6 +174,7 @@ func getUploadBatchNodesRequestData(c *gin.Context) (nodeList []*api.NodeData, e
 				SSHLoginData: loginData,
 				IP:           matchMap["ip"],
 				Port:         uint16(port),
+				JumpHosts:    jumpHosts,
 			},
 		}
 		log.ReqEntry(c).Tracef("node: %#v", node)
@@ -209,6 +224,49 @@ func splitInputRoles(matchMap map[string]string) []constant.MachineRole {
 	return roles
 }
 
+// parseInputJumpHosts parses the jump hosts column, like "ops@192.168.3.1:2222/bastion_key,root@10.0.0.1",
+// the node's login data is used for the jump host without login key name.
+func parseInputJumpHosts(input string, nodeLoginData api.SSHLoginData) ([]api.JumpHost, error) {
+
+	if input == "" || input == "-" {
+		return nil, nil
+	}
+
+	re := regexp.MustCompile(`^(?P<username>[\w\-]+)@(?P<ip>[\d.]+)(?::(?P<port>\d+))?(?:/(?P<privateKeyName>[\-\w]+))?$`)
+	jumpHosts := make([]api.JumpHost, 0)
+	for _, item := range strings.Split(input, ",") {
+
+		match := re.FindStringSubmatch(item)
+		if match == nil {
+			return nil, fmt.Errorf("invalid jump host: %s", item)
+		}
+
+		port := 22
+		if match[3] != "" {
+			var err error
+			if port, err = strconv.Atoi(match[3]); err != nil {
+				return nil, err
+			}
+		}
+
+		loginData := nodeLoginData
+		loginData.Username = match[1]
+		if match[4] != "" {
+			loginData.AuthenticationType = api.AuthenticationTypePrivateKey
+			loginData.PrivateKeyName = match[4]
+			loginData.Password = ""
+		}
+
+		jumpHosts = append(jumpHosts, api.JumpHost{
+			SSHLoginData: loginData,
+			IP:           match[2],
+			Port:         uint16(port),
+		})
+	}
+
+	return jumpHosts, nil
+}
+
 func tryToMatchBatchNodes(data []byte) ([][]string, []string) {
 	re := regexp.MustCompile(`(?m)^\s*` +
 		`(?P<nodeName>[\w\-]+)\s+` +
@@ -218,7 +276,8 @@ func tryToMatchBatchNodes(data []byte) ([][]string, []string) {
 		`(?P<port>[\d]+)\s+` +
 		`(?P<password>[\w` + "`" + `~!@#$%^&*()\-+=\\|\[\]{};:'",./<>?]+)\s+` +
 		`(?P<privateKeyName>[\-\w]+)\s+` +
-		`(?P<dockerPath>[\w\-\/]+)`,
+		`(?P<dockerPath>[\w\-\/]+)` +
+		`(?:[ \t]+(?P<jumpHosts>[\w\-.@:/,]+))?`,
 	)
 	return re.FindAllStringSubmatch(string(data), -1), re.SubexpNames()
 }
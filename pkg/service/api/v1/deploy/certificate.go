@@ -40,7 +40,17 @@ func AddSSHCertificate(c *gin.Context) {
 		return
 	}
 
-	sshcertificate.AddCertificate(requestData.Name, requestData.Content)
+	err := sshcertificate.SaveCertificate(&sshcertificate.Certificate{
+		Name:        requestData.Name,
+		PrivateKey:  requestData.Content,
+		Passphrase:  requestData.Passphrase,
+		Certificate: requestData.Certificate,
+	})
+	if err != nil {
+		h.E(c, h.ERepositoryError.WithPayload(err))
+		log.ReqEntry(c).Errorf("store ssh certificate error: %v", err)
+		return
+	}
 
 	h.R(c, api.SuccessfulOption{Success: true})
 }
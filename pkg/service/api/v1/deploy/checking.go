@@ -37,11 +37,12 @@ import (
 // @Description Check if the node meets the pre-deployment requirements
 // @Tags checking
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 201 {object} api.SuccessfulOption
-// @Router /api/v1/deploy/wizard/checks [post]
+// @Router /api/v1/deploy/wizard/clusters/{id}/checks [post]
 func CheckNodeList(c *gin.Context) {
 
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := getCluster(c)
 	if len(wizardData.Nodes) <= 0 {
 		h.E(c, h.ENotFound.WithPayload("No node information, node list is empty, please add node information"))
 		return
@@ -52,7 +53,7 @@ func CheckNodeList(c *gin.Context) {
 		return
 	}
 
-	if !checkClusterConfiguration() {
+	if !checkClusterConfiguration(wizardData) {
 
 		// Cluster Configuration check failed, no need to check the nodes
 		// Return true because this is a go check trigger API
@@ -72,7 +73,7 @@ func CheckNodeList(c *gin.Context) {
 	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
 	defer cancel()
 
-	resp, err := client.CheckNodes(grpcContext, getCallCheckNodesData())
+	resp, err := client.CheckNodes(grpcContext, getCallCheckNodesData(wizardData))
 	if err != nil {
 		h.E(c, h.EDeployControllerError.WithPayload(err))
 		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
@@ -85,7 +86,7 @@ func CheckNodeList(c *gin.Context) {
 		log.ReqEntry(c).Errorf("call deploy controller result error, error: %#v", resp.GetErr())
 	}
 
-	go listenCheckNodesData()
+	go listenCheckNodesData(wizardData)
 
 	h.R(c, api.SuccessfulOption{Success: resp.GetAccepted()})
 }
@@ -95,25 +96,27 @@ func CheckNodeList(c *gin.Context) {
 // @Description Get the result of the check node
 // @Tags checking
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 200 {object} api.GetCheckingResultResponse
-// @Router /api/v1/deploy/wizard/checks [get]
+// @Router /api/v1/deploy/wizard/clusters/{id}/checks [get]
 func GetCheckingNodeListResult(c *gin.Context) {
 
 	responseData := new(api.GetCheckingResultResponse)
-	wizardData := wizard.GetCurrentWizard()
-	checkResults := getWizardCheckingData()
+	wizardData := getCluster(c)
+	checkResults := getWizardCheckingData(wizardData)
 	responseData.Nodes = *checkResults
 	responseData.Result = wizardData.GetCheckResult()
-	responseData.Cluster = getCheckedClusterConfiguration()
+	responseData.Cluster = getCheckedClusterConfiguration(wizardData)
 
 	h.R(c, responseData)
 }
 
-func getCallCheckNodesData() *protos.CheckNodesRequest {
+func getCallCheckNodesData(wizardData *wizard.Cluster) *protos.CheckNodesRequest {
 
-	requestData := &protos.CheckNodesRequest{}
+	requestData := &protos.CheckNodesRequest{
+		ClusterId: getDeployClusterId(wizardData),
+	}
 
-	wizardData := wizard.GetCurrentWizard()
 	for _, node := range wizardData.Nodes {
 
 		nodeConfig := new(protos.NodeCheckConfig)
@@ -122,9 +125,10 @@ func getCallCheckNodesData() *protos.CheckNodesRequest {
 		}
 
 		nodeConfig.Node = &protos.Node{
-			Name: node.Name,
-			Ip:   node.IP,
-			Ssh:  convertModelConnectionDataToDeployControllerSSHData(&node.ConnectionData),
+			Name:      node.Name,
+			Ip:        node.IP,
+			Ssh:       convertModelConnectionDataToDeployControllerSSHData(&node.ConnectionData),
+			Localhost: node.Localhost,
 		}
 
 		requestData.Configs = append(requestData.Configs, nodeConfig)
@@ -133,33 +137,34 @@ func getCallCheckNodesData() *protos.CheckNodesRequest {
 	return requestData
 }
 
-func listenCheckNodesData() {
+func listenCheckNodesData(wizardData *wizard.Cluster) {
 
-	wizardData := wizard.GetCurrentWizard()
 	for {
 		if wizardData.GetCheckResult() != constant.CheckResultRunning {
 			break
 		}
 
-		refreshCheckResultOneTime()
+		refreshCheckResultOneTime(wizardData)
+		saveCluster(wizardData)
 		time.Sleep(time.Second)
 	}
 }
 
-func refreshCheckResultOneTime() {
+func refreshCheckResultOneTime(wizardData *wizard.Cluster) {
 
 	client := clientUtils.GetDeployController()
 
 	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
 	defer cancel()
 
-	resp, err := client.GetCheckNodesResult(grpcContext, &protos.GetCheckNodesResultRequest{})
+	resp, err := client.GetCheckNodesResult(grpcContext, &protos.GetCheckNodesResultRequest{
+		ClusterId: getDeployClusterId(wizardData),
+	})
 	if err != nil {
 		logrus.Errorf("call deploy controller error, errorMessage: %v", err)
 		return
 	}
 
-	wizardData := wizard.GetCurrentWizard()
 	wizardData.SetClusterCheckResult(
 		convertDeployControllerCheckResultToModelCheckResult(resp.GetStatus()),
 		convertDeployControllerErrorToFailureDetail(resp.GetErr()))
@@ -214,15 +219,14 @@ func getItemNameFromDeployControllerCheckItem(item *protos.CheckItem) string {
 	return item.Description
 }
 
-func checkClusterConfiguration() bool {
+func checkClusterConfiguration(wizardData *wizard.Cluster) bool {
 
-	return len(checkWrongClusterConfiguration()) <= 0
+	return len(checkWrongClusterConfiguration(wizardData)) <= 0
 }
 
-func checkWrongClusterConfiguration() (errs []*api.CheckingItem) {
+func checkWrongClusterConfiguration(wizardData *wizard.Cluster) (errs []*api.CheckingItem) {
 
 	errs = make([]*api.CheckingItem, 0)
-	wizardData := wizard.GetCurrentWizard()
 	if len(wizardData.Nodes) <= 0 {
 
 		errs = append(errs, &api.CheckingItem{
@@ -270,8 +274,8 @@ func checkWrongClusterConfiguration() (errs []*api.CheckingItem) {
 	return errs
 }
 
-func getCheckedClusterConfiguration() api.CheckClusterResponseData {
+func getCheckedClusterConfiguration(wizardData *wizard.Cluster) api.CheckClusterResponseData {
 	return api.CheckClusterResponseData{
-		Items: checkWrongClusterConfiguration(),
+		Items: checkWrongClusterConfiguration(wizardData),
 	}
 }
@@ -34,8 +34,7 @@ import (
 
 func TestCheckNodeList(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	mockNode := wizard.NewNode()
 	mockNode.Name = "master1"
 	wizardData.Nodes = []*wizard.Node{mockNode}
@@ -46,7 +45,8 @@ func TestCheckNodeList(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/checks", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/checks", nil)
 
 	CheckNodeList(ctx)
 	resp.Flush()
@@ -61,7 +61,7 @@ func TestCheckNodeList(t *testing.T) {
 
 func TestCheckNodeList2(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
+	wizardData := wizard.NewCluster()
 
 	grpcClient.SetDeployController(mock.NewDeployController())
 
@@ -69,7 +69,8 @@ func TestCheckNodeList2(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/checks", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/checks", nil)
 
 	CheckNodeList(ctx)
 	resp.Flush()
@@ -84,8 +85,7 @@ func TestCheckNodeList2(t *testing.T) {
 
 func TestGetCheckingNodeListResult(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.ClusterCheckResult = constant.CheckResultSuccessful
 	wizardData.Nodes = []*wizard.Node{
 		{
@@ -110,7 +110,8 @@ func TestGetCheckingNodeListResult(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/checks", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/checks", nil)
 
 	GetCheckingNodeListResult(ctx)
 	resp.Flush()
@@ -32,11 +32,12 @@ import (
 // @Tags cluster
 // @Accept application/json
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Param cluster body api.Cluster true "RequiredFields: shortName, name, kubeAPIServerConnectType"
-// @Success 201 {object} api.SuccessfulOption
+// @Success 200 {object} api.SuccessfulOption
 // @Failure 400 {object} h.AppErr
 // @Failure 409 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/clusters [post]
+// @Router /api/v1/deploy/wizard/clusters/{id} [put]
 func SetCluster(c *gin.Context) {
 
 	requestData, hasError := getClusterRequestData(c)
@@ -44,7 +45,7 @@ func SetCluster(c *gin.Context) {
 		return
 	}
 
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := getCluster(c)
 	if err := initDefaultNodePort(requestData, wizardData); err != nil {
 		log.ReqEntry(c).Info(err)
 		h.E(c, err)
@@ -108,11 +109,12 @@ func initDefaultNodePort(requestData *api.Cluster, wizardData *wizard.Cluster) e
 // @Description Describe cluster information
 // @Tags cluster
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 200 {object} api.Cluster
-// @Router /api/v1/deploy/wizard/clusters [get]
+// @Router /api/v1/deploy/wizard/clusters/{id} [get]
 func GetCluster(c *gin.Context) {
 
-	clusterInfo := getWizardClusterInfo()
+	clusterInfo := getWizardClusterInfo(getCluster(c))
 
 	h.R(c, clusterInfo)
 }
@@ -30,10 +30,12 @@ import (
 
 func TestSetCluster(t *testing.T) {
 
+	wizardData := wizard.NewCluster()
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
 	body := api.Cluster{
 		Name:                     "cluster-name",
 		ShortName:                "short-name",
@@ -56,7 +58,7 @@ func TestSetCluster(t *testing.T) {
 	bodyContent, err := json.Marshal(body)
 	assert.Nil(t, err)
 	bodyReader := bytes.NewReader(bodyContent)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters", bodyReader)
+	ctx.Request = httptest.NewRequest("PUT", "/api/v1/deploy/wizard/clusters/1", bodyReader)
 
 	SetCluster(ctx)
 	resp.Flush()
@@ -68,7 +70,6 @@ func TestSetCluster(t *testing.T) {
 
 	assert.True(t, responseData.Success)
 
-	wizardData := wizard.GetCurrentWizard()
 	assert.Equal(t, "cluster-name", wizardData.Info.Name)
 	assert.Equal(t, "short-name", wizardData.Info.ShortName)
 	assert.Equal(t, wizard.KubeAPIServerConnectTypeFirstMasterIP, wizardData.Info.KubeAPIServerConnection.KubeAPIServerConnectType)
@@ -84,10 +85,12 @@ func TestSetCluster(t *testing.T) {
 
 func TestSetCluster2(t *testing.T) {
 
+	wizardData := wizard.NewCluster()
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
 	body := api.Cluster{
 		Name:                     "cluster-name",
 		ShortName:                "short-name",
@@ -112,7 +115,7 @@ func TestSetCluster2(t *testing.T) {
 	bodyContent, err := json.Marshal(body)
 	assert.Nil(t, err)
 	bodyReader := bytes.NewReader(bodyContent)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters", bodyReader)
+	ctx.Request = httptest.NewRequest("PUT", "/api/v1/deploy/wizard/clusters/1", bodyReader)
 
 	SetCluster(ctx)
 	resp.Flush()
@@ -124,7 +127,6 @@ func TestSetCluster2(t *testing.T) {
 
 	assert.True(t, responseData.Success)
 
-	wizardData := wizard.GetCurrentWizard()
 	assert.Equal(t, "cluster-name", wizardData.Info.Name)
 	assert.Equal(t, "short-name", wizardData.Info.ShortName)
 	assert.Equal(t, wizard.KubeAPIServerConnectTypeKeepalived, wizardData.Info.KubeAPIServerConnection.KubeAPIServerConnectType)
@@ -140,10 +142,12 @@ func TestSetCluster2(t *testing.T) {
 
 func TestSetCluster3(t *testing.T) {
 
+	wizardData := wizard.NewCluster()
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
 	body := api.Cluster{
 		Name:                     "cluster-name",
 		ShortName:                "short-name",
@@ -156,7 +160,7 @@ func TestSetCluster3(t *testing.T) {
 	bodyContent, err := json.Marshal(body)
 	assert.Nil(t, err)
 	bodyReader := bytes.NewReader(bodyContent)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters", bodyReader)
+	ctx.Request = httptest.NewRequest("PUT", "/api/v1/deploy/wizard/clusters/1", bodyReader)
 
 	SetCluster(ctx)
 	resp.Flush()
@@ -168,7 +172,6 @@ func TestSetCluster3(t *testing.T) {
 
 	assert.True(t, responseData.Success)
 
-	wizardData := wizard.GetCurrentWizard()
 	assert.Equal(t, "cluster-name", wizardData.Info.Name)
 	assert.Equal(t, "short-name", wizardData.Info.ShortName)
 	assert.Equal(t, wizard.KubeAPIServerConnectTypeLoadBalancer, wizardData.Info.KubeAPIServerConnection.KubeAPIServerConnectType)
@@ -182,12 +185,13 @@ func TestSetCluster3(t *testing.T) {
 
 func TestGetCluster(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
+	wizardData := wizard.NewCluster()
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1", nil)
 
 	GetCluster(ctx)
 	resp.Flush()
@@ -212,8 +216,7 @@ func TestGetCluster(t *testing.T) {
 
 func TestGetCluster2(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Info.ShortName = "test-cluster"
 	wizardData.Info.Name = "ClusterName"
 	wizardData.Info.KubeAPIServerConnection.KubeAPIServerConnectType = wizard.KubeAPIServerConnectTypeKeepalived
@@ -238,7 +241,8 @@ func TestGetCluster2(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1", nil)
 
 	GetCluster(ctx)
 	resp.Flush()
@@ -32,6 +32,8 @@ func convertModelAuthenticationTypeToAPIAuthenticationType(authenticationType wi
 		return api.AuthenticationTypePassword
 	case wizard.AuthenticationTypePrivateKey:
 		return api.AuthenticationTypePrivateKey
+	case wizard.AuthenticationTypeKeyboardInteractive:
+		return api.AuthenticationTypeKeyboardInteractive
 	}
 
 	return api.AuthenticationType(fmt.Sprintf("unknown(%s)", authenticationType))
@@ -144,6 +146,8 @@ func convertAPIAuthenticationTypeToModelAuthenticationType(authenticationType ap
 		return wizard.AuthenticationTypePassword
 	case api.AuthenticationTypePrivateKey:
 		return wizard.AuthenticationTypePrivateKey
+	case api.AuthenticationTypeKeyboardInteractive:
+		return wizard.AuthenticationTypeKeyboardInteractive
 	}
 
 	return wizard.AuthenticationType(fmt.Sprintf("unknown(%s)", authenticationType))
@@ -180,10 +184,64 @@ func convertModelNodeToAPINode(node *wizard.Node) *api.NodeData {
 				AuthenticationType: convertModelAuthenticationTypeToAPIAuthenticationType(node.AuthenticationType),
 				PrivateKeyName:     node.PrivateKeyName,
 			},
+			SudoData: api.SudoData{
+				Sudo: node.Sudo,
+			},
+			JumpHosts: convertModelJumpHostsToAPIJumpHosts(node.JumpHosts),
+			Localhost: node.Localhost,
 		},
 	}
 }
 
+func convertModelJumpHostsToAPIJumpHosts(jumpHosts []*wizard.JumpHost) []api.JumpHost {
+
+	if len(jumpHosts) == 0 {
+		return nil
+	}
+
+	apiJumpHosts := make([]api.JumpHost, 0, len(jumpHosts))
+	for _, jumpHost := range jumpHosts {
+		apiJumpHosts = append(apiJumpHosts, api.JumpHost{
+			IP:   jumpHost.IP,
+			Port: jumpHost.Port,
+			SSHLoginData: api.SSHLoginData{
+				Username:           jumpHost.Username,
+				AuthenticationType: convertModelAuthenticationTypeToAPIAuthenticationType(jumpHost.AuthenticationType),
+				PrivateKeyName:     jumpHost.PrivateKeyName,
+			},
+		})
+	}
+	return apiJumpHosts
+}
+
+func convertAPIJumpHostsToModelJumpHosts(jumpHosts []api.JumpHost) []*wizard.JumpHost {
+
+	if len(jumpHosts) == 0 {
+		return nil
+	}
+
+	modelJumpHosts := make([]*wizard.JumpHost, 0, len(jumpHosts))
+	for _, jumpHost := range jumpHosts {
+		modelJumpHost := &wizard.JumpHost{
+			IP:                 jumpHost.IP,
+			Port:               jumpHost.Port,
+			Username:           jumpHost.Username,
+			AuthenticationType: convertAPIAuthenticationTypeToModelAuthenticationType(jumpHost.AuthenticationType),
+		}
+		switch jumpHost.AuthenticationType {
+		case api.AuthenticationTypePassword:
+			modelJumpHost.Password = jumpHost.Password
+		case api.AuthenticationTypePrivateKey:
+			modelJumpHost.PrivateKeyName = jumpHost.PrivateKeyName
+		case api.AuthenticationTypeKeyboardInteractive:
+			modelJumpHost.Password = jumpHost.Password
+			modelJumpHost.ChallengeResponses = jumpHost.ChallengeResponses
+		}
+		modelJumpHosts = append(modelJumpHosts, modelJumpHost)
+	}
+	return modelJumpHosts
+}
+
 func convertDeployControllerErrorToAPIError(err *protos.Error) *api.Error {
 
 	if err == nil {
@@ -216,22 +274,55 @@ func convertModelConnectionDataToDeployControllerSSHData(data *wizard.Connection
 		return nil
 	}
 
+	var jumpHosts []*protos.JumpHost
+	for _, jumpHost := range data.JumpHosts {
+		jumpHosts = append(jumpHosts, &protos.JumpHost{
+			Ip:   jumpHost.IP,
+			Port: uint32(jumpHost.Port),
+			Auth: convertModelLoginDataToDeployControllerAuth(jumpHost.Username, jumpHost.AuthenticationType, jumpHost.Password, jumpHost.PrivateKeyName, jumpHost.ChallengeResponses),
+		})
+	}
+
+	var sudo *protos.Sudo
+	if data.Sudo {
+		sudo = &protos.Sudo{
+			Enabled:  true,
+			Password: data.SudoPassword,
+		}
+	}
+
+	return &protos.SSH{
+		Port:      uint32(data.Port),
+		Auth:      convertModelLoginDataToDeployControllerAuth(data.Username, data.AuthenticationType, data.Password, data.PrivateKeyName, data.ChallengeResponses),
+		JumpHosts: jumpHosts,
+		Sudo:      sudo,
+	}
+}
+
+func convertModelLoginDataToDeployControllerAuth(
+	username string, authenticationType wizard.AuthenticationType, password, privateKeyName string, challengeResponses []string) *protos.Auth {
+
 	auth := &protos.Auth{
-		Username: data.Username,
+		Username: username,
 	}
-	switch data.AuthenticationType {
+	switch authenticationType {
 	case wizard.AuthenticationTypePassword:
 		auth.Type = deployControllerAuthCredentialPassword
-		auth.Credential = data.Password
+		auth.Credential = password
 	case wizard.AuthenticationTypePrivateKey:
 		auth.Type = deployControllerAuthCredentialPrivateKey
-		auth.Credential = sshcertificate.GetPrivateKey(data.PrivateKeyName)
+		if certificate := sshcertificate.GetCertificate(privateKeyName); certificate != nil {
+			auth.Credential = certificate.PrivateKey
+			auth.Passphrase = certificate.Passphrase
+			auth.Certificate = certificate.Certificate
+		}
+	case wizard.AuthenticationTypeKeyboardInteractive:
+		auth.Type = deployControllerAuthCredentialKeyboardInteractive
+		auth.Credential = password
+		auth.ChallengeResponses = challengeResponses
 	}
 
-	return &protos.SSH{
-		Port: uint32(data.Port),
-		Auth: auth,
-	}
+	return auth
 }
 
 func convertDeployControllerCheckResultToModelCheckResult(status string) constant.CheckResult {
@@ -285,3 +376,76 @@ func convertDeployControllerDeployResultToModelDeployResult(status string) wizar
 
 	return wizard.DeployStatus(fmt.Sprintf("unknown(%s)", status))
 }
+
+func convertModelClusterToAPIClusterDraft(cluster *wizard.Cluster) api.ClusterDraft {
+
+	return api.ClusterDraft{
+		ClusterId:           cluster.ClusterId,
+		ShortName:           cluster.Info.ShortName,
+		Name:                cluster.Info.Name,
+		CheckResult:         cluster.GetCheckResult(),
+		DeployClusterStatus: convertModelDeployClusterStatusToAPIDeployClusterStatus(cluster.GetDeployClusterStatus()),
+	}
+}
+
+func convertDeployControllerTaskEventToAPITaskEvent(clusterId uint64, event *protos.TaskEvent) api.TaskEvent {
+
+	return api.TaskEvent{
+		ClusterId:  clusterId,
+		TaskName:   event.GetTaskName(),
+		TaskType:   event.GetTaskType(),
+		Kind:       event.GetKind(),
+		Name:       event.GetName(),
+		Parent:     event.GetParent(),
+		ActionType: event.GetActionType(),
+		NodeName:   event.GetNodeName(),
+		Status:     event.GetStatus(),
+		Error:      convertDeployControllerErrorToAPIError(event.GetErr()),
+		Timestamp:  event.GetTimestamp(),
+	}
+}
+
+func convertDeployControllerTaskPlanToAPITaskPlan(plan *protos.TaskPlan) *api.TaskPlan {
+
+	if plan == nil {
+		return nil
+	}
+
+	taskPlan := &api.TaskPlan{
+		Name:         plan.GetName(),
+		Type:         plan.GetType(),
+		Parent:       plan.GetParent(),
+		Dependencies: plan.GetDependencies(),
+		Error:        convertDeployControllerErrorToAPIError(plan.GetErr()),
+	}
+	for _, subTask := range plan.GetSubTasks() {
+		taskPlan.SubTasks = append(taskPlan.SubTasks, convertDeployControllerTaskPlanToAPITaskPlan(subTask))
+	}
+	for _, action := range plan.GetActions() {
+		taskPlan.Actions = append(taskPlan.Actions, convertDeployControllerActionPlanToAPIActionPlan(action))
+	}
+	return taskPlan
+}
+
+func convertDeployControllerActionPlanToAPIActionPlan(plan *protos.ActionPlan) *api.ActionPlan {
+
+	actionPlan := &api.ActionPlan{
+		Name:     plan.GetName(),
+		Type:     plan.GetType(),
+		NodeName: plan.GetNodeName(),
+		Steps:    make([]*api.PlanStep, 0, len(plan.GetSteps())),
+		Error:    convertDeployControllerErrorToAPIError(plan.GetErr()),
+	}
+	for _, step := range plan.GetSteps() {
+		actionPlan.Steps = append(actionPlan.Steps, &api.PlanStep{
+			NodeName:      step.GetNodeName(),
+			Action:        step.GetAction(),
+			Command:       step.GetCommand(),
+			Path:          step.GetPath(),
+			Mode:          step.GetMode(),
+			Content:       step.GetContent(),
+			ContentHidden: step.GetContentHidden(),
+		})
+	}
+	return actionPlan
+}
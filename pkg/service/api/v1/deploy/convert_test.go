@@ -32,6 +32,7 @@ func TestConvertModelAuthenticationTypeToAPIAuthenticationType(t *testing.T) {
 
 	assert.Equal(t, api.AuthenticationTypePassword, convertModelAuthenticationTypeToAPIAuthenticationType(wizard.AuthenticationTypePassword))
 	assert.Equal(t, api.AuthenticationTypePrivateKey, convertModelAuthenticationTypeToAPIAuthenticationType(wizard.AuthenticationTypePrivateKey))
+	assert.Equal(t, api.AuthenticationTypeKeyboardInteractive, convertModelAuthenticationTypeToAPIAuthenticationType(wizard.AuthenticationTypeKeyboardInteractive))
 	assert.Equal(t, api.AuthenticationType("unknown(OtherType)"), convertModelAuthenticationTypeToAPIAuthenticationType("OtherType"))
 }
 
@@ -133,6 +134,7 @@ func TestConvertAPIAuthenticationTypeToModelAuthenticationType(t *testing.T) {
 
 	assert.Equal(t, wizard.AuthenticationTypePassword, convertAPIAuthenticationTypeToModelAuthenticationType(api.AuthenticationTypePassword))
 	assert.Equal(t, wizard.AuthenticationTypePrivateKey, convertAPIAuthenticationTypeToModelAuthenticationType(api.AuthenticationTypePrivateKey))
+	assert.Equal(t, wizard.AuthenticationTypeKeyboardInteractive, convertAPIAuthenticationTypeToModelAuthenticationType(api.AuthenticationTypeKeyboardInteractive))
 	assert.Equal(t, wizard.AuthenticationType("unknown(OtherType)"), convertAPIAuthenticationTypeToModelAuthenticationType("OtherType"))
 }
 
@@ -213,6 +215,140 @@ ls3Q/5aeF7hB2MXfAAAAGEx1Y2t5Ym95c0BMdWNreU1hYy5sb2NhbAEC
 		AuthenticationType: wizard.AuthenticationTypePrivateKey,
 		PrivateKeyName:     keyName,
 	}))
+
+	assert.Equal(t, &protos.SSH{
+		Port: 22,
+		Auth: &protos.Auth{
+			Type:       "password",
+			Username:   "root",
+			Credential: "123456",
+		},
+		JumpHosts: []*protos.JumpHost{
+			{
+				Ip:   "192.168.31.1",
+				Port: 2222,
+				Auth: &protos.Auth{
+					Type:       "privatekey",
+					Username:   "ops",
+					Credential: privateKey,
+				},
+			},
+		},
+	}, convertModelConnectionDataToDeployControllerSSHData(&wizard.ConnectionData{
+		Port:               uint16(22),
+		Username:           "root",
+		AuthenticationType: wizard.AuthenticationTypePassword,
+		Password:           "123456",
+		JumpHosts: []*wizard.JumpHost{
+			{
+				IP:                 "192.168.31.1",
+				Port:               uint16(2222),
+				Username:           "ops",
+				AuthenticationType: wizard.AuthenticationTypePrivateKey,
+				PrivateKeyName:     keyName,
+			},
+		},
+	}))
+}
+
+func TestConvertModelConnectionDataWithSudoToDeployControllerSSHData(t *testing.T) {
+
+	assert.Equal(t, &protos.SSH{
+		Port: 22,
+		Auth: &protos.Auth{
+			Type:       "password",
+			Username:   "kpaas",
+			Credential: "123456",
+		},
+		Sudo: &protos.Sudo{
+			Enabled:  true,
+			Password: "654321",
+		},
+	}, convertModelConnectionDataToDeployControllerSSHData(&wizard.ConnectionData{
+		Port:               uint16(22),
+		Username:           "kpaas",
+		AuthenticationType: wizard.AuthenticationTypePassword,
+		Password:           "123456",
+		Sudo:               true,
+		SudoPassword:       "654321",
+	}))
+}
+
+func TestConvertModelConnectionDataWithCertificateToDeployControllerSSHData(t *testing.T) {
+
+	sshcertificate.ClearList()
+	keyName := "id_rsa_cert"
+	sshcertificate.SaveCertificate(&sshcertificate.Certificate{
+		Name:        keyName,
+		PrivateKey:  "encrypted private key",
+		Passphrase:  "passphrase",
+		Certificate: "ssh-rsa-cert-v01@openssh.com AAAA",
+	})
+
+	assert.Equal(t, &protos.SSH{
+		Port: 22,
+		Auth: &protos.Auth{
+			Type:        "privatekey",
+			Username:    "root",
+			Credential:  "encrypted private key",
+			Passphrase:  "passphrase",
+			Certificate: "ssh-rsa-cert-v01@openssh.com AAAA",
+		},
+	}, convertModelConnectionDataToDeployControllerSSHData(&wizard.ConnectionData{
+		Port:               uint16(22),
+		Username:           "root",
+		AuthenticationType: wizard.AuthenticationTypePrivateKey,
+		PrivateKeyName:     keyName,
+	}))
+}
+
+func TestConvertModelConnectionDataWithKeyboardInteractiveToDeployControllerSSHData(t *testing.T) {
+
+	assert.Equal(t, &protos.SSH{
+		Port: 22,
+		Auth: &protos.Auth{
+			Type:               "keyboardinteractive",
+			Username:           "root",
+			Credential:         "123456",
+			ChallengeResponses: []string{"123456", "908172"},
+		},
+	}, convertModelConnectionDataToDeployControllerSSHData(&wizard.ConnectionData{
+		Port:               uint16(22),
+		Username:           "root",
+		AuthenticationType: wizard.AuthenticationTypeKeyboardInteractive,
+		Password:           "123456",
+		ChallengeResponses: []string{"123456", "908172"},
+	}))
+}
+
+func TestConvertJumpHosts(t *testing.T) {
+
+	apiJumpHosts := []api.JumpHost{
+		{
+			IP:   "192.168.31.1",
+			Port: 22,
+			SSHLoginData: api.SSHLoginData{
+				Username:           "ops",
+				AuthenticationType: api.AuthenticationTypePassword,
+				Password:           "123456",
+			},
+		},
+	}
+	modelJumpHosts := convertAPIJumpHostsToModelJumpHosts(apiJumpHosts)
+	assert.Equal(t, []*wizard.JumpHost{
+		{
+			IP:                 "192.168.31.1",
+			Port:               22,
+			Username:           "ops",
+			AuthenticationType: wizard.AuthenticationTypePassword,
+			Password:           "123456",
+		},
+	}, modelJumpHosts)
+
+	// the password is not responded
+	apiJumpHosts[0].Password = ""
+	assert.Equal(t, apiJumpHosts, convertModelJumpHostsToAPIJumpHosts(modelJumpHosts))
+	assert.Nil(t, convertModelJumpHostsToAPIJumpHosts(nil))
 }
 
 func TestConvertDeployControllerCheckResultToModelCheckResult(t *testing.T) {
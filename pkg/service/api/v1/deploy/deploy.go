@@ -31,17 +31,21 @@ import (
 	"github.com/kpaas-io/kpaas/pkg/utils/log"
 )
 
+// the type of the deploy task in deploy controller
+const deployTaskType = "Deploy"
+
 // @ID LaunchDeployment
 // @Summary Launch deployment
 // @Description Launch deployment
 // @Tags deploy
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 201 {object} api.SuccessfulOption
 // @Failure 404 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/deploys [post]
+// @Router /api/v1/deploy/wizard/clusters/{id}/deploys [post]
 func Deploy(c *gin.Context) {
 
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := getCluster(c)
 	if len(wizardData.Nodes) <= 0 {
 		h.E(c, h.ENotFound.WithPayload("No node information, node list is empty, please add node information"))
 		return
@@ -73,7 +77,7 @@ func Deploy(c *gin.Context) {
 	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
 	defer cancel()
 
-	resp, err := client.Deploy(grpcContext, getCallDeployData())
+	resp, err := client.Deploy(grpcContext, getCallDeployData(wizardData))
 	if err != nil {
 		h.E(c, h.EDeployControllerError.WithPayload(err))
 		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
@@ -86,22 +90,67 @@ func Deploy(c *gin.Context) {
 		log.ReqEntry(c).Errorf("call deploy controller result error, error: %#v", resp.GetErr())
 	}
 
-	go listenDeploymentData()
+	go listenDeploymentData(wizardData)
 
 	h.R(c, api.SuccessfulOption{Success: resp.GetAccepted()})
 }
 
+// @ID CancelDeployment
+// @Summary Cancel deployment
+// @Description Cancel the running deployment, the remaining deploy items will be aborted
+// @Tags deploy
+// @Param id path int true "Cluster ID"
+// @Success 204
+// @Failure 400 {object} h.AppErr
+// @Failure 500 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/clusters/{id}/deploys [delete]
+func CancelDeploy(c *gin.Context) {
+
+	wizardData := getCluster(c)
+	if wizardData.GetDeployClusterStatus() != wizard.DeployClusterStatusRunning {
+		h.E(c, h.EStatusError.WithPayload("It was not deploying"))
+		return
+	}
+
+	client := clientUtils.GetDeployController()
+
+	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
+	defer cancel()
+
+	resp, err := client.CancelTask(grpcContext, &protos.CancelTaskRequest{
+		TaskType:  deployTaskType,
+		ClusterId: getDeployClusterId(wizardData),
+	})
+	if err != nil {
+		h.E(c, h.EDeployControllerError.WithPayload(err))
+		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
+		return
+	}
+
+	if resp.GetErr() != nil {
+
+		h.E(c, h.EDeployControllerError.WithPayload(convertDeployControllerErrorToAPIError(resp.GetErr())))
+		log.ReqEntry(c).Errorf("call deploy controller result error, error: %#v", resp.GetErr())
+		return
+	}
+
+	log.ReqEntry(c).Warn("cancel deployment")
+
+	h.R(c, nil)
+}
+
 // @ID GetDeploymentReport
 // @Summary Get the result of deployment
 // @Description Get the result of the deployment
 // @Tags deploy
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 200 {object} api.GetDeploymentReportResponse
-// @Router /api/v1/deploy/wizard/deploys [get]
+// @Router /api/v1/deploy/wizard/clusters/{id}/deploys [get]
 func GetDeployReport(c *gin.Context) {
 
-	wizardData := wizard.GetCurrentWizard()
-	nodeList := getWizardDeploymentData()
+	wizardData := getCluster(c)
+	nodeList := getWizardDeploymentData(wizardData)
 	responseData := api.GetDeploymentReportResponse{
 		DeployItems:         *nodeList,
 		DeployClusterStatus: convertModelDeployClusterStatusToAPIDeployClusterStatus(wizardData.DeployClusterStatus),
@@ -111,17 +160,17 @@ func GetDeployReport(c *gin.Context) {
 	h.R(c, responseData)
 }
 
-func getCallDeployData() *protos.DeployRequest {
+func getCallDeployData(wizardData *wizard.Cluster) *protos.DeployRequest {
 
 	return &protos.DeployRequest{
-		NodeConfigs:   buildCallDeployDataNodesPart(),
-		ClusterConfig: buildCallDeployDataClusterPart(),
+		NodeConfigs:   buildCallDeployDataNodesPart(wizardData),
+		ClusterConfig: buildCallDeployDataClusterPart(wizardData),
+		ClusterId:     getDeployClusterId(wizardData),
 	}
 }
 
-func buildCallDeployDataNodesPart() (nodeConfigs []*protos.NodeDeployConfig) {
+func buildCallDeployDataNodesPart(wizardData *wizard.Cluster) (nodeConfigs []*protos.NodeDeployConfig) {
 
-	wizardData := wizard.GetCurrentWizard()
 	nodeConfigs = make([]*protos.NodeDeployConfig, 0, len(wizardData.Nodes))
 	for _, node := range wizardData.Nodes {
 
@@ -131,9 +180,10 @@ func buildCallDeployDataNodesPart() (nodeConfigs []*protos.NodeDeployConfig) {
 		}
 
 		nodeConfig.Node = &protos.Node{
-			Name: node.Name,
-			Ip:   node.IP,
-			Ssh:  convertModelConnectionDataToDeployControllerSSHData(&node.ConnectionData),
+			Name:      node.Name,
+			Ip:        node.IP,
+			Ssh:       convertModelConnectionDataToDeployControllerSSHData(&node.ConnectionData),
+			Localhost: node.Localhost,
 		}
 
 		nodeConfig.Labels = make(map[string]string)
@@ -156,9 +206,8 @@ func buildCallDeployDataNodesPart() (nodeConfigs []*protos.NodeDeployConfig) {
 	return
 }
 
-func buildCallDeployDataClusterPart() (clusterConfig *protos.ClusterConfig) {
+func buildCallDeployDataClusterPart(wizardData *wizard.Cluster) (clusterConfig *protos.ClusterConfig) {
 
-	wizardData := wizard.GetCurrentWizard()
 	clusterConfig = &protos.ClusterConfig{
 		ClusterName: wizardData.Info.ShortName,
 		KubeAPIServerConnect: &protos.KubeAPIServerConnect{
@@ -198,33 +247,34 @@ func buildCallDeployDataClusterPart() (clusterConfig *protos.ClusterConfig) {
 	return
 }
 
-func listenDeploymentData() {
+func listenDeploymentData(wizardData *wizard.Cluster) {
 
-	wizardData := wizard.GetCurrentWizard()
 	for {
 		if wizardData.GetDeployClusterStatus() != wizard.DeployClusterStatusRunning {
 			break
 		}
 
-		refreshDeployResultOneTime()
+		refreshDeployResultOneTime(wizardData)
+		saveCluster(wizardData)
 		time.Sleep(time.Second)
 	}
 }
 
-func refreshDeployResultOneTime() {
+func refreshDeployResultOneTime(wizardData *wizard.Cluster) {
 
 	client := clientUtils.GetDeployController()
 
 	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
 	defer cancel()
 
-	resp, err := client.GetDeployResult(grpcContext, &protos.GetDeployResultRequest{})
+	resp, err := client.GetDeployResult(grpcContext, &protos.GetDeployResultRequest{
+		ClusterId: getDeployClusterId(wizardData),
+	})
 	if err != nil {
 		logrus.Errorf("call deploy controller error, errorMessage: %v", err)
 		return
 	}
 
-	wizardData := wizard.GetCurrentWizard()
 	wizardData.SetClusterDeploymentStatus(
 		computeClusterDeployStatus(resp),
 		convertDeployControllerErrorToFailureDetail(resp.GetErr()))
@@ -257,7 +307,7 @@ func refreshDeployResultOneTime() {
 	switch wizardData.DeployClusterStatus {
 	case wizard.DeployClusterStatusSuccessful, wizard.DeployClusterStatusWorkedButHaveError:
 
-		fetchKubeConfigContent()
+		fetchKubeConfigContent(wizardData)
 	}
 }
 
@@ -287,9 +337,8 @@ func computeClusterDeployStatus(resp *protos.GetDeployResultReply) wizard.Deploy
 	return wizard.DeployClusterStatusFailed
 }
 
-func fetchKubeConfigContent() {
+func fetchKubeConfigContent(wizardData *wizard.Cluster) {
 
-	wizardData := wizard.GetCurrentWizard()
 	client := clientUtils.GetDeployController()
 	ctx := context.Background()
 
@@ -306,11 +355,15 @@ func fetchKubeConfigContent() {
 		return
 	}
 
-	fetchResponse, err := client.FetchKubeConfig(ctx, &protos.FetchKubeConfigRequest{Node: &protos.Node{
-		Name: node.Name,
-		Ip:   node.IP,
-		Ssh:  convertModelConnectionDataToDeployControllerSSHData(&node.ConnectionData),
-	}})
+	fetchResponse, err := client.FetchKubeConfig(ctx, &protos.FetchKubeConfigRequest{
+		Node: &protos.Node{
+			Name:      node.Name,
+			Ip:        node.IP,
+			Ssh:       convertModelConnectionDataToDeployControllerSSHData(&node.ConnectionData),
+			Localhost: node.Localhost,
+		},
+		ClusterId: getDeployClusterId(wizardData),
+	})
 
 	if err != nil {
 		logrus.Errorf("Call gRPC deploy controller error, errorMessage: %v", err)
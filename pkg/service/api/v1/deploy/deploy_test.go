@@ -34,12 +34,13 @@ import (
 
 func TestDeploy(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
+	wizardData := wizard.NewCluster()
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/deploys", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/deploys", nil)
 
 	Deploy(ctx)
 	resp.Flush()
@@ -54,8 +55,7 @@ func TestDeploy(t *testing.T) {
 
 func TestDeploy2(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	node := wizard.NewNode()
 	node.Name = "master1"
 	node.CheckReport = &wizard.CheckReport{
@@ -69,7 +69,8 @@ func TestDeploy2(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/deploys", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/deploys", nil)
 
 	Deploy(ctx)
 	resp.Flush()
@@ -84,8 +85,7 @@ func TestDeploy2(t *testing.T) {
 
 func TestDeploy3(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.ClusterCheckResult = constant.CheckResultSuccessful
 	node := wizard.NewNode()
 	node.Name = "master1"
@@ -100,7 +100,8 @@ func TestDeploy3(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/deploys", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/deploys", nil)
 
 	Deploy(ctx)
 	resp.Flush()
@@ -115,8 +116,7 @@ func TestDeploy3(t *testing.T) {
 
 func TestGetDeployReport(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Nodes = []*wizard.Node{
 		{
 			Name: "master1",
@@ -138,7 +138,8 @@ func TestGetDeployReport(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/deploys", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/deploys", nil)
 
 	GetDeployReport(ctx)
 	resp.Flush()
@@ -198,10 +199,9 @@ func TestFetchKubeConfigContent(t *testing.T) {
 
 	for _, test := range tests {
 
-		wizard.ClearCurrentWizardData()
-		wizardData := wizard.GetCurrentWizard()
+		wizardData := wizard.NewCluster()
 		wizardData.Nodes = test.OriginNodeList
-		fetchKubeConfigContent()
+		fetchKubeConfigContent(wizardData)
 		assert.Equal(t, test.WantKubeConfig, *wizardData.KubeConfig)
 	}
 }
@@ -0,0 +1,161 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Service for cluster drafts manage
+
+package deploy
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/constant"
+	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
+	"github.com/kpaas-io/kpaas/pkg/utils/h"
+	"github.com/kpaas-io/kpaas/pkg/utils/log"
+)
+
+// the key of the cluster draft in the request context
+const clusterContextKey = "wizardCluster"
+
+// @ID CreateClusterDraft
+// @Summary Create a cluster draft
+// @Description Create a new cluster draft, the wizard data of the cluster can be accessed by the cluster id
+// @Tags cluster
+// @Produce application/json
+// @Success 201 {object} api.ClusterDraft
+// @Router /api/v1/deploy/wizard/clusters [post]
+func CreateClusterDraft(c *gin.Context) {
+
+	cluster, err := wizard.CreateCluster()
+	if err != nil {
+		h.E(c, h.ERepositoryError.WithPayload(err))
+		log.ReqEntry(c).Errorf("create cluster draft error: %v", err)
+		return
+	}
+
+	log.ReqEntry(c).WithField("clusterId", cluster.ClusterId).Info("create cluster draft")
+
+	h.R(c, convertModelClusterToAPIClusterDraft(cluster))
+}
+
+// @ID ListClusterDrafts
+// @Summary List cluster drafts
+// @Description List all of the cluster drafts
+// @Tags cluster
+// @Produce application/json
+// @Success 200 {array} api.ClusterDraft
+// @Router /api/v1/deploy/wizard/clusters [get]
+func ListClusterDrafts(c *gin.Context) {
+
+	clusters := wizard.ListClusters()
+	responseData := make([]api.ClusterDraft, 0, len(clusters))
+	for _, cluster := range clusters {
+		responseData = append(responseData, convertModelClusterToAPIClusterDraft(cluster))
+	}
+
+	h.R(c, responseData)
+}
+
+// @ID DeleteClusterDraft
+// @Summary Delete a cluster draft
+// @Description Delete a cluster draft and all of its wizard data, a checking or deploying cluster can not be deleted
+// @Tags cluster
+// @Param id path int true "Cluster ID"
+// @Success 204
+// @Failure 400 {object} h.AppErr
+// @Failure 404 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/clusters/{id} [delete]
+func DeleteClusterDraft(c *gin.Context) {
+
+	cluster := getCluster(c)
+	if err := wizard.DeleteCluster(cluster.ClusterId); err != nil {
+		h.E(c, err)
+		log.ReqEntry(c).Info(err)
+		return
+	}
+
+	log.ReqEntry(c).WithField("clusterId", cluster.ClusterId).Warn("delete cluster draft")
+	h.R(c, nil)
+}
+
+// LoadClusterDraft is a middleware to load the cluster draft by the cluster id in the path,
+// the handlers after it operate on the loaded cluster draft, which is stored after a modifying request.
+func LoadClusterDraft(c *gin.Context) {
+
+	clusterId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.E(c, h.EParamsError.WithPayload(err))
+		c.Abort()
+		return
+	}
+
+	cluster := wizard.GetCluster(clusterId)
+	if cluster == nil {
+		h.E(c, h.ENotFound.WithPayload("cluster not exist"))
+		c.Abort()
+		return
+	}
+
+	setCluster(c, cluster)
+	c.Next()
+
+	// the draft may be deleted or replaced by the handler, it must not be stored again
+	if c.Request.Method == http.MethodGet || wizard.GetCluster(clusterId) != cluster {
+		return
+	}
+	saveCluster(cluster)
+}
+
+func setCluster(c *gin.Context, cluster *wizard.Cluster) {
+
+	c.Set(clusterContextKey, cluster)
+}
+
+func getCluster(c *gin.Context) *wizard.Cluster {
+
+	return c.MustGet(clusterContextKey).(*wizard.Cluster)
+}
+
+// saveCluster stores the cluster draft, the error is only logged because the draft was updated in memory.
+func saveCluster(cluster *wizard.Cluster) {
+
+	if err := wizard.SaveCluster(cluster); err != nil {
+		logrus.Errorf("save cluster %d error: %v", cluster.ClusterId, err)
+	}
+}
+
+// getDeployClusterId returns the cluster id used in deploy controller, the tasks of different clusters
+// are separated by it.
+func getDeployClusterId(cluster *wizard.Cluster) string {
+
+	return strconv.FormatUint(cluster.ClusterId, 10)
+}
+
+// ListenRunningCluster listens the result of the running check or deployment of the cluster draft in background,
+// it's used to listen again after the draft was loaded from the repository, e.g. after restart.
+func ListenRunningCluster(cluster *wizard.Cluster) {
+
+	if cluster.GetCheckResult() == constant.CheckResultRunning {
+		go listenCheckNodesData(cluster)
+	}
+
+	if cluster.GetDeployClusterStatus() == wizard.DeployClusterStatusRunning {
+		go listenDeploymentData(cluster)
+	}
+}
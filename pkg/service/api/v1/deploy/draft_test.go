@@ -0,0 +1,151 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/constant"
+	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
+)
+
+func TestCreateClusterDraft(t *testing.T) {
+
+	wizard.ClearClusters()
+	resp := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters", nil)
+
+	CreateClusterDraft(ctx)
+	resp.Flush()
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	responseData := new(api.ClusterDraft)
+	err := json.Unmarshal(resp.Body.Bytes(), responseData)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, wizard.GetCluster(responseData.ClusterId))
+	assert.Equal(t, constant.CheckResultPending, responseData.CheckResult)
+	assert.Equal(t, api.DeployClusterStatusPending, responseData.DeployClusterStatus)
+}
+
+func TestListClusterDrafts(t *testing.T) {
+
+	wizard.ClearClusters()
+	cluster1, err := wizard.CreateCluster()
+	assert.Nil(t, err)
+	cluster1.Info.ShortName = "cluster1"
+	cluster2, err := wizard.CreateCluster()
+	assert.Nil(t, err)
+	cluster2.Info.ShortName = "cluster2"
+
+	resp := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters", nil)
+
+	ListClusterDrafts(ctx)
+	resp.Flush()
+	responseData := make([]api.ClusterDraft, 0)
+	err = json.Unmarshal(resp.Body.Bytes(), &responseData)
+	assert.Nil(t, err)
+
+	assert.Len(t, responseData, 2)
+	assert.Equal(t, cluster1.ClusterId, responseData[0].ClusterId)
+	assert.Equal(t, "cluster1", responseData[0].ShortName)
+	assert.Equal(t, cluster2.ClusterId, responseData[1].ClusterId)
+	assert.Equal(t, "cluster2", responseData[1].ShortName)
+}
+
+func TestLoadClusterDraft(t *testing.T) {
+
+	wizard.ClearClusters()
+	cluster1, err := wizard.CreateCluster()
+	assert.Nil(t, err)
+	cluster1.Info.ShortName = "cluster1"
+	cluster2, err := wizard.CreateCluster()
+	assert.Nil(t, err)
+	cluster2.Info.ShortName = "cluster2"
+	cluster2.DeployClusterStatus = wizard.DeployClusterStatusRunning
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	clusterGroup := router.Group("/clusters/:id", LoadClusterDraft)
+	clusterGroup.GET("", GetCluster)
+	clusterGroup.DELETE("", DeleteClusterDraft)
+
+	tests := []struct {
+		Method    string
+		Path      string
+		WantCode  int
+		ShortName string
+	}{
+		{
+			Method:    "GET",
+			Path:      fmt.Sprintf("/clusters/%d", cluster1.ClusterId),
+			WantCode:  http.StatusOK,
+			ShortName: "cluster1",
+		},
+		{
+			Method:    "GET",
+			Path:      fmt.Sprintf("/clusters/%d", cluster2.ClusterId),
+			WantCode:  http.StatusOK,
+			ShortName: "cluster2",
+		},
+		{
+			Method:   "GET",
+			Path:     "/clusters/invalid",
+			WantCode: http.StatusBadRequest,
+		},
+		{
+			Method:   "DELETE",
+			Path:     fmt.Sprintf("/clusters/%d", cluster2.ClusterId),
+			WantCode: http.StatusBadRequest,
+		},
+		{
+			Method:   "DELETE",
+			Path:     fmt.Sprintf("/clusters/%d", cluster1.ClusterId),
+			WantCode: http.StatusNoContent,
+		},
+		{
+			Method:   "GET",
+			Path:     fmt.Sprintf("/clusters/%d", cluster1.ClusterId),
+			WantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range tests {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(testCase.Method, testCase.Path, nil))
+		assert.Equal(t, testCase.WantCode, resp.Code)
+
+		if testCase.ShortName != "" {
+			responseData := new(api.Cluster)
+			err := json.Unmarshal(resp.Body.Bytes(), responseData)
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.ShortName, responseData.ShortName)
+		}
+	}
+
+	assert.Equal(t, []*wizard.Cluster{cluster2}, wizard.ListClusters())
+}
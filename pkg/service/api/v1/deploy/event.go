@@ -0,0 +1,90 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	clientUtils "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
+	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
+	"github.com/kpaas-io/kpaas/pkg/utils/h"
+	"github.com/kpaas-io/kpaas/pkg/utils/log"
+)
+
+const (
+	sseEventTask  = "task"
+	sseEventError = "error"
+)
+
+// @ID WatchWizardEvents
+// @Summary Watch the checking and deployment progress
+// @Description Push the status transitions of the checking and deployment tasks of the cluster by Server-Sent Events,
+// @Description each "task" event carries a TaskEvent, an "error" event is sent before the stream is broken by an error.
+// @Description The current status of the tasks are sent at first, the stream is kept until the client disconnects or the
+// @Description server write timeout reached, the client should reconnect then.
+// @Tags event
+// @Produce text/event-stream
+// @Param clusterId query int true "Cluster ID"
+// @Success 200 {object} api.TaskEvent
+// @Failure 400 {object} h.AppErr
+// @Failure 404 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/events [get]
+func WatchEvents(c *gin.Context) {
+
+	clusterId, err := strconv.ParseUint(c.Query("clusterId"), 10, 64)
+	if err != nil {
+		h.E(c, h.EParamsError.WithPayload(err))
+		return
+	}
+
+	cluster := wizard.GetCluster(clusterId)
+	if cluster == nil {
+		h.E(c, h.ENotFound.WithPayload("cluster not exist"))
+		return
+	}
+
+	client := clientUtils.GetDeployController()
+	stream, err := client.WatchTask(c.Request.Context(), &protos.WatchTaskRequest{
+		ClusterId: getDeployClusterId(cluster),
+	})
+	if err != nil {
+		h.E(c, h.EDeployControllerError.WithPayload(err))
+		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
+		return
+	}
+
+	log.ReqEntry(c).WithField("clusterId", clusterId).Debug("start to watch events")
+	c.Stream(func(w io.Writer) bool {
+
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			if c.Request.Context().Err() == nil {
+				c.SSEvent(sseEventError, h.EDeployControllerError.WithPayload(err.Error()))
+				log.ReqEntry(c).Infof("watch events error: %v", err)
+			}
+			return false
+		}
+
+		c.SSEvent(sseEventTask, convertDeployControllerTaskEventToAPITaskEvent(clusterId, event))
+		return true
+	})
+}
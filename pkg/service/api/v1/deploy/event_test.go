@@ -0,0 +1,96 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	grpcClient "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
+	"github.com/kpaas-io/kpaas/pkg/service/grpcutils/mock"
+	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
+)
+
+// closeNotifyRecorder is a recorder implements http.CloseNotifier which is required by streaming response.
+type closeNotifyRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (recorder *closeNotifyRecorder) CloseNotify() <-chan bool {
+
+	return make(chan bool)
+}
+
+func TestWatchEvents(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	wizard.ClearClusters()
+	cluster, err := wizard.CreateCluster()
+	assert.Nil(t, err)
+
+	resp := &closeNotifyRecorder{httptest.NewRecorder()}
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/deploy/wizard/events?clusterId=%d", cluster.ClusterId), nil)
+
+	WatchEvents(ctx)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+
+	var events []api.TaskEvent
+	for _, line := range strings.Split(resp.Body.String(), "\n") {
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		event := api.TaskEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event))
+		events = append(events, event)
+	}
+
+	assert.Equal(t, 2, strings.Count(resp.Body.String(), "event:task"))
+	assert.Len(t, events, 2)
+	assert.Equal(t, cluster.ClusterId, events[0].ClusterId)
+	assert.Equal(t, "task", events[0].Kind)
+	assert.Equal(t, "init-action", events[1].Name)
+	assert.Equal(t, "master1", events[1].NodeName)
+}
+
+func TestWatchEventsWithWrongCluster(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	wizard.ClearClusters()
+
+	for url, status := range map[string]int{
+		"/api/v1/deploy/wizard/events":             http.StatusBadRequest,
+		"/api/v1/deploy/wizard/events?clusterId=a": http.StatusBadRequest,
+		"/api/v1/deploy/wizard/events?clusterId=1": http.StatusNotFound,
+	} {
+		resp := httptest.NewRecorder()
+		gin.SetMode(gin.TestMode)
+		ctx, _ := gin.CreateTestContext(resp)
+		ctx.Request = httptest.NewRequest("GET", url, nil)
+
+		WatchEvents(ctx)
+		assert.Equal(t, status, resp.Code, url)
+	}
+}
@@ -26,13 +26,14 @@ import (
 // @Description Download kubeconfig file
 // @Tags kubeconfig
 // @Produce text/plain
+// @Param id path int true "Cluster ID"
 // @Success 200 {string} string "Kube Config File Content"
 // @Failure 400 {object} h.AppErr
 // @Failure 404 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/kubeconfigs [get]
+// @Router /api/v1/deploy/wizard/clusters/{id}/kubeconfigs [get]
 func DownloadKubeConfig(c *gin.Context) {
 
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := getCluster(c)
 	if wizardData.DeployClusterStatus != wizard.DeployClusterStatusSuccessful &&
 		wizardData.DeployClusterStatus != wizard.DeployClusterStatusWorkedButHaveError {
 		h.E(c, h.EStatusError.WithPayload("Current cluster has not been deployed yet"))
@@ -43,7 +44,7 @@ func DownloadKubeConfig(c *gin.Context) {
 		*wizardData.KubeConfig == "" {
 		h.E(c, h.ENotFound.WithPayload("kubeconfig file has not ready yet, try it later"))
 
-		fetchKubeConfigContent()
+		fetchKubeConfigContent(wizardData)
 		return
 	}
 
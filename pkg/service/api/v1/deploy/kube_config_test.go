@@ -29,8 +29,7 @@ import (
 
 func TestDownloadKubeConfig(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.DeployClusterStatus = wizard.DeployClusterStatusSuccessful
 	kubeConfig := "123456"
 	wizardData.KubeConfig = &kubeConfig
@@ -39,7 +38,8 @@ func TestDownloadKubeConfig(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/kubeconfigs", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/kubeconfigs", nil)
 
 	DownloadKubeConfig(ctx)
 	resp.Flush()
@@ -54,15 +54,15 @@ func TestDownloadKubeConfig(t *testing.T) {
 
 func TestDownloadKubeConfig2(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.DeployClusterStatus = wizard.DeployClusterStatusSuccessful
 
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/kubeconfigs", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/kubeconfigs", nil)
 
 	DownloadKubeConfig(ctx)
 	resp.Flush()
@@ -77,13 +77,14 @@ func TestDownloadKubeConfig2(t *testing.T) {
 
 func TestDownloadKubeConfig3(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
+	wizardData := wizard.NewCluster()
 
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/kubeconfigs", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/kubeconfigs", nil)
 
 	DownloadKubeConfig(ctx)
 	resp.Flush()
@@ -15,12 +15,25 @@
 package deploy
 
 import (
+	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/kpaas-io/kpaas/pkg/constant"
+	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	clientUtils "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
 	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
 	"github.com/kpaas-io/kpaas/pkg/utils/h"
+	"github.com/kpaas-io/kpaas/pkg/utils/log"
+)
+
+type (
+	// logChunkStream is the stream of following a log from deploy controller
+	logChunkStream interface {
+		Recv() (*protos.LogChunk, error)
+	}
 )
 
 // @ID DownloadLog
@@ -55,3 +68,120 @@ func DownloadLog(c *gin.Context) {
 
 	h.R(c, string(content))
 }
+
+// @ID FollowCheckNodesLog
+// @Summary Follow the checking log of a node
+// @Description Send the checking log of the node as it is written in a chunked response, the response ends when the checking finished.
+// @Description If the response is broken, request again with the offset of the received length to resume.
+// @Tags log
+// @Produce text/plain
+// @Param id path int true "Cluster ID"
+// @Param ip path string true "Node IP"
+// @Param offset query int false "Position in the log to start from"
+// @Success 200 {string} string "Log Content"
+// @Failure 400 {object} h.AppErr
+// @Failure 404 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/clusters/{id}/checks/logs/{ip} [get]
+func FollowCheckNodesLog(c *gin.Context) {
+
+	wizardData := getCluster(c)
+	node, offset, hasError := getFollowLogParams(c, wizardData)
+	if hasError {
+		return
+	}
+
+	stream, err := clientUtils.GetDeployController().TailCheckNodesLog(c.Request.Context(), &protos.TailCheckNodesLogRequest{
+		ClusterId: getDeployClusterId(wizardData),
+		NodeName:  node.Name,
+		Offset:    offset,
+	})
+	followLog(c, stream, err)
+}
+
+// @ID FollowDeployLog
+// @Summary Follow the deployment log of a node
+// @Description Send the deployment log of the node and role as it is written in a chunked response, the response ends when the deployment finished.
+// @Description If the response is broken, request again with the offset of the received length to resume.
+// @Tags log
+// @Produce text/plain
+// @Param id path int true "Cluster ID"
+// @Param ip path string true "Node IP"
+// @Param role query string true "Deploy role" Enums(master, worker, etcd, ingress)
+// @Param offset query int false "Position in the log to start from"
+// @Success 200 {string} string "Log Content"
+// @Failure 400 {object} h.AppErr
+// @Failure 404 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/clusters/{id}/deploys/logs/{ip} [get]
+func FollowDeployLog(c *gin.Context) {
+
+	wizardData := getCluster(c)
+	node, offset, hasError := getFollowLogParams(c, wizardData)
+	if hasError {
+		return
+	}
+
+	role := constant.MachineRole(c.Query("role"))
+	switch role {
+	case constant.MachineRoleMaster, constant.MachineRoleWorker, constant.MachineRoleEtcd, constant.MachineRoleIngress:
+	default:
+		h.E(c, h.EParamsError.WithPayload(fmt.Sprintf("unknown role: %s", role)))
+		return
+	}
+
+	stream, err := clientUtils.GetDeployController().TailDeployLog(c.Request.Context(), &protos.TailDeployLogRequest{
+		ClusterId: getDeployClusterId(wizardData),
+		Role:      string(role),
+		NodeName:  node.Name,
+		Offset:    offset,
+	})
+	followLog(c, stream, err)
+}
+
+func getFollowLogParams(c *gin.Context, wizardData *wizard.Cluster) (node *wizard.Node, offset int64, hasError bool) {
+
+	node = wizardData.GetNode(c.Param("ip"))
+	if node == nil {
+		h.E(c, h.ENotFound.WithPayload("node not exist"))
+		return nil, 0, true
+	}
+
+	offset, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+	if err != nil || offset < 0 {
+		h.E(c, h.EParamsError.WithPayload(fmt.Sprintf("invalid offset: %s", c.Query("offset"))))
+		return nil, 0, true
+	}
+
+	return node, offset, false
+}
+
+// followLog writes the log chunks into the response until the stream ends.
+func followLog(c *gin.Context, stream logChunkStream, err error) {
+
+	if err != nil {
+		h.E(c, h.EDeployControllerError.WithPayload(err))
+		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Stream(func(w io.Writer) bool {
+
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			if c.Request.Context().Err() == nil {
+				log.ReqEntry(c).Infof("follow log error: %v", err)
+			}
+			return false
+		}
+
+		if _, err = w.Write(chunk.GetData()); err != nil {
+			log.ReqEntry(c).Infof("write log error: %v", err)
+			return false
+		}
+		return true
+	})
+}
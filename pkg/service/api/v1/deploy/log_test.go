@@ -18,14 +18,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 
+	grpcClient "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
+	"github.com/kpaas-io/kpaas/pkg/service/grpcutils/mock"
 	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
 	"github.com/kpaas-io/kpaas/pkg/utils/h"
 )
@@ -132,3 +136,61 @@ func TestDownloadLog_IDEcmpty(t *testing.T) {
 
 	assert.Equal(t, h.ENotFound.Status, resp.Code)
 }
+
+func newFollowLogContext(wizardData *wizard.Cluster, url, ip string) (*gin.Context, *closeNotifyRecorder) {
+
+	resp := &closeNotifyRecorder{httptest.NewRecorder()}
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", url, nil)
+	ctx.Params = gin.Params{
+		{
+			Key:   "ip",
+			Value: ip,
+		},
+	}
+	return ctx, resp
+}
+
+func TestFollowCheckNodesLog(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	wizardData := wizard.NewCluster()
+	node := wizard.NewNode()
+	node.Name = "master1"
+	node.IP = "192.168.31.140"
+	assert.Nil(t, wizardData.AddNode(node))
+
+	ctx, resp := newFollowLogContext(wizardData, "/api/v1/deploy/wizard/clusters/1/checks/logs/192.168.31.140", node.IP)
+	FollowCheckNodesLog(ctx)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Equal(t, strings.Repeat("check nodes log of master1\n", 3), resp.Body.String())
+
+	ctx, resp = newFollowLogContext(wizardData, "/api/v1/deploy/wizard/clusters/1/checks/logs/192.168.31.141", "192.168.31.141")
+	FollowCheckNodesLog(ctx)
+	assert.Equal(t, h.ENotFound.Status, resp.Code)
+}
+
+func TestFollowDeployLog(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	wizardData := wizard.NewCluster()
+	node := wizard.NewNode()
+	node.Name = "master1"
+	node.IP = "192.168.31.140"
+	assert.Nil(t, wizardData.AddNode(node))
+
+	line := "master deploy log of master1\n"
+	ctx, resp := newFollowLogContext(wizardData, fmt.Sprintf("/api/v1/deploy/wizard/clusters/1/deploys/logs/192.168.31.140?role=master&offset=%d", len(line)+6), node.IP)
+	FollowDeployLog(ctx)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, strings.Repeat(line, 3)[len(line)+6:], resp.Body.String())
+
+	for _, query := range []string{"role=unknown", "role=master&offset=-1", "role=master&offset=a"} {
+		ctx, resp = newFollowLogContext(wizardData, "/api/v1/deploy/wizard/clusters/1/deploys/logs/192.168.31.140?"+query, node.IP)
+		FollowDeployLog(ctx)
+		assert.Equal(t, h.EParamsError.Status, resp.Code, query)
+	}
+}
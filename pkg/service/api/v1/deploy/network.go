@@ -18,7 +18,6 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/kpaas-io/kpaas/pkg/service/model/api"
-	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
 	"github.com/kpaas-io/kpaas/pkg/utils/h"
 	"github.com/kpaas-io/kpaas/pkg/utils/log"
 )
@@ -30,10 +29,11 @@ import (
 // @Tags network
 // @Accept application/json
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Param networkOptions body api.NetworkOptions true "options of network components in the cluster"
 // @Success 201 {object} api.SuccessfulOption
 // @Failure 400 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/networks [post]
+// @Router /api/v1/deploy/wizard/clusters/{id}/networks [post]
 func SetNetwork(c *gin.Context) {
 	logger := log.ReqEntry(c)
 	networkOptions := &api.NetworkOptions{}
@@ -46,7 +46,7 @@ func SetNetwork(c *gin.Context) {
 
 	// TODO: validate network options.
 
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := getCluster(c)
 	wizardData.SetNetworkOptions(networkOptions)
 	h.R(c, &api.SuccessfulOption{Success: true})
 }
@@ -57,11 +57,12 @@ func SetNetwork(c *gin.Context) {
 // @Description get currently stored network options, returns default options if nothing stored.
 // @Tags network
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 200 {object} api.NetworkOptions
-// @Router /api/v1/deploy/wizard/networks [get]
+// @Router /api/v1/deploy/wizard/clusters/{id}/networks [get]
 func GetNetwork(c *gin.Context) {
 	logger := log.ReqEntry(c)
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := getCluster(c)
 	logger.WithField("cluster", wizardData.Info.ShortName).Debug("get network options of cluster")
 	networkOptions := wizardData.GetNetworkOptions()
 	h.R(c, networkOptions)
@@ -68,16 +68,17 @@ func TestSetNetwork(t *testing.T) {
 	}
 
 	for _, testCase := range tests {
-		wizard.ClearCurrentWizardData()
+		wizardData := wizard.NewCluster()
 		resp := httptest.NewRecorder()
 		gin.SetMode(gin.TestMode)
 		ctx, _ := gin.CreateTestContext(resp)
+		setCluster(ctx, wizardData)
 		bodyReader := bytes.NewReader(testCase.inputBody)
-		ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/networks", bodyReader)
+		ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/networks", bodyReader)
 
 		SetNetwork(ctx)
 		assert.Equal(t, testCase.wantStatusCode, resp.Code)
-		assert.Equal(t, testCase.wantOptions, wizard.GetCurrentWizard().NetworkOptions)
+		assert.Equal(t, testCase.wantOptions, wizardData.NetworkOptions)
 
 	}
 }
@@ -112,14 +113,15 @@ func TestGetNetwork(t *testing.T) {
 		},
 	}
 	for _, testCase := range tests {
-		wizard.ClearCurrentWizardData()
-		wizard.GetCurrentWizard().SetNetworkOptions(testCase.inputOptions)
+		wizardData := wizard.NewCluster()
+		wizardData.SetNetworkOptions(testCase.inputOptions)
 
 		resp := httptest.NewRecorder()
 		gin.SetMode(gin.TestMode)
 		ctx, _ := gin.CreateTestContext(resp)
+		setCluster(ctx, wizardData)
 		bodyReader := bytes.NewReader([]byte{})
-		ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/networks", bodyReader)
+		ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/networks", bodyReader)
 
 		GetNetwork(ctx)
 		var options *api.NetworkOptions
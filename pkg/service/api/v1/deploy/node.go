@@ -32,12 +32,13 @@ import (
 // @Description Get nodes information
 // @Tags node
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 200 {object} api.GetNodeListResponse
-// @Router /api/v1/deploy/wizard/nodes [get]
+// @Router /api/v1/deploy/wizard/clusters/{id}/nodes [get]
 func GetNodeList(c *gin.Context) {
 
 	responseData := new(api.GetNodeListResponse)
-	nodes := getWizardNodes()
+	nodes := getWizardNodes(getCluster(c))
 	responseData.Nodes = *nodes
 
 	h.R(c, responseData)
@@ -48,11 +49,12 @@ func GetNodeList(c *gin.Context) {
 // @Description Get a node information
 // @Tags node
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Param ip path string true "Node IP Address"
 // @Success 200 {object} api.NodeData
 // @Failure 400 {object} h.AppErr
 // @Failure 404 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/nodes/{ip} [get]
+// @Router /api/v1/deploy/wizard/clusters/{id}/nodes/{ip} [get]
 func GetNode(c *gin.Context) {
 
 	ip := c.Param("ip")
@@ -62,7 +64,7 @@ func GetNode(c *gin.Context) {
 		return
 	}
 
-	node := wizard.GetCurrentWizard().GetNode(ip)
+	node := getCluster(c).GetNode(ip)
 	if node == nil {
 
 		h.E(c, h.ENotFound.WithPayload("node ip not exist"))
@@ -78,11 +80,12 @@ func GetNode(c *gin.Context) {
 // @Tags node
 // @Accept application/json
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Param node body api.NodeData true "Node information"
 // @Success 201 {object} api.NodeData
 // @Failure 400 {object} h.AppErr
 // @Failure 409 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/nodes [post]
+// @Router /api/v1/deploy/wizard/clusters/{id}/nodes [post]
 func AddNode(c *gin.Context) {
 
 	requestData, hasError := getNodeRequestData(c)
@@ -124,9 +127,16 @@ func AddNode(c *gin.Context) {
 		node.Password = requestData.Password
 	case api.AuthenticationTypePrivateKey:
 		node.PrivateKeyName = requestData.PrivateKeyName
+	case api.AuthenticationTypeKeyboardInteractive:
+		node.Password = requestData.Password
+		node.ChallengeResponses = requestData.ChallengeResponses
 	}
+	node.JumpHosts = convertAPIJumpHostsToModelJumpHosts(requestData.JumpHosts)
+	node.Sudo = requestData.Sudo
+	node.SudoPassword = requestData.SudoPassword
+	node.Localhost = requestData.Localhost
 
-	err := wizard.GetCurrentWizard().AddNode(node)
+	err := getCluster(c).AddNode(node)
 	if err != nil {
 		h.E(c, err)
 		log.ReqEntry(c).Info(err)
@@ -142,13 +152,14 @@ func AddNode(c *gin.Context) {
 // @Tags node
 // @Accept application/json
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Param node body api.UpdateNodeData true "Node information"
 // @Param ip path string true "Node IP Address"
 // @Success 200 {object} api.NodeData
 // @Failure 400 {object} h.AppErr
 // @Failure 404 {object} h.AppErr
 // @Failure 409 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/nodes/{ip} [put]
+// @Router /api/v1/deploy/wizard/clusters/{id}/nodes/{ip} [put]
 func UpdateNode(c *gin.Context) {
 
 	requestData, ip, hasError := getUpdateNodeRequestData(c)
@@ -192,9 +203,18 @@ func UpdateNode(c *gin.Context) {
 		}
 	case api.AuthenticationTypePrivateKey:
 		node.PrivateKeyName = requestData.PrivateKeyName
+	case api.AuthenticationTypeKeyboardInteractive:
+		if len(requestData.Password) > 0 {
+			node.Password = requestData.Password
+		}
+		node.ChallengeResponses = requestData.ChallengeResponses
 	}
+	node.JumpHosts = convertAPIJumpHostsToModelJumpHosts(requestData.JumpHosts)
+	node.Sudo = requestData.Sudo
+	node.SudoPassword = requestData.SudoPassword
+	node.Localhost = requestData.Localhost
 
-	err := wizard.GetCurrentWizard().UpdateNode(node)
+	err := getCluster(c).UpdateNode(node)
 	if err != nil {
 		h.E(c, err)
 		log.ReqEntry(c).Info(err)
@@ -207,6 +227,8 @@ func UpdateNode(c *gin.Context) {
 			IP:           ip,
 			Port:         requestData.Port,
 			SSHLoginData: requestData.SSHLoginData,
+			SudoData:     requestData.SudoData,
+			JumpHosts:    requestData.JumpHosts,
 		},
 	})
 }
@@ -216,12 +238,13 @@ func UpdateNode(c *gin.Context) {
 // @Description Delete a node from deployment candidate node list
 // @Tags node
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Param ip path string true "Node IP Address"
 // @Success 204
 // @Failure 400 {object} h.AppErr
 // @Failure 404 {object} h.AppErr
 // @Failure 409 {object} h.AppErr
-// @Router /api/v1/deploy/wizard/nodes/{ip} [delete]
+// @Router /api/v1/deploy/wizard/clusters/{id}/nodes/{ip} [delete]
 func DeleteNode(c *gin.Context) {
 
 	ip := c.Param("ip")
@@ -230,7 +253,7 @@ func DeleteNode(c *gin.Context) {
 		return
 	}
 
-	err := wizard.GetCurrentWizard().DeleteNode(ip)
+	err := getCluster(c).DeleteNode(ip)
 	if err != nil {
 		h.E(c, err)
 		log.ReqEntry(c).Info(err)
@@ -259,6 +282,11 @@ func getNodeRequestData(c *gin.Context) (*api.NodeData, bool) {
 		}
 	}
 
+	if err := validateJumpHostsPrivateKeyName(requestData.JumpHosts); err != nil {
+		h.E(c, h.EParamsError.WithPayload(err))
+		return nil, true
+	}
+
 	logger = logger.WithField("data", requestData)
 	logger.Debug("Request data")
 
@@ -296,6 +324,27 @@ func getUpdateNodeRequestData(c *gin.Context) (*api.UpdateNodeData, string, bool
 		}
 	}
 
+	if err := validateJumpHostsPrivateKeyName(requestData.JumpHosts); err != nil {
+		h.E(c, h.EParamsError.WithPayload(err))
+		return nil, "", true
+	}
+
 	logger.WithField("data", requestData)
 	return requestData, ip, false
 }
+
+func validateJumpHostsPrivateKeyName(jumpHosts []api.JumpHost) error {
+
+	for _, jumpHost := range jumpHosts {
+		if jumpHost.AuthenticationType != api.AuthenticationTypePrivateKey {
+			continue
+		}
+
+		validateFunction := validator.ValidateStringOptions(jumpHost.PrivateKeyName, "jumpHost.privateKeyName", sshcertificate.GetNameList())
+		if err := validateFunction(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
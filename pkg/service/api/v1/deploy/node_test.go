@@ -33,11 +33,12 @@ import (
 
 func TestAddNode(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
+	wizardData := wizard.NewCluster()
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
 	body := api.NodeData{
 		NodeBaseData: api.NodeBaseData{
 			Name:         "name",
@@ -71,7 +72,7 @@ func TestAddNode(t *testing.T) {
 	bodyContent, err := json.Marshal(body)
 	assert.Nil(t, err)
 	bodyReader := bytes.NewReader(bodyContent)
-	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/nodes", bodyReader)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/nodes", bodyReader)
 
 	AddNode(ctx)
 	resp.Flush()
@@ -86,8 +87,7 @@ func TestAddNode(t *testing.T) {
 
 func TestUpdateNode(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Nodes = []*wizard.Node{
 		{
 			Name:         "master1",
@@ -121,6 +121,7 @@ func TestUpdateNode(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
 	body := api.NodeData{
 		NodeBaseData: api.NodeBaseData{
 			Name:         "name",
@@ -154,7 +155,7 @@ func TestUpdateNode(t *testing.T) {
 	bodyContent, err := json.Marshal(body)
 	assert.Nil(t, err)
 	bodyReader := bytes.NewReader(bodyContent)
-	ctx.Request = httptest.NewRequest("PUT", "/api/v1/deploy/wizard/nodes/192.168.31.140", bodyReader)
+	ctx.Request = httptest.NewRequest("PUT", "/api/v1/deploy/wizard/clusters/1/nodes/192.168.31.140", bodyReader)
 	ctx.Params = gin.Params{
 		{
 			Key:   "ip",
@@ -175,12 +176,13 @@ func TestUpdateNode(t *testing.T) {
 
 func TestUpdateNode_NotExist(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
+	wizardData := wizard.NewCluster()
 
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
 	body := api.NodeData{
 		NodeBaseData: api.NodeBaseData{
 			Name:         "name",
@@ -214,7 +216,7 @@ func TestUpdateNode_NotExist(t *testing.T) {
 	bodyContent, err := json.Marshal(body)
 	assert.Nil(t, err)
 	bodyReader := bytes.NewReader(bodyContent)
-	ctx.Request = httptest.NewRequest("PUT", "/api/v1/deploy/wizard/nodes/192.168.31.140", bodyReader)
+	ctx.Request = httptest.NewRequest("PUT", "/api/v1/deploy/wizard/clusters/1/nodes/192.168.31.140", bodyReader)
 	ctx.Params = gin.Params{
 		{
 			Key:   "ip",
@@ -235,8 +237,7 @@ func TestUpdateNode_NotExist(t *testing.T) {
 
 func TestDeleteNode(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Nodes = []*wizard.Node{
 		{
 			Name:         "master1",
@@ -269,7 +270,8 @@ func TestDeleteNode(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("DELETE", "/api/v1/deploy/wizard/nodes/192.168.31.140", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("DELETE", "/api/v1/deploy/wizard/clusters/1/nodes/192.168.31.140", nil)
 	ctx.Params = gin.Params{
 		{
 			Key:   "ip",
@@ -286,8 +288,7 @@ func TestDeleteNode(t *testing.T) {
 func TestGetNodeList(t *testing.T) {
 
 	var err error
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Nodes = []*wizard.Node{
 		{
 			Name:         "master1",
@@ -320,7 +321,8 @@ func TestGetNodeList(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/nodes", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/nodes", nil)
 
 	GetNodeList(ctx)
 	resp.Flush()
@@ -367,8 +369,7 @@ func TestGetNodeList(t *testing.T) {
 func TestGetNode(t *testing.T) {
 
 	var err error
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Nodes = []*wizard.Node{
 		{
 			Name:         "master1",
@@ -401,7 +402,8 @@ func TestGetNode(t *testing.T) {
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/nodes/192.168.31.140", nil)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/nodes/192.168.31.140", nil)
 	ctx.Params = gin.Params{
 		{
 			Key:   "ip",
@@ -0,0 +1,76 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/service/config"
+	clientUtils "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
+	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/utils/h"
+	"github.com/kpaas-io/kpaas/pkg/utils/log"
+)
+
+// @ID PlanDeployment
+// @Summary Plan deployment
+// @Description Get what the deployment would do without executing it, including the task tree, the actions on each node,
+// @Description the commands run and the files put in order. Nothing is done on the nodes, the commands are planned as if
+// @Description all of them succeed, the content of the scripts, certs and keys are hidden.
+// @Tags deploy
+// @Produce application/json
+// @Param id path int true "Cluster ID"
+// @Success 201 {object} api.DeployPlan
+// @Failure 404 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/clusters/{id}/plans [post]
+func PlanDeploy(c *gin.Context) {
+
+	wizardData := getCluster(c)
+	if len(wizardData.Nodes) <= 0 {
+		h.E(c, h.ENotFound.WithPayload("No node information, node list is empty, please add node information"))
+		return
+	}
+
+	client := clientUtils.GetDeployController()
+
+	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
+	defer cancel()
+
+	deployData := getCallDeployData(wizardData)
+	resp, err := client.PlanDeploy(grpcContext, &protos.PlanDeployRequest{
+		NodeConfigs:   deployData.GetNodeConfigs(),
+		ClusterConfig: deployData.GetClusterConfig(),
+		ClusterId:     deployData.GetClusterId(),
+	})
+	if err != nil {
+		h.E(c, h.EDeployControllerError.WithPayload(err))
+		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
+		return
+	}
+
+	if resp.GetErr() != nil {
+
+		log.ReqEntry(c).Infof("call deploy controller result error, error: %#v", resp.GetErr())
+	}
+
+	h.R(c, api.DeployPlan{
+		ClusterId: wizardData.ClusterId,
+		Plan:      convertDeployControllerTaskPlanToAPITaskPlan(resp.GetPlan()),
+		Error:     convertDeployControllerErrorToAPIError(resp.GetErr()),
+	})
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	grpcClient "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
+	"github.com/kpaas-io/kpaas/pkg/service/grpcutils/mock"
+	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
+	"github.com/kpaas-io/kpaas/pkg/utils/h"
+)
+
+func TestPlanDeploy(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	wizardData := wizard.NewCluster()
+	wizardData.ClusterId = 1
+	node := wizard.NewNode()
+	node.Name = "master1"
+	wizardData.Nodes = []*wizard.Node{
+		node,
+	}
+
+	resp := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/plans", nil)
+
+	PlanDeploy(ctx)
+	resp.Flush()
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	fmt.Printf("result: %s\n", resp.Body.String())
+	responseData := new(api.DeployPlan)
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), responseData))
+
+	assert.Equal(t, uint64(1), responseData.ClusterId)
+	assert.Nil(t, responseData.Error)
+	if assert.NotNil(t, responseData.Plan) && assert.Len(t, responseData.Plan.SubTasks, 1) {
+		subTask := responseData.Plan.SubTasks[0]
+		assert.Equal(t, "DeployEtcd", subTask.Type)
+		if assert.Len(t, subTask.Actions, 1) && assert.Len(t, subTask.Actions[0].Steps, 2) {
+			assert.True(t, subTask.Actions[0].Steps[0].ContentHidden)
+			assert.Equal(t, "run", subTask.Actions[0].Steps[1].Action)
+		}
+	}
+}
+
+func TestPlanDeployWithoutNodes(t *testing.T) {
+
+	resp := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizard.NewCluster())
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/deploy/wizard/clusters/1/plans", nil)
+
+	PlanDeploy(ctx)
+	resp.Flush()
+	responseData := new(h.AppErr)
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), responseData))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -31,15 +31,17 @@ import (
 // @Description Get all data, include current progress, cluster and node data. deploying progress or error.
 // @Tags wizard
 // @Produce application/json
+// @Param id path int true "Cluster ID"
 // @Success 200 {object} api.GetWizardResponse
-// @Router /api/v1/deploy/wizard/progresses [get]
+// @Router /api/v1/deploy/wizard/clusters/{id}/progresses [get]
 func GetWizardProgress(c *gin.Context) {
 
-	clusterInfo := getWizardClusterInfo()
-	nodes := getWizardNodes()
-	networkOptions := getWizardNetworkOptions()
-	checkingData := getWizardCheckingData()
-	deploymentData := getWizardDeploymentData()
+	wizardData := getCluster(c)
+	clusterInfo := getWizardClusterInfo(wizardData)
+	nodes := getWizardNodes(wizardData)
+	networkOptions := getWizardNetworkOptions(wizardData)
+	checkingData := getWizardCheckingData(wizardData)
+	deploymentData := getWizardDeploymentData(wizardData)
 
 	responseData := api.GetWizardResponse{
 		ClusterData:         *clusterInfo,
@@ -47,8 +49,8 @@ func GetWizardProgress(c *gin.Context) {
 		NodesData:           *nodes,
 		CheckingData:        *checkingData,
 		DeploymentData:      *deploymentData,
-		CheckResult:         wizard.GetCurrentWizard().GetCheckResult(),
-		DeployClusterStatus: convertModelDeployClusterStatusToAPIDeployClusterStatus(wizard.GetCurrentWizard().DeployClusterStatus),
+		CheckResult:         wizardData.GetCheckResult(),
+		DeployClusterStatus: convertModelDeployClusterStatusToAPIDeployClusterStatus(wizardData.DeployClusterStatus),
 	}
 
 	h.R(c, responseData)
@@ -58,19 +60,24 @@ func GetWizardProgress(c *gin.Context) {
 // @Summary Clear all of current deploy wizard data
 // @Description Clear all data, include current progress, cluster and node data. deploying progress or error.
 // @Tags wizard
+// @Param id path int true "Cluster ID"
 // @Success 204
-// @Router /api/v1/deploy/wizard/progresses [delete]
+// @Failure 400 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/clusters/{id}/progresses [delete]
 func ClearWizard(c *gin.Context) {
 
-	wizard.ClearCurrentWizardData()
+	if _, err := wizard.ClearClusterData(getCluster(c).ClusterId); err != nil {
+		h.E(c, err)
+		log.ReqEntry(c).Info(err)
+		return
+	}
 	log.ReqEntry(c).Warn("clear wizard data")
 
 	h.R(c, nil)
 }
 
-func getWizardClusterInfo() *api.Cluster {
+func getWizardClusterInfo(wizardData *wizard.Cluster) *api.Cluster {
 
-	wizardData := wizard.GetCurrentWizard()
 	clusterInfo := &api.Cluster{
 		ShortName:       wizardData.Info.ShortName,
 		Name:            wizardData.Info.Name,
@@ -104,9 +111,8 @@ func getWizardClusterInfo() *api.Cluster {
 	return clusterInfo
 }
 
-func getWizardNodes() *[]api.NodeData {
+func getWizardNodes(wizardData *wizard.Cluster) *[]api.NodeData {
 
-	wizardData := wizard.GetCurrentWizard()
 	nodes := new([]api.NodeData)
 	*nodes = make([]api.NodeData, 0, len(wizardData.Nodes))
 
@@ -120,13 +126,12 @@ func getWizardNodes() *[]api.NodeData {
 	return nodes
 }
 
-func getWizardNetworkOptions() *api.NetworkOptions {
-	return wizard.GetCurrentWizard().GetNetworkOptions()
+func getWizardNetworkOptions(wizardData *wizard.Cluster) *api.NetworkOptions {
+	return wizardData.GetNetworkOptions()
 }
 
-func getWizardCheckingData() *[]api.CheckingResultResponseData {
+func getWizardCheckingData(wizardData *wizard.Cluster) *[]api.CheckingResultResponseData {
 
-	wizardData := wizard.GetCurrentWizard()
 	responseData := new([]api.CheckingResultResponseData)
 
 	*responseData = make([]api.CheckingResultResponseData, 0, len(wizardData.Nodes))
@@ -153,9 +158,8 @@ func getWizardCheckingData() *[]api.CheckingResultResponseData {
 	return responseData
 }
 
-func getWizardDeploymentData() *[]api.DeploymentResponseData {
+func getWizardDeploymentData(wizardData *wizard.Cluster) *[]api.DeploymentResponseData {
 
-	wizardData := wizard.GetCurrentWizard()
 	responseData := new([]api.DeploymentResponseData)
 	*responseData = make([]api.DeploymentResponseData, 0, 0)
 
@@ -31,8 +31,8 @@ import (
 
 func TestGetWizardProgress(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	responseData := getWizardProgressData(t)
+	wizardData := wizard.NewCluster()
+	responseData := getWizardProgressData(t, wizardData)
 
 	assert.Equal(t, api.KubeAPIServerConnectTypeFirstMasterIP, responseData.ClusterData.KubeAPIServerConnectType)
 	assert.Equal(t, "", responseData.ClusterData.ShortName)
@@ -54,8 +54,7 @@ func TestGetWizardProgress(t *testing.T) {
 
 func TestGetWizardProgress2(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Info.ShortName = "test-cluster"
 	wizardData.Info.Name = "ClusterName"
 	wizardData.Info.KubeAPIServerConnection.KubeAPIServerConnectType = wizard.KubeAPIServerConnectTypeKeepalived
@@ -76,7 +75,7 @@ func TestGetWizardProgress2(t *testing.T) {
 		},
 	}
 
-	responseData := getWizardProgressData(t)
+	responseData := getWizardProgressData(t, wizardData)
 
 	assert.Equal(t, api.KubeAPIServerConnectTypeKeepalived, responseData.ClusterData.KubeAPIServerConnectType)
 	assert.Equal(t, "test-cluster", responseData.ClusterData.ShortName)
@@ -93,8 +92,7 @@ func TestGetWizardProgress2(t *testing.T) {
 
 func TestGetWizardProgress3(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Nodes = []*wizard.Node{
 		{
 			Name:         "master1",
@@ -128,7 +126,7 @@ func TestGetWizardProgress3(t *testing.T) {
 		},
 	}
 
-	responseData := getWizardProgressData(t)
+	responseData := getWizardProgressData(t, wizardData)
 	assert.Len(t, responseData.NodesData, 1)
 	node := responseData.NodesData[0]
 	assert.Equal(t, "master1", node.Name)
@@ -147,8 +145,7 @@ func TestGetWizardProgress3(t *testing.T) {
 
 func TestGetWizardProgress4(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.ClusterCheckResult = constant.CheckResultSuccessful
 	wizardData.Nodes = []*wizard.Node{
 		{
@@ -169,7 +166,7 @@ func TestGetWizardProgress4(t *testing.T) {
 		},
 	}
 
-	responseData := getWizardProgressData(t)
+	responseData := getWizardProgressData(t, wizardData)
 	assert.Len(t, responseData.CheckingData, 1)
 	checkData := responseData.CheckingData[0]
 	assert.Equal(t, "master1", checkData.Name)
@@ -188,8 +185,7 @@ func TestGetWizardProgress4(t *testing.T) {
 
 func TestGetWizardProgress5(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Nodes = []*wizard.Node{
 		{
 			Name: "master1",
@@ -210,7 +206,7 @@ func TestGetWizardProgress5(t *testing.T) {
 		},
 	}
 
-	responseData := getWizardProgressData(t)
+	responseData := getWizardProgressData(t, wizardData)
 	assert.Len(t, responseData.DeploymentData, 2)
 	sortRoles(responseData.DeploymentData)
 	assert.Equal(t, []api.DeploymentResponseData{
@@ -237,24 +233,22 @@ func TestGetWizardProgress5(t *testing.T) {
 
 func TestGetWizardProgress6(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.DeployClusterStatus = wizard.DeployClusterStatusSuccessful
-	responseData := getWizardProgressData(t)
+	responseData := getWizardProgressData(t, wizardData)
 	assert.Equal(t, api.DeployClusterStatusSuccessful, responseData.DeployClusterStatus)
 }
 
 func TestGetWizardProgress7(t *testing.T) {
 
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Info.ShortName = "test-cluster"
 	wizardData.Info.Name = "ClusterName"
 	wizardData.Info.KubeAPIServerConnection.KubeAPIServerConnectType = wizard.KubeAPIServerConnectTypeLoadBalancer
 	wizardData.Info.KubeAPIServerConnection.LoadbalancerIP = "192.168.31.200"
 	wizardData.Info.KubeAPIServerConnection.LoadbalancerPort = uint16(3434)
 
-	responseData := getWizardProgressData(t)
+	responseData := getWizardProgressData(t, wizardData)
 
 	assert.Equal(t, api.KubeAPIServerConnectTypeLoadBalancer, responseData.ClusterData.KubeAPIServerConnectType)
 	assert.Equal(t, "test-cluster", responseData.ClusterData.ShortName)
@@ -266,8 +260,7 @@ func TestGetWizardProgress7(t *testing.T) {
 }
 
 func TestGetWizardProgressNetworkOptions(t *testing.T) {
-	wizard.ClearCurrentWizardData()
-	wizardData := wizard.GetCurrentWizard()
+	wizardData := wizard.NewCluster()
 	wizardData.Info.ShortName = "test-cluster"
 	wizardData.Info.Name = "ClusterName"
 
@@ -283,7 +276,7 @@ func TestGetWizardProgressNetworkOptions(t *testing.T) {
 		},
 	})
 
-	responseData := getWizardProgressData(t)
+	responseData := getWizardProgressData(t, wizardData)
 	o := responseData.NetworkOptions
 
 	assert.Equal(t, "calico", string(o.NetworkType))
@@ -296,13 +289,14 @@ func TestGetWizardProgressNetworkOptions(t *testing.T) {
 	assert.Equal(t, "bond0", string(o.CalicoOptions.IPDetectionInterface))
 }
 
-func getWizardProgressData(t *testing.T) (responseData *api.GetWizardResponse) {
+func getWizardProgressData(t *testing.T, wizardData *wizard.Cluster) (responseData *api.GetWizardResponse) {
 
 	var err error
 	resp := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
 	ctx, _ := gin.CreateTestContext(resp)
-	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/progresses", bytes.NewReader([]byte{}))
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/progresses", bytes.NewReader([]byte{}))
 	GetWizardProgress(ctx)
 	resp.Flush()
 	assert.True(t, resp.Body.Len() > 0)
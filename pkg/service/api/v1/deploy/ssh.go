@@ -16,22 +16,28 @@ package deploy
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
 	"github.com/kpaas-io/kpaas/pkg/service/config"
 	clientUtils "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
 	"github.com/kpaas-io/kpaas/pkg/service/model/api"
 	"github.com/kpaas-io/kpaas/pkg/service/model/sshcertificate"
+	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
 	"github.com/kpaas-io/kpaas/pkg/utils/h"
 	"github.com/kpaas-io/kpaas/pkg/utils/log"
 	"github.com/kpaas-io/kpaas/pkg/utils/validator"
 )
 
 const (
-	deployControllerAuthCredentialPassword   = "password"
-	deployControllerAuthCredentialPrivateKey = "privatekey"
+	deployControllerAuthCredentialPassword            = "password"
+	deployControllerAuthCredentialPrivateKey          = "privatekey"
+	deployControllerAuthCredentialKeyboardInteractive = "keyboardinteractive"
 )
 
 // @ID TestSSH
@@ -53,6 +59,18 @@ func TestConnectNode(c *gin.Context) {
 		return
 	}
 
+	if err := validateLoginPrivateKey(&requestData.SSHLoginData, "privateKeyName"); err != nil {
+		h.E(c, h.EParamsError.WithPayload(err))
+		return
+	}
+
+	for _, jumpHost := range requestData.JumpHosts {
+		if err := validateLoginPrivateKey(&jumpHost.SSHLoginData, "jumpHost.privateKeyName"); err != nil {
+			h.E(c, h.EParamsError.WithPayload(err))
+			return
+		}
+	}
+
 	client := clientUtils.GetDeployController()
 
 	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
@@ -71,31 +89,135 @@ func TestConnectNode(c *gin.Context) {
 	})
 }
 
-func getCallTestConnectionData(requestData *api.ConnectionData) *protos.TestConnectionRequest {
+// @ID ListKnownHosts
+// @Summary List the pinned SSH host keys
+// @Description List the SSH host keys pinned on the first connection to the nodes, the later connections fail if the host key changed
+// @Tags ssh
+// @Produce application/json
+// @Success 200 {object} api.GetKnownHostListResponse
+// @Failure 500 {object} h.AppErr
+// @Router /api/v1/ssh/knownhosts [get]
+func ListKnownHosts(c *gin.Context) {
+
+	client := clientUtils.GetDeployController()
+
+	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
+	defer cancel()
+
+	resp, err := client.ListKnownHosts(grpcContext, &protos.ListKnownHostsRequest{})
+	if err != nil {
+		h.E(c, h.EDeployControllerError.WithPayload(err))
+		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
+		return
+	}
+
+	responseData := api.GetKnownHostListResponse{
+		KnownHosts: make([]api.KnownHost, 0, len(resp.GetKnownHosts())),
+	}
+	for _, knownHost := range resp.GetKnownHosts() {
+		responseData.KnownHosts = append(responseData.KnownHosts, api.KnownHost{
+			Host:        knownHost.GetHost(),
+			KeyType:     knownHost.GetKeyType(),
+			Fingerprint: knownHost.GetFingerprint(),
+			PinnedAt:    knownHost.GetPinnedTimestamp(),
+		})
+	}
+
+	h.R(c, responseData)
+}
+
+// @ID ForgetKnownHost
+// @Summary Forget the pinned SSH host key of a node
+// @Description Forget the pinned SSH host key of a node after verifying the node, the host key is pinned again on the next connection
+// @Tags ssh
+// @Param ip path string true "Node IP"
+// @Param port query int false "SSH port, 22 by default"
+// @Success 204
+// @Failure 400 {object} h.AppErr
+// @Failure 404 {object} h.AppErr
+// @Failure 500 {object} h.AppErr
+// @Router /api/v1/ssh/knownhosts/{ip} [delete]
+func ForgetKnownHost(c *gin.Context) {
 
-	auth := &protos.Auth{
-		Username: requestData.Username,
+	ip := c.Param("ip")
+	if net.ParseIP(ip) == nil {
+		h.E(c, h.EParamsError.WithPayload(fmt.Sprintf("invalid ip: %s", ip)))
+		return
 	}
 
-	switch requestData.AuthenticationType {
-	case api.AuthenticationTypePassword:
-		auth.Type = deployControllerAuthCredentialPassword
-		auth.Credential = requestData.Password
-	case api.AuthenticationTypePrivateKey:
-		auth.Type = deployControllerAuthCredentialPrivateKey
-		auth.Credential = sshcertificate.GetPrivateKey(requestData.PrivateKeyName)
+	port := uint64(22)
+	if portParam := c.Query("port"); portParam != "" {
+		var err error
+		if port, err = strconv.ParseUint(portParam, 10, 16); err != nil || port == 0 {
+			h.E(c, h.EParamsError.WithPayload(fmt.Sprintf("invalid port: %s", portParam)))
+			return
+		}
 	}
 
+	client := clientUtils.GetDeployController()
+
+	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
+	defer cancel()
+
+	resp, err := client.ForgetKnownHost(grpcContext, &protos.ForgetKnownHostRequest{
+		Host: net.JoinHostPort(ip, strconv.FormatUint(port, 10)),
+	})
+	if err != nil {
+		h.E(c, h.EDeployControllerError.WithPayload(err))
+		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
+		return
+	}
+
+	if !resp.GetForgotten() {
+		h.E(c, h.ENotFound.WithPayload("host key not pinned"))
+		return
+	}
+
+	log.ReqEntry(c).WithField("ip", ip).WithField("port", port).Warn("forget ssh host key")
+	h.R(c, nil)
+}
+
+func getCallTestConnectionData(requestData *api.ConnectionData) *protos.TestConnectionRequest {
+
 	return &protos.TestConnectionRequest{Node: &protos.Node{
 		Name: requestData.IP,
 		Ip:   requestData.IP,
-		Ssh: &protos.SSH{
-			Port: uint32(requestData.Port),
-			Auth: auth,
-		},
+		Ssh: convertModelConnectionDataToDeployControllerSSHData(&wizard.ConnectionData{
+			IP:                 requestData.IP,
+			Port:               requestData.Port,
+			Username:           requestData.Username,
+			AuthenticationType: convertAPIAuthenticationTypeToModelAuthenticationType(requestData.AuthenticationType),
+			Password:           requestData.Password,
+			PrivateKeyName:     requestData.PrivateKeyName,
+			ChallengeResponses: requestData.ChallengeResponses,
+			JumpHosts:          convertAPIJumpHostsToModelJumpHosts(requestData.JumpHosts),
+			Sudo:               requestData.Sudo,
+			SudoPassword:       requestData.SudoPassword,
+		}),
+		Localhost: requestData.Localhost,
 	}}
 }
 
+// validateLoginPrivateKey checks the private key of login exists and can be used to sign,
+// that is the passphrase is correct and the certificate belongs to the private key.
+func validateLoginPrivateKey(loginData *api.SSHLoginData, fieldName string) error {
+
+	if loginData.AuthenticationType != api.AuthenticationTypePrivateKey {
+		return nil
+	}
+
+	certificate := sshcertificate.GetCertificate(loginData.PrivateKeyName)
+	if certificate == nil {
+		return fmt.Errorf("%s %q not found", fieldName, loginData.PrivateKeyName)
+	}
+
+	if _, err := mssh.NewSigner(certificate.PrivateKey, certificate.Passphrase, certificate.Certificate); err != nil {
+		return fmt.Errorf("%s %q can not be used: %v", fieldName, loginData.PrivateKeyName, err)
+	}
+
+	return nil
+}
+
 func getConnectionData(c *gin.Context) (requestData *api.ConnectionData, hasError bool) {
 
 	requestData = new(api.ConnectionData)
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -27,6 +28,7 @@ import (
 	grpcClient "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
 	"github.com/kpaas-io/kpaas/pkg/service/grpcutils/mock"
 	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/service/model/sshcertificate"
 )
 
 func TestTestConnectNode(t *testing.T) {
@@ -61,3 +63,149 @@ func TestTestConnectNode(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, responseData.Success)
 }
+
+func TestGetCallTestConnectionDataOfLocalhost(t *testing.T) {
+
+	request := getCallTestConnectionData(&api.ConnectionData{
+		IP:   "127.0.0.1",
+		Port: uint16(22),
+		SSHLoginData: api.SSHLoginData{
+			Username:           "root",
+			AuthenticationType: api.AuthenticationTypePassword,
+			Password:           "123456",
+		},
+		Localhost: true,
+	})
+	assert.True(t, request.GetNode().GetLocalhost())
+	assert.Equal(t, "127.0.0.1", request.GetNode().GetIp())
+}
+
+func TestTestConnectNodeWithInvalidPrivateKey(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	sshcertificate.ClearList()
+	sshcertificate.SaveCertificate(&sshcertificate.Certificate{
+		Name:       "broken",
+		PrivateKey: "not a private key",
+	})
+
+	tests := []struct {
+		input api.ConnectionData
+		want  int
+	}{
+		{
+			input: api.ConnectionData{
+				IP:   "192.168.31.101",
+				Port: uint16(22),
+				SSHLoginData: api.SSHLoginData{
+					Username:           "root",
+					AuthenticationType: api.AuthenticationTypePrivateKey,
+					PrivateKeyName:     "notExist",
+				},
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			input: api.ConnectionData{
+				IP:   "192.168.31.101",
+				Port: uint16(22),
+				SSHLoginData: api.SSHLoginData{
+					Username:           "root",
+					AuthenticationType: api.AuthenticationTypePrivateKey,
+					PrivateKeyName:     "broken",
+				},
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			input: api.ConnectionData{
+				IP:   "192.168.31.101",
+				Port: uint16(22),
+				SSHLoginData: api.SSHLoginData{
+					Username:           "root",
+					AuthenticationType: api.AuthenticationTypeKeyboardInteractive,
+				},
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			input: api.ConnectionData{
+				IP:   "192.168.31.101",
+				Port: uint16(22),
+				SSHLoginData: api.SSHLoginData{
+					Username:           "root",
+					AuthenticationType: api.AuthenticationTypeKeyboardInteractive,
+					ChallengeResponses: []string{"123456", "908172"},
+				},
+			},
+			want: http.StatusCreated,
+		},
+	}
+
+	for _, test := range tests {
+		resp := httptest.NewRecorder()
+		gin.SetMode(gin.TestMode)
+		ctx, _ := gin.CreateTestContext(resp)
+		bodyContent, err := json.Marshal(test.input)
+		assert.Nil(t, err)
+		ctx.Request = httptest.NewRequest("POST", "/api/v1/ssh/tests", bytes.NewReader(bodyContent))
+
+		TestConnectNode(ctx)
+		resp.Flush()
+		assert.Equal(t, test.want, resp.Code)
+	}
+}
+
+func TestListKnownHosts(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+
+	resp := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/ssh/knownhosts", nil)
+
+	ListKnownHosts(ctx)
+	resp.Flush()
+	assert.Equal(t, http.StatusOK, resp.Code)
+	responseData := new(api.GetKnownHostListResponse)
+	err := json.Unmarshal(resp.Body.Bytes(), responseData)
+	assert.Nil(t, err)
+	assert.Len(t, responseData.KnownHosts, 1)
+	assert.Equal(t, "192.168.31.101", responseData.KnownHosts[0].Host)
+	assert.NotEmpty(t, responseData.KnownHosts[0].Fingerprint)
+}
+
+func TestForgetKnownHost(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		ip   string
+		port string
+		code int
+	}{
+		{ip: "192.168.31.101", code: http.StatusNoContent},
+		{ip: "192.168.31.101", port: "22", code: http.StatusNoContent},
+		{ip: "192.168.31.101", port: "2222", code: http.StatusNotFound},
+		{ip: "192.168.31.102", code: http.StatusNotFound},
+		{ip: "node1", code: http.StatusBadRequest},
+		{ip: "192.168.31.101", port: "65536", code: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		resp := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(resp)
+		ctx.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/ssh/knownhosts/%s?port=%s", test.ip, test.port), nil)
+		ctx.Params = gin.Params{
+			{
+				Key:   "ip",
+				Value: test.ip,
+			},
+		}
+
+		ForgetKnownHost(ctx)
+		assert.Equal(t, test.code, ctx.Writer.Status(), "ip: %s, port: %s", test.ip, test.port)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	"github.com/kpaas-io/kpaas/pkg/service/config"
+	clientUtils "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
+	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/utils/h"
+	"github.com/kpaas-io/kpaas/pkg/utils/log"
+)
+
+// the statuses of the tasks and actions which won't change any more
+var finishedStatuses = map[string]bool{
+	"successful": true,
+	"done":       true,
+	"failed":     true,
+	"aborted":    true,
+}
+
+// @ID GetDeploymentTimeline
+// @Summary Get the timeline of deployment
+// @Description Get when the deploy task, its sub tasks and actions started and finished, and how long they stayed in
+// @Description each status, which is summarized from the journal of the status transitions in deploy controller.
+// @Description The item which failed the deployment at first is pointed out if the deployment failed.
+// @Tags deploy
+// @Produce application/json
+// @Param id path int true "Cluster ID"
+// @Success 200 {object} api.DeployTimeline
+// @Failure 404 {object} h.AppErr
+// @Router /api/v1/deploy/wizard/clusters/{id}/deploys/timelines [get]
+func GetDeployTimeline(c *gin.Context) {
+
+	wizardData := getCluster(c)
+
+	client := clientUtils.GetDeployController()
+
+	grpcContext, cancel := context.WithTimeout(context.Background(), config.Config.DeployController.GetTimeout())
+	defer cancel()
+
+	resp, err := client.GetTaskEvents(grpcContext, &protos.GetTaskEventsRequest{
+		ClusterId: getDeployClusterId(wizardData),
+	})
+	if err != nil {
+		h.E(c, h.EDeployControllerError.WithPayload(err))
+		log.ReqEntry(c).Errorf("call deploy controller error, errorMessage: %v", err)
+		return
+	}
+
+	timeline := buildDeployTimeline(resp.GetTaskName(), resp.GetEvents())
+	timeline.ClusterId = wizardData.ClusterId
+
+	h.R(c, timeline)
+}
+
+// buildDeployTimeline summarizes the status transitions of the task tree into a timeline, the
+// items are in the order they started.
+func buildDeployTimeline(taskName string, events []*protos.JournalEvent) *api.DeployTimeline {
+
+	timeline := &api.DeployTimeline{
+		TaskName: taskName,
+		Items:    make([]*api.TimelineItem, 0),
+	}
+
+	items := make(map[string]*api.TimelineItem)
+	eventItems := make(map[uint64]*api.TimelineItem)
+	eventsBySeq := make(map[uint64]*protos.JournalEvent)
+	for _, event := range events {
+		id := event.GetKind() + "/" + event.GetParent() + "/" + event.GetName()
+		item, ok := items[id]
+		if !ok {
+			item = &api.TimelineItem{
+				Id:         id,
+				Kind:       event.GetKind(),
+				Name:       event.GetName(),
+				Parent:     event.GetParent(),
+				ActionType: event.GetActionType(),
+				NodeName:   event.GetNodeName(),
+				Start:      event.GetTimestamp(),
+				Phases:     make([]*api.TimelinePhase, 0),
+			}
+			items[id] = item
+			timeline.Items = append(timeline.Items, item)
+		}
+		eventItems[event.GetSeq()] = item
+		eventsBySeq[event.GetSeq()] = event
+
+		if phases := item.Phases; len(phases) > 0 && phases[len(phases)-1].End == 0 {
+			phases[len(phases)-1].End = event.GetTimestamp()
+		}
+		item.Status = event.GetToStatus()
+		if event.GetErr() != nil {
+			item.Error = convertDeployControllerErrorToAPIError(event.GetErr())
+		}
+		if finishedStatuses[item.Status] {
+			item.End = event.GetTimestamp()
+			item.Duration = item.End - item.Start
+		} else {
+			item.End = 0
+			item.Duration = 0
+			item.Phases = append(item.Phases, &api.TimelinePhase{
+				Status: item.Status,
+				Start:  event.GetTimestamp(),
+			})
+		}
+
+		// the deploy task itself
+		if event.GetKind() == "task" && event.GetName() == taskName && event.GetParent() == "" {
+			timeline.Status = item.Status
+			timeline.Start = item.Start
+			timeline.End = item.End
+			timeline.FailedBy = ""
+			// trace the failure down to the sub task or action failed at first
+			cause := event
+			for cause.GetCauseSeq() != 0 && eventsBySeq[cause.GetCauseSeq()] != nil {
+				cause = eventsBySeq[cause.GetCauseSeq()]
+			}
+			if cause != event {
+				timeline.FailedBy = eventItems[cause.GetSeq()].Id
+			}
+		}
+	}
+
+	return timeline
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
+	grpcClient "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
+	"github.com/kpaas-io/kpaas/pkg/service/grpcutils/mock"
+	"github.com/kpaas-io/kpaas/pkg/service/model/api"
+	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
+)
+
+func TestGetDeployTimeline(t *testing.T) {
+
+	grpcClient.SetDeployController(mock.NewDeployController())
+	wizardData := wizard.NewCluster()
+	wizardData.ClusterId = 1
+
+	resp := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(resp)
+	setCluster(ctx, wizardData)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/deploy/wizard/clusters/1/deploys/timelines", nil)
+
+	GetDeployTimeline(ctx)
+	resp.Flush()
+	assert.Equal(t, http.StatusOK, resp.Code)
+	fmt.Printf("result: %s\n", resp.Body.String())
+	responseData := new(api.DeployTimeline)
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), responseData))
+
+	assert.Equal(t, uint64(1), responseData.ClusterId)
+	assert.Equal(t, "unknown-deploy", responseData.TaskName)
+	assert.Equal(t, "failed", responseData.Status)
+	assert.Equal(t, int64(1570000000000), responseData.Start)
+	assert.Equal(t, int64(1570000031100), responseData.End)
+	assert.Equal(t, "action/unknown-deploy/init-action", responseData.FailedBy)
+	assert.Len(t, responseData.Items, 2)
+}
+
+func TestBuildDeployTimeline(t *testing.T) {
+
+	events := []*protos.JournalEvent{
+		{Seq: 1, Kind: "task", Name: "deploy", FromStatus: "pending", ToStatus: "doing", Timestamp: 1000},
+		{Seq: 2, Kind: "task", Name: "init", Parent: "deploy", FromStatus: "pending", ToStatus: "doing", Timestamp: 1100},
+		{Seq: 3, Kind: "action", Name: "init-node1", Parent: "init", NodeName: "node1", FromStatus: "pending", ToStatus: "doing", Timestamp: 1200},
+		{Seq: 4, Kind: "action", Name: "init-node1", Parent: "init", NodeName: "node1", FromStatus: "doing", ToStatus: "failed", Err: &protos.Error{Reason: "timeout"}, Timestamp: 3200},
+		{Seq: 5, Kind: "task", Name: "init", Parent: "deploy", FromStatus: "doing", ToStatus: "failed", Timestamp: 3300, CauseSeq: 4},
+		{Seq: 6, Kind: "task", Name: "deploy", FromStatus: "doing", ToStatus: "failed", Timestamp: 3400, CauseSeq: 5},
+		// resumed
+		{Seq: 7, Kind: "task", Name: "deploy", FromStatus: "failed", ToStatus: "doing", Timestamp: 5000},
+		{Seq: 8, Kind: "action", Name: "init-node1", Parent: "init", NodeName: "node1", FromStatus: "failed", ToStatus: "pending", Timestamp: 5100},
+	}
+
+	timeline := buildDeployTimeline("deploy", events)
+	assert.Equal(t, "doing", timeline.Status)
+	assert.Equal(t, int64(1000), timeline.Start)
+	assert.Equal(t, int64(0), timeline.End)
+	assert.Empty(t, timeline.FailedBy)
+	if !assert.Len(t, timeline.Items, 3) {
+		return
+	}
+
+	assert.Equal(t, "task//deploy", timeline.Items[0].Id)
+	assert.Equal(t, []*api.TimelinePhase{
+		{Status: "doing", Start: 1000, End: 3400},
+		{Status: "doing", Start: 5000},
+	}, timeline.Items[0].Phases)
+
+	initItem := timeline.Items[1]
+	assert.Equal(t, "failed", initItem.Status)
+	assert.Equal(t, int64(3300), initItem.End)
+	assert.Equal(t, int64(2200), initItem.Duration)
+
+	actionItem := timeline.Items[2]
+	assert.Equal(t, "action/init/init-node1", actionItem.Id)
+	assert.Equal(t, "node1", actionItem.NodeName)
+	assert.Equal(t, "pending", actionItem.Status)
+	assert.Equal(t, int64(0), actionItem.End)
+	assert.Equal(t, "timeout", actionItem.Error.Reason)
+	assert.Equal(t, []*api.TimelinePhase{
+		{Status: "doing", Start: 1200, End: 3200},
+		{Status: "pending", Start: 5100},
+	}, actionItem.Phases)
+
+	// the failure is traced down to the action
+	timeline = buildDeployTimeline("deploy", events[:6])
+	assert.Equal(t, "failed", timeline.Status)
+	assert.Equal(t, int64(3400), timeline.End)
+	assert.Equal(t, "action/init/init-node1", timeline.FailedBy)
+}
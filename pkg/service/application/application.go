@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -28,9 +29,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/kpaas-io/kpaas/pkg/service/api/v1/deploy"
 	"github.com/kpaas-io/kpaas/pkg/service/config"
 	"github.com/kpaas-io/kpaas/pkg/service/grpcutils/connection"
 	"github.com/kpaas-io/kpaas/pkg/service/model/wizard"
+	"github.com/kpaas-io/kpaas/pkg/service/repository"
 	configUtils "github.com/kpaas-io/kpaas/pkg/utils/config"
 	"github.com/kpaas-io/kpaas/pkg/utils/idcreator"
 	"github.com/kpaas-io/kpaas/pkg/utils/log"
@@ -66,6 +69,7 @@ func (a *app) initService() {
 	a.initRandomSeed()
 	a.initSnowFlake()
 	a.initClients()
+	a.initRepository()
 	a.initMemoriesData()
 	a.initRESTfulAPIHandler()
 	a.initRequestLogger()
@@ -120,6 +124,7 @@ func (a *app) close() {
 	a.ClearMemoryData()
 	a.closeHTTPServer()
 	a.closeGRPCClient()
+	a.closeRepository()
 }
 
 func (a *app) loadConfig() {
@@ -136,6 +141,7 @@ func (a *app) parseParameters() {
 	a.parseParameterListenPort()
 	a.parseParameterLogLevel()
 	a.parseParameterServiceId()
+	a.parseParameterRepository()
 }
 
 func (a *app) parseParameterListenPort() {
@@ -171,6 +177,25 @@ func (a *app) parseParameterServiceId() {
 	logrus.Infof("serviceId: %d", config.Config.Service.GetServiceId())
 }
 
+func (a *app) parseParameterRepository() {
+
+	repositoryType, err := pflag.CommandLine.GetString(FlagRepositoryType)
+	if err == nil && repositoryType != "" {
+		config.Config.Repository.Type = repositoryType
+	}
+
+	repositoryPath, err := pflag.CommandLine.GetString(FlagRepositoryPath)
+	if err == nil && repositoryPath != "" {
+		config.Config.Repository.Path = repositoryPath
+	}
+
+	encryptionKeyFile, err := pflag.CommandLine.GetString(FlagRepositoryEncryptionKeyFile)
+	if err == nil && encryptionKeyFile != "" {
+		config.Config.Repository.EncryptionKeyFile = encryptionKeyFile
+	}
+	logrus.Infof("repository type: %s", config.Config.Repository.GetType())
+}
+
 func (a *app) initRESTfulAPIHandler() {
 
 	logrus.Debug("start to init restful api service handler")
@@ -234,9 +259,75 @@ func (a *app) initSnowFlake() {
 	logrus.Debug("id creator init succeed")
 }
 
+func (a *app) initRepository() {
+
+	logrus.Debug("start to init repository")
+
+	var repo repository.Repository
+	var err error
+	setting := config.Config.Repository
+	switch repository.Type(setting.GetType()) {
+	case repository.TypeNone:
+		logrus.Warn("no repository was set, data will be lost after restart")
+		return
+	case repository.TypeFile:
+		repo, err = repository.NewFileRepository(setting.GetPath())
+	case repository.TypeEtcd:
+		repo, err = repository.NewEtcdRepository(repository.EtcdOptions{
+			Endpoints:      setting.Etcd.Endpoints,
+			Username:       setting.Etcd.Username,
+			Password:       setting.Etcd.Password,
+			CertFile:       setting.Etcd.CertFile,
+			KeyFile:        setting.Etcd.KeyFile,
+			TrustedCAFile:  setting.Etcd.TrustedCAFile,
+			Prefix:         setting.Etcd.Prefix,
+			DialTimeout:    setting.Etcd.DialTimeout,
+			RequestTimeout: setting.Etcd.RequestTimeout,
+		})
+	default:
+		err = fmt.Errorf("unknown repository type: %s", setting.GetType())
+	}
+
+	if err != nil {
+		logrus.Fatalf("init repository error, %v", err)
+	}
+
+	a.initEncryptionKey()
+	repository.SetRepository(repo)
+	logrus.Debug("init repository succeed")
+}
+
+// initEncryptionKey loads the key which encrypts the secrets in repository, the key of the file repository is
+// created in its data directory if the key file is not set.
+func (a *app) initEncryptionKey() {
+
+	setting := config.Config.Repository
+	keyFile := setting.EncryptionKeyFile
+	createIfNotExist := false
+	if keyFile == "" {
+		if repository.Type(setting.GetType()) != repository.TypeFile {
+			logrus.Fatalf("the encryption key file must be set for the %s repository", setting.GetType())
+		}
+		keyFile = filepath.Join(setting.GetPath(), repository.DefaultEncryptionKeyFileName)
+		createIfNotExist = true
+	}
+
+	key, err := repository.LoadEncryptionKey(keyFile, createIfNotExist)
+	if err == nil {
+		err = repository.SetEncryptionKey(key)
+	}
+	if err != nil {
+		logrus.Fatalf("init repository encryption key error, %v", err)
+	}
+}
+
 func (a *app) initMemoriesData() {
 
-	wizard.ClearCurrentWizardData()
+	wizard.ClearClusters()
+
+	// the listeners of the running checks and deployments were lost with the old process, listen them again
+	wizard.SetRunningClusterListener(deploy.ListenRunningCluster)
+	wizard.ListClusters()
 }
 
 func (a *app) markClosing() {
@@ -245,7 +336,7 @@ func (a *app) markClosing() {
 
 func (a *app) ClearMemoryData() {
 
-	wizard.ClearCurrentWizardData()
+	wizard.ClearClusters()
 }
 
 func (a *app) closeGRPCClient() {
@@ -259,6 +350,15 @@ func (a *app) closeGRPCClient() {
 	logrus.Infof("gRPC client closed")
 }
 
+func (a *app) closeRepository() {
+
+	logrus.Infof("closing repository")
+	if err := repository.Close(); err != nil {
+		logrus.Warnf("close repository error, errorMessage: %s", err)
+	}
+	logrus.Infof("repository closed")
+}
+
 func (a *app) closeHTTPServer() {
 
 	logrus.Infof("closing http server")
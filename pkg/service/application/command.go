@@ -26,6 +26,11 @@ const (
 	FlagLogLevel   = "log-level"
 	FlagConfigFile = "config-file"
 	FlagServiceId  = "service-id"
+
+	FlagRepositoryType = "repository-type"
+	FlagRepositoryPath = "repository-path"
+
+	FlagRepositoryEncryptionKeyFile = "repository-encryption-key-file"
 )
 
 func GetCommand() *cobra.Command {
@@ -46,4 +51,7 @@ func setFlags() {
 	pflag.String(FlagLogLevel, "info", "log level(options: trace, debug, info, warn|warning, error, fatal, panic)")
 	pflag.String(FlagConfigFile, "", "config file for json format")
 	pflag.Uint16(FlagServiceId, 0, "distinguish between different services when highly available.")
+	pflag.String(FlagRepositoryType, "", "repository to store data(options: none, file, etcd), etcd settings can only be set in config file.")
+	pflag.String(FlagRepositoryPath, "", "data directory of the file repository.")
+	pflag.String(FlagRepositoryEncryptionKeyFile, "", "file of the key to encrypt the secrets in repository, it's created in the data directory of the file repository if not set.")
 }
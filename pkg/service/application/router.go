@@ -29,34 +29,49 @@ func (a *app) setRoutes() {
 	v1 := a.httpHandler.Group("/api/v1")
 	wizardGroup := v1.Group("/deploy/wizard")
 
-	wizardGroup.GET("/progresses", deploy.GetWizardProgress)
-	wizardGroup.DELETE("/progresses", deploy.ClearWizard)
+	wizardGroup.GET("/clusters", deploy.ListClusterDrafts)
+	wizardGroup.POST("/clusters", deploy.CreateClusterDraft)
 
-	wizardGroup.GET("/clusters", deploy.GetCluster)
-	wizardGroup.POST("/clusters", deploy.SetCluster)
+	// group for the wizard data of a cluster draft.
+	clusterGroup := wizardGroup.Group("/clusters/:id", deploy.LoadClusterDraft)
+	clusterGroup.GET("", deploy.GetCluster)
+	clusterGroup.PUT("", deploy.SetCluster)
+	clusterGroup.DELETE("", deploy.DeleteClusterDraft)
 
-	wizardGroup.GET("/nodes", deploy.GetNodeList)
-	wizardGroup.GET("/nodes/:ip", deploy.GetNode)
-	wizardGroup.POST("/nodes", deploy.AddNode)
-	wizardGroup.PUT("/nodes/:ip", deploy.UpdateNode)
-	wizardGroup.DELETE("/nodes/:ip", deploy.DeleteNode)
+	clusterGroup.GET("/progresses", deploy.GetWizardProgress)
+	clusterGroup.DELETE("/progresses", deploy.ClearWizard)
 
-	wizardGroup.POST("/batchnodes", deploy.UploadBatchNodes)
+	clusterGroup.GET("/nodes", deploy.GetNodeList)
+	clusterGroup.GET("/nodes/:ip", deploy.GetNode)
+	clusterGroup.POST("/nodes", deploy.AddNode)
+	clusterGroup.PUT("/nodes/:ip", deploy.UpdateNode)
+	clusterGroup.DELETE("/nodes/:ip", deploy.DeleteNode)
 
-	wizardGroup.POST("/checks", deploy.CheckNodeList)
-	wizardGroup.GET("/checks", deploy.GetCheckingNodeListResult)
+	clusterGroup.POST("/batchnodes", deploy.UploadBatchNodes)
 
-	wizardGroup.POST("/deploys", deploy.Deploy)
-	wizardGroup.GET("/deploys", deploy.GetDeployReport)
+	clusterGroup.POST("/checks", deploy.CheckNodeList)
+	clusterGroup.GET("/checks", deploy.GetCheckingNodeListResult)
+	clusterGroup.GET("/checks/logs/:ip", deploy.FollowCheckNodesLog)
 
-	wizardGroup.GET("/logs/:id", deploy.DownloadLog)
+	clusterGroup.POST("/deploys", deploy.Deploy)
+	clusterGroup.GET("/deploys", deploy.GetDeployReport)
+	clusterGroup.DELETE("/deploys", deploy.CancelDeploy)
+	clusterGroup.GET("/deploys/logs/:ip", deploy.FollowDeployLog)
+	clusterGroup.GET("/deploys/timelines", deploy.GetDeployTimeline)
+
+	clusterGroup.POST("/plans", deploy.PlanDeploy)
 
-	wizardGroup.GET("/kubeconfigs", deploy.DownloadKubeConfig)
+	clusterGroup.GET("/kubeconfigs", deploy.DownloadKubeConfig)
 
-	wizardGroup.POST("/networks", deploy.SetNetwork)
-	wizardGroup.GET("/networks", deploy.GetNetwork)
+	clusterGroup.POST("/networks", deploy.SetNetwork)
+	clusterGroup.GET("/networks", deploy.GetNetwork)
+
+	wizardGroup.GET("/logs/:id", deploy.DownloadLog)
+	wizardGroup.GET("/events", deploy.WatchEvents)
 
 	v1.POST("/ssh/tests", deploy.TestConnectNode)
+	v1.GET("/ssh/knownhosts", deploy.ListKnownHosts)
+	v1.DELETE("/ssh/knownhosts/:ip", deploy.ForgetKnownHost)
 
 	v1.POST("/ssh_certificates", deploy.AddSSHCertificate)
 	v1.GET("/ssh_certificates", deploy.GetCertificateList)
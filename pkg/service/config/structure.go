@@ -28,6 +28,8 @@ const (
 	DefaultReadWriteTimeout                       = time.Minute
 	DefaultDeployControllerAddress                = "127.0.0.1:8081"
 	DefaultServiceId                              = 0
+	DefaultRepositoryType                         = "none"
+	DefaultRepositoryPath                         = "/var/lib/kpaas/service"
 )
 
 type (
@@ -38,6 +40,7 @@ type (
 		Service          serviceSetting          `json:"service"`
 		Log              logSetting              `json:"log"`
 		DeployController deployControllerSetting `json:"deployController"`
+		Repository       repositorySetting       `json:"repository"`
 	}
 
 	serviceSetting struct {
@@ -55,6 +58,27 @@ type (
 		Address string        `json:"address"`
 		Timeout time.Duration `json:"timeout"`
 	}
+
+	repositorySetting struct {
+		Type string      `json:"type"` // type: none, file, etcd. none means the data is only kept in memory.
+		Path string      `json:"path"` // data directory of the file repository
+		Etcd etcdSetting `json:"etcd"`
+		// file of the key which encrypts the secrets in repository, like ssh private keys. It's created in the data
+		// directory of the file repository if not set, and must be set for the etcd repository shared by replicas.
+		EncryptionKeyFile string `json:"encryptionKeyFile"`
+	}
+
+	etcdSetting struct {
+		Endpoints      []string      `json:"endpoints"`
+		Username       string        `json:"username"`
+		Password       string        `json:"password"`
+		CertFile       string        `json:"certFile"` // client certificate file of the TLS connection
+		KeyFile        string        `json:"keyFile"`
+		TrustedCAFile  string        `json:"trustedCAFile"`
+		Prefix         string        `json:"prefix"` // prefix of the keys, multiple replicas share data by the same prefix
+		DialTimeout    time.Duration `json:"dialTimeout"`
+		RequestTimeout time.Duration `json:"requestTimeout"`
+	}
 )
 
 var (
@@ -110,3 +134,19 @@ func (controller *deployControllerSetting) GetTimeout() time.Duration {
 	}
 	return controller.Timeout
 }
+
+func (repository *repositorySetting) GetType() string {
+
+	if repository.Type == "" {
+		return DefaultRepositoryType
+	}
+	return repository.Type
+}
+
+func (repository *repositorySetting) GetPath() string {
+
+	if repository.Path == "" {
+		return DefaultRepositoryPath
+	}
+	return repository.Path
+}
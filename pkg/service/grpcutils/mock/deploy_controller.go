@@ -15,7 +15,10 @@
 package mock
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"strings"
 
 	"google.golang.org/grpc"
 
@@ -88,6 +91,13 @@ func (mock *DeployController) Deploy(ctx context.Context, in *protos.DeployReque
 		Err:      nil,
 	}, nil
 }
+func (mock *DeployController) ResumeDeploy(ctx context.Context, in *protos.ResumeDeployRequest, opts ...grpc.CallOption) (*protos.ResumeDeployReply, error) {
+
+	return &protos.ResumeDeployReply{
+		Accepted: true,
+		Err:      nil,
+	}, nil
+}
 func (mock *DeployController) GetDeployResult(ctx context.Context, in *protos.GetDeployResultRequest, opts ...grpc.CallOption) (*protos.GetDeployResultReply, error) {
 
 	return &protos.GetDeployResultReply{
@@ -158,3 +168,231 @@ func (mock *DeployController) GetDeployLog(ctx context.Context, in *protos.GetDe
 	// To be implmented
 	return nil, nil
 }
+
+func (mock *DeployController) CancelTask(ctx context.Context, in *protos.CancelTaskRequest, opts ...grpc.CallOption) (*protos.CancelTaskReply, error) {
+
+	return &protos.CancelTaskReply{
+		Cancelled: true,
+		Err:       nil,
+	}, nil
+}
+
+func (mock *DeployController) ListTasks(ctx context.Context, in *protos.ListTasksRequest, opts ...grpc.CallOption) (*protos.ListTasksReply, error) {
+
+	return &protos.ListTasksReply{
+		Tasks: []*protos.TaskSummary{
+			{
+				Name:              "unknown-deploy",
+				Type:              "Deploy",
+				Status:            string(constant.OperationStatusSuccessful),
+				CreationTimestamp: 1570000000,
+				ClusterId:         in.GetClusterId(),
+			},
+		},
+		ClusterId: in.GetClusterId(),
+	}, nil
+}
+
+func (mock *DeployController) GetTaskGraph(ctx context.Context, in *protos.GetTaskGraphRequest, opts ...grpc.CallOption) (*protos.GetTaskGraphReply, error) {
+
+	return &protos.GetTaskGraphReply{
+		ClusterId: in.GetClusterId(),
+		TaskName:  "unknown-deploy",
+		Nodes: []*protos.TaskGraphNode{
+			{Kind: "task", Name: "unknown-deploy", Type: "Deploy", Status: "successful"},
+			{Kind: "task", Name: "init-master1", Type: "NodeInit", Parent: "unknown-deploy", Status: "successful"},
+			{Kind: "task", Name: "deploy-etcd", Type: "DeployEtcd", Parent: "unknown-deploy", Status: "successful"},
+		},
+		Edges: []*protos.TaskGraphEdge{
+			{From: "init-master1", To: "deploy-etcd"},
+		},
+	}, nil
+}
+
+func (mock *DeployController) PlanDeploy(ctx context.Context, in *protos.PlanDeployRequest, opts ...grpc.CallOption) (*protos.PlanDeployReply, error) {
+
+	return &protos.PlanDeployReply{
+		ClusterId: in.GetClusterId(),
+		Plan: &protos.TaskPlan{
+			Name: "unknown-deploy",
+			Type: "Deploy",
+			SubTasks: []*protos.TaskPlan{
+				{
+					Name:   "deploy-etcd",
+					Type:   "DeployEtcd",
+					Parent: "unknown-deploy",
+					Actions: []*protos.ActionPlan{
+						{
+							Name:     "deploy-etcd-master1",
+							Type:     "DeployEtcd",
+							NodeName: "master1",
+							Steps: []*protos.PlanStep{
+								{NodeName: "master1", Action: "putFile", Path: "/etc/kubernetes/pki/etcd/ca.crt", Mode: "0644", ContentHidden: true},
+								{NodeName: "master1", Action: "run", Command: "docker start kpaas-etcd"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (mock *DeployController) GetTaskEvents(ctx context.Context, in *protos.GetTaskEventsRequest, opts ...grpc.CallOption) (*protos.GetTaskEventsReply, error) {
+
+	return &protos.GetTaskEventsReply{
+		ClusterId: in.GetClusterId(),
+		TaskName:  "unknown-deploy",
+		TaskType:  "Deploy",
+		Events: []*protos.JournalEvent{
+			{Seq: 1, Kind: "task", Name: "unknown-deploy", FromStatus: "pending", ToStatus: "initializing", Timestamp: 1570000000000},
+			{Seq: 2, Kind: "task", Name: "unknown-deploy", FromStatus: "initializing", ToStatus: "splitting", Timestamp: 1570000000100, Duration: 100},
+			{Seq: 3, Kind: "task", Name: "unknown-deploy", FromStatus: "splitting", ToStatus: "doing", Timestamp: 1570000000200, Duration: 100},
+			{Seq: 4, Kind: "action", Name: "init-action", Parent: "unknown-deploy", ActionType: "NodeInit", NodeName: "master1", FromStatus: "pending", ToStatus: "doing", Timestamp: 1570000001000, Duration: 1000},
+			{
+				Seq:        5,
+				Kind:       "action",
+				Name:       "init-action",
+				Parent:     "unknown-deploy",
+				ActionType: "NodeInit",
+				NodeName:   "master1",
+				FromStatus: "doing",
+				ToStatus:   "failed",
+				Err:        &protos.Error{Reason: "init failed"},
+				Timestamp:  1570000031000,
+				Duration:   30000,
+			},
+			{Seq: 6, Kind: "task", Name: "unknown-deploy", FromStatus: "doing", ToStatus: "failed", Timestamp: 1570000031100, Duration: 30900, CauseSeq: 5},
+		},
+	}, nil
+}
+
+func (mock *DeployController) WatchTask(ctx context.Context, in *protos.WatchTaskRequest, opts ...grpc.CallOption) (protos.DeployContoller_WatchTaskClient, error) {
+
+	return &watchTaskClient{
+		ctx: ctx,
+		events: []*protos.TaskEvent{
+			{
+				ClusterId: in.GetClusterId(),
+				TaskName:  "unknown-deploy",
+				TaskType:  "Deploy",
+				Kind:      "task",
+				Name:      "unknown-deploy",
+				Status:    "doing",
+				Timestamp: 1570000000,
+			},
+			{
+				ClusterId:  in.GetClusterId(),
+				TaskName:   "unknown-deploy",
+				TaskType:   "Deploy",
+				Kind:       "action",
+				Name:       "init-action",
+				Parent:     "unknown-deploy",
+				ActionType: "init",
+				NodeName:   "master1",
+				Status:     "done",
+				Timestamp:  1570000001,
+			},
+		},
+	}, nil
+}
+
+// watchTaskClient sends the events one by one, then ends the stream.
+type watchTaskClient struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events []*protos.TaskEvent
+}
+
+func (client *watchTaskClient) Recv() (*protos.TaskEvent, error) {
+
+	if len(client.events) == 0 {
+		return nil, io.EOF
+	}
+
+	event := client.events[0]
+	client.events = client.events[1:]
+	return event, nil
+}
+
+func (client *watchTaskClient) Context() context.Context {
+
+	return client.ctx
+}
+
+func (mock *DeployController) TailCheckNodesLog(ctx context.Context, in *protos.TailCheckNodesLogRequest, opts ...grpc.CallOption) (protos.DeployContoller_TailCheckNodesLogClient, error) {
+
+	return newLogChunkClient(ctx, "check nodes log of "+in.GetNodeName()+"\n", in.GetOffset()), nil
+}
+
+func (mock *DeployController) TailDeployLog(ctx context.Context, in *protos.TailDeployLogRequest, opts ...grpc.CallOption) (protos.DeployContoller_TailDeployLogClient, error) {
+
+	return newLogChunkClient(ctx, in.GetRole()+" deploy log of "+in.GetNodeName()+"\n", in.GetOffset()), nil
+}
+
+// logChunkClient sends the log after the offset line by line, then ends the stream.
+type logChunkClient struct {
+	grpc.ClientStream
+	ctx    context.Context
+	log    []byte
+	offset int64
+}
+
+func newLogChunkClient(ctx context.Context, line string, offset int64) *logChunkClient {
+
+	log := []byte(strings.Repeat(line, 3))
+	if offset > int64(len(log)) {
+		offset = int64(len(log))
+	}
+
+	return &logChunkClient{
+		ctx:    ctx,
+		log:    log,
+		offset: offset,
+	}
+}
+
+func (client *logChunkClient) Recv() (*protos.LogChunk, error) {
+
+	if client.offset >= int64(len(client.log)) {
+		return nil, io.EOF
+	}
+
+	data := client.log[client.offset:]
+	if index := bytes.IndexByte(data, '\n'); index >= 0 {
+		data = data[:index+1]
+	}
+
+	chunk := &protos.LogChunk{
+		Data:   data,
+		Offset: client.offset,
+	}
+	client.offset += int64(len(data))
+	return chunk, nil
+}
+
+func (client *logChunkClient) Context() context.Context {
+
+	return client.ctx
+}
+
+func (mock *DeployController) ListKnownHosts(ctx context.Context, in *protos.ListKnownHostsRequest, opts ...grpc.CallOption) (*protos.ListKnownHostsReply, error) {
+
+	return &protos.ListKnownHostsReply{
+		KnownHosts: []*protos.KnownHost{
+			{
+				Host:            "192.168.31.101",
+				KeyType:         "ssh-ed25519",
+				Fingerprint:     "SHA256:2mrE0HfGl6xWW0Hbx0Cz1yGz1BmXMD6wlm5rFqL5eOY",
+				PinnedTimestamp: 1570000000,
+			},
+		},
+	}, nil
+}
+
+func (mock *DeployController) ForgetKnownHost(ctx context.Context, in *protos.ForgetKnownHostRequest, opts ...grpc.CallOption) (*protos.ForgetKnownHostReply, error) {
+
+	return &protos.ForgetKnownHostReply{
+		Forgotten: in.GetHost() == "192.168.31.101:22",
+	}, nil
+}
@@ -19,10 +19,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 	"github.com/kpaas-io/kpaas/pkg/deploy/protos"
 	clientutils "github.com/kpaas-io/kpaas/pkg/service/grpcutils/client"
 	"github.com/kpaas-io/kpaas/pkg/service/model/sshcertificate"
@@ -57,9 +59,15 @@ func KubeConfigPathForCluster(clusterName string) (string, error) {
 		return "", fmt.Errorf("failed to create directory, error %v", err)
 	}
 	// download kubeconfig and save it.
-	w := wizard.GetCurrentWizard()
+	var w *wizard.Cluster
+	for _, cluster := range wizard.ListClusters() {
+		if cluster.Info.ShortName == clusterName {
+			w = cluster
+			break
+		}
+	}
 	if w == nil {
-		return "", fmt.Errorf("failed to get wizard")
+		return "", fmt.Errorf("failed to get wizard of cluster %s", clusterName)
 	}
 	if w.KubeConfig == nil || (*w.KubeConfig) == "" {
 		return "", fmt.Errorf("kubeconfig file is not ready yet, try it later")
@@ -84,23 +92,36 @@ func KubeConfigPathForCluster(clusterName string) (string, error) {
 	connectionData := masterNode.ConnectionData
 	sshAuth := protos.Auth{
 		Username: masterNode.Username,
-		Type:     string(connectionData.AuthenticationType),
 	}
 	switch connectionData.AuthenticationType {
 	case wizard.AuthenticationTypePassword:
+		sshAuth.Type = mssh.AuthTypePassword
 		sshAuth.Credential = connectionData.Password
 	case wizard.AuthenticationTypePrivateKey:
-		sshAuth.Credential = sshcertificate.GetPrivateKey(connectionData.PrivateKeyName)
+		sshAuth.Type = mssh.AuthTypePrivateKey
+		if certificate := sshcertificate.GetCertificate(connectionData.PrivateKeyName); certificate != nil {
+			sshAuth.Credential = certificate.PrivateKey
+			sshAuth.Passphrase = certificate.Passphrase
+			sshAuth.Certificate = certificate.Certificate
+		}
+	case wizard.AuthenticationTypeKeyboardInteractive:
+		sshAuth.Type = mssh.AuthTypeKeyboardInteractive
+		sshAuth.Credential = connectionData.Password
+		sshAuth.ChallengeResponses = connectionData.ChallengeResponses
 	}
 	fetchResponse, err := client.FetchKubeConfig(context.Background(),
-		&protos.FetchKubeConfigRequest{Node: &protos.Node{
-			Name: masterNode.Name,
-			Ip:   masterNode.IP,
-			Ssh: &protos.SSH{
-				Port: uint32(connectionData.Port),
-				Auth: &sshAuth,
+		&protos.FetchKubeConfigRequest{
+			Node: &protos.Node{
+				Name: masterNode.Name,
+				Ip:   masterNode.IP,
+				Ssh: &protos.SSH{
+					Port: uint32(connectionData.Port),
+					Auth: &sshAuth,
+				},
+				Localhost: connectionData.Localhost,
 			},
-		}})
+			ClusterId: strconv.FormatUint(w.ClusterId, 10),
+		})
 	if err != nil {
 		return "", fmt.Errorf("failed to get response of fetching kubeconfig")
 	}
@@ -15,15 +15,16 @@
 package api
 
 import (
-	"golang.org/x/crypto/ssh"
-
+	mssh "github.com/kpaas-io/kpaas/pkg/deploy/machine/ssh"
 	"github.com/kpaas-io/kpaas/pkg/utils/validator"
 )
 
 type (
 	SSHCertificate struct {
-		Name    string `json:"name" binding:"required" minimum:"1" maximum:"20"`
-		Content string `json:"content" binding:"required"`
+		Name        string `json:"name" binding:"required" minimum:"1" maximum:"20"`
+		Content     string `json:"content" binding:"required"`
+		Passphrase  string `json:"passphrase,omitempty"`  // passphrase of the encrypted private key
+		Certificate string `json:"certificate,omitempty"` // OpenSSH user certificate signed by CA for the private key, the content of the "-cert.pub" file
 	}
 
 	GetSSHCertificateListResponse struct {
@@ -34,6 +35,7 @@ type (
 const (
 	CertificateNameLimit    = 20
 	CertificateContentLimit = 10000
+	CertificateLimit        = 20000
 )
 
 func (cert *SSHCertificate) Validate() error {
@@ -41,14 +43,15 @@ func (cert *SSHCertificate) Validate() error {
 	return validator.NewWrapper(
 		validator.ValidateString(cert.Name, "name", validator.ItemNotEmptyLimit, CertificateNameLimit),
 		validator.ValidateString(cert.Content, "content", validator.ItemNotEmptyLimit, CertificateContentLimit),
+		validator.ValidateString(cert.Certificate, "certificate", validator.ItemNoLimit, CertificateLimit),
 		func() error {
-			return verifyPrivateKeyContent(cert.Content)
+			return verifyPrivateKeyContent(cert.Content, cert.Passphrase, cert.Certificate)
 		},
 	).Validate()
 }
 
-func verifyPrivateKeyContent(content string) (err error) {
+func verifyPrivateKeyContent(content, passphrase, certificate string) (err error) {
 
-	_, err = ssh.ParsePrivateKey([]byte(content))
+	_, err = mssh.NewSigner(content, passphrase, certificate)
 	return
 }
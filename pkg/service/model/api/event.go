@@ -0,0 +1,31 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+type (
+	TaskEvent struct {
+		ClusterId  uint64 `json:"clusterId"`                // Cluster ID of the cluster draft
+		TaskName   string `json:"taskName"`                 // Name of the top level task
+		TaskType   string `json:"taskType"`                 // Type of the top level task
+		Kind       string `json:"kind" enums:"task,action"` // Whose status was changed, a task or an action
+		Name       string `json:"name"`                     // Name of the task, sub task or action
+		Parent     string `json:"parent,omitempty"`         // Name of the task which the sub task or action belongs to
+		ActionType string `json:"actionType,omitempty"`     // Action type, only for action
+		NodeName   string `json:"nodeName,omitempty"`       // Node name, only for action
+		Status     string `json:"status"`                   // The new status
+		Error      *Error `json:"error,omitempty"`          // Error message
+		Timestamp  int64  `json:"timestamp"`                // Unix time when the transition was found
+	}
+)
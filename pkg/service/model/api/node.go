@@ -40,23 +40,42 @@ type (
 
 	ConnectionData struct {
 		SSHLoginData `json:",inline"`
+		SudoData     `json:",inline"`
 
-		IP   string `json:"ip" binding:"required" minLength:"1" maxLength:"15"`               // node ip
-		Port uint16 `json:"port" binding:"required" minimum:"1" maximum:"65535" default:"22"` // ssh port
+		IP        string     `json:"ip" binding:"required" minLength:"1" maxLength:"15"`               // node ip
+		Port      uint16     `json:"port" binding:"required" minimum:"1" maximum:"65535" default:"22"` // ssh port
+		JumpHosts []JumpHost `json:"jumpHosts,omitempty"`                                              // bastion hosts which the ssh connection is tunneled through, in connecting order
+		Localhost bool       `json:"localhost,omitempty"`                                              // the node is the host running the deploy controller, which is deployed without ssh
 	}
 
 	UpdateNodeData struct {
 		NodeBaseData `json:",inline"`
 		SSHLoginData `json:",inline"`
+		SudoData     `json:",inline"`
+
+		Port      uint16     `json:"port" binding:"required" minimum:"1" maximum:"65535" default:"22"` // ssh port
+		JumpHosts []JumpHost `json:"jumpHosts,omitempty"`                                              // bastion hosts which the ssh connection is tunneled through, in connecting order
+		Localhost bool       `json:"localhost,omitempty"`                                              // the node is the host running the deploy controller, which is deployed without ssh
+	}
+
+	JumpHost struct {
+		SSHLoginData `json:",inline"`
 
+		IP   string `json:"ip" binding:"required" minLength:"1" maxLength:"15"`               // bastion host ip
 		Port uint16 `json:"port" binding:"required" minimum:"1" maximum:"65535" default:"22"` // ssh port
 	}
 
 	SSHLoginData struct {
-		Username           string             `json:"username" binding:"required" maxLength:"128"`   // ssh username
-		AuthenticationType AuthenticationType `json:"authorizationType" enums:"password,privateKey"` // type of authorization
-		Password           string             `json:"password,omitempty"`                            // login password
-		PrivateKeyName     string             `json:"privateKeyName,omitempty"`                      // the private key name of login
+		Username           string             `json:"username" binding:"required" maxLength:"128"`                       // ssh username
+		AuthenticationType AuthenticationType `json:"authorizationType" enums:"password,privateKey,keyboardInteractive"` // type of authorization
+		Password           string             `json:"password,omitempty"`                                                // login password
+		PrivateKeyName     string             `json:"privateKeyName,omitempty"`                                          // the private key name of login
+		ChallengeResponses []string           `json:"challengeResponses,omitempty"`                                      // responses of keyboard interactive challenges in order, password is used if empty
+	}
+
+	SudoData struct {
+		Sudo         bool   `json:"sudo,omitempty"`         // run commands and put files via sudo, it's required if the user is not root
+		SudoPassword string `json:"sudoPassword,omitempty"` // sudo password, leave it empty if NOPASSWD is configured for the user
 	}
 
 	Taint struct {
@@ -69,7 +88,7 @@ type (
 		Nodes []NodeData `json:"nodes"` // node list
 	}
 
-	AuthenticationType string // Type of authorization,  password or privateKey
+	AuthenticationType string // Type of authorization,  password, privateKey or keyboardInteractive
 
 	TaintEffect string // Taint Effect, NoSchedule, NoExecute or PreferNoSchedule
 )
@@ -78,6 +97,8 @@ const (
 	AuthenticationTypePassword   AuthenticationType = "password"   // Use Password to authorize
 	AuthenticationTypePrivateKey AuthenticationType = "privateKey" // Use RSA PrivateKey to authorize
 
+	AuthenticationTypeKeyboardInteractive AuthenticationType = "keyboardInteractive" // Use keyboard interactive challenges to authorize, like OTP
+
 	TaintEffectNoSchedule       TaintEffect = "NoSchedule"
 	TaintEffectNoExecute        TaintEffect = "NoExecute"
 	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
@@ -90,6 +111,8 @@ const (
 
 	NodeSSHPortMinimum = 1
 	NodeSSHPortMaximum = 65535
+
+	JumpHostCountLimit = 5
 )
 
 func (node *NodeBaseData) Validate() error {
@@ -133,7 +156,7 @@ func (login *SSHLoginData) Validate() error {
 	wrapper := validator.NewWrapper(
 		validator.ValidateString(login.Username, "username", validator.ItemNotEmptyLimit, validator.ItemNoLimit),
 		validator.ValidateRegexp(regexp.MustCompile(NodeUsernameRegularExpression), login.Username, "username"),
-		validator.ValidateStringOptions(string(login.AuthenticationType), "authorizationType", []string{string(AuthenticationTypePassword), string(AuthenticationTypePrivateKey)}),
+		validator.ValidateStringOptions(string(login.AuthenticationType), "authorizationType", []string{string(AuthenticationTypePassword), string(AuthenticationTypePrivateKey), string(AuthenticationTypeKeyboardInteractive)}),
 	)
 
 	switch login.AuthenticationType {
@@ -145,6 +168,12 @@ func (login *SSHLoginData) Validate() error {
 		wrapper.AddValidateFunc(
 			validator.ValidateString(login.PrivateKeyName, "privateKeyName", validator.ItemNotEmptyLimit, validator.ItemNoLimit),
 		)
+	case AuthenticationTypeKeyboardInteractive:
+		if len(login.ChallengeResponses) == 0 {
+			wrapper.AddValidateFunc(
+				validator.ValidateString(login.Password, "password", validator.ItemNotEmptyLimit, validator.ItemNoLimit),
+			)
+		}
 	}
 
 	return wrapper.Validate()
@@ -155,7 +184,7 @@ func (login *SSHLoginData) ValidateWithoutPassword() error {
 	wrapper := validator.NewWrapper(
 		validator.ValidateString(login.Username, "username", validator.ItemNotEmptyLimit, validator.ItemNoLimit),
 		validator.ValidateRegexp(regexp.MustCompile(NodeUsernameRegularExpression), login.Username, "username"),
-		validator.ValidateStringOptions(string(login.AuthenticationType), "authorizationType", []string{string(AuthenticationTypePassword), string(AuthenticationTypePrivateKey)}),
+		validator.ValidateStringOptions(string(login.AuthenticationType), "authorizationType", []string{string(AuthenticationTypePassword), string(AuthenticationTypePrivateKey), string(AuthenticationTypeKeyboardInteractive)}),
 	)
 
 	switch login.AuthenticationType {
@@ -170,12 +199,45 @@ func (login *SSHLoginData) ValidateWithoutPassword() error {
 
 func (node *ConnectionData) Validate() error {
 
-	return validator.NewWrapper(
+	wrapper := validator.NewWrapper(
 		validator.ValidateIP(node.IP, "ip"),
 		validator.ValidateIntRange(int(node.Port), "port", NodeSSHPortMinimum, NodeSSHPortMaximum),
 		func() error {
 			return node.SSHLoginData.Validate()
 		},
+		validator.ValidateIntRange(len(node.JumpHosts), "jumpHosts", 0, JumpHostCountLimit),
+	)
+
+	for i := range node.JumpHosts {
+
+		jumpHost := &node.JumpHosts[i]
+		wrapper.AddValidateFunc(
+			func() error {
+				return jumpHost.Validate()
+			},
+		)
+	}
+
+	return wrapper.Validate()
+}
+
+func (jumpHost *JumpHost) Validate() error {
+
+	return validator.NewWrapper(
+		validator.ValidateIP(jumpHost.IP, "jumpHost.ip"),
+		validator.ValidateIntRange(int(jumpHost.Port), "jumpHost.port", NodeSSHPortMinimum, NodeSSHPortMaximum),
+		func() error {
+			return jumpHost.SSHLoginData.Validate()
+		},
+	).Validate()
+}
+
+func (jumpHost *JumpHost) ValidateWithoutPassword() error {
+
+	return validator.NewWrapper(
+		validator.ValidateIP(jumpHost.IP, "jumpHost.ip"),
+		validator.ValidateIntRange(int(jumpHost.Port), "jumpHost.port", NodeSSHPortMinimum, NodeSSHPortMaximum),
+		jumpHost.SSHLoginData.ValidateWithoutPassword,
 	).Validate()
 }
 
@@ -205,11 +267,19 @@ func (taint *Taint) Validate() error {
 
 func (node *UpdateNodeData) Validate() error {
 
-	return validator.NewWrapper(
+	wrapper := validator.NewWrapper(
 		func() error {
 			return node.NodeBaseData.Validate()
 		},
 		validator.ValidateIntRange(int(node.Port), "port", NodeSSHPortMinimum, NodeSSHPortMaximum),
 		node.SSHLoginData.ValidateWithoutPassword,
-	).Validate()
+		validator.ValidateIntRange(len(node.JumpHosts), "jumpHosts", 0, JumpHostCountLimit),
+	)
+
+	for i := range node.JumpHosts {
+
+		wrapper.AddValidateFunc(node.JumpHosts[i].ValidateWithoutPassword)
+	}
+
+	return wrapper.Validate()
 }
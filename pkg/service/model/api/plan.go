@@ -0,0 +1,51 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+type (
+	DeployPlan struct {
+		ClusterId uint64    `json:"clusterId"`       // Cluster ID of the cluster draft
+		Plan      *TaskPlan `json:"plan,omitempty"`  // Plan of the top level deploy task
+		Error     *Error    `json:"error,omitempty"` // Error message if the deployment can't be planned
+	}
+
+	TaskPlan struct {
+		Name         string        `json:"name"`                   // Name of the task
+		Type         string        `json:"type"`                   // Type of the task
+		Parent       string        `json:"parent,omitempty"`       // Name of the parent task
+		Dependencies []string      `json:"dependencies,omitempty"` // Names of the sibling tasks which must be done before the task
+		SubTasks     []*TaskPlan   `json:"subTasks,omitempty"`     // Plans of the sub tasks
+		Actions      []*ActionPlan `json:"actions,omitempty"`      // Plans of the actions of the task
+		Error        *Error        `json:"error,omitempty"`        // Error message if the task can't be planned
+	}
+
+	ActionPlan struct {
+		Name     string      `json:"name"`            // Name of the action
+		Type     string      `json:"type"`            // Type of the action
+		NodeName string      `json:"nodeName"`        // Node which the action is done on
+		Steps    []*PlanStep `json:"steps"`           // Commands run and files put in order
+		Error    *Error      `json:"error,omitempty"` // Error message if the action can't be planned
+	}
+
+	PlanStep struct {
+		NodeName      string `json:"nodeName"`                          // Node which the step is done on
+		Action        string `json:"action" enums:"run,putFile,putDir"` // What the step does
+		Command       string `json:"command,omitempty"`                 // Command run, only for run
+		Path          string `json:"path,omitempty"`                    // Path of the file or directory put
+		Mode          string `json:"mode,omitempty"`                    // File mode of the file put
+		Content       string `json:"content,omitempty"`                 // Content of the file put
+		ContentHidden bool   `json:"contentHidden,omitempty"`           // Whether the content of the file put is hidden, for the scripts and certs
+	}
+)
@@ -20,4 +20,15 @@ type (
 
 		Error *Error `json:"error,omitempty"` // Error Detail
 	}
+
+	KnownHost struct {
+		Host        string `json:"host"`        // Host address, the port is omitted if it's 22, like "192.168.0.1" or "[192.168.0.1]:2222"
+		KeyType     string `json:"keyType"`     // Type of the pinned host key
+		Fingerprint string `json:"fingerprint"` // SHA256 fingerprint of the pinned host key
+		PinnedAt    int64  `json:"pinnedAt"`    // Unix time when the host key was pinned
+	}
+
+	GetKnownHostListResponse struct {
+		KnownHosts []KnownHost `json:"knownHosts"`
+	}
 )
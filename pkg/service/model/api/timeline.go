@@ -0,0 +1,48 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+type (
+	DeployTimeline struct {
+		ClusterId uint64          `json:"clusterId"`          // Cluster ID of the cluster draft
+		TaskName  string          `json:"taskName"`           // Name of the deploy task
+		Status    string          `json:"status"`             // The current status of the deploy task
+		Start     int64           `json:"start"`              // Unix time in milliseconds when the deployment started
+		End       int64           `json:"end,omitempty"`      // Unix time in milliseconds when the deployment finished, 0 if unfinished
+		FailedBy  string          `json:"failedBy,omitempty"` // ID of the item which failed the deployment at first
+		Items     []*TimelineItem `json:"items"`              // The deploy task, its sub tasks and actions in the order they started
+	}
+
+	TimelineItem struct {
+		Id         string           `json:"id"`                       // ID of the item, made of the kind, parent and name
+		Kind       string           `json:"kind" enums:"task,action"` // A task or an action
+		Name       string           `json:"name"`                     // Name of the task, sub task or action
+		Parent     string           `json:"parent,omitempty"`         // Name of the task which the sub task or action belongs to
+		ActionType string           `json:"actionType,omitempty"`     // Action type, only for action
+		NodeName   string           `json:"nodeName,omitempty"`       // Node name, only for action
+		Status     string           `json:"status"`                   // The current status
+		Start      int64            `json:"start"`                    // Unix time in milliseconds when it started
+		End        int64            `json:"end,omitempty"`            // Unix time in milliseconds when it finished, 0 if unfinished
+		Duration   int64            `json:"duration,omitempty"`       // Milliseconds from start to end, 0 if unfinished
+		Error      *Error           `json:"error,omitempty"`          // The last error message
+		Phases     []*TimelinePhase `json:"phases"`                   // The statuses it stayed in before finishing, for the bars of a Gantt chart
+	}
+
+	TimelinePhase struct {
+		Status string `json:"status"`        // The status in the phase
+		Start  int64  `json:"start"`         // Unix time in milliseconds when the phase started
+		End    int64  `json:"end,omitempty"` // Unix time in milliseconds when the phase ended, 0 if it's the current one
+	}
+)
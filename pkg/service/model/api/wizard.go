@@ -31,6 +31,14 @@ type (
 		DeployClusterStatus DeployClusterStatus          `json:"deployClusterStatus" enums:"pending,running,successful,failed,workedButHaveError"`                           // Cluster deployment status
 	}
 
+	ClusterDraft struct {
+		ClusterId           uint64               `json:"clusterId"`                                                                        // Cluster ID, used to access the wizard data of the cluster
+		ShortName           string               `json:"shortName"`                                                                        // Cluster short name
+		Name                string               `json:"name"`                                                                             // Cluster name
+		CheckResult         constant.CheckResult `json:"checkResult" enums:"pending,running,successful,failed"`                            // Nodes check result
+		DeployClusterStatus DeployClusterStatus  `json:"deployClusterStatus" enums:"pending,running,successful,failed,workedButHaveError"` // Cluster deployment status
+	}
+
 	Progress   string
 	WizardMode string
 )
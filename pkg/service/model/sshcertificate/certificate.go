@@ -15,18 +15,32 @@
 package sshcertificate
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
 	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/service/repository"
+)
+
+const (
+	repositoryKeyPrefix = "sshcertificates/"
 )
 
 type (
 	Certificate struct {
-		Name       string
-		PrivateKey string
+		Name        string
+		PrivateKey  string
+		Passphrase  string // passphrase of the encrypted private key
+		Certificate string // OpenSSH user certificate of the private key, in authorized_keys format
 	}
 )
 
 var (
-	list *sync.Map
+	list *sync.Map // cache of the certificates, Key name, Value *Certificate
 )
 
 func NewCertificate() *Certificate {
@@ -41,26 +55,137 @@ func ClearList() {
 	list = new(sync.Map)
 }
 
-func AddCertificate(name, privateKey string) {
+func AddCertificate(name, privateKey string) error {
 
-	list.Store(name, privateKey)
+	return SaveCertificate(&Certificate{
+		Name:       name,
+		PrivateKey: privateKey,
+	})
+}
+
+// SaveCertificate adds the certificate, or replaces the one with the same name.
+// The certificate is encrypted in the repository because it has the private key.
+func SaveCertificate(certificate *Certificate) error {
+
+	if repo := repository.GetRepository(); repo != nil {
+		value, err := json.Marshal(certificate)
+		if err != nil {
+			return err
+		}
+		if value, err = repository.Encrypt(value); err != nil {
+			return fmt.Errorf("encrypt ssh certificate %s error: %v", certificate.Name, err)
+		}
+		if err := repo.Put(getRepositoryKey(certificate.Name), value); err != nil {
+			return err
+		}
+	}
+
+	list.Store(certificate.Name, certificate)
+	return nil
 }
 
 func GetNameList() []string {
 
 	var names []string
+	exists := make(map[string]bool)
 	list.Range(func(key, value interface{}) bool {
 		names = append(names, key.(string))
+		exists[key.(string)] = true
 		return true
 	})
+
+	repo := repository.GetRepository()
+	if repo == nil {
+		return names
+	}
+
+	values, err := repo.List(repositoryKeyPrefix)
+	if err != nil {
+		logrus.Errorf("list ssh certificates from repository error: %v", err)
+		return names
+	}
+
+	for key := range values {
+		name, err := url.PathUnescape(strings.TrimPrefix(key, repositoryKeyPrefix))
+		if err != nil || exists[name] {
+			continue
+		}
+		names = append(names, name)
+	}
 	return names
 }
 
-func GetPrivateKey(name string) string {
+// GetCertificate returns nil if the certificate doesn't exist.
+func GetCertificate(name string) *Certificate {
 
-	privateKey, exist := list.Load(name)
+	certificate, exist := list.Load(name)
 	if exist {
-		return privateKey.(string)
+		return certificate.(*Certificate)
+	}
+
+	repo := repository.GetRepository()
+	if repo == nil {
+		return nil
+	}
+
+	value, err := repo.Get(getRepositoryKey(name))
+	if err != nil {
+		if err != repository.ErrNotFound {
+			logrus.Errorf("get ssh certificate %s from repository error: %v", name, err)
+		}
+		return nil
 	}
-	return ""
+
+	loaded, err := decodeCertificate(name, value)
+	if err != nil {
+		logrus.Errorf("decode ssh certificate %s error: %v", name, err)
+		return nil
+	}
+
+	// the certificate saved by the older version is not encrypted, encrypt it
+	if !repository.IsEncrypted(value) {
+		if err := SaveCertificate(loaded); err != nil {
+			logrus.Errorf("encrypt ssh certificate %s in repository error: %v", name, err)
+		}
+	}
+
+	list.Store(name, loaded)
+	return loaded
+}
+
+func GetPrivateKey(name string) string {
+
+	certificate := GetCertificate(name)
+	if certificate == nil {
+		return ""
+	}
+	return certificate.PrivateKey
+}
+
+// decodeCertificate decodes the certificate saved in the repository, the value is the private key
+// itself if it was saved before the passphrase and certificate are supported.
+func decodeCertificate(name string, value []byte) (*Certificate, error) {
+
+	if repository.IsEncrypted(value) {
+		var err error
+		if value, err = repository.Decrypt(value); err != nil {
+			return nil, err
+		}
+	}
+
+	certificate := new(Certificate)
+	if err := json.Unmarshal(value, certificate); err != nil {
+		return &Certificate{
+			Name:       name,
+			PrivateKey: string(value),
+		}, nil
+	}
+
+	certificate.Name = name
+	return certificate, nil
+}
+
+func getRepositoryKey(name string) string {
+
+	return repositoryKeyPrefix + url.PathEscape(name)
 }
@@ -15,9 +15,14 @@
 package sshcertificate
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/service/repository"
 )
 
 func TestNewCertificate(t *testing.T) {
@@ -57,9 +62,9 @@ ls3Q/5aeF7hB2MXfAAAAGEx1Y2t5Ym95c0BMdWNreU1hYy5sb2NhbAEC
 -----END OPENSSH PRIVATE KEY-----`
 	AddCertificate(keyName, privateKey)
 
-	loadPrivateKey, exist := list.Load(keyName)
+	loadCertificate, exist := list.Load(keyName)
 	assert.True(t, exist)
-	assert.Equal(t, privateKey, loadPrivateKey)
+	assert.Equal(t, privateKey, loadCertificate.(*Certificate).PrivateKey)
 }
 
 func TestGetPrivateKey(t *testing.T) {
@@ -92,7 +97,7 @@ UpYgsuXDxma0RK713UCzZCP8o1ucwoNdGJaRDxIEIzTyKAwC2k6vURxlY6djoZHv79sfD7
 NiyK6OkjUmiwIwsL4IQ/dsFD+Lrfp1Ilo3Yirz1UE3Zg6UNP5GUKiys8WnvvtC28uv4dGy
 ls3Q/5aeF7hB2MXfAAAAGEx1Y2t5Ym95c0BMdWNreU1hYy5sb2NhbAEC
 -----END OPENSSH PRIVATE KEY-----`
-	list.Store(keyName, privateKey)
+	list.Store(keyName, &Certificate{Name: keyName, PrivateKey: privateKey})
 
 	assert.Equal(t, privateKey, GetPrivateKey(keyName))
 }
@@ -127,7 +132,65 @@ UpYgsuXDxma0RK713UCzZCP8o1ucwoNdGJaRDxIEIzTyKAwC2k6vURxlY6djoZHv79sfD7
 NiyK6OkjUmiwIwsL4IQ/dsFD+Lrfp1Ilo3Yirz1UE3Zg6UNP5GUKiys8WnvvtC28uv4dGy
 ls3Q/5aeF7hB2MXfAAAAGEx1Y2t5Ym95c0BMdWNreU1hYy5sb2NhbAEC
 -----END OPENSSH PRIVATE KEY-----`
-	list.Store(keyName, privateKey)
+	list.Store(keyName, &Certificate{Name: keyName, PrivateKey: privateKey})
 
 	assert.Equal(t, []string{keyName}, GetNameList())
 }
+
+func TestCertificateRepository(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "kpaas-certificate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := repository.NewFileRepository(dir)
+	assert.Nil(t, err)
+	repository.SetRepository(repo)
+	defer repository.SetRepository(nil)
+
+	// the certificates can not be saved without the encryption key
+	ClearList()
+	assert.NotNil(t, AddCertificate("key/1", "privateKey1"))
+
+	assert.Nil(t, repository.SetEncryptionKey(bytes.Repeat([]byte{1}, repository.EncryptionKeySize)))
+	defer repository.SetEncryptionKey(nil)
+
+	assert.Nil(t, AddCertificate("key/1", "privateKey1"))
+	value, err := repo.Get(getRepositoryKey("key/1"))
+	assert.Nil(t, err)
+	assert.True(t, repository.IsEncrypted(value))
+	assert.NotContains(t, string(value), "privateKey1")
+
+	// simulate restart
+	ClearList()
+	assert.Equal(t, []string{"key/1"}, GetNameList())
+	assert.Equal(t, "privateKey1", GetPrivateKey("key/1"))
+	assert.Equal(t, "", GetPrivateKey("key/2"))
+	assert.Nil(t, GetCertificate("key/2"))
+
+	certificate := &Certificate{
+		Name:        "key3",
+		PrivateKey:  "privateKey3",
+		Passphrase:  "passphrase3",
+		Certificate: "certificate3",
+	}
+	assert.Nil(t, SaveCertificate(certificate))
+	// the private key saved by the older version
+	assert.Nil(t, repo.Put(getRepositoryKey("key4"), []byte("privateKey4")))
+
+	ClearList()
+	assert.Equal(t, certificate, GetCertificate("key3"))
+	assert.Equal(t, &Certificate{Name: "key4", PrivateKey: "privateKey4"}, GetCertificate("key4"))
+
+	// the private key saved by the older version is encrypted after loaded
+	value, err = repo.Get(getRepositoryKey("key4"))
+	assert.Nil(t, err)
+	assert.True(t, repository.IsEncrypted(value))
+	ClearList()
+	assert.Equal(t, &Certificate{Name: "key4", PrivateKey: "privateKey4"}, GetCertificate("key4"))
+
+	// the certificates can not be decrypted by another key
+	assert.Nil(t, repository.SetEncryptionKey(bytes.Repeat([]byte{2}, repository.EncryptionKeySize)))
+	ClearList()
+	assert.Nil(t, GetCertificate("key3"))
+}
@@ -15,13 +15,19 @@
 package wizard
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/kpaas-io/kpaas/pkg/constant"
 	"github.com/kpaas-io/kpaas/pkg/service/model/api"
 	"github.com/kpaas-io/kpaas/pkg/service/model/common"
+	"github.com/kpaas-io/kpaas/pkg/service/repository"
 	"github.com/kpaas-io/kpaas/pkg/utils/h"
 	"github.com/kpaas-io/kpaas/pkg/utils/idcreator"
 )
@@ -39,6 +45,7 @@ type (
 		Wizard              *WizardData
 		KubeConfig          *string
 		lock                *sync.RWMutex
+		storedContent       []byte // content of the draft in repository when it was saved or loaded last time
 	}
 
 	ClusterInfo struct {
@@ -78,10 +85,21 @@ const (
 
 	DefaultNodePortMinimum uint16 = 30000
 	DefaultNodePortMaximum uint16 = 32767
+
+	clusterRepositoryKeyPrefix = "wizard/clusters/"
 )
 
 var (
-	wizardData *Cluster
+	// cluster drafts of the wizard, keyed by cluster id
+	clusters = struct {
+		sync.RWMutex
+		m map[uint64]*Cluster
+	}{
+		m: make(map[uint64]*Cluster),
+	}
+
+	// listens the result of the running check or deployment of the draft loaded from repository
+	runningClusterListener func(cluster *Cluster)
 )
 
 func NewCluster() *Cluster {
@@ -184,13 +202,43 @@ func (cluster *Cluster) UpdateNode(node *Node) error {
 	targetNode.ConnectionData.Username = node.ConnectionData.Username
 	targetNode.ConnectionData.AuthenticationType = node.ConnectionData.AuthenticationType
 	targetNode.ConnectionData.PrivateKeyName = node.ConnectionData.PrivateKeyName
+	targetNode.ConnectionData.ChallengeResponses = node.ConnectionData.ChallengeResponses
 	if len(node.ConnectionData.Password) != 0 {
 		targetNode.ConnectionData.Password = node.ConnectionData.Password
 	}
+	targetNode.ConnectionData.Sudo = node.ConnectionData.Sudo
+	if !node.ConnectionData.Sudo {
+		targetNode.ConnectionData.SudoPassword = ""
+	} else if len(node.ConnectionData.SudoPassword) != 0 {
+		targetNode.ConnectionData.SudoPassword = node.ConnectionData.SudoPassword
+	}
+	targetNode.ConnectionData.Localhost = node.ConnectionData.Localhost
+	targetNode.ConnectionData.JumpHosts = mergeJumpHosts(targetNode.ConnectionData.JumpHosts, node.ConnectionData.JumpHosts)
 
 	return nil
 }
 
+// mergeJumpHosts returns the new jump hosts, the password of a jump host is kept from the old one
+// with the same address and user if it's not set.
+func mergeJumpHosts(oldJumpHosts, newJumpHosts []*JumpHost) []*JumpHost {
+
+	for _, newJumpHost := range newJumpHosts {
+		if len(newJumpHost.Password) != 0 ||
+			(newJumpHost.AuthenticationType != AuthenticationTypePassword && newJumpHost.AuthenticationType != AuthenticationTypeKeyboardInteractive) {
+			continue
+		}
+
+		for _, oldJumpHost := range oldJumpHosts {
+			if oldJumpHost.IP == newJumpHost.IP && oldJumpHost.Port == newJumpHost.Port && oldJumpHost.Username == newJumpHost.Username {
+				newJumpHost.Password = oldJumpHost.Password
+				break
+			}
+		}
+	}
+
+	return newJumpHosts
+}
+
 func (cluster *Cluster) DeleteNode(ip string) error {
 
 	cluster.lock.Lock()
@@ -418,17 +466,323 @@ func (data *KubeAPIServerConnectionData) init() {
 	data.KubeAPIServerConnectType = KubeAPIServerConnectTypeFirstMasterIP
 }
 
-func init() {
+// CreateCluster creates a new cluster draft and stores it, the draft can be got by its cluster id later.
+func CreateCluster() (*Cluster, error) {
+
+	cluster := NewCluster()
+
+	clusters.Lock()
+	defer clusters.Unlock()
+
+	if err := SaveCluster(cluster); err != nil {
+		return nil, err
+	}
+
+	clusters.m[cluster.ClusterId] = cluster
+	return cluster, nil
+}
+
+// GetCluster returns the cluster draft, returns nil if the draft not exist.
+// The draft is re-read from the repository, so the changes made by another replica or after restart are loaded.
+func GetCluster(clusterId uint64) *Cluster {
+
+	clusters.Lock()
+	defer clusters.Unlock()
+
+	return getClusterLocked(clusterId)
+}
+
+// ListClusters returns all of the cluster drafts, order by cluster id.
+func ListClusters() []*Cluster {
+
+	clusters.Lock()
+	defer clusters.Unlock()
+
+	loadClustersLocked()
+
+	list := make([]*Cluster, 0, len(clusters.m))
+	for _, cluster := range clusters.m {
+		list = append(list, cluster)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ClusterId < list[j].ClusterId
+	})
+	return list
+}
+
+// DeleteCluster deletes the cluster draft, a checking or deploying draft can not be deleted.
+func DeleteCluster(clusterId uint64) error {
+
+	clusters.Lock()
+	defer clusters.Unlock()
+
+	cluster := getClusterLocked(clusterId)
+	if cluster == nil {
+		return h.ENotFound.WithPayload("cluster not exist")
+	}
+
+	if cluster.GetCheckResult() == constant.CheckResultRunning {
+		return h.EStatusError.WithPayload("It was checking")
+	}
+
+	if cluster.GetDeployClusterStatus() == DeployClusterStatusRunning {
+		return h.EStatusError.WithPayload("It was deploying")
+	}
+
+	if repo := repository.GetRepository(); repo != nil {
+		if err := repo.Delete(getClusterRepositoryKey(clusterId)); err != nil {
+			return err
+		}
+	}
+
+	delete(clusters.m, clusterId)
+	return nil
+}
+
+// ClearClusterData clears all data of the cluster draft but keeps the cluster id,
+// a checking or deploying draft can not be cleared.
+func ClearClusterData(clusterId uint64) (*Cluster, error) {
+
+	clusters.Lock()
+	defer clusters.Unlock()
+
+	oldCluster := getClusterLocked(clusterId)
+	if oldCluster == nil {
+		return nil, h.ENotFound.WithPayload("cluster not exist")
+	}
+
+	if oldCluster.GetCheckResult() == constant.CheckResultRunning {
+		return nil, h.EStatusError.WithPayload("It was checking")
+	}
+
+	if oldCluster.GetDeployClusterStatus() == DeployClusterStatusRunning {
+		return nil, h.EStatusError.WithPayload("It was deploying")
+	}
+
+	cluster := NewCluster()
+	cluster.ClusterId = clusterId
+	if err := SaveCluster(cluster); err != nil {
+		return nil, err
+	}
+
+	clusters.m[clusterId] = cluster
+	return cluster, nil
+}
+
+// SetRunningClusterListener sets the listener of the cluster draft which is loaded from the repository while
+// it's checking or deploying, e.g. after restart, so the result of the check or deployment can be listened again.
+// The listener is called with the drafts locked, it should listen in background.
+func SetRunningClusterListener(listener func(cluster *Cluster)) {
+
+	clusters.Lock()
+	defer clusters.Unlock()
+
+	runningClusterListener = listener
+}
+
+// ClearClusters clears the cached cluster drafts, the drafts stored in repository are kept.
+func ClearClusters() {
+
+	clusters.Lock()
+	defer clusters.Unlock()
+
+	clusters.m = make(map[uint64]*Cluster)
+}
+
+// SaveCluster stores the cluster draft into the repository, it does nothing if there is no repository.
+func SaveCluster(cluster *Cluster) error {
+
+	repo := repository.GetRepository()
+	if repo == nil {
+		return nil
+	}
+
+	content, err := json.Marshal(cluster)
+	if err != nil {
+		return err
+	}
+
+	return storeCluster(repo, cluster, content)
+}
+
+// storeCluster encrypts the encoded cluster draft and stores it into the repository, the draft contains the
+// passwords and private keys of the nodes and jump hosts.
+func storeCluster(repo repository.Repository, cluster *Cluster, content []byte) error {
+
+	value, err := repository.Encrypt(content)
+	if err != nil {
+		return fmt.Errorf("encrypt cluster %d error: %v", cluster.ClusterId, err)
+	}
+
+	if err := repo.Put(getClusterRepositoryKey(cluster.ClusterId), value); err != nil {
+		return err
+	}
+
+	cluster.lock.Lock()
+	defer cluster.lock.Unlock()
+
+	cluster.storedContent = value
+	return nil
+}
+
+// MarshalJSON encodes the cluster with its read lock held, so it can be stored while it is being updated.
+func (cluster *Cluster) MarshalJSON() ([]byte, error) {
+
+	cluster.lock.RLock()
+	defer cluster.lock.RUnlock()
+
+	type plainCluster Cluster
+	return json.Marshal((*plainCluster)(cluster))
+}
+
+func (cluster *Cluster) UnmarshalJSON(data []byte) error {
+
+	type plainCluster Cluster
+	if err := json.Unmarshal(data, (*plainCluster)(cluster)); err != nil {
+		return err
+	}
+
+	cluster.lock = &sync.RWMutex{}
+	return nil
+}
+
+// getClusterLocked returns the cluster draft in repository, the cached draft is returned if there is no repository
+// or the draft was not changed in repository. clusters lock must be held.
+func getClusterLocked(clusterId uint64) *Cluster {
+
+	cached := clusters.m[clusterId]
+
+	repo := repository.GetRepository()
+	if repo == nil {
+		return cached
+	}
+
+	content, err := repo.Get(getClusterRepositoryKey(clusterId))
+	if err != nil {
+		if err != repository.ErrNotFound {
+			logrus.Errorf("get cluster %d from repository error: %v", clusterId, err)
+			return cached
+		}
+		// deleted by another replica
+		delete(clusters.m, clusterId)
+		return nil
+	}
+
+	cluster, err := cacheClusterLocked(content)
+	if err != nil {
+		logrus.Errorf("decode cluster %d error: %v", clusterId, err)
+		return cached
+	}
+
+	return cluster
+}
+
+// loadClustersLocked caches the cluster drafts in repository, the drafts not in repository are removed from the cache.
+// clusters lock must be held.
+func loadClustersLocked() {
+
+	repo := repository.GetRepository()
+	if repo == nil {
+		return
+	}
+
+	values, err := repo.List(clusterRepositoryKeyPrefix)
+	if err != nil {
+		logrus.Errorf("list clusters from repository error: %v", err)
+		return
+	}
+
+	loaded := make(map[uint64]bool, len(values))
+	for key, content := range values {
+
+		cluster, err := cacheClusterLocked(content)
+		if err != nil {
+			logrus.Errorf("decode cluster %s error: %v", key, err)
+			continue
+		}
+
+		loaded[cluster.ClusterId] = true
+	}
+
+	for clusterId := range clusters.m {
+		if !loaded[clusterId] {
+			delete(clusters.m, clusterId)
+		}
+	}
+}
+
+// cacheClusterLocked caches the cluster draft stored in repository, the cached draft is refreshed in place if it
+// was changed in repository by others, so the holders of the draft see the changes. clusters lock must be held.
+func cacheClusterLocked(content []byte) (*Cluster, error) {
+
+	cluster, err := decodeCluster(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// the draft stored by the older versions was not encrypted, encrypt it in place
+	if !repository.IsEncrypted(content) {
+		if err := storeCluster(repository.GetRepository(), cluster, content); err != nil {
+			logrus.Errorf("encrypt stored cluster %d error: %v", cluster.ClusterId, err)
+		}
+	}
+
+	if cached, exist := clusters.m[cluster.ClusterId]; exist {
+		cached.refresh(cluster)
+		return cached, nil
+	}
+
+	clusters.m[cluster.ClusterId] = cluster
+
+	// the listener of the running check or deployment was lost, e.g. after restart, listen it again
+	if runningClusterListener != nil &&
+		(cluster.ClusterCheckResult == constant.CheckResultRunning ||
+			cluster.DeployClusterStatus == DeployClusterStatusRunning) {
+
+		runningClusterListener(cluster)
+	}
+
+	return cluster, nil
+}
+
+// refresh replaces the data of the cluster by the one loaded from repository if it's changed.
+func (cluster *Cluster) refresh(loaded *Cluster) {
+
+	lock := cluster.lock
+	lock.Lock()
+	defer lock.Unlock()
 
-	ClearCurrentWizardData()
+	if bytes.Equal(cluster.storedContent, loaded.storedContent) {
+		return
+	}
+
+	*cluster = *loaded
+	cluster.lock = lock
 }
 
-func GetCurrentWizard() *Cluster {
+// decodeCluster decodes the cluster draft stored in repository, the plaintext draft stored by the older versions
+// is decoded as it is.
+func decodeCluster(value []byte) (*Cluster, error) {
+
+	content := value
+	if repository.IsEncrypted(value) {
+		var err error
+		if content, err = repository.Decrypt(value); err != nil {
+			return nil, err
+		}
+	}
+
+	cluster := new(Cluster)
+	if err := json.Unmarshal(content, cluster); err != nil {
+		return nil, err
+	}
 
-	return wizardData
+	cluster.storedContent = value
+	return cluster, nil
 }
 
-func ClearCurrentWizardData() {
+func getClusterRepositoryKey(clusterId uint64) string {
 
-	wizardData = NewCluster()
+	return fmt.Sprintf("%s%d", clusterRepositoryKeyPrefix, clusterId)
 }
@@ -15,8 +15,12 @@
 package wizard
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"sync"
 	"testing"
 
@@ -24,6 +28,7 @@ import (
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
 	"github.com/kpaas-io/kpaas/pkg/service/model/common"
+	"github.com/kpaas-io/kpaas/pkg/service/repository"
 	"github.com/kpaas-io/kpaas/pkg/utils/h"
 )
 
@@ -37,28 +42,256 @@ func TestNewClusterInfo(t *testing.T) {
 	assert.IsType(t, &ClusterInfo{}, NewClusterInfo())
 }
 
-func TestGetCurrentWizard(t *testing.T) {
+func TestCreateCluster(t *testing.T) {
 
-	cluster := GetCurrentWizard()
+	ClearClusters()
+	cluster, err := CreateCluster()
+	assert.Nil(t, err)
 	assert.Equal(t, DeployClusterStatusPending, cluster.DeployClusterStatus)
 	assert.NotNil(t, cluster.Wizard)
 	assert.NotNil(t, cluster.Nodes)
 	assert.NotNil(t, cluster.Info)
 	assert.Equal(t, ProgressSettingClusterInformation, cluster.Wizard.Progress)
 	assert.Equal(t, WizardModeNormal, cluster.Wizard.WizardMode)
+	assert.Equal(t, cluster, GetCluster(cluster.ClusterId))
+	assert.Nil(t, GetCluster(cluster.ClusterId+1))
 }
 
-func TestClearCurrentWizardData(t *testing.T) {
+func TestListClusters(t *testing.T) {
 
-	var cluster *Cluster
-	cluster = GetCurrentWizard()
+	ClearClusters()
+	assert.Empty(t, ListClusters())
+
+	cluster1, err := CreateCluster()
+	assert.Nil(t, err)
+	cluster2, err := CreateCluster()
+	assert.Nil(t, err)
+	assert.Equal(t, []*Cluster{cluster1, cluster2}, ListClusters())
+
+	ClearClusters()
+	assert.Empty(t, ListClusters())
+}
+
+func TestDeleteCluster(t *testing.T) {
+
+	ClearClusters()
+	cluster1, err := CreateCluster()
+	assert.Nil(t, err)
+	cluster2, err := CreateCluster()
+	assert.Nil(t, err)
+
+	cluster2.DeployClusterStatus = DeployClusterStatusRunning
+	assert.IsType(t, &h.AppErr{}, DeleteCluster(cluster2.ClusterId))
+	assert.Equal(t, h.EStatusError.Status, DeleteCluster(cluster2.ClusterId).(*h.AppErr).Status)
+
+	assert.Nil(t, DeleteCluster(cluster1.ClusterId))
+	assert.Nil(t, GetCluster(cluster1.ClusterId))
+	assert.Equal(t, h.ENotFound.Status, DeleteCluster(cluster1.ClusterId).(*h.AppErr).Status)
+	assert.Equal(t, []*Cluster{cluster2}, ListClusters())
+}
+
+func TestClearClusterData(t *testing.T) {
+
+	ClearClusters()
+	cluster, err := CreateCluster()
+	assert.Nil(t, err)
 	cluster.Wizard.Progress = ProgressSettingNodesInformation
-	cluster = GetCurrentWizard()
-	assert.Equal(t, ProgressSettingNodesInformation, cluster.Wizard.Progress)
+	assert.Equal(t, ProgressSettingNodesInformation, GetCluster(cluster.ClusterId).Wizard.Progress)
+
+	cleared, err := ClearClusterData(cluster.ClusterId)
+	assert.Nil(t, err)
+	assert.Equal(t, cluster.ClusterId, cleared.ClusterId)
+	assert.Equal(t, ProgressSettingClusterInformation, cleared.Wizard.Progress)
+	assert.Equal(t, cleared, GetCluster(cluster.ClusterId))
+
+	_, err = ClearClusterData(cluster.ClusterId + 1)
+	assert.NotNil(t, err)
+
+	cleared.ClusterCheckResult = constant.CheckResultRunning
+	_, err = ClearClusterData(cluster.ClusterId)
+	assert.Equal(t, h.EStatusError.Status, err.(*h.AppErr).Status)
+
+	cleared.ClusterCheckResult = constant.CheckResultSuccessful
+	cleared.DeployClusterStatus = DeployClusterStatusRunning
+	_, err = ClearClusterData(cluster.ClusterId)
+	assert.Equal(t, h.EStatusError.Status, err.(*h.AppErr).Status)
+	assert.Equal(t, cleared, GetCluster(cluster.ClusterId))
+}
 
-	ClearCurrentWizardData()
-	cluster = GetCurrentWizard()
-	assert.Equal(t, ProgressSettingClusterInformation, cluster.Wizard.Progress)
+func TestClusterRepository(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "kpaas-wizard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := repository.NewFileRepository(dir)
+	assert.Nil(t, err)
+	repository.SetRepository(repo)
+	defer repository.SetRepository(nil)
+	assert.Nil(t, repository.SetEncryptionKey(bytes.Repeat([]byte{1}, repository.EncryptionKeySize)))
+	defer repository.SetEncryptionKey(nil)
+
+	ClearClusters()
+	cluster, err := CreateCluster()
+	assert.Nil(t, err)
+	cluster.Info.ShortName = "cluster1"
+	cluster.Wizard.Progress = ProgressSettingNodesInformation
+	node := NewNode()
+	node.Name = "node1"
+	node.IP = "192.168.1.1"
+	assert.Nil(t, cluster.AddNode(node))
+	assert.Nil(t, SaveCluster(cluster))
+
+	// simulate restart
+	ClearClusters()
+	loaded := GetCluster(cluster.ClusterId)
+	assert.NotNil(t, loaded)
+	assert.Equal(t, "cluster1", loaded.Info.ShortName)
+	assert.Equal(t, ProgressSettingNodesInformation, loaded.Wizard.Progress)
+	assert.Equal(t, "192.168.1.1", loaded.GetNodeByName("node1").IP)
+	assert.Nil(t, loaded.AddNode(&Node{ConnectionData: ConnectionData{IP: "192.168.1.2"}, Name: "node2"}))
+
+	ClearClusters()
+	clusters := ListClusters()
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, cluster.ClusterId, clusters[0].ClusterId)
+
+	assert.Nil(t, DeleteCluster(cluster.ClusterId))
+	ClearClusters()
+	assert.Nil(t, GetCluster(cluster.ClusterId))
+	assert.Empty(t, ListClusters())
+}
+
+func TestClusterChangedByOtherReplica(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "kpaas-wizard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := repository.NewFileRepository(dir)
+	assert.Nil(t, err)
+	repository.SetRepository(repo)
+	defer repository.SetRepository(nil)
+	assert.Nil(t, repository.SetEncryptionKey(bytes.Repeat([]byte{1}, repository.EncryptionKeySize)))
+	defer repository.SetEncryptionKey(nil)
+
+	ClearClusters()
+	cluster, err := CreateCluster()
+	assert.Nil(t, err)
+
+	// the draft changed in memory but not saved yet is kept
+	cluster.Info.ShortName = "cluster1"
+	assert.Equal(t, "cluster1", GetCluster(cluster.ClusterId).Info.ShortName)
+	assert.Nil(t, SaveCluster(cluster))
+
+	// another replica updates the draft
+	other, err := decodeCluster(mustGetRepositoryValue(t, repo, cluster.ClusterId))
+	assert.Nil(t, err)
+	other.Info.ShortName = "cluster2"
+	content, err := json.Marshal(other)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.Put(getClusterRepositoryKey(cluster.ClusterId), content))
+
+	assert.Equal(t, cluster, GetCluster(cluster.ClusterId))
+	assert.Equal(t, "cluster2", cluster.Info.ShortName)
+
+	// another replica deletes the draft
+	assert.Nil(t, repo.Delete(getClusterRepositoryKey(cluster.ClusterId)))
+	assert.Nil(t, GetCluster(cluster.ClusterId))
+	assert.Empty(t, ListClusters())
+}
+
+func TestRunningClusterListener(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "kpaas-wizard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := repository.NewFileRepository(dir)
+	assert.Nil(t, err)
+	repository.SetRepository(repo)
+	defer repository.SetRepository(nil)
+	assert.Nil(t, repository.SetEncryptionKey(bytes.Repeat([]byte{1}, repository.EncryptionKeySize)))
+	defer repository.SetEncryptionKey(nil)
+
+	var listened []uint64
+	SetRunningClusterListener(func(cluster *Cluster) {
+		listened = append(listened, cluster.ClusterId)
+	})
+	defer SetRunningClusterListener(nil)
+
+	ClearClusters()
+	checking, err := CreateCluster()
+	assert.Nil(t, err)
+	checking.ClusterCheckResult = constant.CheckResultRunning
+	assert.Nil(t, SaveCluster(checking))
+	deploying, err := CreateCluster()
+	assert.Nil(t, err)
+	deploying.DeployClusterStatus = DeployClusterStatusRunning
+	assert.Nil(t, SaveCluster(deploying))
+	_, err = CreateCluster()
+	assert.Nil(t, err)
+	assert.Empty(t, listened)
+
+	// simulate restart
+	ClearClusters()
+	assert.NotNil(t, GetCluster(checking.ClusterId))
+	assert.Equal(t, []uint64{checking.ClusterId}, listened)
+	assert.Len(t, ListClusters(), 3)
+	assert.Equal(t, []uint64{checking.ClusterId, deploying.ClusterId}, listened)
+
+	// the cached drafts are not listened again
+	assert.Len(t, ListClusters(), 3)
+	assert.Len(t, listened, 2)
+}
+
+func TestClusterEncrypted(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "kpaas-wizard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := repository.NewFileRepository(dir)
+	assert.Nil(t, err)
+	repository.SetRepository(repo)
+	defer repository.SetRepository(nil)
+	assert.Nil(t, repository.SetEncryptionKey(bytes.Repeat([]byte{1}, repository.EncryptionKeySize)))
+	defer repository.SetEncryptionKey(nil)
+
+	ClearClusters()
+	cluster, err := CreateCluster()
+	assert.Nil(t, err)
+	node := NewNode()
+	node.Name = "node1"
+	node.IP = "192.168.1.1"
+	node.Password = "node-password"
+	node.SudoPassword = "sudo-password"
+	assert.Nil(t, cluster.AddNode(node))
+	assert.Nil(t, SaveCluster(cluster))
+
+	value := mustGetRepositoryValue(t, repo, cluster.ClusterId)
+	assert.True(t, repository.IsEncrypted(value))
+	assert.NotContains(t, string(value), "node-password")
+	assert.NotContains(t, string(value), "sudo-password")
+
+	// simulate restart
+	ClearClusters()
+	assert.Equal(t, "node-password", GetCluster(cluster.ClusterId).GetNodeByName("node1").Password)
+
+	// the plaintext draft stored by the older versions is loaded and encrypted
+	content, err := json.Marshal(cluster)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.Put(getClusterRepositoryKey(cluster.ClusterId), content))
+	ClearClusters()
+	assert.Equal(t, "sudo-password", GetCluster(cluster.ClusterId).GetNodeByName("node1").SudoPassword)
+	assert.True(t, repository.IsEncrypted(mustGetRepositoryValue(t, repo, cluster.ClusterId)))
+}
+
+func mustGetRepositoryValue(t *testing.T, repo repository.Repository, clusterId uint64) []byte {
+
+	content, err := repo.Get(getClusterRepositoryKey(clusterId))
+	assert.Nil(t, err)
+	return content
 }
 
 func TestNewKubeAPIServerConnectionData(t *testing.T) {
@@ -450,6 +683,66 @@ func TestCluster_UpdateNode(t *testing.T) {
 	}
 }
 
+func TestCluster_UpdateNodeSudo(t *testing.T) {
+
+	cluster := NewCluster()
+	node := NewNode()
+	node.Name = "node1"
+	node.IP = "192.168.31.101"
+	node.Username = "kpaas"
+	node.Sudo = true
+	node.SudoPassword = "123456"
+	assert.Nil(t, cluster.AddNode(node))
+
+	// the sudo password is kept if it's not set
+	updatedNode := NewNode()
+	updatedNode.Name = "node1"
+	updatedNode.IP = "192.168.31.101"
+	updatedNode.Username = "kpaas"
+	updatedNode.Sudo = true
+	assert.Nil(t, cluster.UpdateNode(updatedNode))
+	assert.True(t, cluster.GetNode("192.168.31.101").Sudo)
+	assert.Equal(t, "123456", cluster.GetNode("192.168.31.101").SudoPassword)
+
+	updatedNode.Sudo = false
+	assert.Nil(t, cluster.UpdateNode(updatedNode))
+	assert.False(t, cluster.GetNode("192.168.31.101").Sudo)
+	assert.Equal(t, "", cluster.GetNode("192.168.31.101").SudoPassword)
+}
+
+func TestMergeJumpHosts(t *testing.T) {
+
+	oldJumpHosts := []*JumpHost{
+		{
+			IP:                 "192.168.31.1",
+			Port:               22,
+			Username:           "ops",
+			AuthenticationType: AuthenticationTypePassword,
+			Password:           "123456",
+		},
+	}
+	newJumpHosts := []*JumpHost{
+		{
+			IP:                 "192.168.31.1",
+			Port:               22,
+			Username:           "ops",
+			AuthenticationType: AuthenticationTypePassword,
+		},
+		{
+			IP:                 "192.168.31.2",
+			Port:               22,
+			Username:           "ops",
+			AuthenticationType: AuthenticationTypePassword,
+		},
+	}
+
+	mergedJumpHosts := mergeJumpHosts(oldJumpHosts, newJumpHosts)
+	assert.Len(t, mergedJumpHosts, 2)
+	assert.Equal(t, "123456", mergedJumpHosts[0].Password)
+	assert.Equal(t, "", mergedJumpHosts[1].Password)
+	assert.Nil(t, mergeJumpHosts(oldJumpHosts, nil))
+}
+
 func TestCluster_DeleteNode(t *testing.T) {
 
 	tests := []struct {
@@ -16,18 +16,26 @@ package wizard
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"sync"
 
+	"github.com/sirupsen/logrus"
+
+	"github.com/kpaas-io/kpaas/pkg/service/repository"
 	"github.com/kpaas-io/kpaas/pkg/utils/idcreator"
 )
 
 const (
 	ServiceNodeID = 0
+
+	logRepositoryKeyPrefix = "logs/"
 )
 
 var (
-	logs map[uint64][]byte // Key Log Id, Value Log detail
+	logs     map[uint64][]byte // Key Log Id, Value Log detail, cache of the logs in repository
+	logsLock = new(sync.RWMutex)
 )
 
 func init() {
@@ -37,43 +45,85 @@ func init() {
 
 func SetLogByReader(reader io.Reader) (logId uint64, err error) {
 
-	logId = newLogId()
-	logs[logId], err = ioutil.ReadAll(reader)
-	return
+	var content []byte
+	if content, err = ioutil.ReadAll(reader); err != nil {
+		return
+	}
+
+	return setLog(content)
 }
 
 func SetLogByString(content string) (logId uint64, err error) {
 
-	logId = newLogId()
-	logs[logId] = []byte(content)
-	return
+	return setLog([]byte(content))
 }
 
 func GetLog(logId uint64) []byte {
 
-	if content, exist := logs[logId]; exist {
+	logsLock.RLock()
+	content, exist := logs[logId]
+	logsLock.RUnlock()
+	if exist {
 		return content
 	}
 
-	return nil
+	repo := repository.GetRepository()
+	if repo == nil {
+		return nil
+	}
+
+	content, err := repo.Get(getLogRepositoryKey(logId))
+	if err != nil {
+		if err != repository.ErrNotFound {
+			logrus.Errorf("get log %d from repository error: %v", logId, err)
+		}
+		return nil
+	}
+
+	logsLock.Lock()
+	logs[logId] = content
+	logsLock.Unlock()
+	return content
 }
 
 func GetLogReader(logId uint64) io.ReadCloser {
 
-	var content []byte
-	var exist bool
-	if content, exist = logs[logId]; !exist {
+	content := GetLog(logId)
+	if content == nil {
 		return nil
 	}
 
 	return ioutil.NopCloser(bytes.NewReader(content))
 }
 
+// InitLogs clears the cached logs, the logs stored in repository are kept.
 func InitLogs() {
 
+	logsLock.Lock()
+	defer logsLock.Unlock()
 	logs = make(map[uint64][]byte)
 }
 
+func setLog(content []byte) (logId uint64, err error) {
+
+	logId = newLogId()
+	if repo := repository.GetRepository(); repo != nil {
+		if err = repo.Put(getLogRepositoryKey(logId), content); err != nil {
+			return
+		}
+	}
+
+	logsLock.Lock()
+	logs[logId] = content
+	logsLock.Unlock()
+	return
+}
+
+func getLogRepositoryKey(logId uint64) string {
+
+	return fmt.Sprintf("%s%d", logRepositoryKeyPrefix, logId)
+}
+
 func newLogId() uint64 {
 
 	return idcreator.NextID()
@@ -17,9 +17,12 @@ package wizard
 import (
 	"bytes"
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kpaas-io/kpaas/pkg/service/repository"
 )
 
 func TestInitLogs(t *testing.T) {
@@ -68,3 +71,23 @@ func TestGetLogReader(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, logContent, readContent)
 }
+
+func TestLogRepository(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "kpaas-log")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := repository.NewFileRepository(dir)
+	assert.Nil(t, err)
+	repository.SetRepository(repo)
+	defer repository.SetRepository(nil)
+
+	logId, err := SetLogByString("testRepository")
+	assert.Nil(t, err)
+
+	// simulate restart
+	InitLogs()
+	assert.Equal(t, []byte("testRepository"), GetLog(logId))
+	assert.Nil(t, GetLog(logId+1))
+}
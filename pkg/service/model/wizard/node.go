@@ -15,6 +15,7 @@
 package wizard
 
 import (
+	"encoding/json"
 	"sync"
 
 	"github.com/kpaas-io/kpaas/pkg/constant"
@@ -43,6 +44,21 @@ type (
 		AuthenticationType AuthenticationType // type of authorization
 		Password           string             // login password
 		PrivateKeyName     string             // the private key name of login
+		ChallengeResponses []string           // responses of keyboard interactive challenges in order
+		JumpHosts          []*JumpHost        // bastion hosts which the ssh connection is tunneled through, in connecting order
+		Sudo               bool               // run commands and put files via sudo
+		SudoPassword       string             // sudo password, empty if NOPASSWD is configured for the user
+		Localhost          bool               // the node is the host running the deploy controller, which is deployed without ssh
+	}
+
+	JumpHost struct {
+		IP                 string             // bastion host ip
+		Port               uint16             // ssh port
+		Username           string             // ssh username
+		AuthenticationType AuthenticationType // type of authorization
+		Password           string             // login password
+		PrivateKeyName     string             // the private key name of login
+		ChallengeResponses []string           // responses of keyboard interactive challenges in order
 	}
 
 	DeploymentReport struct {
@@ -79,7 +95,7 @@ type (
 		Effect TaintEffect
 	}
 
-	AuthenticationType string // Type of authorization,  password or privateKey
+	AuthenticationType string // Type of authorization,  password, privateKey or keyboardInteractive
 
 	TaintEffect string // Taint Effect, NoSchedule, NoExecute or PreferNoSchedule
 
@@ -90,6 +106,8 @@ const (
 	AuthenticationTypePassword   AuthenticationType = "password"   // Use Password to authorize
 	AuthenticationTypePrivateKey AuthenticationType = "privateKey" // Use RSA PrivateKey to authorize
 
+	AuthenticationTypeKeyboardInteractive AuthenticationType = "keyboardInteractive" // Use keyboard interactive challenges to authorize, like OTP
+
 	TaintEffectNoSchedule       TaintEffect = "NoSchedule"
 	TaintEffectNoExecute        TaintEffect = "NoExecute"
 	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
@@ -104,6 +122,16 @@ const (
 	DefaultUsername            = "root"
 )
 
+// MarshalJSON encodes the node with its read lock held, so it can be stored while it is being updated.
+func (node *Node) MarshalJSON() ([]byte, error) {
+
+	node.rwLock.RLock()
+	defer node.rwLock.RUnlock()
+
+	type plainNode Node
+	return json.Marshal((*plainNode)(node))
+}
+
 func NewNode() *Node {
 
 	node := new(Node)
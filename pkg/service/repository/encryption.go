@@ -0,0 +1,167 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// EncryptionKeySize is the size of the AES-256 key which encrypts the secrets in repository
+	EncryptionKeySize = 32
+	// DefaultEncryptionKeyFileName is the key file created in the data directory of the file repository,
+	// it starts with "." so it's never accessed as a key of the repository.
+	DefaultEncryptionKeyFileName = ".encryption.key"
+
+	encryptedValuePrefix = "kpaas-aes-gcm:"
+)
+
+var (
+	ErrNoEncryptionKey = errors.New("encryption key not set")
+
+	encryptionKey []byte
+)
+
+// SetEncryptionKey sets the key which encrypts the secrets in repository, like ssh private keys.
+// The replicas sharing the repository must use the same key.
+func SetEncryptionKey(key []byte) error {
+
+	if key != nil && len(key) != EncryptionKeySize {
+		return fmt.Errorf("invalid encryption key size %d, must be %d", len(key), EncryptionKeySize)
+	}
+
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	encryptionKey = key
+	return nil
+}
+
+// LoadEncryptionKey reads the base64 encoded key from the file,
+// a random key is generated and written into the file if it not exist and create is true.
+func LoadEncryptionKey(path string, create bool) ([]byte, error) {
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && create {
+		return createEncryptionKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read encryption key file %s error: %v", path, err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(content)))
+	if err != nil {
+		return nil, fmt.Errorf("decode encryption key file %s error: %v", path, err)
+	}
+	return key, nil
+}
+
+func createEncryptionKey(path string) ([]byte, error) {
+
+	key := make([]byte, EncryptionKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), fileRepositoryDirMode); err != nil {
+		return nil, err
+	}
+
+	// O_EXCL avoids overwriting the key created by others at the same time
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileRepositoryFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("create encryption key file %s error: %v", path, err)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteString(base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, err
+	}
+	return key, file.Sync()
+}
+
+// Encrypt encrypts the secret value before it's stored into the repository.
+func Encrypt(plaintext []byte) ([]byte, error) {
+
+	aead, err := newAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return []byte(encryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// Decrypt decrypts the value encrypted by Encrypt.
+func Decrypt(value []byte) ([]byte, error) {
+
+	if !IsEncrypted(value) {
+		return nil, errors.New("value is not encrypted")
+	}
+
+	aead, err := newAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(string(value[len(encryptedValuePrefix):]))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("encrypted value is too short")
+	}
+
+	nonce := sealed[:aead.NonceSize()]
+	return aead.Open(nil, nonce, sealed[aead.NonceSize():], nil)
+}
+
+// IsEncrypted returns whether the value was encrypted by Encrypt, the values stored by the older versions were not.
+func IsEncrypted(value []byte) bool {
+
+	return bytes.HasPrefix(value, []byte(encryptedValuePrefix))
+}
+
+func newAEAD() (cipher.AEAD, error) {
+
+	rwLock.RLock()
+	key := encryptionKey
+	rwLock.RUnlock()
+
+	if key == nil {
+		return nil, ErrNoEncryptionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryption(t *testing.T) {
+
+	_, err := Encrypt([]byte("secret"))
+	assert.Equal(t, ErrNoEncryptionKey, err)
+	assert.NotNil(t, SetEncryptionKey([]byte("short")))
+
+	dir, err := ioutil.TempDir("", "kpaas-repository")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, DefaultEncryptionKeyFileName)
+	_, err = LoadEncryptionKey(path, false)
+	assert.NotNil(t, err)
+	key, err := LoadEncryptionKey(path, true)
+	assert.Nil(t, err)
+	assert.Len(t, key, EncryptionKeySize)
+	loaded, err := LoadEncryptionKey(path, true)
+	assert.Nil(t, err)
+	assert.Equal(t, key, loaded)
+
+	assert.Nil(t, SetEncryptionKey(key))
+	defer SetEncryptionKey(nil)
+
+	value, err := Encrypt([]byte("secret"))
+	assert.Nil(t, err)
+	assert.True(t, IsEncrypted(value))
+	assert.NotContains(t, string(value), "secret")
+	plaintext, err := Decrypt(value)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("secret"), plaintext)
+
+	_, err = Decrypt([]byte("secret"))
+	assert.NotNil(t, err)
+
+	// decrypted by another key
+	anotherKey, err := LoadEncryptionKey(filepath.Join(dir, "another.key"), true)
+	assert.Nil(t, err)
+	assert.Nil(t, SetEncryptionKey(anotherKey))
+	_, err = Decrypt(value)
+	assert.NotNil(t, err)
+}
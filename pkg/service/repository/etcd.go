@@ -0,0 +1,178 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/pkg/transport"
+)
+
+const (
+	DefaultEtcdPrefix         = "/kpaas/service/"
+	DefaultEtcdDialTimeout    = 5 * time.Second
+	DefaultEtcdRequestTimeout = 5 * time.Second
+)
+
+type (
+	EtcdOptions struct {
+		Endpoints      []string
+		Username       string // user of the etcd auth, the auth is not used if it's empty
+		Password       string
+		CertFile       string // client certificate file, the connection is secured by TLS if any of the files is set
+		KeyFile        string // client key file
+		TrustedCAFile  string // file of the CA which signs the etcd server certificates, the system CAs are used if empty
+		Prefix         string // prefix of all the keys stored in etcd
+		DialTimeout    time.Duration
+		RequestTimeout time.Duration
+	}
+
+	// etcdRepository stores the data in etcd, so it can be shared by multiple restful replicas.
+	etcdRepository struct {
+		client         *clientv3.Client
+		prefix         string
+		requestTimeout time.Duration
+	}
+)
+
+// NewEtcdRepository returns a repository which stores data in the etcd cluster.
+func NewEtcdRepository(options EtcdOptions) (Repository, error) {
+
+	if len(options.Endpoints) == 0 {
+		return nil, fmt.Errorf("etcd endpoints not set")
+	}
+
+	if options.Prefix == "" {
+		options.Prefix = DefaultEtcdPrefix
+	}
+	if !strings.HasSuffix(options.Prefix, "/") {
+		options.Prefix += "/"
+	}
+	if options.DialTimeout == 0 {
+		options.DialTimeout = DefaultEtcdDialTimeout
+	}
+	if options.RequestTimeout == 0 {
+		options.RequestTimeout = DefaultEtcdRequestTimeout
+	}
+
+	if options.Username == "" && options.Password != "" {
+		return nil, fmt.Errorf("etcd username not set")
+	}
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		return nil, fmt.Errorf("etcd cert file and key file must be set together")
+	}
+
+	tlsConfig, err := newEtcdTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   options.Endpoints,
+		Username:    options.Username,
+		Password:    options.Password,
+		TLS:         tlsConfig,
+		DialTimeout: options.DialTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create etcd client error: %v", err)
+	}
+
+	return &etcdRepository{
+		client:         client,
+		prefix:         options.Prefix,
+		requestTimeout: options.RequestTimeout,
+	}, nil
+}
+
+// newEtcdTLSConfig returns the TLS config of the etcd client, it returns nil if none of the TLS files is set.
+func newEtcdTLSConfig(options EtcdOptions) (*tls.Config, error) {
+
+	if options.CertFile == "" && options.KeyFile == "" && options.TrustedCAFile == "" {
+		return nil, nil
+	}
+
+	tlsInfo := transport.TLSInfo{
+		CertFile:      options.CertFile,
+		KeyFile:       options.KeyFile,
+		TrustedCAFile: options.TrustedCAFile,
+	}
+	tlsConfig, err := tlsInfo.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load etcd tls files error: %v", err)
+	}
+	return tlsConfig, nil
+}
+
+func (repo *etcdRepository) Get(key string) ([]byte, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), repo.requestTimeout)
+	defer cancel()
+
+	resp, err := repo.client.Get(ctx, repo.prefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, ErrNotFound
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+func (repo *etcdRepository) Put(key string, value []byte) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), repo.requestTimeout)
+	defer cancel()
+
+	_, err := repo.client.Put(ctx, repo.prefix+key, string(value))
+	return err
+}
+
+func (repo *etcdRepository) Delete(key string) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), repo.requestTimeout)
+	defer cancel()
+
+	_, err := repo.client.Delete(ctx, repo.prefix+key)
+	return err
+}
+
+func (repo *etcdRepository) List(prefix string) (map[string][]byte, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), repo.requestTimeout)
+	defer cancel()
+
+	resp, err := repo.client.Get(ctx, repo.prefix+prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		values[strings.TrimPrefix(string(kv.Key), repo.prefix)] = kv.Value
+	}
+	return values, nil
+}
+
+func (repo *etcdRepository) Close() error {
+
+	return repo.client.Close()
+}
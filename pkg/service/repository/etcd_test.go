@@ -0,0 +1,254 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/embed"
+	"github.com/coreos/etcd/pkg/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+// startTestEtcd starts an embedded etcd server, the client connection is secured by TLS if tlsInfo is not nil.
+func startTestEtcd(t *testing.T, tlsInfo *transport.TLSInfo) (string, func()) {
+
+	dir, err := ioutil.TempDir("", "kpaas-etcd")
+	assert.Nil(t, err)
+
+	scheme := "http"
+	cfg := embed.NewConfig()
+	cfg.Dir = dir
+	if tlsInfo != nil {
+		scheme = "https"
+		cfg.ClientTLSInfo = *tlsInfo
+	}
+	clientURL := mustParseURL(t, fmt.Sprintf("%s://127.0.0.1:%d", scheme, mustGetFreePort(t)))
+	peerURL := mustParseURL(t, fmt.Sprintf("http://127.0.0.1:%d", mustGetFreePort(t)))
+	cfg.LCUrls, cfg.ACUrls = []url.URL{clientURL}, []url.URL{clientURL}
+	cfg.LPUrls, cfg.APUrls = []url.URL{peerURL}, []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	etcd, err := embed.StartEtcd(cfg)
+	if !assert.Nil(t, err) {
+		os.RemoveAll(dir)
+		t.FailNow()
+	}
+
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		etcd.Close()
+		os.RemoveAll(dir)
+		t.Fatal("etcd server is not ready")
+	}
+
+	return clientURL.String(), func() {
+		etcd.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func mustGetFreePort(t *testing.T) int {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func mustParseURL(t *testing.T, rawURL string) url.URL {
+
+	u, err := url.Parse(rawURL)
+	assert.Nil(t, err)
+	return *u
+}
+
+func TestEtcdRepository(t *testing.T) {
+
+	endpoint, stop := startTestEtcd(t, nil)
+	defer stop()
+
+	repo, err := NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}, Prefix: "/kpaas/test"})
+	assert.Nil(t, err)
+	defer repo.Close()
+
+	value, err := repo.Get("a/b")
+	assert.Equal(t, ErrNotFound, err)
+	assert.Nil(t, value)
+
+	assert.Nil(t, repo.Put("a/b", []byte("value1")))
+	assert.Nil(t, repo.Put("a/b", []byte("value2")))
+	value, err = repo.Get("a/b")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value2"), value)
+
+	assert.Nil(t, repo.Put("logs/1", []byte("log1")))
+	assert.Nil(t, repo.Put("logs/2", []byte("log2")))
+	values, err := repo.List("logs/")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]byte{
+		"logs/1": []byte("log1"),
+		"logs/2": []byte("log2"),
+	}, values)
+
+	assert.Nil(t, repo.Delete("a/b"))
+	_, err = repo.Get("a/b")
+	assert.Equal(t, ErrNotFound, err)
+	assert.Nil(t, repo.Delete("a/b"))
+
+	// the keys are stored with the prefix, so the replicas with other prefixes don't share them
+	other, err := NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}})
+	assert.Nil(t, err)
+	defer other.Close()
+	_, err = other.Get("logs/1")
+	assert.Equal(t, ErrNotFound, err)
+	values, err = other.List("")
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+}
+
+func TestEtcdRepositoryAuth(t *testing.T) {
+
+	endpoint, stop := startTestEtcd(t, nil)
+	defer stop()
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{endpoint}, DialTimeout: DefaultEtcdDialTimeout})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultEtcdRequestTimeout)
+	defer cancel()
+	_, err = client.UserAdd(ctx, "root", "password")
+	assert.Nil(t, err)
+	_, err = client.UserGrantRole(ctx, "root", "root")
+	assert.Nil(t, err)
+	_, err = client.AuthEnable(ctx)
+	assert.Nil(t, err)
+
+	_, err = NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}, Password: "password"})
+	assert.NotNil(t, err)
+
+	_, err = NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}, Username: "root", Password: "wrong"})
+	assert.NotNil(t, err)
+
+	repo, err := NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}})
+	assert.Nil(t, err)
+	assert.NotNil(t, repo.Put("a", []byte("value")))
+	repo.Close()
+
+	repo, err = NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}, Username: "root", Password: "password"})
+	assert.Nil(t, err)
+	defer repo.Close()
+	assert.Nil(t, repo.Put("a", []byte("value")))
+	value, err := repo.Get("a")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestEtcdRepositoryTLS(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "kpaas-etcd-tls")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	caCert, caKey := mustCreateCertificate(t, dir, "ca", nil, nil)
+	mustCreateCertificate(t, dir, "server", caCert, caKey)
+	mustCreateCertificate(t, dir, "client", caCert, caKey)
+	caFile := filepath.Join(dir, "ca.crt")
+
+	endpoint, stop := startTestEtcd(t, &transport.TLSInfo{
+		CertFile:       filepath.Join(dir, "server.crt"),
+		KeyFile:        filepath.Join(dir, "server.key"),
+		TrustedCAFile:  caFile,
+		ClientCertAuth: true,
+	})
+	defer stop()
+
+	_, err = NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}, CertFile: filepath.Join(dir, "client.crt")})
+	assert.NotNil(t, err)
+
+	_, err = NewEtcdRepository(EtcdOptions{Endpoints: []string{endpoint}, TrustedCAFile: filepath.Join(dir, "none.crt")})
+	assert.NotNil(t, err)
+
+	repo, err := NewEtcdRepository(EtcdOptions{
+		Endpoints:     []string{endpoint},
+		CertFile:      filepath.Join(dir, "client.crt"),
+		KeyFile:       filepath.Join(dir, "client.key"),
+		TrustedCAFile: caFile,
+	})
+	assert.Nil(t, err)
+	defer repo.Close()
+
+	assert.Nil(t, repo.Put("a", []byte("value")))
+	value, err := repo.Get("a")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+// mustCreateCertificate writes the certificate and key of 127.0.0.1 into <name>.crt and <name>.key in the dir,
+// the certificate is a self signed CA if parent is nil.
+func mustCreateCertificate(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (
+	*x509.Certificate, *ecdsa.PrivateKey) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0600))
+
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return cert, key
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	fileRepositoryDirMode  = 0700
+	fileRepositoryFileMode = 0600
+	tempFilePrefix         = ".tmp-"
+)
+
+// fileRepository stores each key in a file under the root directory, the key is the relative path of the file.
+type fileRepository struct {
+	root   string
+	rwLock sync.RWMutex
+}
+
+// NewFileRepository returns a repository which stores data in the directory,
+// the directory will be created if it not exist.
+func NewFileRepository(root string) (Repository, error) {
+
+	if root == "" {
+		return nil, fmt.Errorf("file repository path not set")
+	}
+
+	if err := os.MkdirAll(root, fileRepositoryDirMode); err != nil {
+		return nil, fmt.Errorf("create file repository directory %s error: %v", root, err)
+	}
+
+	return &fileRepository{root: root}, nil
+}
+
+func (repo *fileRepository) Get(key string) ([]byte, error) {
+
+	path, err := repo.getPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.rwLock.RLock()
+	defer repo.rwLock.RUnlock()
+
+	value, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+func (repo *fileRepository) Put(key string, value []byte) error {
+
+	path, err := repo.getPath(key)
+	if err != nil {
+		return err
+	}
+
+	repo.rwLock.Lock()
+	defer repo.rwLock.Unlock()
+
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, fileRepositoryDirMode); err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it, so that readers never see a partially written value
+	tempFile, err := ioutil.TempFile(dir, tempFilePrefix)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err = tempFile.Write(value); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err = tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err = tempFile.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tempFile.Name(), fileRepositoryFileMode); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), path)
+}
+
+func (repo *fileRepository) Delete(key string) error {
+
+	path, err := repo.getPath(key)
+	if err != nil {
+		return err
+	}
+
+	repo.rwLock.Lock()
+	defer repo.rwLock.Unlock()
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (repo *fileRepository) List(prefix string) (map[string][]byte, error) {
+
+	repo.rwLock.RLock()
+	defer repo.rwLock.RUnlock()
+
+	values := make(map[string][]byte)
+	err := filepath.Walk(repo.root, func(path string, info os.FileInfo, err error) error {
+
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(repo.root, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relativePath)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		values[key], err = ioutil.ReadFile(path)
+		return err
+	})
+
+	return values, err
+}
+
+func (repo *fileRepository) Close() error {
+
+	return nil
+}
+
+func (repo *fileRepository) getPath(key string) (string, error) {
+
+	if key == "" {
+		return "", fmt.Errorf("empty key")
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+
+		if segment == "" || strings.HasPrefix(segment, ".") || strings.Contains(segment, `\`) {
+			return "", fmt.Errorf("invalid key: %s", key)
+		}
+	}
+
+	return filepath.Join(repo.root, filepath.FromSlash(key)), nil
+}
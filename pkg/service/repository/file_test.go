@@ -0,0 +1,111 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFileRepository(t *testing.T) (Repository, func()) {
+
+	dir, err := ioutil.TempDir("", "kpaas-repository")
+	assert.Nil(t, err)
+
+	repo, err := NewFileRepository(dir)
+	assert.Nil(t, err)
+	return repo, func() { os.RemoveAll(dir) }
+}
+
+func TestFileRepositoryPutGet(t *testing.T) {
+
+	repo, cleanup := newTestFileRepository(t)
+	defer cleanup()
+
+	value, err := repo.Get("a/b")
+	assert.Equal(t, ErrNotFound, err)
+	assert.Nil(t, value)
+
+	assert.Nil(t, repo.Put("a/b", []byte("value1")))
+	value, err = repo.Get("a/b")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value1"), value)
+
+	assert.Nil(t, repo.Put("a/b", []byte("value2")))
+	value, err = repo.Get("a/b")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value2"), value)
+}
+
+func TestFileRepositoryDelete(t *testing.T) {
+
+	repo, cleanup := newTestFileRepository(t)
+	defer cleanup()
+
+	assert.Nil(t, repo.Put("a/b", []byte("value")))
+	assert.Nil(t, repo.Delete("a/b"))
+	_, err := repo.Get("a/b")
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Nil(t, repo.Delete("a/b"))
+}
+
+func TestFileRepositoryList(t *testing.T) {
+
+	repo, cleanup := newTestFileRepository(t)
+	defer cleanup()
+
+	assert.Nil(t, repo.Put("logs/1", []byte("log1")))
+	assert.Nil(t, repo.Put("logs/2", []byte("log2")))
+	assert.Nil(t, repo.Put("wizard/clusters/1", []byte("cluster1")))
+
+	values, err := repo.List("logs/")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]byte{
+		"logs/1": []byte("log1"),
+		"logs/2": []byte("log2"),
+	}, values)
+
+	values, err = repo.List("not-exist/")
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+}
+
+func TestFileRepositoryInvalidKey(t *testing.T) {
+
+	repo, cleanup := newTestFileRepository(t)
+	defer cleanup()
+
+	for _, key := range []string{"", "/a", "a/", "a//b", "../a", "a/../../b", ".hidden"} {
+		assert.NotNil(t, repo.Put(key, []byte("value")), key)
+		_, err := repo.Get(key)
+		assert.NotNil(t, err, key)
+	}
+}
+
+func TestSetRepository(t *testing.T) {
+
+	repo, cleanup := newTestFileRepository(t)
+	defer cleanup()
+
+	SetRepository(repo)
+	assert.Equal(t, repo, GetRepository())
+
+	assert.Nil(t, Close())
+	assert.Nil(t, GetRepository())
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 Shanghai JingDuo Information Technology co., Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package repository persists the data of the restful service, like wizard cluster drafts,
+// uploaded ssh private keys and deployment logs, so they can survive restarts and be shared
+// by multiple restful replicas.
+package repository
+
+import (
+	"errors"
+	"sync"
+)
+
+type (
+	// Repository is a key-value storage, keys are slash separated paths like "wizard/clusters/1".
+	Repository interface {
+		// Get returns the value of the key, returns ErrNotFound if the key not exist.
+		Get(key string) ([]byte, error)
+		// Put creates or overwrites the value of the key.
+		Put(key string, value []byte) error
+		// Delete deletes the key, deleting a not exist key is not an error.
+		Delete(key string) error
+		// List returns all the key-values which key has the prefix, keyed by the full key.
+		List(prefix string) (map[string][]byte, error)
+		// Close releases the resources held by the repository.
+		Close() error
+	}
+
+	Type string
+)
+
+const (
+	TypeNone Type = "none" // data is only kept in memory of the service
+	TypeFile Type = "file"
+	TypeEtcd Type = "etcd"
+)
+
+var (
+	ErrNotFound = errors.New("key not found")
+
+	repository Repository
+	rwLock     = new(sync.RWMutex)
+)
+
+// GetRepository returns the repository in use, returns nil if no repository was set.
+func GetRepository() Repository {
+
+	rwLock.RLock()
+	defer rwLock.RUnlock()
+	return repository
+}
+
+// SetRepository sets the repository in use, nil means the data is only kept in memory.
+func SetRepository(repo Repository) {
+
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	repository = repo
+}
+
+// Close closes the repository in use.
+func Close() error {
+
+	repo := GetRepository()
+	if repo == nil {
+		return nil
+	}
+
+	SetRepository(nil)
+	return repo.Close()
+}
@@ -31,7 +31,164 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/deploy/wizard/batchnodes": {
+        "/api/v1/deploy/wizard/clusters": {
+            "get": {
+                "description": "List all of the cluster drafts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "List cluster drafts",
+                "operationId": "ListClusterDrafts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.ClusterDraft"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new cluster draft, the wizard data of the cluster can be accessed by the cluster id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "Create a cluster draft",
+                "operationId": "CreateClusterDraft",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.ClusterDraft"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/deploy/wizard/clusters/{id}": {
+            "get": {
+                "description": "Describe cluster information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "Get Cluster Information",
+                "operationId": "GetCluster",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Cluster"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Store new cluster information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "Set Cluster Information",
+                "operationId": "SetCluster",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RequiredFields: shortName, name, kubeAPIServerConnectType",
+                        "name": "cluster",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.Cluster"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuccessfulOption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a cluster draft and all of its wizard data, a checking or deploying cluster can not be deleted",
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "Delete a cluster draft",
+                "operationId": "DeleteClusterDraft",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {},
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/deploy/wizard/clusters/{id}/batchnodes": {
             "post": {
                 "description": "Upload batch nodes configuration file to node list",
                 "consumes": [
@@ -46,6 +203,13 @@ var doc = `{
                 "summary": "Upload batch nodes configuration",
                 "operationId": "UploadBatchNodes",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "node list",
                         "name": "nodes",
@@ -66,7 +230,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/deploy/wizard/checks": {
+        "/api/v1/deploy/wizard/clusters/{id}/checks": {
             "get": {
                 "description": "Get the result of the check node",
                 "produces": [
@@ -77,6 +241,15 @@ var doc = `{
                 ],
                 "summary": "Get the result of check node",
                 "operationId": "GetCheckNodeListResult",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -96,6 +269,15 @@ var doc = `{
                 ],
                 "summary": "check node list",
                 "operationId": "CheckNodeList",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "201": {
                         "description": "Created",
@@ -106,55 +288,44 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/deploy/wizard/clusters": {
+        "/api/v1/deploy/wizard/clusters/{id}/checks/logs/{ip}": {
             "get": {
-                "description": "Describe cluster information",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "cluster"
-                ],
-                "summary": "Get Cluster Information",
-                "operationId": "GetCluster",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api.Cluster"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Store new cluster information",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Send the checking log of the node as it is written in a chunked response, the response ends when the checking finished.\nIf the response is broken, request again with the offset of the received length to resume.",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "cluster"
+                    "log"
                 ],
-                "summary": "Set Cluster Information",
-                "operationId": "SetCluster",
+                "summary": "Follow the checking log of a node",
+                "operationId": "FollowCheckNodesLog",
                 "parameters": [
                     {
-                        "description": "RequiredFields: shortName, name, kubeAPIServerConnectType",
-                        "name": "cluster",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.Cluster"
-                        }
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node IP",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position in the log to start from",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Log Content",
                         "schema": {
-                            "$ref": "#/definitions/api.SuccessfulOption"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -163,8 +334,8 @@ var doc = `{
                             "$ref": "#/definitions/h.AppErr"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/h.AppErr"
                         }
@@ -172,7 +343,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/deploy/wizard/deploys": {
+        "/api/v1/deploy/wizard/clusters/{id}/deploys": {
             "get": {
                 "description": "Get the result of the deployment",
                 "produces": [
@@ -183,6 +354,15 @@ var doc = `{
                 ],
                 "summary": "Get the result of deployment",
                 "operationId": "GetDeploymentReport",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -202,6 +382,15 @@ var doc = `{
                 ],
                 "summary": "Launch deployment",
                 "operationId": "LaunchDeployment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "201": {
                         "description": "Created",
@@ -216,30 +405,133 @@ var doc = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "description": "Cancel the running deployment, the remaining deploy items will be aborted",
+                "tags": [
+                    "deploy"
+                ],
+                "summary": "Cancel deployment",
+                "operationId": "CancelDeployment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {},
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
             }
         },
-        "/api/v1/deploy/wizard/kubeconfigs": {
+        "/api/v1/deploy/wizard/clusters/{id}/deploys/logs/{ip}": {
             "get": {
-                "description": "Download kubeconfig file",
+                "description": "Send the deployment log of the node and role as it is written in a chunked response, the response ends when the deployment finished.\nIf the response is broken, request again with the offset of the received length to resume.",
                 "produces": [
                     "text/plain"
                 ],
                 "tags": [
-                    "kubeconfig"
+                    "log"
+                ],
+                "summary": "Follow the deployment log of a node",
+                "operationId": "FollowDeployLog",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node IP",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "master",
+                            "worker",
+                            "etcd",
+                            "ingress"
+                        ],
+                        "type": "string",
+                        "description": "Deploy role",
+                        "name": "role",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position in the log to start from",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/deploy/wizard/clusters/{id}/deploys/timelines": {
+            "get": {
+                "description": "Get when the deploy task, its sub tasks and actions started and finished, and how long they stayed in\neach status, which is summarized from the journal of the status transitions in deploy controller.\nThe item which failed the deployment at first is pointed out if the deployment failed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deploy"
+                ],
+                "summary": "Get the timeline of deployment",
+                "operationId": "GetDeploymentTimeline",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Download kubeconfig file content",
-                "operationId": "DownloadKubeConfig",
                 "responses": {
                     "200": {
-                        "description": "Kube Config File Content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/h.AppErr"
+                            "$ref": "#/definitions/api.DeployTimeline"
                         }
                     },
                     "404": {
@@ -251,21 +543,21 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/deploy/wizard/logs/{id}": {
+        "/api/v1/deploy/wizard/clusters/{id}/kubeconfigs": {
             "get": {
-                "description": "Download the deployment log details to check the cause of the error",
+                "description": "Download kubeconfig file",
                 "produces": [
                     "text/plain"
                 ],
                 "tags": [
-                    "log"
+                    "kubeconfig"
                 ],
-                "summary": "Download the log detail",
-                "operationId": "DownloadLog",
+                "summary": "Download kubeconfig file content",
+                "operationId": "DownloadKubeConfig",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Log ID",
+                        "description": "Cluster ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -273,7 +565,7 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Log File Content",
+                        "description": "Kube Config File Content",
                         "schema": {
                             "type": "string"
                         }
@@ -293,7 +585,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/deploy/wizard/networks": {
+        "/api/v1/deploy/wizard/clusters/{id}/networks": {
             "get": {
                 "description": "get currently stored network options, returns default options if nothing stored.",
                 "produces": [
@@ -304,6 +596,15 @@ var doc = `{
                 ],
                 "summary": "get current network options",
                 "operationId": "GetNetwork",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -327,6 +628,13 @@ var doc = `{
                 "summary": "set network options",
                 "operationId": "SetNetwork",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "options of network components in the cluster",
                         "name": "networkOptions",
@@ -353,7 +661,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/deploy/wizard/nodes": {
+        "/api/v1/deploy/wizard/clusters/{id}/nodes": {
             "get": {
                 "description": "Get nodes information",
                 "produces": [
@@ -364,6 +672,15 @@ var doc = `{
                 ],
                 "summary": "Get nodes information",
                 "operationId": "GetNodeList",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -387,6 +704,13 @@ var doc = `{
                 "summary": "Add Node Information",
                 "operationId": "AddNode",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "Node information",
                         "name": "node",
@@ -419,7 +743,7 @@ var doc = `{
                 }
             }
         },
-        "/api/v1/deploy/wizard/nodes/{ip}": {
+        "/api/v1/deploy/wizard/clusters/{id}/nodes/{ip}": {
             "get": {
                 "description": "Get a node information",
                 "produces": [
@@ -431,6 +755,13 @@ var doc = `{
                 "summary": "Get a node information",
                 "operationId": "GetNode",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Node IP Address",
@@ -471,23 +802,218 @@ var doc = `{
                 "tags": [
                     "node"
                 ],
-                "summary": "Update Node Information",
-                "operationId": "UpdateNode",
+                "summary": "Update Node Information",
+                "operationId": "UpdateNode",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Node information",
+                        "name": "node",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateNodeData"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node IP Address",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.NodeData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a node from deployment candidate node list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "node"
+                ],
+                "summary": "Delete a node",
+                "operationId": "DeleteNode",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node IP Address",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {},
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/deploy/wizard/clusters/{id}/plans": {
+            "post": {
+                "description": "Get what the deployment would do without executing it, including the task tree, the actions on each node,\nthe commands run and the files put in order. Nothing is done on the nodes, the commands are planned as if\nall of them succeed, the content of the scripts, certs and keys are hidden.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deploy"
+                ],
+                "summary": "Plan deployment",
+                "operationId": "PlanDeployment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.DeployPlan"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/deploy/wizard/clusters/{id}/progresses": {
+            "get": {
+                "description": "Get all data, include current progress, cluster and node data. deploying progress or error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wizard"
+                ],
+                "summary": "Get all of current deploy wizard data",
+                "operationId": "GetWizardProgress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetWizardResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Clear all data, include current progress, cluster and node data. deploying progress or error.",
+                "tags": [
+                    "wizard"
+                ],
+                "summary": "Clear all of current deploy wizard data",
+                "operationId": "ClearWizard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {},
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/deploy/wizard/events": {
+            "get": {
+                "description": "Push the status transitions of the checking and deployment tasks of the cluster by Server-Sent Events,\neach \"task\" event carries a TaskEvent, an \"error\" event is sent before the stream is broken by an error.\nThe current status of the tasks are sent at first, the stream is kept until the client disconnects or the\nserver write timeout reached, the client should reconnect then.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "Watch the checking and deployment progress",
+                "operationId": "WatchWizardEvents",
                 "parameters": [
                     {
-                        "description": "Node information",
-                        "name": "node",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.UpdateNodeData"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Node IP Address",
-                        "name": "ip",
-                        "in": "path",
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "clusterId",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -495,7 +1021,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.NodeData"
+                            "$ref": "#/definitions/api.TaskEvent"
                         }
                     },
                     "400": {
@@ -509,36 +1035,37 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/h.AppErr"
                         }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/h.AppErr"
-                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a node from deployment candidate node list",
+            }
+        },
+        "/api/v1/deploy/wizard/logs/{id}": {
+            "get": {
+                "description": "Download the deployment log details to check the cause of the error",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "node"
+                    "log"
                 ],
-                "summary": "Delete a node",
-                "operationId": "DeleteNode",
+                "summary": "Download the log detail",
+                "operationId": "DownloadLog",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Node IP Address",
-                        "name": "ip",
+                        "type": "integer",
+                        "description": "Log ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {},
+                    "200": {
+                        "description": "Log File Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -550,46 +1077,8 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/h.AppErr"
                         }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/h.AppErr"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/deploy/wizard/progresses": {
-            "get": {
-                "description": "Get all data, include current progress, cluster and node data. deploying progress or error.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "wizard"
-                ],
-                "summary": "Get all of current deploy wizard data",
-                "operationId": "GetWizardProgress",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api.GetWizardResponse"
-                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Clear all data, include current progress, cluster and node data. deploying progress or error.",
-                "tags": [
-                    "wizard"
-                ],
-                "summary": "Clear all of current deploy wizard data",
-                "operationId": "ClearWizard",
-                "responses": {
-                    "204": {}
-                }
             }
         },
         "/api/v1/helm/clusters/{cluster}/namespaces/{namespace}/releases": {
@@ -960,6 +1449,79 @@ var doc = `{
                 }
             }
         },
+        "/api/v1/ssh/knownhosts": {
+            "get": {
+                "description": "List the SSH host keys pinned on the first connection to the nodes, the later connections fail if the host key changed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ssh"
+                ],
+                "summary": "List the pinned SSH host keys",
+                "operationId": "ListKnownHosts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetKnownHostListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ssh/knownhosts/{ip}": {
+            "delete": {
+                "description": "Forget the pinned SSH host key of a node after verifying the node, the host key is pinned again on the next connection",
+                "tags": [
+                    "ssh"
+                ],
+                "summary": "Forget the pinned SSH host key of a node",
+                "operationId": "ForgetKnownHost",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node IP",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "SSH port, 22 by default",
+                        "name": "port",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {},
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/h.AppErr"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/ssh/tests": {
             "post": {
                 "description": "Try to connection a node using ssh",
@@ -1069,6 +1631,35 @@ var doc = `{
         }
     },
     "definitions": {
+        "api.ActionPlan": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error message if the action can't be planned",
+                    "type": "object",
+                    "$ref": "#/definitions/api.Error"
+                },
+                "name": {
+                    "description": "Name of the action",
+                    "type": "string"
+                },
+                "nodeName": {
+                    "description": "Node which the action is done on",
+                    "type": "string"
+                },
+                "steps": {
+                    "description": "Commands run and files put in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PlanStep"
+                    }
+                },
+                "type": {
+                    "description": "Type of the action",
+                    "type": "string"
+                }
+            }
+        },
         "api.Annotation": {
             "type": "object",
             "required": [
@@ -1236,6 +1827,44 @@ var doc = `{
                 }
             }
         },
+        "api.ClusterDraft": {
+            "type": "object",
+            "properties": {
+                "checkResult": {
+                    "description": "Nodes check result",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "running",
+                        "successful",
+                        "failed"
+                    ]
+                },
+                "clusterId": {
+                    "description": "Cluster ID, used to access the wizard data of the cluster",
+                    "type": "integer"
+                },
+                "deployClusterStatus": {
+                    "description": "Cluster deployment status",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "running",
+                        "successful",
+                        "failed",
+                        "workedButHaveError"
+                    ]
+                },
+                "name": {
+                    "description": "Cluster name",
+                    "type": "string"
+                },
+                "shortName": {
+                    "description": "Cluster short name",
+                    "type": "string"
+                }
+            }
+        },
         "api.ConnectionData": {
             "type": "object",
             "required": [
@@ -1249,34 +1878,116 @@ var doc = `{
                     "type": "string",
                     "enum": [
                         "password",
-                        "privateKey"
+                        "privateKey",
+                        "keyboardInteractive"
                     ]
                 },
-                "ip": {
-                    "description": "node ip",
-                    "type": "string",
-                    "maxLength": 15,
-                    "minLength": 1
+                "challengeResponses": {
+                    "description": "responses of keyboard interactive challenges in order, password is used if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ip": {
+                    "description": "node ip",
+                    "type": "string",
+                    "maxLength": 15,
+                    "minLength": 1
+                },
+                "jumpHosts": {
+                    "description": "bastion hosts which the ssh connection is tunneled through, in connecting order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.JumpHost"
+                    }
+                },
+                "localhost": {
+                    "description": "the node is the host running the deploy controller, which is deployed without ssh",
+                    "type": "boolean"
+                },
+                "password": {
+                    "description": "login password",
+                    "type": "string"
+                },
+                "port": {
+                    "description": "ssh port",
+                    "type": "integer",
+                    "default": 22,
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "privateKeyName": {
+                    "description": "the private key name of login",
+                    "type": "string"
+                },
+                "sudo": {
+                    "description": "run commands and put files via sudo, it's required if the user is not root",
+                    "type": "boolean"
+                },
+                "sudoPassword": {
+                    "description": "sudo password, leave it empty if NOPASSWD is configured for the user",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "ssh username",
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "api.DeployPlan": {
+            "type": "object",
+            "properties": {
+                "clusterId": {
+                    "description": "Cluster ID of the cluster draft",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Error message if the deployment can't be planned",
+                    "type": "object",
+                    "$ref": "#/definitions/api.Error"
+                },
+                "plan": {
+                    "description": "Plan of the top level deploy task",
+                    "type": "object",
+                    "$ref": "#/definitions/api.TaskPlan"
+                }
+            }
+        },
+        "api.DeployTimeline": {
+            "type": "object",
+            "properties": {
+                "clusterId": {
+                    "description": "Cluster ID of the cluster draft",
+                    "type": "integer"
+                },
+                "end": {
+                    "description": "Unix time in milliseconds when the deployment finished, 0 if unfinished",
+                    "type": "integer"
                 },
-                "password": {
-                    "description": "login password",
+                "failedBy": {
+                    "description": "ID of the item which failed the deployment at first",
                     "type": "string"
                 },
-                "port": {
-                    "description": "ssh port",
-                    "type": "integer",
-                    "default": 22,
-                    "maximum": 65535,
-                    "minimum": 1
+                "items": {
+                    "description": "The deploy task, its sub tasks and actions in the order they started",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TimelineItem"
+                    }
                 },
-                "privateKeyName": {
-                    "description": "the private key name of login",
+                "start": {
+                    "description": "Unix time in milliseconds when the deployment started",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "The current status of the deploy task",
                     "type": "string"
                 },
-                "username": {
-                    "description": "ssh username",
-                    "type": "string",
-                    "maxLength": 128
+                "taskName": {
+                    "description": "Name of the deploy task",
+                    "type": "string"
                 }
             }
         },
@@ -1399,6 +2110,17 @@ var doc = `{
                 }
             }
         },
+        "api.GetKnownHostListResponse": {
+            "type": "object",
+            "properties": {
+                "knownHosts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.KnownHost"
+                    }
+                }
+            }
+        },
         "api.GetNodeListResponse": {
             "type": "object",
             "properties": {
@@ -1534,6 +2256,79 @@ var doc = `{
                 "type": "object"
             }
         },
+        "api.JumpHost": {
+            "type": "object",
+            "required": [
+                "ip",
+                "port",
+                "username"
+            ],
+            "properties": {
+                "authorizationType": {
+                    "description": "type of authorization",
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "privateKey",
+                        "keyboardInteractive"
+                    ]
+                },
+                "challengeResponses": {
+                    "description": "responses of keyboard interactive challenges in order, password is used if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ip": {
+                    "description": "bastion host ip",
+                    "type": "string",
+                    "maxLength": 15,
+                    "minLength": 1
+                },
+                "password": {
+                    "description": "login password",
+                    "type": "string"
+                },
+                "port": {
+                    "description": "ssh port",
+                    "type": "integer",
+                    "default": 22,
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "privateKeyName": {
+                    "description": "the private key name of login",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "ssh username",
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "api.KnownHost": {
+            "type": "object",
+            "properties": {
+                "fingerprint": {
+                    "description": "SHA256 fingerprint of the pinned host key",
+                    "type": "string"
+                },
+                "host": {
+                    "description": "Host address, the port is omitted if it's 22, like \"192.168.0.1\" or \"[192.168.0.1]:2222\"",
+                    "type": "string"
+                },
+                "keyType": {
+                    "description": "Type of the pinned host key",
+                    "type": "string"
+                },
+                "pinnedAt": {
+                    "description": "Unix time when the host key was pinned",
+                    "type": "integer"
+                }
+            }
+        },
         "api.Label": {
             "type": "object",
             "required": [
@@ -1578,9 +2373,17 @@ var doc = `{
                     "type": "string",
                     "enum": [
                         "password",
-                        "privateKey"
+                        "privateKey",
+                        "keyboardInteractive"
                     ]
                 },
+                "challengeResponses": {
+                    "description": "responses of keyboard interactive challenges in order, password is used if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "description": {
                     "description": "node description",
                     "type": "string"
@@ -1596,6 +2399,13 @@ var doc = `{
                     "maxLength": 15,
                     "minLength": 1
                 },
+                "jumpHosts": {
+                    "description": "bastion hosts which the ssh connection is tunneled through, in connecting order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.JumpHost"
+                    }
+                },
                 "labels": {
                     "description": "Node labels",
                     "type": "array",
@@ -1603,6 +2413,10 @@ var doc = `{
                         "$ref": "#/definitions/api.Label"
                     }
                 },
+                "localhost": {
+                    "description": "the node is the host running the deploy controller, which is deployed without ssh",
+                    "type": "boolean"
+                },
                 "name": {
                     "description": "node name",
                     "type": "string",
@@ -1633,6 +2447,14 @@ var doc = `{
                         "etcd"
                     ]
                 },
+                "sudo": {
+                    "description": "run commands and put files via sudo, it's required if the user is not root",
+                    "type": "boolean"
+                },
+                "sudoPassword": {
+                    "description": "sudo password, leave it empty if NOPASSWD is configured for the user",
+                    "type": "string"
+                },
                 "taints": {
                     "description": "Node taints",
                     "type": "array",
@@ -1647,6 +2469,44 @@ var doc = `{
                 }
             }
         },
+        "api.PlanStep": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "What the step does",
+                    "type": "string",
+                    "enum": [
+                        "run",
+                        "putFile",
+                        "putDir"
+                    ]
+                },
+                "command": {
+                    "description": "Command run, only for run",
+                    "type": "string"
+                },
+                "content": {
+                    "description": "Content of the file put",
+                    "type": "string"
+                },
+                "contentHidden": {
+                    "description": "Whether the content of the file put is hidden, for the scripts and certs",
+                    "type": "boolean"
+                },
+                "mode": {
+                    "description": "File mode of the file put",
+                    "type": "string"
+                },
+                "nodeName": {
+                    "description": "Node which the step is done on",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path of the file or directory put",
+                    "type": "string"
+                }
+            }
+        },
         "api.SSHCertificate": {
             "type": "object",
             "required": [
@@ -1654,11 +2514,19 @@ var doc = `{
                 "name"
             ],
             "properties": {
+                "certificate": {
+                    "description": "OpenSSH user certificate signed by CA for the private key, the content of the \"-cert.pub\" file",
+                    "type": "string"
+                },
                 "content": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
+                },
+                "passphrase": {
+                    "description": "passphrase of the encrypted private key",
+                    "type": "string"
                 }
             }
         },
@@ -1693,6 +2561,181 @@ var doc = `{
                 }
             }
         },
+        "api.TaskEvent": {
+            "type": "object",
+            "properties": {
+                "actionType": {
+                    "description": "Action type, only for action",
+                    "type": "string"
+                },
+                "clusterId": {
+                    "description": "Cluster ID of the cluster draft",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Error message",
+                    "type": "object",
+                    "$ref": "#/definitions/api.Error"
+                },
+                "kind": {
+                    "description": "Whose status was changed, a task or an action",
+                    "type": "string",
+                    "enum": [
+                        "task",
+                        "action"
+                    ]
+                },
+                "name": {
+                    "description": "Name of the task, sub task or action",
+                    "type": "string"
+                },
+                "nodeName": {
+                    "description": "Node name, only for action",
+                    "type": "string"
+                },
+                "parent": {
+                    "description": "Name of the task which the sub task or action belongs to",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "The new status",
+                    "type": "string"
+                },
+                "taskName": {
+                    "description": "Name of the top level task",
+                    "type": "string"
+                },
+                "taskType": {
+                    "description": "Type of the top level task",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "Unix time when the transition was found",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.TaskPlan": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "description": "Plans of the actions of the task",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ActionPlan"
+                    }
+                },
+                "dependencies": {
+                    "description": "Names of the sibling tasks which must be done before the task",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "description": "Error message if the task can't be planned",
+                    "type": "object",
+                    "$ref": "#/definitions/api.Error"
+                },
+                "name": {
+                    "description": "Name of the task",
+                    "type": "string"
+                },
+                "parent": {
+                    "description": "Name of the parent task",
+                    "type": "string"
+                },
+                "subTasks": {
+                    "description": "Plans of the sub tasks",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TaskPlan"
+                    }
+                },
+                "type": {
+                    "description": "Type of the task",
+                    "type": "string"
+                }
+            }
+        },
+        "api.TimelineItem": {
+            "type": "object",
+            "properties": {
+                "actionType": {
+                    "description": "Action type, only for action",
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "Milliseconds from start to end, 0 if unfinished",
+                    "type": "integer"
+                },
+                "end": {
+                    "description": "Unix time in milliseconds when it finished, 0 if unfinished",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "The last error message",
+                    "type": "object",
+                    "$ref": "#/definitions/api.Error"
+                },
+                "id": {
+                    "description": "ID of the item, made of the kind, parent and name",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "A task or an action",
+                    "type": "string",
+                    "enum": [
+                        "task",
+                        "action"
+                    ]
+                },
+                "name": {
+                    "description": "Name of the task, sub task or action",
+                    "type": "string"
+                },
+                "nodeName": {
+                    "description": "Node name, only for action",
+                    "type": "string"
+                },
+                "parent": {
+                    "description": "Name of the task which the sub task or action belongs to",
+                    "type": "string"
+                },
+                "phases": {
+                    "description": "The statuses it stayed in before finishing, for the bars of a Gantt chart",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TimelinePhase"
+                    }
+                },
+                "start": {
+                    "description": "Unix time in milliseconds when it started",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "The current status",
+                    "type": "string"
+                }
+            }
+        },
+        "api.TimelinePhase": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "Unix time in milliseconds when the phase ended, 0 if it's the current one",
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Unix time in milliseconds when the phase started",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "The status in the phase",
+                    "type": "string"
+                }
+            }
+        },
         "api.UpdateNodeData": {
             "type": "object",
             "required": [
@@ -1706,9 +2749,17 @@ var doc = `{
                     "type": "string",
                     "enum": [
                         "password",
-                        "privateKey"
+                        "privateKey",
+                        "keyboardInteractive"
                     ]
                 },
+                "challengeResponses": {
+                    "description": "responses of keyboard interactive challenges in order, password is used if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "description": {
                     "description": "node description",
                     "type": "string"
@@ -1718,6 +2769,13 @@ var doc = `{
                     "type": "string",
                     "default": "/var/lib/docker"
                 },
+                "jumpHosts": {
+                    "description": "bastion hosts which the ssh connection is tunneled through, in connecting order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.JumpHost"
+                    }
+                },
                 "labels": {
                     "description": "Node labels",
                     "type": "array",
@@ -1725,6 +2783,10 @@ var doc = `{
                         "$ref": "#/definitions/api.Label"
                     }
                 },
+                "localhost": {
+                    "description": "the node is the host running the deploy controller, which is deployed without ssh",
+                    "type": "boolean"
+                },
                 "name": {
                     "description": "node name",
                     "type": "string",
@@ -1755,6 +2817,14 @@ var doc = `{
                         "etcd"
                     ]
                 },
+                "sudo": {
+                    "description": "run commands and put files via sudo, it's required if the user is not root",
+                    "type": "boolean"
+                },
+                "sudoPassword": {
+                    "description": "sudo password, leave it empty if NOPASSWD is configured for the user",
+                    "type": "string"
+                },
                 "taints": {
                     "description": "Node taints",
                     "type": "array",
@@ -40,6 +40,7 @@ var (
 	EUnknown               = &AppErr{http.StatusInternalServerError, "Unknown", nil}
 	EExists                = &AppErr{http.StatusConflict, "Exists", nil}
 	EDeployControllerError = &AppErr{http.StatusInternalServerError, "DeployControllerError", nil}
+	ERepositoryError       = &AppErr{http.StatusInternalServerError, "RepositoryError", nil}
 )
 
 const ()
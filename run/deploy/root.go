@@ -23,7 +23,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/kpaas-io/kpaas/pkg/deploy/machine"
 	"github.com/kpaas-io/kpaas/pkg/deploy/server"
+	"github.com/kpaas-io/kpaas/pkg/deploy/task"
 	_ "github.com/kpaas-io/kpaas/pkg/utils/log"
 )
 
@@ -32,6 +34,13 @@ var (
 	port       uint16
 	logLevel   string
 	logFileLoc string
+
+	maxSSHSessionsPerNode int
+
+	maxConcurrentActions            int
+	maxConcurrentActionsPerTaskType map[string]int
+	workerBatchSize                 int
+	workerBatchFailureThreshold     int
 )
 
 const (
@@ -48,8 +57,14 @@ var rootCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		setupLogLevel()
 		options := server.ServerOptions{
-			Port:       port,
-			LogFileLoc: logFileLoc,
+			Port:                  port,
+			LogFileLoc:            logFileLoc,
+			MaxSSHSessionsPerNode: maxSSHSessionsPerNode,
+
+			MaxConcurrentActions:            maxConcurrentActions,
+			MaxConcurrentActionsPerTaskType: maxConcurrentActionsPerTaskType,
+			WorkerBatchSize:                 workerBatchSize,
+			WorkerBatchFailureThreshold:     workerBatchFailureThreshold,
 		}
 		server.New(options).Run(SetupSignalHandler())
 	},
@@ -69,6 +84,11 @@ func init() {
 	rootCmd.Flags().Uint16VarP(&port, "port", "p", defaultPort, "gRPC service listening port")
 	rootCmd.Flags().StringVarP(&logLevel, "log-level", "l", defaultLogLevel, "log level(options: trace, debug, info, warn|warning, error, fatal, panic)")
 	rootCmd.Flags().StringVar(&logFileLoc, "log-file-location", defaultLogFileLoc, "the location to store the detail logs")
+	rootCmd.Flags().IntVar(&maxSSHSessionsPerNode, "max-ssh-sessions-per-node", machine.DefaultMaxSessionsPerNode, "the max number of the concurrent ssh sessions to a node, should not exceed the MaxSessions of sshd")
+	rootCmd.Flags().IntVar(&maxConcurrentActions, "max-concurrent-actions", task.DefaultMaxConcurrentActions, "the max number of the actions executed at the same time by all the tasks")
+	rootCmd.Flags().StringToIntVar(&maxConcurrentActionsPerTaskType, "max-concurrent-actions-per-task-type", nil, "the max number of the actions executed at the same time by the tasks of a type, e.g. DeployWorker=20,NodeInit=30")
+	rootCmd.Flags().IntVar(&workerBatchSize, "worker-batch-size", task.DefaultWorkerBatchSize, "the number of the workers joined in a rolling batch")
+	rootCmd.Flags().IntVar(&workerBatchFailureThreshold, "worker-batch-failure-threshold", task.DefaultWorkerBatchFailureThreshold, "the number of the failed workers which halts the following batches")
 }
 
 // initConfig reads in config file and ENV variables if set.